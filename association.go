@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 type Association struct {
@@ -14,6 +15,95 @@ type Association struct {
 	Field      *Field
 }
 
+// AssociationChange is a change of associations passed to BeforeAssociationSave and AfterAssociationSave hooks of
+// the record, Action is one of save, append, replace, delete and clear, Values are associated records saved, or
+// values passed to Association's methods
+type AssociationChange struct {
+	Field  string
+	Kind   string
+	Action string
+	Values []interface{}
+}
+
+// hooks of records to audit or veto changes of their associations, e.g:
+//
+//	func (user *User) BeforeAssociationSave(db *gorm.DB, change *gorm.AssociationChange) error {
+//		if change.Field == "Roles" && user.Locked {
+//			return errors.New("roles of locked users can't be changed")
+//		}
+//		return nil
+//	}
+type beforeAssociationSaver interface {
+	BeforeAssociationSave(db *DB, change *AssociationChange) error
+}
+
+type afterAssociationSaver interface {
+	AfterAssociationSave(db *DB, change *AssociationChange) error
+}
+
+// callAssociationHook call BeforeAssociationSave or AfterAssociationSave of the record, the change is vetoed if
+// BeforeAssociationSave returns error
+func (scope *Scope) callAssociationHook(before bool, field *Field, action string, values []interface{}) error {
+	change := &AssociationChange{Field: field.Name, Kind: field.Relationship.Kind, Action: action, Values: values}
+	if saver, ok := scope.Value.(beforeAssociationSaver); ok && before {
+		return scope.Err(saver.BeforeAssociationSave(scope.NewDB(), change))
+	}
+	if saver, ok := scope.Value.(afterAssociationSaver); ok && !before {
+		return scope.Err(saver.AfterAssociationSave(scope.NewDB(), change))
+	}
+	return nil
+}
+
+// callSaveAssociationHook call association hooks when saving the record, unless they are called by Association
+func (scope *Scope) callSaveAssociationHook(before bool, field *Field, values []interface{}) error {
+	if skip, ok := scope.InstanceGet("gorm:skip_association_hooks"); ok && skip.(bool) {
+		return nil
+	}
+	return scope.callAssociationHook(before, field, "save", values)
+}
+
+// RefuseZeroForeignKeys return ZeroForeignKey error when saving has one, has many or many to many associations of
+// records without primary keys, e.g. primary keys not returned by the database, instead of saving orphan associations
+// with zero foreign keys
+func (s *DB) RefuseZeroForeignKeys(refuse bool) *DB {
+	return s.Set("gorm:refuse_zero_foreign_keys", refuse)
+}
+
+// AllowNullForeignKeys allow Replace and Clear of has one and has many associations without orphan policies, which
+// set foreign keys of removed records to NULL, they return NullForeignKeyNotAllowed error by default, e.g:
+//
+//	db.AllowNullForeignKeys(true).Model(&user).Association("Emails").Replace(emails)
+//	//// UPDATE emails SET user_id = NULL WHERE user_id = 1 AND id NOT IN (3,4)
+func (s *DB) AllowNullForeignKeys(allow bool) *DB {
+	return s.Set("gorm:allow_null_foreign_keys", allow)
+}
+
+func (scope *Scope) refuseZeroForeignKeys() bool {
+	refuse, ok := scope.Get("gorm:refuse_zero_foreign_keys")
+	return ok && refuse.(bool)
+}
+
+func (scope *Scope) allowNullForeignKeys() bool {
+	allow, ok := scope.Get("gorm:allow_null_foreign_keys")
+	return ok && allow.(bool)
+}
+
+// withHooks run the change between BeforeAssociationSave and AfterAssociationSave hooks of the record, hooks of
+// saving associated records in the change are skipped
+func (association *Association) withHooks(action string, values []interface{}, change func()) *Association {
+	scope := association.Scope
+	if association.setErr(scope.callAssociationHook(true, association.Field, action, values)).Error != nil {
+		return association
+	}
+	scope.InstanceSet("gorm:skip_association_hooks", true)
+	change()
+	scope.InstanceDelete("gorm:skip_association_hooks")
+	if association.Error == nil {
+		association.setErr(scope.callAssociationHook(false, association.Field, action, values))
+	}
+	return association
+}
+
 func (association *Association) setErr(err error) *Association {
 	if err != nil {
 		association.Error = err
@@ -26,7 +116,12 @@ func (association *Association) Find(value interface{}) *Association {
 	return association.setErr(association.Scope.db.Error)
 }
 
+// Append save values and link them to the record, between BeforeAssociationSave and AfterAssociationSave hooks of the record
 func (association *Association) Append(values ...interface{}) *Association {
+	return association.withHooks("append", values, func() { association.append(values...) })
+}
+
+func (association *Association) append(values ...interface{}) *Association {
 	scope := association.Scope
 	field := association.Field
 
@@ -66,7 +161,12 @@ func (association *Association) getPrimaryKeys(values ...interface{}) []interfac
 	return primaryKeys
 }
 
+// Delete unlink values from the record, between BeforeAssociationSave and AfterAssociationSave hooks of the record
 func (association *Association) Delete(values ...interface{}) *Association {
+	return association.withHooks("delete", values, func() { association.delete(values...) })
+}
+
+func (association *Association) delete(values ...interface{}) *Association {
 	primaryKeys := association.getPrimaryKeys(values...)
 
 	if len(primaryKeys) == 0 {
@@ -103,7 +203,12 @@ func (association *Association) Delete(values ...interface{}) *Association {
 	return association
 }
 
+// Replace link values to the record and unlink others, between BeforeAssociationSave and AfterAssociationSave hooks of the record
 func (association *Association) Replace(values ...interface{}) *Association {
+	return association.withHooks("replace", values, func() { association.replace(values...) })
+}
+
+func (association *Association) replace(values ...interface{}) *Association {
 	relationship := association.Field.Relationship
 	scope := association.Scope
 	if relationship.Kind == "many_to_many" {
@@ -111,7 +216,7 @@ func (association *Association) Replace(values ...interface{}) *Association {
 
 		oldPrimaryKeys := association.getPrimaryKeys(field.Interface())
 		association.Field.Set(reflect.Zero(association.Field.Field.Type()))
-		association.Append(values...)
+		association.append(values...)
 		newPrimaryKeys := association.getPrimaryKeys(field.Interface())
 
 		var addedPrimaryKeys = []interface{}{}
@@ -134,13 +239,141 @@ func (association *Association) Replace(values ...interface{}) *Association {
 		sql := fmt.Sprintf("%v = ? AND %v NOT IN (?)", scope.Quote(relationship.ForeignDBName), scope.Quote(relationship.AssociationForeignDBName))
 		query := scope.NewDB().Where(sql, association.PrimaryKey, addedPrimaryKeys)
 		association.setErr(relationship.JoinTableHandler.Delete(query, relationship))
+	} else if relationship.Kind == "has_many" || relationship.Kind == "has_one" {
+		association.replaceOwned(values...)
 	} else {
-		association.setErr(errors.New("replace only support many to many"))
+		association.setErr(errors.New("replace only support many to many, has many and has one"))
 	}
 	return association
 }
 
+// replaceOwned save values as has one or has many associations, other associated records are orphaned and handled
+// by the orphan policy of the relationship
+func (association *Association) replaceOwned(values ...interface{}) {
+	relationship := association.Field.Relationship
+	scope := association.Scope
+	if association.setErr(association.removeOrphans(values...)).Error != nil {
+		return
+	}
+
+	field := association.Field
+	field.Set(reflect.Zero(field.Field.Type()))
+	if relationship.Kind == "has_many" {
+		association.append(values...)
+	} else if len(values) > 0 {
+		value := reflect.ValueOf(values[0])
+		if field.Field.Kind() != reflect.Ptr {
+			value = reflect.Indirect(value)
+		}
+		if association.setErr(field.Set(value)).Error != nil {
+			return
+		}
+		scope.Search.Select(association.Column)
+		scope.callCallbacks(scope.db.parent.callback.updates)
+		association.setErr(scope.db.Error)
+	}
+}
+
+// removeOrphans handle has one or has many records of the association except kept ones by the orphan policy of the
+// relationship, `orphan:nullify` sets their foreign keys to NULL, `orphan:delete` deletes them, `orphan:error` returns
+// OrphanedAssociations error if there are any, foreign keys are set to NULL if allowed by AllowNullForeignKeys by default,
+// kept records are matched by all of their primary keys
+func (association *Association) removeOrphans(keptValues ...interface{}) error {
+	relationship := association.Field.Relationship
+	scope := association.Scope
+	if relationship.OrphanPolicy == "" && !scope.allowNullForeignKeys() {
+		return NullForeignKeyNotAllowed
+	}
+
+	toScope := scope.New(reflect.New(association.Field.Struct.Type).Interface())
+	query := scope.NewDB().Table(toScope.TableName()).Where(fmt.Sprintf("%v = ?", scope.Quote(relationship.ForeignDBName)), association.PrimaryKey)
+	if relationship.PolymorphicType != "" {
+		query = query.Where(fmt.Sprintf("%v = ?", scope.Quote(relationship.PolymorphicDBName)), scope.TableName())
+	}
+	if sql, vars := keptRecordsSql(toScope, keptValues); sql != "" {
+		query = query.Where(sql, vars...)
+	}
+
+	switch relationship.OrphanPolicy {
+	case "delete":
+		return query.Delete(reflect.New(toScope.GetModelStruct().ModelType).Interface()).Error
+	case "error":
+		var count int
+		if err := query.Count(&count).Error; err != nil {
+			return err
+		} else if count > 0 {
+			return OrphanedAssociations
+		}
+		return nil
+	case "", "nullify":
+		return query.UpdateColumn(relationship.ForeignDBName, nil).Error
+	}
+	return fmt.Errorf("unknown orphan policy %v of %v", relationship.OrphanPolicy, association.Column)
+}
+
+// keptRecordsSql get the condition excluding records of values by their primary keys, records of composite primary keys
+// are excluded by tuples of them, records with blank primary keys are new ones, they are skipped
+func keptRecordsSql(scope *Scope, values []interface{}) (string, []interface{}) {
+	primaryFields := scope.GetModelStruct().PrimaryFields
+	if len(primaryFields) == 0 {
+		return "", nil
+	}
+
+	var records []interface{}
+	for _, value := range values {
+		if reflectValue := reflect.Indirect(reflect.ValueOf(value)); reflectValue.Kind() == reflect.Slice {
+			for i := 0; i < reflectValue.Len(); i++ {
+				records = append(records, reflectValue.Index(i).Interface())
+			}
+		} else if reflectValue.Kind() == reflect.Struct {
+			records = append(records, value)
+		}
+	}
+
+	var keys [][]interface{}
+	for _, record := range records {
+		var key []interface{}
+		fields := scope.New(record).Fields()
+		for _, primaryField := range primaryFields {
+			if field := fields[primaryField.DBName]; field != nil && !field.IsBlank {
+				key = append(key, field.Field.Interface())
+			}
+		}
+		if len(key) == len(primaryFields) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return "", nil
+	}
+
+	if len(primaryFields) == 1 {
+		var vars []interface{}
+		for _, key := range keys {
+			vars = append(vars, key[0])
+		}
+		return fmt.Sprintf("%v NOT IN (?)", scope.Quote(primaryFields[0].DBName)), []interface{}{vars}
+	}
+
+	var conditions []string
+	var vars []interface{}
+	for _, key := range keys {
+		var columns []string
+		for _, primaryField := range primaryFields {
+			columns = append(columns, fmt.Sprintf("%v = ?", scope.Quote(primaryField.DBName)))
+		}
+		conditions = append(conditions, "("+strings.Join(columns, " AND ")+")")
+		vars = append(vars, key...)
+	}
+	return fmt.Sprintf("NOT (%v)", strings.Join(conditions, " OR ")), vars
+}
+
+// Clear unlink all associated records, between BeforeAssociationSave and AfterAssociationSave hooks of the record
 func (association *Association) Clear() *Association {
+	return association.withHooks("clear", nil, func() { association.clear() })
+}
+
+func (association *Association) clear() *Association {
 	relationship := association.Field.Relationship
 	scope := association.Scope
 	if relationship.Kind == "many_to_many" {
@@ -151,8 +384,12 @@ func (association *Association) Clear() *Association {
 		} else {
 			association.setErr(err)
 		}
+	} else if relationship.Kind == "has_many" || relationship.Kind == "has_one" {
+		if association.setErr(association.removeOrphans()).Error == nil {
+			association.Field.Set(reflect.Zero(association.Field.Field.Type()))
+		}
 	} else {
-		association.setErr(errors.New("clear only support many to many"))
+		association.setErr(errors.New("clear only support many to many, has many and has one"))
 	}
 	return association
 }
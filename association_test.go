@@ -1,8 +1,13 @@
 package gorm_test
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"testing"
+	"time"
+
+	"golib/gorm"
 )
 
 func TestHasOneAndHasManyAssociation(t *testing.T) {
@@ -262,3 +267,186 @@ func TestForeignKey(t *testing.T) {
 		}
 	}
 }
+
+type TouchPost struct {
+	Id        int64
+	Title     string
+	UpdatedAt time.Time
+}
+
+type TouchComment struct {
+	Id          int64
+	Content     string
+	TouchPostId int64
+	TouchPost   TouchPost `gorm:"touch"`
+}
+
+func TestTouchBelongsToAssociation(t *testing.T) {
+	DB.DropTable(&TouchPost{})
+	DB.DropTable(&TouchComment{})
+	DB.CreateTable(&TouchPost{})
+	DB.CreateTable(&TouchComment{})
+
+	post := TouchPost{Title: "touch post"}
+	DB.Save(&post)
+
+	lastUpdatedAt := time.Now().Add(-time.Hour)
+	DB.Model(&post).UpdateColumn("updated_at", lastUpdatedAt)
+
+	comment := TouchComment{Content: "touch comment", TouchPostId: post.Id}
+	if err := DB.Save(&comment).Error; err != nil {
+		t.Errorf("No error should happen when save comment, but got %v", err)
+	}
+
+	var touchedPost TouchPost
+	DB.First(&touchedPost, post.Id)
+	if !touchedPost.UpdatedAt.After(lastUpdatedAt) {
+		t.Errorf("Post's updated_at should be touched after save comment")
+	}
+
+	DB.Model(&post).UpdateColumn("updated_at", lastUpdatedAt)
+	DB.Delete(&comment)
+	DB.First(&touchedPost, post.Id)
+	if !touchedPost.UpdatedAt.After(lastUpdatedAt) {
+		t.Errorf("Post's updated_at should be touched after delete comment")
+	}
+}
+
+func TestReplaceHasManyAssociation(t *testing.T) {
+	user := User{Name: "replace", Emails: []Email{{Email: "replace1@example.com"}, {Email: "replace2@example.com"}}}
+	DB.Save(&user)
+
+	if err := DB.Model(&user).Association("Emails").Replace([]Email{{Email: "replace3@example.com"}}).Error; err != gorm.NullForeignKeyNotAllowed {
+		t.Errorf("replacing has many associations should be refused by default, but got %v", err)
+	}
+
+	if err := DB.AllowNullForeignKeys(true).Model(&user).Association("Emails").Replace([]Email{user.Emails[0], {Email: "replace3@example.com"}}).Error; err != nil {
+		t.Errorf("no error should happen when replacing has many associations, but got %v", err)
+	}
+	var count int
+	DB.Model(&Email{}).Where("user_id = ?", user.Id).Count(&count)
+	if count != 2 {
+		t.Errorf("foreign keys of removed emails should be set to NULL, but got %v emails", count)
+	}
+}
+
+func TestRefuseZeroForeignKeys(t *testing.T) {
+	user := User{Name: "zero key", Emails: []Email{{Email: "zero@example.com"}}}
+	scope := DB.RefuseZeroForeignKeys(true).NewScope(&user)
+	if gorm.SaveAfterAssociations(scope); scope.DB().Error != gorm.ZeroForeignKey {
+		t.Errorf("associations of records without primary keys should be refused, but got %v", scope.DB().Error)
+	}
+}
+
+type OrphanPost struct {
+	Id       int64
+	Comments []OrphanComment `gorm:"orphan:delete"`
+	Notes    []OrphanNote    `gorm:"orphan:error"`
+}
+
+type OrphanComment struct {
+	Id           int64
+	OrphanPostId int64
+}
+
+type OrphanNote struct {
+	Id           int64
+	OrphanPostId int64
+}
+
+func TestAssociationOrphanPolicy(t *testing.T) {
+	DB.DropTable(&OrphanComment{})
+	DB.AutoMigrate(&OrphanPost{}, &OrphanComment{}, &OrphanNote{})
+	post := OrphanPost{Comments: []OrphanComment{{}, {}}, Notes: []OrphanNote{{}}}
+	DB.Save(&post)
+
+	if err := DB.Model(&post).Association("Comments").Replace(post.Comments[0]).Error; err != nil {
+		t.Errorf("no error should happen when replacing comments, but got %v", err)
+	}
+	var count int
+	if DB.Model(&OrphanComment{}).Count(&count); count != 1 {
+		t.Errorf("removed comments should be deleted, but got %v comments", count)
+	}
+
+	if err := DB.Model(&post).Association("Notes").Clear().Error; err != gorm.OrphanedAssociations {
+		t.Errorf("clearing notes should be refused, but got %v", err)
+	}
+	if DB.Model(&OrphanNote{}).Where("orphan_post_id = ?", post.Id).Count(&count); count != 1 {
+		t.Errorf("notes should not be removed, but got %v notes", count)
+	}
+}
+
+type OrphanBook struct {
+	Id    int64
+	Pages []OrphanPage `gorm:"orphan:delete"`
+}
+
+type OrphanPage struct {
+	Chapter      int64 `gorm:"primary_key;auto_increment:false"`
+	Number       int64 `gorm:"primary_key;auto_increment:false"`
+	OrphanBookId int64
+}
+
+func TestAssociationOrphanPolicyWithCompositeKeys(t *testing.T) {
+	DB.DropTable(&OrphanBook{})
+	DB.DropTable(&OrphanPage{})
+	DB.AutoMigrate(&OrphanBook{}, &OrphanPage{})
+	book := OrphanBook{}
+	DB.Save(&book)
+	for _, page := range []OrphanPage{{Chapter: 1, Number: 1}, {Chapter: 1, Number: 2}, {Chapter: 2, Number: 1}} {
+		page.OrphanBookId = book.Id
+		DB.Create(&page)
+	}
+
+	if err := DB.Model(&book).Association("Pages").Replace(OrphanPage{Chapter: 1, Number: 2}).Error; err != nil {
+		t.Errorf("no error should happen when replacing pages, but got %v", err)
+	}
+	var pages []OrphanPage
+	if DB.Where("orphan_book_id = ?", book.Id).Find(&pages); len(pages) != 1 || pages[0].Chapter != 1 || pages[0].Number != 2 {
+		t.Errorf("pages should be kept by all primary keys, but got %+v", pages)
+	}
+}
+
+type HookedTag struct {
+	Id   int64
+	Name string
+}
+
+type HookedPost struct {
+	Id      int64
+	Locked  bool
+	Tags    []HookedTag `gorm:"many2many:hooked_post_tags"`
+	changes []string
+}
+
+func (post *HookedPost) BeforeAssociationSave(db *gorm.DB, change *gorm.AssociationChange) error {
+	if post.Locked {
+		return errors.New("tags of locked posts can't be changed")
+	}
+	return nil
+}
+
+func (post *HookedPost) AfterAssociationSave(db *gorm.DB, change *gorm.AssociationChange) error {
+	post.changes = append(post.changes, fmt.Sprintf("%v %v %v", change.Action, change.Field, len(change.Values)))
+	return nil
+}
+
+func TestAssociationSaveHooks(t *testing.T) {
+	DB.AutoMigrate(&HookedPost{}, &HookedTag{})
+
+	post := HookedPost{Tags: []HookedTag{{Name: "go"}}}
+	DB.Save(&post)
+	DB.Model(&post).Association("Tags").Append(HookedTag{Name: "orm"})
+	DB.Model(&post).Association("Tags").Clear()
+	if expected := []string{"save Tags 1", "append Tags 1", "clear Tags 0"}; !reflect.DeepEqual(post.changes, expected) {
+		t.Errorf("association hooks should be called around changes, expected %v, but got %v", expected, post.changes)
+	}
+
+	post.Locked = true
+	if err := DB.Model(&post).Association("Tags").Append(HookedTag{Name: "vetoed"}).Error; err == nil {
+		t.Errorf("changes vetoed by BeforeAssociationSave should return its error")
+	}
+	if DB.Model(&post).Association("Tags").Count() != 0 {
+		t.Errorf("vetoed tags should not be appended")
+	}
+}
@@ -0,0 +1,118 @@
+package gorm
+
+import "reflect"
+
+// BatchSize split records of BatchCreate, BatchCreateIgnore, BatchUpsert and BatchUpdate into chunks of the size,
+// each chunk of batch creates is inserted with one statement, 0 writes all records as one chunk, e.g:
+//
+//	db.BatchSize(500).BatchCreate(users)
+func (s *DB) BatchSize(size int) *DB {
+	return s.Set("gorm:batch_size", size)
+}
+
+// BatchChunkTransactions commit each chunk of batch writes in its own transaction, writing stops at the first failed
+// chunk, chunks committed before it are kept, and the error is a *BatchError, by default all chunks are written in one
+// transaction, and nothing is kept if any of them fails, e.g:
+//
+//	if err, ok := db.BatchSize(500).BatchChunkTransactions(true).BatchUpdate(&accounts).Error.(*gorm.BatchError); ok {
+//		retry(accounts[err.Chunk*500:])
+//	}
+func (s *DB) BatchChunkTransactions(perChunk bool) *DB {
+	return s.Set("gorm:batch_chunk_transactions", perChunk)
+}
+
+func (scope *Scope) batchChunkTransactions() bool {
+	perChunk, ok := scope.Get("gorm:batch_chunk_transactions")
+	return ok && perChunk.(bool)
+}
+
+// batchChunks split indexes of records into chunks of BatchSize
+func (scope *Scope) batchChunks(indexes []int) (chunks [][]int) {
+	size := len(indexes)
+	if batchSize, ok := scope.Get("gorm:batch_size"); ok && batchSize.(int) > 0 && batchSize.(int) < size {
+		size = batchSize.(int)
+	}
+	for start := 0; start < len(indexes); start += size {
+		end := start + size
+		if end > len(indexes) {
+			end = len(indexes)
+		}
+		chunks = append(chunks, indexes[start:end])
+	}
+	return
+}
+
+// batchCreate insert records with batch create callbacks chunk by chunk, set prepares the scope of each chunk
+func (s *DB) batchCreate(value interface{}, set func(scope *Scope) *Scope) *DB {
+	records := reflect.Indirect(reflect.ValueOf(value))
+	if records.Kind() != reflect.Slice {
+		return set(s.clone().NewScope(value)).callCallbacks(s.parent.callback.batch_creates).db
+	}
+
+	indexes := make([]int, records.Len())
+	for i := range indexes {
+		indexes[i] = i
+	}
+	var lastInsertId int64
+	db := s.writeBatch(value, indexes, func(tx *DB, chunk []int) (int64, int, error) {
+		chunkValue := records.Slice(chunk[0], chunk[len(chunk)-1]+1).Interface()
+		result := set(tx.clone().NewScope(chunkValue)).callCallbacks(s.parent.callback.batch_creates).db
+		lastInsertId = result.lastInsertId
+		// a chunk is inserted with one statement, the failed row is unknown
+		return result.RowsAffected, -1, result.Error
+	})
+	if db.Error == nil {
+		db.lastInsertId = lastInsertId
+	}
+	return db
+}
+
+// writeBatch write chunks of records in a transaction, or in one transaction per chunk with BatchChunkTransactions,
+// it joins the transaction if called on the db returned by Begin, write returns rows affected by the chunk, and the
+// index of the failed record, -1 if it is unknown, RowsAffected is the sum of all chunks
+func (s *DB) writeBatch(values interface{}, indexes []int, write func(tx *DB, chunk []int) (int64, int, error)) *DB {
+	scope := s.clone().NewScope(values)
+	_, inTransaction := s.db.(sqlTx)
+	perChunk := scope.batchChunkTransactions()
+
+	tx := scope.db
+	if !inTransaction && !perChunk {
+		if tx = s.Begin(); tx.Error != nil {
+			return tx
+		}
+	}
+
+	var rowsAffected int64
+	for i, chunk := range scope.batchChunks(indexes) {
+		chunkTx := tx
+		if !inTransaction && perChunk {
+			chunkTx = s.Begin()
+		}
+
+		affected, row, err := int64(0), -1, chunkTx.Error
+		if err == nil {
+			if affected, row, err = write(chunkTx, chunk); err != nil && !inTransaction {
+				chunkTx.Rollback()
+			} else if err == nil && !inTransaction && perChunk {
+				err = chunkTx.Commit().Error
+			}
+		}
+
+		if err != nil {
+			if perChunk {
+				scope.Err(&BatchError{Chunk: i, Row: row, RowsAffected: rowsAffected, Err: err})
+				scope.db.RowsAffected = rowsAffected
+			} else {
+				scope.Err(err)
+			}
+			return scope.db
+		}
+		rowsAffected += affected
+	}
+
+	if !inTransaction && !perChunk && scope.Err(tx.Commit().Error) != nil {
+		return scope.db
+	}
+	scope.db.RowsAffected = rowsAffected
+	return scope.db
+}
@@ -1,8 +1,11 @@
 package gorm_test
 
 import (
+	"os"
 	"testing"
 	"time"
+
+	"golib/gorm"
 )
 
 func TestBatchCreate(t *testing.T) {
@@ -14,3 +17,37 @@ func TestBatchCreate(t *testing.T) {
 		t.Error("batch create shoud be success")
 	}
 }
+
+func TestBatchUpsert(t *testing.T) {
+	if dialect := os.Getenv("GORM_DIALECT"); dialect == "mssql" || dialect == "foundation" {
+		return
+	}
+
+	DB.DropTableIfExists(&Subscription{})
+	DB.AutoMigrate(&Subscription{})
+	DB.Create(&Subscription{UserId: 2, Topic: "news", Level: 1})
+
+	subscriptions := []Subscription{{UserId: 2, Topic: "news", Level: 2}, {UserId: 2, Topic: "sports", Level: 2}}
+	ignore := gorm.OnConflict{Target: []string{"uix_subscriptions_user_topic"}, DoNothing: true}
+	if err := DB.BatchUpsert(subscriptions, ignore).Error; err != nil {
+		t.Errorf("No error should happen when batch upsert ignoring conflicts, but got %v", err)
+	}
+
+	var levels []int
+	DB.Model(&Subscription{}).Where("user_id = ?", 2).Order("topic").Pluck("level", &levels)
+	if len(levels) != 2 || levels[0] != 1 || levels[1] != 2 {
+		t.Errorf("conflicting records should be ignored, but got levels %v", levels)
+	}
+
+	subscriptions = []Subscription{{UserId: 2, Topic: "news", Level: 3}, {UserId: 2, Topic: "sports", Level: 3}}
+	update := gorm.OnConflict{Target: []string{"user_id", "topic"}, UpdateColumns: []string{"Level"}}
+	if err := DB.BatchUpsert(subscriptions, update).Error; err != nil {
+		t.Errorf("No error should happen when batch upsert updating conflicts, but got %v", err)
+	}
+
+	levels = nil
+	DB.Model(&Subscription{}).Where("user_id = ?", 2).Order("topic").Pluck("level", &levels)
+	if len(levels) != 2 || levels[0] != 3 || levels[1] != 3 {
+		t.Errorf("conflicting records should be updated, but got levels %v", levels)
+	}
+}
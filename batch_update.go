@@ -0,0 +1,105 @@
+package gorm
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// SortWritesByPrimaryKey write rows in the order of primary keys when BatchUpdate or saving has many associations,
+// so concurrent batch writers lock rows in the same order, which reduces deadlocks, e.g:
+//
+//	db.SortWritesByPrimaryKey().BatchUpdate(&accounts)
+func (s *DB) SortWritesByPrimaryKey() *DB {
+	return s.Set("gorm:sort_writes_by_primary_key", true)
+}
+
+// BatchUpdate save records of the slice in a transaction, records without primary key are created, it joins the
+// transaction if called on the db returned by Begin, records are split by BatchSize, RowsAffected is the sum of all records
+func (s *DB) BatchUpdate(values interface{}) *DB {
+	scope := s.clone().NewScope(values)
+	records := reflect.Indirect(reflect.ValueOf(values))
+	if records.Kind() != reflect.Slice {
+		scope.Err(errors.New("batch update requires a slice of records"))
+		return scope.db
+	}
+
+	return s.writeBatch(values, scope.writeOrder(records), func(tx *DB, chunk []int) (int64, int, error) {
+		var rowsAffected int64
+		for _, i := range chunk {
+			record := records.Index(i)
+			if record.Kind() != reflect.Ptr {
+				record = record.Addr()
+			}
+			result := tx.Save(record.Interface())
+			if result.Error != nil {
+				return rowsAffected, i, result.Error
+			}
+			rowsAffected += result.RowsAffected
+		}
+		return rowsAffected, -1, nil
+	})
+}
+
+// writeOrder get indexes of records in the order to write them, which is sorted by primary keys with
+// SortWritesByPrimaryKey, records without primary key are written after others in their original order
+func (scope *Scope) writeOrder(records reflect.Value) []int {
+	indexes := make([]int, records.Len())
+	for i := range indexes {
+		indexes[i] = i
+	}
+	if sortWrites, ok := scope.Get("gorm:sort_writes_by_primary_key"); !ok || !sortWrites.(bool) {
+		return indexes
+	}
+
+	keys := make([][]interface{}, records.Len())
+	for i := range indexes {
+		record := records.Index(i)
+		if record.Kind() != reflect.Ptr {
+			record = record.Addr()
+		}
+		recordScope := scope.New(record.Interface())
+		if recordScope.PrimaryKeyZero() {
+			continue
+		}
+		fields := recordScope.Fields()
+		for _, primaryField := range recordScope.GetModelStruct().PrimaryFields {
+			keys[i] = append(keys[i], fields[primaryField.DBName].Field.Interface())
+		}
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, b := keys[indexes[i]], keys[indexes[j]]
+		if a == nil || b == nil {
+			return a != nil
+		}
+		for k := range a {
+			if lessValue(a[k], b[k]) {
+				return true
+			} else if lessValue(b[k], a[k]) {
+				return false
+			}
+		}
+		return false
+	})
+	return indexes
+}
+
+// lessValue compare values of primary keys, numbers are compared by values, others by their strings
+func lessValue(a interface{}, b interface{}) bool {
+	va, vb := reflect.Indirect(reflect.ValueOf(a)), reflect.Indirect(reflect.ValueOf(b))
+	if va.Kind() == vb.Kind() {
+		switch va.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return va.Int() < vb.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return va.Uint() < vb.Uint()
+		case reflect.Float32, reflect.Float64:
+			return va.Float() < vb.Float()
+		case reflect.String:
+			return va.String() < vb.String()
+		}
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
@@ -0,0 +1,91 @@
+package gorm
+
+import (
+	"reflect"
+	"testing"
+)
+
+type sortedAccount struct {
+	Id      int64
+	Balance int
+}
+
+type sortedMembership struct {
+	GroupId string `gorm:"primary_key"`
+	UserId  int    `gorm:"primary_key"`
+}
+
+func TestWriteOrder(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	accounts := []sortedAccount{{Id: 30}, {}, {Id: 2}, {Id: 10}, {}}
+	if order := db.NewScope(&accounts).writeOrder(reflect.ValueOf(accounts)); !reflect.DeepEqual(order, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("records should be written in original order by default, but got %v", order)
+	}
+
+	sorted := db.SortWritesByPrimaryKey()
+	if order := sorted.NewScope(&accounts).writeOrder(reflect.ValueOf(accounts)); !reflect.DeepEqual(order, []int{2, 3, 0, 1, 4}) {
+		t.Errorf("records should be sorted by primary keys, new records are written last, but got %v", order)
+	}
+
+	memberships := []*sortedMembership{{"b", 1}, {"a", 2}, {"a", 1}}
+	if order := sorted.NewScope(&memberships).writeOrder(reflect.ValueOf(memberships)); !reflect.DeepEqual(order, []int{2, 1, 0}) {
+		t.Errorf("records should be sorted by composite primary keys, but got %v", order)
+	}
+}
+
+func TestBatchUpdateRequiresSlice(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	if err := db.BatchUpdate(&sortedAccount{Id: 1}).Error; err == nil {
+		t.Errorf("should get error when batch update a record")
+	}
+}
+
+type batchAccount struct {
+	Id   int64
+	Name string
+}
+
+func TestBatchTransactions(t *testing.T) {
+	db := newFakeDB("mysql", "transactions,identity")
+
+	accounts := []batchAccount{{Id: 1, Name: "a"}, {Id: 2, Name: "b"}, {Id: 3, Name: "fail"}}
+	fakeStatements = nil
+	if err := db.BatchSize(2).BatchUpdate(&accounts).Error; err == nil || reflect.TypeOf(err) == reflect.TypeOf(&BatchError{}) {
+		t.Errorf("batch update should fail with the error of the failed record, but got %v", err)
+	}
+	update := "UPDATE `batch_accounts` SET `name` = ?  WHERE (`id` = ?)"
+	expected := []string{"BEGIN", update, update, update, "ROLLBACK"}
+	if !reflect.DeepEqual(fakeStatements, expected) {
+		t.Errorf("all chunks should be written in one transaction, but got %v", fakeStatements)
+	}
+
+	fakeStatements = nil
+	result := db.BatchSize(2).BatchChunkTransactions(true).BatchUpdate(&accounts)
+	batchErr, ok := result.Error.(*BatchError)
+	if !ok || batchErr.Chunk != 1 || batchErr.Row != 2 || batchErr.RowsAffected != 2 || result.RowsAffected != 2 {
+		t.Errorf("failed chunk and row should be reported, but got %#v", result.Error)
+	}
+	expected = []string{"BEGIN", update, update, "COMMIT", "BEGIN", update, "ROLLBACK"}
+	if !reflect.DeepEqual(fakeStatements, expected) {
+		t.Errorf("each chunk should be written in its own transaction, but got %v", fakeStatements)
+	}
+
+	created := []batchAccount{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	fakeStatements = nil
+	if result := db.BatchSize(2).BatchCreate(created); result.Error != nil || result.RowsAffected != 2 {
+		t.Errorf("records should be created chunk by chunk, but got %v, %v", result.Error, result.RowsAffected)
+	}
+	expected = []string{"BEGIN", "INSERT INTO `batch_accounts` (`name`) VALUES (?),(?) ", "INSERT INTO `batch_accounts` (`name`) VALUES (?) ", "COMMIT"}
+	if !reflect.DeepEqual(fakeStatements, expected) {
+		t.Errorf("each chunk should be inserted with one statement in a transaction, but got %v", fakeStatements)
+	}
+
+	created[2].Name = "fail"
+	fakeStatements = nil
+	result = db.BatchSize(2).BatchChunkTransactions(true).BatchCreate(created)
+	if batchErr, ok := result.Error.(*BatchError); !ok || batchErr.Chunk != 1 || batchErr.Row != -1 {
+		t.Errorf("failed chunk of batch create should be reported without row, but got %#v", result.Error)
+	}
+}
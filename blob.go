@@ -0,0 +1,178 @@
+package gorm
+
+import (
+	"fmt"
+	"io"
+)
+
+// large binary columns are written and read in chunks, so the payload doesn't need to be buffered in memory
+const blobChunkSize = 1 << 20
+
+func (scope *Scope) blobField(column string) (*Field, error) {
+	field, ok := scope.FieldByName(column)
+	if !ok || !field.IsNormal {
+		return nil, fmt.Errorf("%v doesn't have column %v", scope.GetModelStruct().ModelType, column)
+	}
+	if scope.PrimaryKeyZero() {
+		return nil, fmt.Errorf("%v's primary key is blank", scope.GetModelStruct().ModelType)
+	}
+	if _, ok := ParseTagSetting(field.Tag)["LARGE_OBJECT"]; ok && !scope.Dialect().SupportLargeObject() {
+		return nil, LargeObjectNotSupported
+	}
+	return field, nil
+}
+
+func (scope *Scope) isLargeObject(field *StructField) bool {
+	_, ok := ParseTagSetting(field.Tag)["LARGE_OBJECT"]
+	return ok && scope.Dialect().SupportLargeObject()
+}
+
+// WriteBlob write r into the binary column of value in chunks in a transaction, e.g:
+//
+//	db.WriteBlob(&attachment, "content", file)
+//
+// for postgres, columns tagged with `large_object` keep the oid of a large object instead of the bytes, other columns
+// are appended in place chunk by chunk if the dialect supports it, otherwise BlobStreamNotSupported is returned
+func (s *DB) WriteBlob(value interface{}, column string, r io.Reader) *DB {
+	scope := s.clone().NewScope(value)
+	field, err := scope.blobField(column)
+	if scope.Err(err) != nil {
+		return scope.db
+	}
+
+	scope.Begin()
+	defer scope.CommitOrRollback()
+
+	if scope.isLargeObject(field.StructField) {
+		scope.writeLargeObject(field, r)
+	} else {
+		scope.writeBlob(field, r)
+	}
+	return scope.db
+}
+
+func (scope *Scope) writeBlob(field *Field, r io.Reader) {
+	column := scope.Quote(field.DBName)
+	if scope.Dialect().AppendBlobSql(column, "?") == "" {
+		scope.Err(BlobStreamNotSupported)
+		return
+	}
+
+	buf := make([]byte, blobChunkSize)
+	for offset := 0; ; {
+		n, err := io.ReadFull(r, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			scope.Err(err)
+			return
+		}
+		if n == 0 && offset > 0 {
+			return
+		}
+
+		chunkScope := scope.New(scope.Value)
+		value := chunkScope.AddToVars(buf[:n])
+		set := fmt.Sprintf("%v = %v", column, value)
+		if offset > 0 {
+			set = scope.Dialect().AppendBlobSql(column, value)
+		}
+		chunkScope.Raw(fmt.Sprintf("UPDATE %v SET %v WHERE %v", scope.QuotedTableName(), set, chunkScope.primaryCondition(chunkScope.AddToVars(scope.PrimaryKeyValue())))).Exec()
+		if scope.Err(chunkScope.db.Error) != nil || n < len(buf) {
+			return
+		}
+		offset += n
+	}
+}
+
+func (scope *Scope) writeLargeObject(field *Field, r io.Reader) {
+	oldOid := field.Field.Interface()
+
+	var oid int64
+	if scope.Err(scope.NewDB().Raw("SELECT lo_create(0)").Row().Scan(&oid)) != nil {
+		return
+	}
+
+	buf := make([]byte, blobChunkSize)
+	for offset := 0; ; {
+		n, err := io.ReadFull(r, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			scope.Err(err)
+			return
+		}
+		if n > 0 && scope.Err(scope.NewDB().Exec("SELECT lo_put(?, ?, ?)", oid, offset, buf[:n]).Error) != nil {
+			return
+		}
+		if n < len(buf) {
+			break
+		}
+		offset += n
+	}
+
+	updateScope := scope.New(scope.Value)
+	updateScope.Raw(fmt.Sprintf("UPDATE %v SET %v = %v WHERE %v", scope.QuotedTableName(), scope.Quote(field.DBName), updateScope.AddToVars(oid), updateScope.primaryCondition(updateScope.AddToVars(scope.PrimaryKeyValue())))).Exec()
+	if scope.Err(updateScope.db.Error) != nil {
+		return
+	}
+	if !field.IsBlank {
+		scope.Err(scope.NewDB().Exec("SELECT lo_unlink(?)", oldOid).Error)
+	}
+	scope.Err(field.Set(oid))
+}
+
+// OpenBlob return a reader of the binary column of value, it reads the column in chunks when needed, e.g:
+//
+//	r, err := db.OpenBlob(&attachment, "content")
+//	io.Copy(w, r)
+func (s *DB) OpenBlob(value interface{}, column string) (io.Reader, error) {
+	scope := s.clone().NewScope(value)
+	field, err := scope.blobField(column)
+	if err != nil {
+		return nil, err
+	}
+	return &blobReader{scope: scope, field: field}, nil
+}
+
+type blobReader struct {
+	scope  *Scope
+	field  *Field
+	offset int
+	chunk  []byte
+	eof    bool
+}
+
+func (r *blobReader) Read(p []byte) (int, error) {
+	if len(r.chunk) == 0 {
+		if r.eof {
+			return 0, io.EOF
+		}
+		if err := r.fetch(); err != nil {
+			return 0, err
+		}
+		if len(r.chunk) == 0 {
+			return 0, io.EOF
+		}
+	}
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
+
+func (r *blobReader) fetch() error {
+	scope := r.scope.New(r.scope.Value)
+	column := scope.Quote(r.field.DBName)
+
+	var value string
+	if scope.isLargeObject(r.field.StructField) {
+		value = fmt.Sprintf("lo_get(%v, %v, %v)", column, scope.AddToVars(r.offset), scope.AddToVars(blobChunkSize))
+	} else {
+		value = scope.Dialect().SubstringSql(column, scope.AddToVars(r.offset+1), scope.AddToVars(blobChunkSize))
+	}
+	scope.Raw(fmt.Sprintf("SELECT %v FROM %v WHERE %v", value, scope.QuotedTableName(), scope.primaryCondition(scope.AddToVars(r.scope.PrimaryKeyValue()))))
+
+	var chunk []byte
+	if err := scope.SqlDB().QueryRow(scope.Sql, scope.SqlVars...).Scan(&chunk); err != nil {
+		return err
+	}
+	r.chunk, r.offset = chunk, r.offset+len(chunk)
+	r.eof = len(chunk) < blobChunkSize
+	return nil
+}
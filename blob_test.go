@@ -0,0 +1,36 @@
+package gorm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type streamedAttachment struct {
+	Id      int64
+	Content []byte
+}
+
+func TestWriteBlob(t *testing.T) {
+	content := bytes.NewReader(make([]byte, blobChunkSize*3/2))
+
+	db := newFakeDB("mssql", "transactions")
+	fakeStatements = nil
+	if err := db.WriteBlob(&streamedAttachment{Id: 1}, "Content", content).Error; err != nil {
+		t.Fatalf("blob should be written in chunks, but got %v", err)
+	}
+	if len(fakeStatements) != 4 || !strings.Contains(fakeStatements[1], `SET "content" = @p1 WHERE`) || !strings.Contains(fakeStatements[2], `SET "content".WRITE(@p1, NULL, NULL) WHERE`) {
+		t.Errorf("later chunks should be appended in place, but got %v", fakeStatements)
+	}
+
+	db = newFakeDB("mysql", "transactions")
+	fakeStatements = nil
+	if err := db.WriteBlob(&streamedAttachment{Id: 1}, "Content", content).Error; err != BlobStreamNotSupported {
+		t.Errorf("should return BlobStreamNotSupported if the dialect can't append blobs, but got %v", err)
+	}
+	for _, statement := range fakeStatements {
+		if strings.HasPrefix(statement, "UPDATE") {
+			t.Errorf("blob shouldn't be written if the dialect can't append it, but got %v", fakeStatements)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package gorm
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 )
 
 type callback struct {
@@ -25,10 +27,25 @@ type callbackProcessor struct {
 	callback  *callback
 }
 
-func (c *callback) addProcessor(typ string) *callbackProcessor {
-	cp := &callbackProcessor{typ: typ, callback: c}
+// newProcessor get a processor of the type, it is added into callbacks when registering, replacing or removing,
+// so getting callbacks with it leaves callbacks unchanged
+func (c *callback) newProcessor(typ string) *callbackProcessor {
+	return &callbackProcessor{typ: typ, callback: c}
+}
+
+func (c *callback) addProcessor(cp *callbackProcessor) {
 	c.processors = append(c.processors, cp)
-	return cp
+	c.sort()
+}
+
+// registered check a callback of the type is registered with name
+func (c *callback) registered(typ string, name string) bool {
+	for _, processor := range c.processors {
+		if processor.typ == typ && processor.name == name {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *callback) clone() *callback {
@@ -38,32 +55,33 @@ func (c *callback) clone() *callback {
 		updates:       c.updates,
 		deletes:       c.deletes,
 		queries:       c.queries,
+		rowQueries:    c.rowQueries,
 		processors:    c.processors,
 	}
 }
 
 func (c *callback) Create() *callbackProcessor {
-	return c.addProcessor("create")
+	return c.newProcessor("create")
 }
 
 func (c *callback) BatchCreate() *callbackProcessor {
-	return c.addProcessor("batch_create")
+	return c.newProcessor("batch_create")
 }
 
 func (c *callback) Update() *callbackProcessor {
-	return c.addProcessor("update")
+	return c.newProcessor("update")
 }
 
 func (c *callback) Delete() *callbackProcessor {
-	return c.addProcessor("delete")
+	return c.newProcessor("delete")
 }
 
 func (c *callback) Query() *callbackProcessor {
-	return c.addProcessor("query")
+	return c.newProcessor("query")
 }
 
 func (c *callback) RowQuery() *callbackProcessor {
-	return c.addProcessor("row_query")
+	return c.newProcessor("row_query")
 }
 
 func (cp *callbackProcessor) Before(name string) *callbackProcessor {
@@ -76,17 +94,26 @@ func (cp *callbackProcessor) After(name string) *callbackProcessor {
 	return cp
 }
 
-func (cp *callbackProcessor) Register(name string, fc func(scope *Scope)) {
+// Register register the callback, it returns an error if the callback is ordered before or after an unknown
+// callback, the callback is still registered and ordered once the unknown callback is registered
+func (cp *callbackProcessor) Register(name string, fc func(scope *Scope)) error {
 	cp.name = name
 	cp.processor = &fc
-	cp.callback.sort()
+	cp.callback.addProcessor(cp)
+
+	for _, anchor := range []string{cp.before, cp.after} {
+		if anchor != "" && !cp.callback.registered(cp.typ, anchor) {
+			return fmt.Errorf("%v callback `%v` is ordered with unknown callback `%v`", cp.typ, cp.name, anchor)
+		}
+	}
+	return nil
 }
 
 func (cp *callbackProcessor) Remove(name string) {
 	fmt.Printf("[info] removing callback `%v` from %v\n", name, fileWithLineNum())
 	cp.name = name
 	cp.remove = true
-	cp.callback.sort()
+	cp.callback.addProcessor(cp)
 }
 
 func (cp *callbackProcessor) Replace(name string, fc func(scope *Scope)) {
@@ -94,7 +121,76 @@ func (cp *callbackProcessor) Replace(name string, fc func(scope *Scope)) {
 	cp.name = name
 	cp.processor = &fc
 	cp.replace = true
-	cp.callback.sort()
+	cp.callback.addProcessor(cp)
+}
+
+// Get get the callback function registered with name, return nil if not found
+func (cp *callbackProcessor) Get(name string) func(scope *Scope) {
+	for _, processor := range cp.callback.processorsOf(cp.typ) {
+		if processor.name == name {
+			return *processor.processor
+		}
+	}
+	return nil
+}
+
+// Names get names of callbacks in the order they will be executed, which could help debugging
+//
+//	db.Callback().Create().Names()
+func (cp *callbackProcessor) Names() []string {
+	var names []string
+	for _, processor := range cp.callback.processorsOf(cp.typ) {
+		names = append(names, processor.name)
+	}
+	return names
+}
+
+// nameOf get the name of a registered callback function, it is blank if not found
+func (c *callback) nameOf(f *func(scope *Scope)) string {
+	for _, processor := range c.processors {
+		if processor.processor == f {
+			return processor.name
+		}
+	}
+	return ""
+}
+
+// processorsOf get sorted processors of the type
+func (c *callback) processorsOf(typ string) []*callbackProcessor {
+	var processors []*callbackProcessor
+	for _, processor := range c.processors {
+		if processor.typ == typ {
+			processors = append(processors, processor)
+		}
+	}
+	return sortedProcessors(processors)
+}
+
+// Validate check all callbacks are ordered with registered callbacks, and replaced or removed callbacks exist,
+// it is better to validate after registering callbacks as unknown callbacks are ignored when ordering
+func (c *callback) Validate() error {
+	var errs []string
+	registered := map[string]bool{}
+	for _, processor := range c.processors {
+		key := processor.typ + ":" + processor.name
+		if (processor.replace || processor.remove) && !registered[key] {
+			errs = append(errs, fmt.Sprintf("%v callback `%v` is replaced or removed before registered", processor.typ, processor.name))
+		}
+		registered[key] = true
+	}
+
+	for _, processor := range c.processors {
+		for _, anchor := range []string{processor.before, processor.after} {
+			if anchor != "" && !registered[processor.typ+":"+anchor] {
+				errs = append(errs, fmt.Sprintf("%v callback `%v` is ordered with unknown callback `%v`", processor.typ, processor.name, anchor))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
 }
 
 func getRIndex(strs []string, str string) int {
@@ -107,6 +203,14 @@ func getRIndex(strs []string, str string) int {
 }
 
 func sortProcessors(cps []*callbackProcessor) []*func(scope *Scope) {
+	var funcs = []*func(scope *Scope){}
+	for _, cp := range sortedProcessors(cps) {
+		funcs = append(funcs, cp.processor)
+	}
+	return funcs
+}
+
+func sortedProcessors(cps []*callbackProcessor) []*callbackProcessor {
 	var sortCallbackProcessor func(c *callbackProcessor)
 	var names, sortedNames = []string{}, []string{}
 
@@ -158,24 +262,23 @@ func sortProcessors(cps []*callbackProcessor) []*func(scope *Scope) {
 		sortCallbackProcessor(cp)
 	}
 
-	var funcs = []*func(scope *Scope){}
-	var sortedFuncs = []*func(scope *Scope){}
+	var processors, sortedProcessors []*callbackProcessor
 	for _, name := range sortedNames {
 		index := getRIndex(names, name)
 		if !cps[index].remove {
-			sortedFuncs = append(sortedFuncs, cps[index].processor)
+			sortedProcessors = append(sortedProcessors, cps[index])
 		}
 	}
 
 	for _, cp := range cps {
 		if sindex := getRIndex(sortedNames, cp.name); sindex == -1 {
 			if !cp.remove {
-				funcs = append(funcs, cp.processor)
+				processors = append(processors, cp)
 			}
 		}
 	}
 
-	return append(sortedFuncs, funcs...)
+	return append(sortedProcessors, processors...)
 }
 
 func (c *callback) sort() {
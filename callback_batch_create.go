@@ -37,7 +37,7 @@ func BatchCreate(scope *Scope) {
 								if !field.IsBlank || !field.HasDefaultValue {
 									columns = append(columns, scope.Quote(field.DBName))
 									travesalNames = append(travesalNames, field.DBName)
-									sqls = append(sqls, scope.AddToVars(field.Field.Interface()))
+									sqls = append(sqls, scope.AddToVars(scope.fieldVar(field)))
 								}
 							}
 						} else if relationship := field.Relationship; relationship != nil && relationship.Kind == "belongs_to" {
@@ -61,7 +61,7 @@ func BatchCreate(scope *Scope) {
 						if field.IsNormal {
 							if !field.IsPrimaryKey || (field.IsPrimaryKey && !field.IsBlank) {
 								if !field.IsBlank || !field.HasDefaultValue {
-									sqls = append(sqls, scope.AddToVars(field.Field.Interface()))
+									sqls = append(sqls, scope.AddToVars(scope.fieldVar(field)))
 								}
 							}
 						} else if relationship := field.Relationship; relationship != nil && relationship.Kind == "belongs_to" {
@@ -84,21 +84,33 @@ func BatchCreate(scope *Scope) {
 		var BatchCreate_sql string = "INSERT INTO"
 		var extraOption string
 
-		if insert_ignore, ok := scope.InstanceGet("gorm:insert_ignore"); ok {
-			if insert_ignore.(bool) {
-				BatchCreate_sql = "INSERT IGNORE INTO"
-			}
+		if insert_ignore, _ := scope.InstanceGetBool("gorm:insert_ignore"); insert_ignore {
+			BatchCreate_sql = "INSERT IGNORE INTO"
 		}
 		if str, ok := scope.Get("gorm:insert_option"); ok {
 			extraOption = fmt.Sprint(str)
 		}
+		onConflict, upsert := scope.InstanceGet("gorm:on_conflict")
+		if upsert {
+			extraOption = strings.TrimSpace(extraOption + " " + scope.onConflictSql(onConflict.(OnConflict), batchColumns))
+			if scope.HasError() {
+				return
+			}
+		}
+
+		returning := scope.Dialect().ReturningStr(scope.TableName(), returningKey)
+		if upsert {
+			// conflicting rows might be ignored, so primary keys can't be matched with returned rows
+			returning = ""
+		}
 
 		if len(batchColumns) == 0 {
-			scope.Raw(fmt.Sprintf("%s %v DEFAULT VALUES%v%v",
+			scope.Raw(fmt.Sprintf("%s %v DEFAULT VALUES%v%v%v",
 				BatchCreate_sql,
 				scope.QuotedTableName(),
 				addExtraSpaceIfExist(extraOption),
-				addExtraSpaceIfExist(scope.Dialect().ReturningStr(scope.TableName(), returningKey)),
+				addExtraSpaceIfExist(strings.TrimSpace(scope.clausesSql(ClauseEnd))),
+				addExtraSpaceIfExist(returning),
 			))
 		} else {
 			rows := []string{}
@@ -107,30 +119,34 @@ func BatchCreate(scope *Scope) {
 				rows = append(rows, tmpStr)
 			}
 			scope.Raw(fmt.Sprintf(
-				"%s %v (%v) VALUES %v %v%v",
+				"%s %v (%v) VALUES %v %v%v%v",
 				BatchCreate_sql,
 				scope.QuotedTableName(),
 				strings.Join(batchColumns, ","),
 				strings.Join(rows, ","),
 				addExtraSpaceIfExist(extraOption),
-				addExtraSpaceIfExist(scope.Dialect().ReturningStr(scope.TableName(), returningKey)),
+				addExtraSpaceIfExist(strings.TrimSpace(scope.clausesSql(ClauseEnd))),
+				addExtraSpaceIfExist(returning),
 			))
 		}
 
 		// execute BatchCreate sql
-		if scope.Dialect().SupportLastInsertId() {
+		if upsert {
+			if result, err := scope.SqlDB().Exec(scope.Sql, scope.SqlVars...); scope.Err(err) == nil {
+				scope.db.RowsAffected, _ = result.RowsAffected()
+			}
+		} else if scope.Dialect().SupportLastInsertId() {
 			if result, err := scope.SqlDB().Exec(scope.Sql, scope.SqlVars...); scope.Err(err) == nil {
 				id, err := result.LastInsertId()
+				// the last insert id of a multi-row insert can't be mapped to records reliably, only report it
 				if scope.Err(err) == nil && id != 0 {
+					scope.db.lastInsertId = id
 					scope.db.RowsAffected, _ = result.RowsAffected()
-					if autoIncrementField := scope.AutoIncrementField(); autoIncrementField != nil {
-						scope.Err(scope.SetColumn(autoIncrementField, id))
-					}
 				}
 			}
 		} else {
 			if primaryField == nil {
-				if results, err := scope.SqlDB().Exec(scope.Sql, scope.SqlVars...); err != nil {
+				if results, err := scope.SqlDB().Exec(scope.Sql, scope.SqlVars...); scope.Err(err) == nil {
 					scope.db.RowsAffected, _ = results.RowsAffected()
 				}
 			} else if scope.Err(scope.SqlDB().QueryRow(scope.Sql, scope.SqlVars...).Scan(primaryField.Field.Addr().Interface())) == nil {
@@ -146,9 +162,12 @@ func AfterBatchCreate(scope *Scope) {
 }
 
 func init() {
+	DefaultCallback.BatchCreate().Register("gorm:check_view", CheckView)
 	DefaultCallback.BatchCreate().Register("gorm:before_create", BeforeBatchCreate)
 	DefaultCallback.BatchCreate().Register("gorm:save_before_associations", SaveBeforeAssociations)
 	DefaultCallback.BatchCreate().Register("gorm:update_time_stamp_when_create", UpdateTimeStampWhenCreate)
+	DefaultCallback.BatchCreate().Register("gorm:assign_uuid", AssignUUID)
+	DefaultCallback.BatchCreate().Register("gorm:assign_sequence", AssignSequence)
 	DefaultCallback.BatchCreate().Register("gorm:create", BatchCreate)
 	DefaultCallback.BatchCreate().Register("gorm:save_after_associations", SaveAfterAssociations)
 }
@@ -1,10 +1,26 @@
 package gorm
 
 import (
+	"database/sql"
 	"fmt"
+	"math"
+	"reflect"
 	"strings"
 )
 
+// SkipIdentityFetch don't fetch primary keys generated by the database after creating, neither LastInsertId nor RETURNING,
+// e.g. when restoring rows with their own primary keys, or when the table has no identity column
+//
+//	db.SkipIdentityFetch(true).Create(&User{Id: 111, Name: "jinzhu"})
+func (s *DB) SkipIdentityFetch(skip bool) *DB {
+	return s.Set("gorm:skip_identity_fetch", skip)
+}
+
+func (scope *Scope) skipIdentityFetch() bool {
+	skip, ok := scope.Get("gorm:skip_identity_fetch")
+	return ok && skip.(bool)
+}
+
 func BeforeCreate(scope *Scope) {
 	scope.CallMethodWithErrorCheck("BeforeSave")
 	scope.CallMethodWithErrorCheck("BeforeCreate")
@@ -18,6 +34,28 @@ func UpdateTimeStampWhenCreate(scope *Scope) {
 	}
 }
 
+// AssignUUID generate UUIDs for blank fields tagged with `uuid`, like UUIDModel's ID
+func AssignUUID(scope *Scope) {
+	if scope.HasError() {
+		return
+	}
+
+	batchFields := []map[string]*Field{}
+	if scope.IndirectValue().Kind() == reflect.Slice {
+		batchFields = scope.BatchFields()
+	} else {
+		batchFields = append(batchFields, scope.Fields())
+	}
+
+	for _, fields := range batchFields {
+		for _, field := range fields {
+			if _, ok := ParseTagSetting(field.Tag)["UUID"]; ok && field.IsBlank && field.Field.IsValid() {
+				scope.Err(field.Set(NewUUID()))
+			}
+		}
+	}
+}
+
 func Create(scope *Scope) {
 	defer scope.Trace(NowFunc())
 
@@ -31,7 +69,7 @@ func Create(scope *Scope) {
 					if !field.IsPrimaryKey || (field.IsPrimaryKey && !field.IsBlank) {
 						if !field.IsBlank || !field.HasDefaultValue {
 							columns = append(columns, scope.Quote(field.DBName))
-							sqls = append(sqls, scope.AddToVars(field.Field.Interface()))
+							sqls = append(sqls, scope.AddToVars(scope.fieldVar(field)))
 						}
 					}
 				} else if relationship := field.Relationship; relationship != nil && relationship.Kind == "belongs_to" {
@@ -43,64 +81,130 @@ func Create(scope *Scope) {
 			}
 		}
 
+		// auto increment keys set before creating are inserted as they are, and never overwritten by the last insert id,
+		// sequences of Postgres are advanced past them after inserting
+		var presetId *Field
+		if autoIncrementField := scope.AutoIncrementField(); autoIncrementField != nil && !autoIncrementField.IsBlank {
+			presetId = autoIncrementField
+		}
+
 		returningKey := "*"
 		primaryField := scope.PrimaryField()
+		if scope.skipIdentityFetch() {
+			primaryField = nil
+		}
+		var returningValues []interface{}
 		if primaryField != nil {
 			returningKey = scope.Quote(primaryField.DBName)
+			returningValues = append(returningValues, primaryField.Field.Addr().Interface())
+			// re-read server maintained timestamps if RETURNING is supported
+			for _, field := range scope.serverTimestampFields() {
+				returningKey += ", " + scope.Quote(field.DBName)
+				returningValues = append(returningValues, field.Field.Addr().Interface())
+			}
 		}
 
 		var create_sql string = "INSERT INTO"
 		var extraOption string
 
-		if insert_ignore, ok := scope.InstanceGet("gorm:insert_ignore"); ok {
-			if insert_ignore.(bool) {
-				create_sql = "INSERT IGNORE INTO"
-			}
+		if insert_ignore, _ := scope.InstanceGetBool("gorm:insert_ignore"); insert_ignore {
+			create_sql = "INSERT IGNORE INTO"
 		}
 		if str, ok := scope.Get("gorm:insert_option"); ok {
 			extraOption = fmt.Sprint(str)
 		}
+		if onConflict, ok := scope.InstanceGet("gorm:on_conflict"); ok {
+			extraOption = strings.TrimSpace(extraOption + " " + scope.onConflictSql(onConflict.(OnConflict), columns))
+			if scope.HasError() {
+				return
+			}
+		}
+
+		returning := scope.Dialect().ReturningStr(scope.TableName(), returningKey)
+		if scope.skipIdentityFetch() {
+			returning = ""
+		}
 
 		if len(columns) == 0 {
-			scope.Raw(fmt.Sprintf("%s %v DEFAULT VALUES%v%v",
+			scope.Raw(fmt.Sprintf("%s %v DEFAULT VALUES%v%v%v",
 				create_sql,
 				scope.QuotedTableName(),
 				addExtraSpaceIfExist(extraOption),
-				addExtraSpaceIfExist(scope.Dialect().ReturningStr(scope.TableName(), returningKey)),
+				addExtraSpaceIfExist(strings.TrimSpace(scope.clausesSql(ClauseEnd))),
+				addExtraSpaceIfExist(returning),
 			))
 		} else {
 			scope.Raw(fmt.Sprintf(
-				"%s %v (%v) VALUES (%v)%v%v",
+				"%s %v (%v) VALUES (%v)%v%v%v",
 				create_sql,
 				scope.QuotedTableName(),
 				strings.Join(columns, ","),
 				strings.Join(sqls, ","),
 				addExtraSpaceIfExist(extraOption),
-				addExtraSpaceIfExist(scope.Dialect().ReturningStr(scope.TableName(), returningKey)),
+				addExtraSpaceIfExist(strings.TrimSpace(scope.clausesSql(ClauseEnd))),
+				addExtraSpaceIfExist(returning),
 			))
 		}
 
 		// execute create sql
 		if scope.Dialect().SupportLastInsertId() {
 			if result, err := scope.SqlDB().Exec(scope.Sql, scope.SqlVars...); scope.Err(err) == nil {
-				id, err := result.LastInsertId()
-				if scope.Err(err) == nil && id != 0 {
-					scope.db.RowsAffected, _ = result.RowsAffected()
-					if autoIncrementField := scope.AutoIncrementField(); autoIncrementField != nil {
-						scope.Err(scope.SetColumn(autoIncrementField, id))
+				scope.db.RowsAffected, _ = result.RowsAffected()
+				switch {
+				case scope.skipIdentityFetch() || scope.db.RowsAffected == 0 || scope.upsertsUpdated():
+					// the last insert id is stale if an ignored duplicate inserted nothing, or the conflicting record is updated
+				case presetId != nil:
+					if id, ok := presetIdValue(presetId); ok {
+						scope.db.lastInsertId = id
+					}
+				default:
+					if id, err := result.LastInsertId(); scope.Err(err) == nil && id != 0 {
+						scope.db.lastInsertId = id
+						if autoIncrementField := scope.AutoIncrementField(); autoIncrementField != nil {
+							scope.Err(scope.SetColumn(autoIncrementField, id))
+						}
 					}
 				}
 			}
 		} else {
 			if primaryField == nil {
-				if results, err := scope.SqlDB().Exec(scope.Sql, scope.SqlVars...); err != nil {
+				if results, err := scope.SqlDB().Exec(scope.Sql, scope.SqlVars...); scope.Err(err) == nil {
 					scope.db.RowsAffected, _ = results.RowsAffected()
 				}
-			} else if scope.Err(scope.SqlDB().QueryRow(scope.Sql, scope.SqlVars...).Scan(primaryField.Field.Addr().Interface())) == nil {
+			} else if err := scope.SqlDB().QueryRow(scope.Sql, scope.SqlVars...).Scan(returningValues...); err == sql.ErrNoRows && scope.ignoresDuplicate() {
+				scope.db.RowsAffected = 0
+			} else if scope.Err(err) == nil {
 				scope.db.RowsAffected = 1
 			}
+
+			if id, ok := presetIdValue(presetId); ok && !scope.HasError() && scope.db.RowsAffected > 0 {
+				if sql := scope.Dialect().SyncIdentitySql(scope.QuotedTableName(), presetId.DBName); sql != "" {
+					scope.Err(scope.NewDB().Exec(sql, id, id).Error)
+				}
+			}
+		}
+
+		// nothing is created for an ignored duplicate, associations and callbacks after creating are skipped
+		if !scope.HasError() && scope.db.RowsAffected == 0 && scope.ignoresDuplicate() {
+			scope.SkipLeft()
+		}
+	}
+}
+
+// presetIdValue get the auto increment key set before creating as int64, unsigned keys beyond int64 are skipped
+func presetIdValue(presetId *Field) (int64, bool) {
+	if presetId == nil {
+		return 0, false
+	}
+	switch value := presetId.Field; value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value.Uint() <= math.MaxInt64 {
+			return int64(value.Uint()), true
 		}
 	}
+	return 0, false
 }
 
 func AfterCreate(scope *Scope) {
@@ -109,9 +213,16 @@ func AfterCreate(scope *Scope) {
 }
 
 func init() {
+	DefaultCallback.Create().Register("gorm:begin_transaction", BeginTransaction)
+	DefaultCallback.Create().Register("gorm:check_view", CheckView)
 	DefaultCallback.Create().Register("gorm:before_create", BeforeCreate)
 	DefaultCallback.Create().Register("gorm:save_before_associations", SaveBeforeAssociations)
 	DefaultCallback.Create().Register("gorm:update_time_stamp_when_create", UpdateTimeStampWhenCreate)
+	DefaultCallback.Create().Register("gorm:assign_uuid", AssignUUID)
+	DefaultCallback.Create().Register("gorm:assign_sequence", AssignSequence)
 	DefaultCallback.Create().Register("gorm:create", Create)
+	DefaultCallback.Create().Register("gorm:update_tree_path", UpdateTreePath)
 	DefaultCallback.Create().Register("gorm:save_after_associations", SaveAfterAssociations)
+	DefaultCallback.Create().Register("gorm:touch_associations", TouchAssociations)
+	DefaultCallback.Create().Register("gorm:commit_or_rollback_transaction", CommitOrRollbackTransaction)
 }
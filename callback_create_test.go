@@ -0,0 +1,68 @@
+package gorm
+
+import (
+	"database/sql/driver"
+	"math"
+	"strings"
+	"testing"
+)
+
+type restoredUser struct {
+	Id   int64
+	Name string
+}
+
+func TestCreateWithPresetPrimaryKey(t *testing.T) {
+	db := newFakeDB("mysql", "identity")
+
+	user := restoredUser{Name: "generated"}
+	if result := db.Create(&user).Result(); user.Id != 99 || result.LastInsertId != 99 {
+		t.Errorf("generated id should be fetched, but got %v, %v", user.Id, result.LastInsertId)
+	}
+
+	fakeStatements = nil
+	user = restoredUser{Id: 7, Name: "restored"}
+	if result := db.Create(&user).Result(); user.Id != 7 || result.LastInsertId != 7 {
+		t.Errorf("preset id should be kept and reported, but got %v, %v", user.Id, result.LastInsertId)
+	}
+	if len(fakeStatements) != 1 || !strings.Contains(fakeStatements[0], "`id`") {
+		t.Errorf("preset id should be inserted, but got %v", fakeStatements)
+	}
+
+	user = restoredUser{Name: "skipped"}
+	if result := db.SkipIdentityFetch(true).Create(&user).Result(); user.Id != 0 || result.LastInsertId != 0 || result.RowsAffected != 1 {
+		t.Errorf("id should not be fetched if skipped, but got %v, %v", user.Id, result.LastInsertId)
+	}
+}
+
+type restoredAccount struct {
+	Id   uint64
+	Name string
+}
+
+func TestCreateWithPresetPrimaryKeyOfPostgres(t *testing.T) {
+	db := newFakeDB("postgres", "identity")
+	fakeResults = map[string][][]driver.Value{`INSERT INTO "restored_users"`: {{int64(7)}}}
+	defer func() { fakeResults = map[string][][]driver.Value{} }()
+
+	fakeStatements = nil
+	if err := db.Create(&restoredUser{Id: 7, Name: "restored"}).Error; err != nil {
+		t.Fatalf("failed to create with preset id, got %v", err)
+	}
+	if len(fakeStatements) != 1 || !strings.HasPrefix(fakeStatements[0], `SELECT setval(pg_get_serial_sequence('"restored_users"', 'id'), $1)`) {
+		t.Errorf("sequence should be advanced past the preset id, but got %v", fakeStatements)
+	}
+
+	fakeStatements = nil
+	if err := db.SkipIdentityFetch(true).Create(&restoredAccount{Id: math.MaxUint64, Name: "restored"}).Error; err != nil {
+		t.Fatalf("failed to create with preset id, got %v", err)
+	}
+	if len(fakeStatements) != 1 || strings.Contains(fakeStatements[0], "setval") {
+		t.Errorf("unsigned ids beyond int64 shouldn't be converted, but got %v", fakeStatements)
+	}
+
+	mysqlDB := newFakeDB("mysql", "identity")
+	if result := mysqlDB.Create(&restoredAccount{Id: math.MaxUint64, Name: "restored"}).Result(); result.Error != nil || result.LastInsertId != 0 {
+		t.Errorf("unsigned ids beyond int64 shouldn't be reported as wrapped, but got %v, %v", result.Error, result.LastInsertId)
+	}
+}
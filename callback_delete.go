@@ -1,6 +1,11 @@
 package gorm
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
 
 func BeforeDelete(scope *Scope) {
 	scope.CallMethodWithErrorCheck("BeforeDelete")
@@ -8,10 +13,19 @@ func BeforeDelete(scope *Scope) {
 
 func Delete(scope *Scope) {
 	if !scope.HasError() {
-		if !scope.Search.Unscoped && scope.HasColumn("DeletedAt") {
+		if using := scope.Search.deleteUsing; using != nil {
+			usingTable, on := scope.quoteTable(using["table"].(string)), using["on"].(string)
+			if field, ok := scope.softDeleteField(); ok && !scope.Search.Unscoped {
+				sets := []string{fmt.Sprintf("%v=%v", scope.Quote(field.DBName), scope.AddToVars(NowFunc()))}
+				scope.Raw(scope.Dialect().UpdateFromSql(scope.QuotedTableName(), sets, usingTable, on, scope.whereSql()))
+			} else {
+				scope.Raw(scope.Dialect().DeleteUsingSql(scope.QuotedTableName(), usingTable, on, scope.whereSql()))
+			}
+		} else if field, ok := scope.softDeleteField(); ok && !scope.Search.Unscoped {
 			scope.Raw(
-				fmt.Sprintf("UPDATE %v SET deleted_at=%v %v",
+				fmt.Sprintf("UPDATE %v SET %v=%v %v",
 					scope.QuotedTableName(),
+					scope.Quote(field.DBName),
 					scope.AddToVars(NowFunc()),
 					scope.CombinedConditionSql(),
 				))
@@ -23,12 +37,105 @@ func Delete(scope *Scope) {
 	}
 }
 
+// DeleteAssociations delete has one and has many associations, and join rows of many to many associations, selected
+// with Select before the record in a transaction, nested associations are selected like `Orders.Items` and deleted first,
+// records of many to many associations are kept, so selecting their nested associations is an error. Associations are
+// deleted in bulk, delete hooks of them are called with a blank record instead of the deleted ones
+func DeleteAssociations(scope *Scope) {
+	if scope.HasError() || len(scope.SelectAttrs()) == 0 {
+		return
+	}
+	scope.Begin()
+	scope.deleteAssociations(scope.SelectAttrs())
+}
+
+// deleteAssociations delete selected associations of records of the scope bottom-up
+func (scope *Scope) deleteAssociations(attrs []string) {
+	selected := map[string][]string{}
+	for _, attr := range attrs {
+		names := strings.SplitN(attr, ".", 2)
+		nested := selected[names[0]]
+		if len(names) > 1 {
+			nested = append(nested, names[1])
+		}
+		selected[names[0]] = nested
+	}
+
+	primaryFields := scope.GetModelStruct().PrimaryFields
+	for _, field := range scope.GetModelStruct().StructFields {
+		nested, ok := selected[field.Name]
+		relationship := field.Relationship
+		if !ok || relationship == nil || (relationship.Kind != "has_one" && relationship.Kind != "has_many" && relationship.Kind != "many_to_many") {
+			continue
+		}
+		if len(primaryFields) == 0 {
+			scope.Err(errors.New("deleting associations requires primary keys of records"))
+			return
+		}
+		primaryKeys := scope.getColumnAsArray(primaryFields[0].Name)
+		for _, primaryKey := range primaryKeys {
+			if isBlank(reflect.ValueOf(primaryKey)) {
+				scope.Err(errors.New("deleting associations requires primary keys of records"))
+				return
+			}
+		}
+		if len(primaryKeys) == 0 {
+			return
+		}
+
+		db := scope.NewDB()
+		if scope.Search.Unscoped {
+			db = db.Unscoped()
+		}
+		query := db.Where(fmt.Sprintf("%v IN (?)", scope.Quote(relationship.ForeignDBName)), primaryKeys)
+		if relationship.Kind == "many_to_many" {
+			if len(nested) > 0 {
+				scope.Err(fmt.Errorf("nested associations %v of many to many association %v can't be deleted, only its join rows are", strings.Join(nested, ", "), field.Name))
+				return
+			}
+			scope.Err(relationship.JoinTableHandler.Delete(query, relationship))
+			continue
+		}
+		if relationship.PolymorphicType != "" {
+			query = query.Where(fmt.Sprintf("%v = ?", scope.Quote(relationship.PolymorphicDBName)), scope.TableName())
+		}
+
+		if len(nested) > 0 {
+			records := makeSlice(field.Struct.Type)
+			if scope.Err(query.Find(records).Error) != nil {
+				return
+			}
+			if reflect.Indirect(reflect.ValueOf(records)).Len() > 0 {
+				recordsScope := scope.New(records)
+				recordsScope.Search.Unscoped = scope.Search.Unscoped
+				if recordsScope.deleteAssociations(nested); scope.Err(recordsScope.db.Error) != nil {
+					return
+				}
+			}
+		}
+
+		elemType := field.Struct.Type
+		for elemType.Kind() == reflect.Slice || elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+		if scope.Err(query.Delete(reflect.New(elemType).Interface()).Error) != nil {
+			return
+		}
+	}
+}
+
 func AfterDelete(scope *Scope) {
 	scope.CallMethodWithErrorCheck("AfterDelete")
 }
 
 func init() {
+	DefaultCallback.Delete().Register("gorm:begin_transaction", BeginTransaction)
+	DefaultCallback.Delete().Register("gorm:check_view", CheckView)
 	DefaultCallback.Delete().Register("gorm:before_delete", BeforeDelete)
+	DefaultCallback.Delete().Register("gorm:save_history", SaveHistoryWhenDelete)
+	DefaultCallback.Delete().Register("gorm:delete_associations", DeleteAssociations)
 	DefaultCallback.Delete().Register("gorm:delete", Delete)
+	DefaultCallback.Delete().Register("gorm:touch_associations", TouchAssociations)
 	DefaultCallback.Delete().Register("gorm:after_delete", AfterDelete)
+	DefaultCallback.Delete().Register("gorm:commit_or_rollback_transaction", CommitOrRollbackTransaction)
 }
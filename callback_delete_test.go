@@ -0,0 +1,79 @@
+package gorm
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type deletedItem struct {
+	Id             int64
+	DeletedOrderId int64
+}
+
+type deletedOrder struct {
+	Id            int64
+	DeletedUserId int64
+	Items         []deletedItem
+}
+
+type deletedUser struct {
+	Id     int64
+	Orders []deletedOrder
+	Tags   []deletedItem `gorm:"many2many:deleted_user_tags"`
+}
+
+func TestDeleteAssociations(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	fakeStatements = nil
+	if err := db.Select("Orders", "Orders.Items", "Tags").Delete(&deletedUser{Id: 1}).Error; err != nil {
+		t.Fatalf("failed to delete with associations, got %v", err)
+	}
+	expected := []string{
+		"DELETE FROM `deleted_orders`  WHERE (`deleted_user_id` IN (?))",
+		"DELETE FROM `deleted_user_tags`  WHERE (`deleted_user_id` IN (?))",
+		"DELETE FROM `deleted_users`  WHERE (`id` = ?)",
+	}
+	if !reflect.DeepEqual(fakeStatements, expected) {
+		t.Errorf("associations should be deleted before the user, but got %v", fakeStatements)
+	}
+
+	if err := db.Select("Orders").Delete(&deletedUser{}).Error; err == nil {
+		t.Errorf("deleting associations of records without primary keys should be refused")
+	}
+
+	fakeStatements = nil
+	if err := db.Select("Tags", "Tags.Translations").Delete(&deletedUser{Id: 1}).Error; err == nil || len(fakeStatements) != 0 {
+		t.Errorf("deleting nested associations of many to many associations should be refused, but got %v, %v", err, fakeStatements)
+	}
+}
+
+type touchedBoard struct {
+	Code      string `gorm:"primary_key"`
+	UpdatedAt time.Time
+}
+
+type touchingCard struct {
+	Id             int64
+	TouchedBoardId string
+	TouchedBoard   touchedBoard `gorm:"touch"`
+}
+
+func TestRollbackWhenTouchingFailed(t *testing.T) {
+	db := newFakeDB("mysql", "transactions")
+
+	fakeStatements = nil
+	if err := db.Delete(&touchingCard{Id: 1, TouchedBoardId: "fail"}).Error; err == nil {
+		t.Errorf("failed touching should be returned")
+	}
+	expected := []string{
+		"BEGIN",
+		"DELETE FROM `touching_cards`  WHERE (`id` = ?)",
+		"UPDATE `touched_boards` SET `updated_at` = ? WHERE `code` = ?",
+		"ROLLBACK",
+	}
+	if !reflect.DeepEqual(fakeStatements, expected) {
+		t.Errorf("parents should be touched in the transaction of callbacks and rolled back, but got %v", fakeStatements)
+	}
+}
@@ -1,51 +1,62 @@
 package gorm
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 )
 
-func Query(scope *Scope) {
-	defer scope.Trace(NowFunc())
+// BeforeQuery call model's BeforeFind method before building the query, the method could change
+// conditions with the passed scope, e.g. `func (Post) BeforeFind(scope *gorm.Scope)`
+func BeforeQuery(scope *Scope) {
+	if scope.HasError() || scope.Value == nil {
+		return
+	}
 
-	var (
-		isSlice        bool
-		isPtr          bool
-		anyRecordFound bool
-		destType       reflect.Type
-	)
+	if modelType := scope.GetModelStruct().ModelType; modelType != nil {
+		if scope.IndirectValue().Kind() == reflect.Struct {
+			scope.callMethodOf(scope.Value, "BeforeFind")
+		} else {
+			// the slice is empty before query, call the method of a new model
+			scope.callMethodOf(reflect.New(modelType).Interface(), "BeforeFind")
+		}
+	}
+}
+
+// PrepareQuery build the query sql, callbacks registered after it could inspect or change scope.Sql
+func PrepareQuery(scope *Scope) {
+	if scope.HasError() {
+		return
+	}
 
 	if orderBy, ok := scope.Get("gorm:order_by_primary_key"); ok {
-		if primaryKey := scope.PrimaryKey(); primaryKey != "" {
-			scope.Search.Order(fmt.Sprintf("%v.%v %v", scope.QuotedTableName(), primaryKey, orderBy))
+		for _, column := range scope.sortKeys() {
+			scope.Search.Order(fmt.Sprintf("%v.%v %v", scope.quotedTableAlias(), column, orderBy))
 		}
 	}
 
+	scope.prepareQuerySql()
+	if str, ok := scope.Get("gorm:query_option"); ok {
+		scope.Sql += addExtraSpaceIfExist(fmt.Sprint(str))
+	}
+}
+
+func Query(scope *Scope) {
+	defer scope.Trace(NowFunc())
+
 	var dest = scope.IndirectValue()
 	if value, ok := scope.InstanceGet("gorm:query_destination"); ok {
 		dest = reflect.Indirect(reflect.ValueOf(value))
 	}
 
-	if kind := dest.Kind(); kind == reflect.Slice {
-		isSlice = true
-		destType = dest.Type().Elem()
-		if destType.Kind() == reflect.Ptr {
-			isPtr = true
-			destType = destType.Elem()
-		}
-	} else if kind != reflect.Struct {
+	if kind := dest.Kind(); kind != reflect.Slice && kind != reflect.Struct {
 		scope.Err(errors.New("unsupported destination, should be slice or struct"))
 		return
 	}
 
-	scope.prepareQuerySql()
-
 	if !scope.HasError() {
-		if str, ok := scope.Get("gorm:query_option"); ok {
-			scope.Sql += addExtraSpaceIfExist(fmt.Sprint(str))
-		}
-
 		rows, err := scope.SqlDB().Query(scope.Sql, scope.SqlVars...)
 		scope.db.RowsAffected = 0
 
@@ -54,67 +65,250 @@ func Query(scope *Scope) {
 		}
 		defer rows.Close()
 
-		columns, _ := rows.Columns()
-		for rows.Next() {
-			scope.db.RowsAffected++
+		if !scope.scanRows(rows, dest) && dest.Kind() != reflect.Slice && !scope.HasError() {
+			scope.Err(RecordNotFound)
+		}
+	}
+}
+
+// scanRows scan rows of current result set into dest, which is a struct or slice, returns whether any row is found
+func (scope *Scope) scanRows(rows *sql.Rows, dest reflect.Value) (anyRecordFound bool) {
+	var (
+		isSlice  bool
+		isPtr    bool
+		destType reflect.Type
+	)
+	if dest.Kind() == reflect.Slice {
+		isSlice = true
+		destType = dest.Type().Elem()
+		if destType.Kind() == reflect.Ptr {
+			isPtr = true
+			destType = destType.Elem()
+		}
+	}
+
+	columns, _ := rows.Columns()
+	// source keys of preloaded many to many records are collected, they aren't fields of records
+	sourceKeyIndex, mappedColumns := -1, columns
+	value, _ := scope.Get("gorm:preload_source_keys")
+	sourceKeys, _ := value.(*preloadSourceKeys)
+	if sourceKeys != nil {
+		for index, column := range columns {
+			if column == preloadSourceKeyColumn {
+				sourceKeyIndex = index
+				mappedColumns = append(append([]string{}, columns[:index]...), columns[index+1:]...)
+			}
+		}
+	}
+	if scope.Err(scope.checkUnmappedColumns(mappedColumns, dest)) != nil {
+		return
+	}
 
-			anyRecordFound = true
-			elem := dest
-			if isSlice {
-				elem = reflect.New(destType).Elem()
+	maxRows, warnOnly := scope.maxRows(isSlice)
+	var count int64
+	for rows.Next() {
+		if count++; maxRows > 0 && count > maxRows {
+			if !warnOnly {
+				scope.Err(TooManyRows)
+				return
 			}
+			scope.db.print("warning", fileWithLineNum(), fmt.Sprintf("more than %v rows are found without limit", maxRows))
+			maxRows = 0
+		}
+		scope.db.RowsAffected++
+
+		anyRecordFound = true
+		elem := dest
+		if isSlice {
+			elem = reflect.New(destType).Elem()
+		}
 
-			var values = make([]interface{}, len(columns))
+		var values = make([]interface{}, len(columns))
+		var scanFields = make([]*Field, len(columns))
 
-			fields := scope.New(elem.Addr().Interface()).Fields()
+		fields := scope.New(elem.Addr().Interface()).Fields()
 
-			for index, column := range columns {
-				if field, ok := fields[column]; ok {
-					if field.Field.Kind() == reflect.Ptr {
-						values[index] = field.Field.Addr().Interface()
-					} else {
-						values[index] = reflect.New(reflect.PtrTo(field.Field.Type())).Interface()
-					}
+		for index, column := range columns {
+			if field, ok := scope.scanField(fields, column); ok {
+				scanFields[index] = field
+				if field.Field.Kind() == reflect.Ptr {
+					values[index] = field.Field.Addr().Interface()
+				} else if _, isScanner := field.Field.Addr().Interface().(sql.Scanner); !isScanner && isUnsignedKind(field.Field.Kind()) {
+					values[index] = unsignedScanner{field.Field}
+				} else if unit, ok := scope.durationSetting(field.StructField); ok {
+					values[index] = durationScanner{field.Field, unit}
+				} else if scope.isDelimitedField(field.StructField) {
+					values[index] = delimitedScanner{field.Field}
+				} else if scope.isJsonField(field.StructField) {
+					values[index] = jsonScanner{field.Field}
 				} else {
-					var value interface{}
-					values[index] = &value
+					values[index] = reflect.New(reflect.PtrTo(field.Field.Type())).Interface()
 				}
+			} else {
+				var value interface{}
+				values[index] = &value
 			}
+		}
 
-			scope.Err(rows.Scan(values...))
+		if sourceKeyIndex >= 0 {
+			values[sourceKeyIndex] = reflect.New(sourceKeys.keyType).Interface()
+		}
+
+		scope.Err(rows.Scan(values...))
+		if sourceKeyIndex >= 0 {
+			sourceKeys.keys = append(sourceKeys.keys, reflect.ValueOf(values[sourceKeyIndex]).Elem().Interface())
+		}
 
-			for index, column := range columns {
-				value := values[index]
-				if field, ok := fields[column]; ok {
-					if field.Field.Kind() == reflect.Ptr {
-						field.Field.Set(reflect.ValueOf(value).Elem())
-					} else if v := reflect.ValueOf(value).Elem().Elem(); v.IsValid() {
-						field.Field.Set(v)
-					}
+		for index := range columns {
+			value := values[index]
+			switch value.(type) {
+			case unsignedScanner, durationScanner, delimitedScanner, jsonScanner:
+				// scanned into the field directly
+				continue
+			}
+			if field := scanFields[index]; field != nil {
+				if field.Field.Kind() == reflect.Ptr {
+					field.Field.Set(reflect.ValueOf(value).Elem())
+				} else if v := reflect.ValueOf(value).Elem().Elem(); v.IsValid() {
+					field.Field.Set(v)
 				}
 			}
+		}
 
-			if isSlice {
-				if isPtr {
-					dest.Set(reflect.Append(dest, elem.Addr()))
-				} else {
-					dest.Set(reflect.Append(dest, elem))
-				}
+		if isSlice {
+			if isPtr {
+				dest.Set(reflect.Append(dest, elem.Addr()))
+			} else {
+				dest.Set(reflect.Append(dest, elem))
 			}
 		}
+	}
+	return
+}
 
-		if !anyRecordFound && !isSlice {
-			scope.Err(RecordNotFound)
+// fieldByColumn find field for a returned column, aliases of aggregate results might not be in snake case
+func fieldByColumn(fields map[string]*Field, column string) (*Field, bool) {
+	if field, ok := fields[column]; ok {
+		return field, ok
+	}
+	if field, ok := fields[strings.ToLower(column)]; ok {
+		return field, ok
+	}
+	field, ok := fields[ToDBName(column)]
+	return field, ok
+}
+
+// scanField find the field to scan a column into, the column could be renamed by ScanAliases,
+// columns like `company.name` or `company__name` are scanned into nested structs
+func (scope *Scope) scanField(fields map[string]*Field, column string) (*Field, bool) {
+	if value, ok := scope.Get("gorm:scan_aliases"); ok {
+		if alias, ok := value.(map[string]string)[column]; ok {
+			column = alias
 		}
 	}
+	return fieldByPath(fields, column)
 }
 
+func fieldByPath(fields map[string]*Field, column string) (*Field, bool) {
+	if field, ok := fieldByColumn(fields, column); ok {
+		return field, true
+	}
+
+	path := strings.SplitN(strings.Replace(column, "__", ".", -1), ".", 2)
+	if len(path) < 2 {
+		return nil, false
+	}
+	field, ok := fieldByColumn(fields, path[0])
+	if !ok {
+		return nil, false
+	}
+
+	value := field.Field
+	if value.Kind() == reflect.Ptr && value.Type().Elem().Kind() == reflect.Struct {
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct || !value.CanAddr() {
+		return nil, false
+	}
+
+	nestedScope := &Scope{Value: value.Addr().Interface()}
+	return fieldByPath(nestedScope.Fields(), path[1])
+}
+
+// checkUnmappedColumns return UnmappedColumns error with StrictScan, or log them when StrictScan is warn only
+func (scope *Scope) checkUnmappedColumns(columns []string, dest reflect.Value) error {
+	warnOnly, strict := scope.Get("gorm:strict_scan")
+	if !strict {
+		return nil
+	}
+
+	destType := dest.Type()
+	for destType.Kind() == reflect.Slice || destType.Kind() == reflect.Ptr {
+		destType = destType.Elem()
+	}
+
+	var unmapped []string
+	fields := scope.New(reflect.New(destType).Interface()).Fields()
+	for _, column := range columns {
+		if _, ok := scope.scanField(fields, column); !ok {
+			unmapped = append(unmapped, column)
+		}
+	}
+	if len(unmapped) == 0 {
+		return nil
+	}
+
+	err := &UnmappedColumns{Type: destType, Columns: unmapped}
+	if warnOnly.(bool) {
+		scope.db.print("warning", fileWithLineNum(), err)
+		return nil
+	}
+	return err
+}
+
+// maxRows get the guardrail of SetMaxRows, it only applies to slices queried without limit
+func (scope *Scope) maxRows(isSlice bool) (maxRows int64, warnOnly bool) {
+	policy := scope.db.parent.maxRowsPolicy
+	if policy == nil || !isSlice || scope.Search.limit != "" {
+		return 0, false
+	}
+	return policy.max, policy.warnOnly
+}
+
+// AfterQuery call AfterFind for every found record, then AfterFindBatch of the model once with
+// the found slice, e.g. `func (User) AfterFindBatch(users interface{}) error`
 func AfterQuery(scope *Scope) {
 	scope.CallMethodWithErrorCheck("AfterFind")
+
+	if scope.HasError() || scope.IndirectValue().Kind() != reflect.Slice || scope.IndirectValue().Len() == 0 {
+		return
+	}
+
+	model := reflect.New(scope.GetModelStruct().ModelType).Interface()
+	if method := reflect.ValueOf(model).MethodByName("AfterFindBatch"); method.IsValid() {
+		switch f := method.Interface().(type) {
+		case func(interface{}):
+			f(scope.Value)
+		case func(interface{}) error:
+			scope.Err(f(scope.Value))
+		default:
+			scope.Err(errors.New("unsupported function AfterFindBatch"))
+		}
+	}
 }
 
 func init() {
+	DefaultCallback.Query().Register("gorm:history_as_of", QueryHistoryAsOf)
+	DefaultCallback.Query().Register("gorm:before_query", BeforeQuery)
+	DefaultCallback.Query().Register("gorm:prepare_query", PrepareQuery)
+	DefaultCallback.Query().Register("gorm:route_to_replica", RouteToReplica)
 	DefaultCallback.Query().Register("gorm:query", Query)
 	DefaultCallback.Query().Register("gorm:after_query", AfterQuery)
 	DefaultCallback.Query().Register("gorm:preload", Preload)
+	DefaultCallback.Query().Register("gorm:preload_count", PreloadCount)
+	DefaultCallback.RowQuery().Register("gorm:before_query", BeforeQuery)
+	DefaultCallback.RowQuery().Register("gorm:route_to_replica", RouteToReplica)
 }
@@ -1,6 +1,9 @@
 package gorm
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+)
 
 func BeginTransaction(scope *Scope) {
 	scope.Begin()
@@ -17,10 +20,27 @@ func SaveBeforeAssociations(scope *Scope) {
 	for _, field := range scope.Fields() {
 		if scope.changeableField(field) && !field.IsBlank && !field.IsIgnored {
 			if relationship := field.Relationship; relationship != nil && relationship.Kind == "belongs_to" {
-				value := field.Field
-				scope.Err(scope.NewDB().Save(value.Addr().Interface()).Error)
+				value := associationValue(field.Field)
+				if scope.callSaveAssociationHook(true, field, []interface{}{value}) != nil {
+					continue
+				}
+				scope.Err(scope.NewDB().Save(value).Error)
 				if relationship.ForeignFieldName != "" {
-					scope.Err(scope.SetColumn(relationship.ForeignFieldName, scope.New(value.Addr().Interface()).PrimaryKeyValue()))
+					scope.Err(scope.SetColumn(relationship.ForeignFieldName, scope.New(value).PrimaryKeyValue()))
+				}
+				if !scope.HasError() {
+					scope.callSaveAssociationHook(false, field, []interface{}{value})
+				}
+			} else if relationship != nil && relationship.Kind == "polymorphic_belongs_to" {
+				owner := associationValue(field.Field)
+				if scope.callSaveAssociationHook(true, field, []interface{}{owner}) != nil {
+					continue
+				}
+				scope.Err(scope.NewDB().Save(owner).Error)
+				scope.Err(scope.SetColumn(relationship.ForeignFieldName, scope.New(owner).PrimaryKeyValue()))
+				scope.Err(scope.SetColumn(relationship.PolymorphicType, scope.polymorphicDiscriminator(field, owner)))
+				if !scope.HasError() {
+					scope.callSaveAssociationHook(false, field, []interface{}{owner})
 				}
 			}
 		}
@@ -35,11 +55,29 @@ func SaveAfterAssociations(scope *Scope) {
 		if scope.changeableField(field) && !field.IsBlank && !field.IsIgnored {
 			if relationship := field.Relationship; relationship != nil &&
 				(relationship.Kind == "has_one" || relationship.Kind == "has_many" || relationship.Kind == "many_to_many") {
+				if scope.refuseZeroForeignKeys() && scope.PrimaryKeyZero() {
+					scope.Err(ZeroForeignKey)
+					continue
+				}
 				value := field.Field
+				values := []interface{}{}
+				if value.Kind() == reflect.Slice {
+					for i := 0; i < value.Len(); i++ {
+						values = append(values, value.Index(i).Addr().Interface())
+					}
+				} else {
+					values = append(values, associationValue(value))
+				}
+				if scope.callSaveAssociationHook(true, field, values) != nil {
+					continue
+				}
 
 				switch value.Kind() {
 				case reflect.Slice:
-					for i := 0; i < value.Len(); i++ {
+					// join rows of the default handler are added in batches, custom handlers add them one by one
+					defaultHandler, batchJoinRows := relationship.JoinTableHandler.(*JoinTableHandler)
+					var destinations []interface{}
+					for _, i := range scope.writeOrder(value) {
 						newDB := scope.NewDB()
 						elem := value.Index(i).Addr().Interface()
 						newScope := newDB.NewScope(elem)
@@ -54,12 +92,17 @@ func SaveAfterAssociations(scope *Scope) {
 
 						scope.Err(newDB.Save(elem).Error)
 
-						if joinTableHandler := relationship.JoinTableHandler; joinTableHandler != nil {
+						if batchJoinRows {
+							destinations = append(destinations, newScope.Value)
+						} else if joinTableHandler := relationship.JoinTableHandler; joinTableHandler != nil {
 							scope.Err(joinTableHandler.Add(scope.NewDB(), scope.Value, newScope.Value))
 						}
 					}
+					if batchJoinRows && len(destinations) > 0 && !scope.HasError() {
+						scope.Err(defaultHandler.AddAll(scope.NewDB(), scope.Value, destinations...))
+					}
 				default:
-					elem := value.Addr().Interface()
+					elem := associationValue(value)
 					newScope := scope.New(elem)
 					if relationship.ForeignFieldName != "" {
 						scope.Err(newScope.SetColumn(relationship.ForeignFieldName, scope.PrimaryKeyValue()))
@@ -70,6 +113,47 @@ func SaveAfterAssociations(scope *Scope) {
 					}
 					scope.Err(scope.NewDB().Save(elem).Error)
 				}
+				if !scope.HasError() {
+					scope.callSaveAssociationHook(false, field, values)
+				}
+			}
+		}
+	}
+}
+
+// TouchAssociations update the `updated_at` of belongs_to parents tagged with `touch` in the transaction of callbacks,
+// the write is rolled back if touching fails
+func TouchAssociations(scope *Scope) {
+	if scope.HasError() {
+		return
+	}
+	if !scope.updateTimestamps() {
+		return
+	}
+
+	fields := scope.Fields()
+	for _, field := range fields {
+		if relationship := field.Relationship; relationship != nil && relationship.Kind == "belongs_to" {
+			if _, ok := ParseTagSetting(field.Tag)["TOUCH"]; !ok {
+				continue
+			}
+
+			foreignField := fields[relationship.ForeignDBName]
+			if foreignField == nil || foreignField.IsBlank {
+				continue
+			}
+
+			toScope := scope.New(reflect.New(field.Struct.Type).Interface())
+			if updatedAtField, ok := toScope.FieldByName("UpdatedAt"); ok && toScope.PrimaryKey() != "" {
+				sql := fmt.Sprintf("UPDATE %v SET %v = ? WHERE %v = ?",
+					toScope.QuotedTableName(), scope.Quote(updatedAtField.DBName), scope.Quote(toScope.PrimaryKey()))
+				var now interface{} = NowFunc()
+				if isIntKind(updatedAtField.Field.Kind()) {
+					now = NowFunc().Unix()
+				}
+				if scope.Err(scope.NewDB().Exec(sql, now, foreignField.Field.Interface()).Error) != nil {
+					return
+				}
 			}
 		}
 	}
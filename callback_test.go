@@ -110,3 +110,64 @@ func TestRemoveCallback(t *testing.T) {
 		t.Errorf("remove callback")
 	}
 }
+
+func TestGetCallbackAndNames(t *testing.T) {
+	var callback = &callback{processors: []*callbackProcessor{}}
+
+	callback.Create().Register("create", create)
+	callback.Create().Before("create").Register("before_create1", beforeCreate1)
+	callback.Create().After("create").Register("after_create1", afterCreate1)
+
+	if fc := callback.Create().Get("create"); fc == nil || !equalFuncs([]*func(s *Scope){&fc}, []string{"create"}) {
+		t.Errorf("get callback")
+	}
+
+	if callback.Create().Get("unknown") != nil || callback.Update().Get("create") != nil {
+		t.Errorf("should not get unknown callback")
+	}
+
+	callback.Create().Replace("create", replaceCreate)
+	if fc := callback.Create().Get("create"); !equalFuncs([]*func(s *Scope){&fc}, []string{"replaceCreate"}) {
+		t.Errorf("get replaced callback")
+	}
+
+	if !reflect.DeepEqual(callback.Create().Names(), []string{"before_create1", "create", "after_create1"}) {
+		t.Errorf("list callback names, but got %v", callback.Create().Names())
+	}
+
+	if !equalFuncs(callback.creates, []string{"beforeCreate1", "replaceCreate", "afterCreate1"}) {
+		t.Errorf("getting callbacks should not change registered callbacks")
+	}
+
+	if len(callback.processors) != 4 {
+		t.Errorf("getting callbacks should not add processors, but got %v", len(callback.processors))
+	}
+}
+
+func TestValidateCallback(t *testing.T) {
+	var callback1 = &callback{processors: []*callbackProcessor{}}
+	callback1.Create().Register("create", create)
+	if err := callback1.Create().After("create").Register("after_create1", afterCreate1); err != nil {
+		t.Errorf("no error should happen when register after a registered callback, but got %v", err)
+	}
+	if err := callback1.Validate(); err != nil {
+		t.Errorf("valid callbacks, but got %v", err)
+	}
+
+	if err := callback1.Create().Before("unknown").Register("before_create1", beforeCreate1); err == nil || !strings.Contains(err.Error(), "unknown") {
+		t.Errorf("should get error for unknown anchor when register, but got %v", err)
+	}
+	if err := callback1.Validate(); err == nil || !strings.Contains(err.Error(), "unknown") {
+		t.Errorf("should get error for unknown anchor, but got %v", err)
+	}
+
+	var callback2 = &callback{processors: []*callbackProcessor{}}
+	callback2.Update().Replace("create", replaceCreate)
+	if err := callback2.Validate(); err == nil {
+		t.Errorf("should get error when replace unregistered callback")
+	}
+
+	if err := DefaultCallback.Validate(); err != nil {
+		t.Errorf("default callbacks should be valid, but got %v", err)
+	}
+}
@@ -7,9 +7,23 @@ import (
 
 func AssignUpdateAttributes(scope *Scope) {
 	if attrs, ok := scope.InstanceGet("gorm:update_interface"); ok {
+		// changed fields have been assigned already, only update them
+		if changes, ok := attrs.(*ChangeSet); ok {
+			if changed := changes.Changed(); len(changed) > 0 {
+				for column, value := range changed {
+					scope.setFieldChange(column, changes.original[column], value)
+				}
+				scope.InstanceSet("gorm:update_attrs", changed)
+			} else {
+				scope.SkipLeft()
+			}
+			return
+		}
+
 		if maps := convertInterfaceToMap(attrs); len(maps) > 0 {
 			protected, ok := scope.Get("gorm:ignore_protected_attrs")
-			_, updateColumn := scope.Get("gorm:update_column")
+			updateColumn := scope.updateColumn()
+			scope.recordUpdateChanges(maps, updateColumn)
 			updateAttrs, hasUpdate := scope.updatedAttrsWithValues(maps, ok && protected.(bool))
 
 			if updateColumn {
@@ -21,19 +35,54 @@ func AssignUpdateAttributes(scope *Scope) {
 				return
 			}
 		}
+	} else {
+		scope.recordSaveChanges()
 	}
 }
 
 func BeforeUpdate(scope *Scope) {
-	if _, ok := scope.Get("gorm:update_column"); !ok {
+	if !scope.updateColumn() {
 		scope.CallMethodWithErrorCheck("BeforeSave")
 		scope.CallMethodWithErrorCheck("BeforeUpdate")
 	}
 }
 
 func UpdateTimeStampWhenUpdate(scope *Scope) {
-	if _, ok := scope.Get("gorm:update_column"); !ok {
-		scope.SetColumn("UpdatedAt", NowFunc())
+	if !scope.updateTimestamps() {
+		return
+	}
+	// only models having the UpdatedAt field get it, not other fields with the column updated_at
+	var field *Field
+	for _, f := range scope.Fields() {
+		if f.Name == "UpdatedAt" && f.IsNormal && !f.IsIgnored && f.Field.IsValid() {
+			field = f
+		}
+	}
+	if field == nil {
+		return
+	}
+
+	// updated_at set explicitly is kept
+	attrs, hasAttrs := scope.InstanceGet("gorm:update_attrs")
+	if hasAttrs {
+		if _, ok := attrs.(map[string]interface{})[field.DBName]; ok {
+			return
+		}
+	} else if values, ok := scope.InstanceGet("gorm:update_interface"); ok {
+		if _, ok := convertInterfaceToMap(values)[field.DBName]; ok {
+			return
+		}
+	}
+
+	now := NowFunc()
+	original := field.Field.Interface()
+	if field.Set(now) != nil {
+		return
+	}
+	scope.setFieldChange(field.DBName, original, now)
+	// only attrs are saved when they are set, keep updated_at in them
+	if hasAttrs {
+		attrs.(map[string]interface{})[field.DBName] = field.Field.Interface()
 	}
 }
 
@@ -44,6 +93,9 @@ func Update(scope *Scope) {
 		if updateAttrs, ok := scope.InstanceGet("gorm:update_attrs"); ok {
 			for key, value := range updateAttrs.(map[string]interface{}) {
 				if scope.changeableDBColumn(key) {
+					if field, ok := scope.FieldByName(key); ok {
+						value = scope.columnVar(field.StructField, value)
+					}
 					sqls = append(sqls, fmt.Sprintf("%v = %v", scope.Quote(key), scope.AddToVars(value)))
 				}
 			}
@@ -52,7 +104,7 @@ func Update(scope *Scope) {
 			for _, field := range fields {
 				if scope.changeableField(field) && !field.IsPrimaryKey && field.IsNormal {
 					if !field.IsBlank || !field.HasDefaultValue {
-						sqls = append(sqls, fmt.Sprintf("%v = %v", scope.Quote(field.DBName), scope.AddToVars(field.Field.Interface())))
+						sqls = append(sqls, fmt.Sprintf("%v = %v", scope.Quote(field.DBName), scope.AddToVars(scope.fieldVar(field))))
 					}
 				} else if relationship := field.Relationship; relationship != nil && relationship.Kind == "belongs_to" {
 					if relationField := fields[relationship.ForeignDBName]; !scope.changeableField(relationField) {
@@ -64,31 +116,51 @@ func Update(scope *Scope) {
 			}
 		}
 
-		if len(sqls) > 0 {
+		// server maintained timestamps are refreshed by update statements if the database doesn't do it
+		if len(sqls) > 0 && !scope.Dialect().SupportOnUpdateTimestamp() {
+			for _, field := range scope.GetModelStruct().StructFields {
+				if _, ok := ParseTagSetting(field.Tag)["SERVER_TIMESTAMP"]; ok && field.IsNormal {
+					sqls = append(sqls, fmt.Sprintf("%v = CURRENT_TIMESTAMP", scope.Quote(field.DBName)))
+				}
+			}
+		}
+
+		if len(sqls) > 0 && scope.Search.updateFrom != nil {
+			fromTable := scope.quoteTable(scope.Search.updateFrom["table"].(string))
+			on := scope.Search.updateFrom["on"].(string)
+			scope.Raw(scope.Dialect().UpdateFromSql(scope.QuotedTableName(), sqls, fromTable, on, scope.whereSql()))
+			scope.Exec()
+		} else if len(sqls) > 0 {
 			scope.Raw(fmt.Sprintf(
 				"UPDATE %v SET %v %v",
 				scope.QuotedTableName(),
 				strings.Join(sqls, ", "),
 				scope.CombinedConditionSql(),
 			))
-			scope.Exec()
+			scope.execReturningServerTimestamps()
 		}
 	}
 }
 
 func AfterUpdate(scope *Scope) {
-	if _, ok := scope.Get("gorm:update_column"); !ok {
+	if !scope.updateColumn() {
 		scope.CallMethodWithErrorCheck("AfterUpdate")
 		scope.CallMethodWithErrorCheck("AfterSave")
 	}
 }
 
 func init() {
+	DefaultCallback.Update().Register("gorm:begin_transaction", BeginTransaction)
+	DefaultCallback.Update().Register("gorm:check_view", CheckView)
 	DefaultCallback.Update().Register("gorm:assign_update_attributes", AssignUpdateAttributes)
 	DefaultCallback.Update().Register("gorm:before_update", BeforeUpdate)
 	DefaultCallback.Update().Register("gorm:save_before_associations", SaveBeforeAssociations)
 	DefaultCallback.Update().Register("gorm:update_time_stamp_when_update", UpdateTimeStampWhenUpdate)
+	DefaultCallback.Update().Register("gorm:save_history", SaveHistoryWhenUpdate)
 	DefaultCallback.Update().Register("gorm:update", Update)
+	DefaultCallback.Update().Register("gorm:update_tree_path", UpdateTreePath)
 	DefaultCallback.Update().Register("gorm:save_after_associations", SaveAfterAssociations)
+	DefaultCallback.Update().Register("gorm:touch_associations", TouchAssociations)
 	DefaultCallback.Update().Register("gorm:after_update", AfterUpdate)
+	DefaultCallback.Update().Register("gorm:commit_or_rollback_transaction", CommitOrRollbackTransaction)
 }
@@ -0,0 +1,157 @@
+package gorm
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type updatedColumnUser struct {
+	Id          int64
+	Name        string
+	UpdatedAt   time.Time
+	updateHooks int
+}
+
+func (user *updatedColumnUser) BeforeUpdate() {
+	user.updateHooks++
+}
+
+func TestUpdateColumnsSemantics(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	cases := []struct {
+		name       string
+		update     func(db *DB, user *updatedColumnUser) *DB
+		hooks      int
+		timestamps bool
+	}{
+		{"Updates", func(db *DB, user *updatedColumnUser) *DB {
+			return db.Model(user).Updates(map[string]interface{}{"name": "updates"})
+		}, 1, true},
+		{"UpdateColumns", func(db *DB, user *updatedColumnUser) *DB {
+			return db.Model(user).UpdateColumns(map[string]interface{}{"name": "update_columns"})
+		}, 0, false},
+		{"UpdateColumnsWithTimestamps", func(db *DB, user *updatedColumnUser) *DB {
+			return db.Model(user).UpdateColumnsWithTimestamps(map[string]interface{}{"name": "with_timestamps"})
+		}, 0, true},
+	}
+
+	for _, c := range cases {
+		user := updatedColumnUser{Id: 1, Name: "user"}
+		fakeStatements = nil
+		if err := c.update(db, &user).Error; err != nil {
+			t.Fatalf("%v failed, got %v", c.name, err)
+		}
+		if user.updateHooks != c.hooks {
+			t.Errorf("%v should call update callbacks %v times, but got %v", c.name, c.hooks, user.updateHooks)
+		}
+		if len(fakeStatements) != 1 {
+			t.Fatalf("%v should update with one statement, but got %v", c.name, fakeStatements)
+		}
+		if strings.Contains(fakeStatements[0], "`updated_at`") != c.timestamps || user.UpdatedAt.IsZero() == c.timestamps {
+			t.Errorf("%v should set updated_at: %v, but got %v", c.name, c.timestamps, fakeStatements[0])
+		}
+	}
+}
+
+type savedTopic struct {
+	UserId int64  `gorm:"primary_key"`
+	Topic  string `gorm:"primary_key"`
+	Level  int64
+}
+
+func TestSaveByPrimaryKeys(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	fakeStatements = nil
+	db.Save(&savedTopic{UserId: 1, Level: 2})
+	db.Save(&savedTopic{Topic: "go", Level: 3})
+	db.Save(&savedTopic{Level: 4})
+	expected := []string{
+		"UPDATE `saved_topics` SET `level` = ?  WHERE (`user_id` = ?) AND (`topic` = ?)",
+		"UPDATE `saved_topics` SET `level` = ?  WHERE (`user_id` = ?) AND (`topic` = ?)",
+	}
+	if len(fakeStatements) != 3 || !reflect.DeepEqual(fakeStatements[:2], expected) {
+		t.Errorf("records should be updated by all primary keys unless all of them are blank, but got %v", fakeStatements)
+	}
+	if !strings.HasPrefix(fakeStatements[len(fakeStatements)-1], "INSERT INTO `saved_topics`") {
+		t.Errorf("records without primary keys should be inserted, but got %v", fakeStatements)
+	}
+
+	if db.NewRecord(&savedTopic{Topic: "go"}) || !db.NewRecord(&savedTopic{}) {
+		t.Errorf("only records without any primary keys should be new records")
+	}
+}
+
+type stampedReceipt struct {
+	Id         int64
+	Amount     int64
+	ModifiedAt time.Time `gorm:"server_timestamp"`
+}
+
+func TestUpdateServerTimestamp(t *testing.T) {
+	db := newFakeDB("postgres", "")
+	modifiedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	fakeResults = map[string][][]driver.Value{`UPDATE "stamped_receipts"`: {{modifiedAt}}}
+
+	fakeQueries = nil
+	receipt := stampedReceipt{Id: 1, Amount: 20}
+	updated := db.Save(&receipt)
+	expected := `UPDATE "stamped_receipts" SET "amount" = $1, "modified_at" = CURRENT_TIMESTAMP  WHERE ("id" = $2) RETURNING "stamped_receipts"."modified_at"`
+	if len(fakeQueries) != 1 || fakeQueries[0] != expected {
+		t.Errorf("server timestamps should be refreshed by update statements, but got %v", fakeQueries)
+	}
+	if updated.RowsAffected != 1 || !receipt.ModifiedAt.Equal(modifiedAt) {
+		t.Errorf("server timestamps should be read back, but got %v rows with %v", updated.RowsAffected, receipt.ModifiedAt)
+	}
+
+	fakeResults = map[string][][]driver.Value{}
+	if updated := db.Model(&stampedReceipt{Id: 2}).Update("amount", 30); updated.RowsAffected != 0 {
+		t.Errorf("rows affected should be counted from returned rows, but got %v", updated.RowsAffected)
+	}
+
+	mysqlDB := newFakeDB("mysql", "")
+	fakeStatements = nil
+	mysqlDB.Save(&stampedReceipt{Id: 1, Amount: 20})
+	if expected := []string{"UPDATE `stamped_receipts` SET `amount` = ?  WHERE (`id` = ?)"}; !reflect.DeepEqual(fakeStatements, expected) {
+		t.Errorf("server timestamps should be refreshed by ON UPDATE of mysql, but got %v", fakeStatements)
+	}
+}
+
+type renamedTimestampUser struct {
+	Id       int64
+	Name     string
+	Modified time.Time `gorm:"column:updated_at"`
+}
+
+func TestUpdatedAtOnlyForModelsHavingIt(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	user := renamedTimestampUser{Id: 1}
+	db.Model(&user).Updates(map[string]interface{}{"name": "renamed"})
+	if !user.Modified.IsZero() {
+		t.Errorf("updated_at should be set only for models having the UpdatedAt field, but got %v", user.Modified)
+	}
+
+	updatedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	updated := updatedColumnUser{Id: 1}
+	db.Model(&updated).Updates(map[string]interface{}{"name": "explicit", "updated_at": updatedAt})
+	if !updated.UpdatedAt.Equal(updatedAt) {
+		t.Errorf("updated_at set explicitly should be kept, but got %v", updated.UpdatedAt)
+	}
+}
+
+func TestCommitWhenNothingUpdated(t *testing.T) {
+	db := newFakeDB("mysql", "transactions")
+
+	user := updatedColumnUser{Id: 1, Name: "user"}
+	if err := db.Model(&user).Updates(map[string]interface{}{"name": "user"}).Error; err != nil {
+		t.Errorf("no error should happen when nothing is updated, but got %v", err)
+	}
+	if !reflect.DeepEqual(fakeStatements, []string{"BEGIN", "COMMIT"}) {
+		t.Errorf("transaction should be finished when left callbacks are skipped, but got %v", fakeStatements)
+	}
+}
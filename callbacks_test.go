@@ -6,6 +6,7 @@ import (
 	"golib/gorm"
 
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -175,3 +176,182 @@ func TestCallbacksWithErrors(t *testing.T) {
 		t.Errorf("Record shouldn't be deleted because of an error happened in after delete callback")
 	}
 }
+
+type TenantNote struct {
+	Id       int64
+	TenantId int64
+	Content  string
+}
+
+func (TenantNote) BeforeFind(scope *gorm.Scope) {
+	if tenantId, ok := scope.Get("test:tenant_id"); ok {
+		scope.Search.Where("tenant_id = ?", tenantId)
+	}
+}
+
+func TestQueryHooks(t *testing.T) {
+	DB.DropTableIfExists(&TenantNote{})
+	DB.AutoMigrate(&TenantNote{})
+	DB.Save(&TenantNote{TenantId: 1, Content: "note1"}).Save(&TenantNote{TenantId: 2, Content: "note2"})
+
+	var notes []TenantNote
+	DB.Set("test:tenant_id", 1).Find(&notes)
+	if len(notes) != 1 || notes[0].Content != "note1" {
+		t.Errorf("BeforeFind should add conditions to query, but got %+v", notes)
+	}
+
+	var count int
+	DB.Set("test:tenant_id", 2).Model(&TenantNote{}).Count(&count)
+	if count != 1 {
+		t.Errorf("BeforeFind should add conditions to count, but got %v", count)
+	}
+
+	var note TenantNote
+	if !DB.Set("test:tenant_id", 2).Where("content = ?", "note1").First(&note).RecordNotFound() {
+		t.Errorf("BeforeFind should work with First")
+	}
+
+	var preparedSql string
+	callback := DB.Callback()
+	callback.Query().After("gorm:prepare_query").Register("test:capture_sql", func(scope *gorm.Scope) {
+		preparedSql = scope.Sql
+	})
+	defer callback.Query().Remove("test:capture_sql")
+
+	DB.Where("content = ?", "note2").Find(&notes)
+	if !strings.Contains(preparedSql, "content") {
+		t.Errorf("Callbacks after gorm:prepare_query should get the built sql, but got %v", preparedSql)
+	}
+}
+
+type Secret struct {
+	Id          int64
+	Cipher      string
+	Plain       string `sql:"-"`
+	BatchLoaded int    `sql:"-"`
+}
+
+func (s *Secret) AfterFind() {
+	s.Plain = strings.ToUpper(s.Cipher)
+}
+
+func (Secret) AfterFindBatch(records interface{}) error {
+	if secrets, ok := records.(*[]Secret); ok {
+		for i := range *secrets {
+			(*secrets)[i].BatchLoaded = len(*secrets)
+		}
+		return nil
+	}
+	return errors.New("unexpected records")
+}
+
+func TestAfterFindPerRecordAndBatch(t *testing.T) {
+	DB.DropTableIfExists(&Secret{})
+	DB.AutoMigrate(&Secret{})
+	DB.Save(&Secret{Cipher: "abc"}).Save(&Secret{Cipher: "def"})
+
+	var secret Secret
+	DB.First(&secret)
+	if secret.Plain != "ABC" || secret.BatchLoaded != 0 {
+		t.Errorf("AfterFind should be called for struct, but got %+v", secret)
+	}
+
+	var secrets []Secret
+	DB.Find(&secrets)
+	if len(secrets) != 2 || secrets[1].Plain != "DEF" || secrets[0].BatchLoaded != 2 {
+		t.Errorf("AfterFind and AfterFindBatch should be called for slice, but got %+v", secrets)
+	}
+
+	var pointers []*Secret
+	if err := DB.Find(&pointers).Error; err == nil || len(pointers) != 2 || pointers[0].Plain != "ABC" {
+		t.Errorf("AfterFind should be called for slice of pointers and error of AfterFindBatch should be returned")
+	}
+}
+
+type Ticket struct {
+	Id           int64
+	Title        string
+	DeleteReason string `sql:"-"`
+}
+
+func (t *Ticket) BeforeDelete(scope *gorm.Scope) {
+	if _, ok := scope.InstanceGet("test:delete_reason"); ok {
+		scope.Err(errors.New("context should not be shared between operations"))
+	}
+	scope.InstanceSet("test:delete_reason", "closed "+t.Title).InstanceSet("test:deleted_at", 3)
+}
+
+func (t *Ticket) AfterDelete(scope *gorm.Scope) {
+	reason, _ := scope.InstanceGetString("test:delete_reason")
+	if attempt, ok := scope.InstanceGetInt("test:deleted_at"); ok && attempt == 3 {
+		t.DeleteReason = reason
+	}
+	if _, ok := scope.InstanceGetBool("test:delete_reason"); ok {
+		scope.Err(errors.New("string value should not be returned as bool"))
+	}
+}
+
+func TestInstanceContextBetweenCallbacks(t *testing.T) {
+	DB.DropTableIfExists(&Ticket{})
+	DB.AutoMigrate(&Ticket{})
+
+	ticket1, ticket2 := Ticket{Title: "ticket1"}, Ticket{Title: "ticket2"}
+	DB.Save(&ticket1).Save(&ticket2)
+
+	tx := DB.Where("id > ?", 0)
+	if err := tx.Delete(&ticket1).Error; err != nil || ticket1.DeleteReason != "closed ticket1" {
+		t.Errorf("AfterDelete should get the value set by BeforeDelete, but got %v, %v", err, ticket1.DeleteReason)
+	}
+
+	if err := tx.Delete(&ticket2).Error; err != nil || ticket2.DeleteReason != "closed ticket2" {
+		t.Errorf("context should be reset for every operation, but got %v, %v", err, ticket2.DeleteReason)
+	}
+}
+
+type Gadget struct {
+	Id    int64
+	Name  string
+	Owner *User `sql:"-"`
+}
+
+func (g *Gadget) AfterDelete() {
+	_ = g.Owner.Name
+}
+
+func TestCallbackPanicRecovered(t *testing.T) {
+	DB.DropTableIfExists(&Gadget{})
+	DB.AutoMigrate(&Gadget{})
+
+	gadget := Gadget{Name: "gadget"}
+	DB.Save(&gadget)
+
+	err := DB.Delete(&gadget).Error
+	if panicErr, ok := err.(*gorm.CallbackPanic); !ok || len(panicErr.Stack) == 0 {
+		t.Errorf("panic in callback should be returned as CallbackPanic, but got %v", err)
+	}
+
+	if DB.First(&Gadget{}, gadget.Id).RecordNotFound() {
+		t.Errorf("transaction should be rolled back after callback panicked")
+	}
+
+	if err := DB.Model(&gadget).Update("name", "gadget2").Error; err != nil {
+		t.Errorf("DB should still work after callback panicked, but got %v", err)
+	}
+}
+
+func TestCallbackTimings(t *testing.T) {
+	user := User{Name: "callback_timings"}
+	timings := DB.Debug().Save(&user).CallbackTimings()
+
+	var names []string
+	for _, timing := range timings {
+		names = append(names, timing.Name)
+	}
+	if !strings.Contains(strings.Join(names, ","), "gorm:create") {
+		t.Errorf("callbacks should be timed in debug mode, but got %v", names)
+	}
+
+	if timings := DB.Delete(&user).CallbackTimings(); len(timings) != 0 {
+		t.Errorf("callbacks should not be timed out of debug mode, but got %v", timings)
+	}
+}
@@ -0,0 +1,167 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ChangeSet record fields' values of a struct, so fields changed later could be updated even if they are set to zero values, e.g:
+//
+//	changes := gorm.TrackChanges(&user)
+//	user.Age = 0
+//	user.Name = "jinzhu"
+//	db.Model(&user).Updates(changes)
+//	//// UPDATE users SET age=0, name='jinzhu', updated_at='2013-11-17 21:34:10' WHERE id=111;
+//
+// pointer and sql.Null* fields could also be used to distinguish zero values, nil pointers and invalid Null values are not updated by Updates with struct
+type ChangeSet struct {
+	value    interface{}
+	original map[string]interface{}
+}
+
+// TrackChanges create a ChangeSet with value's current fields
+func TrackChanges(value interface{}) *ChangeSet {
+	changes := &ChangeSet{value: value, original: map[string]interface{}{}}
+	for _, field := range changes.fields() {
+		changes.original[field.DBName] = snapshotValue(field.Field)
+	}
+	return changes
+}
+
+func (changes *ChangeSet) fields() (fields []*Field) {
+	scope := Scope{Value: changes.value}
+	for _, field := range scope.Fields() {
+		if field.IsNormal && !field.IsIgnored && !field.IsPrimaryKey && field.Field.IsValid() {
+			fields = append(fields, field)
+		}
+	}
+	return
+}
+
+// Changed return columns changed since tracked, with their current values
+func (changes *ChangeSet) Changed() map[string]interface{} {
+	changed := map[string]interface{}{}
+	for _, field := range changes.fields() {
+		if !reflect.DeepEqual(changes.original[field.DBName], snapshotValue(field.Field)) {
+			changed[field.DBName] = field.Field.Interface()
+		}
+	}
+	return changed
+}
+
+// snapshotValue copy pointers' targets, slices and maps, so changing them in place could be detected
+func snapshotValue(value reflect.Value) interface{} {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+		return snapshotValue(value.Elem())
+	case reflect.Slice:
+		if value.IsNil() {
+			return nil
+		}
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		reflect.Copy(copied, value)
+		return copied.Interface()
+	case reflect.Map:
+		if value.IsNil() {
+			return nil
+		}
+		copied := reflect.MakeMap(value.Type())
+		for _, key := range value.MapKeys() {
+			copied.SetMapIndex(key, value.MapIndex(key))
+		}
+		return copied.Interface()
+	}
+	return value.Interface()
+}
+
+// FieldChange old and new values of a field changed by the update, the old value is nil if it is unknown, e.g. saved with Save
+type FieldChange struct {
+	Old interface{}
+	New interface{}
+}
+
+// Changes return fields changed by current update with their DB names, so update callbacks could tell what the update statement contains, e.g:
+//
+//	func (user *User) BeforeUpdate(scope *gorm.Scope) error {
+//		if scope.Changed("Role") && scope.MustGet("Role").Old == "admin" {
+//			return errors.New("admin couldn't be demoted")
+//		}
+//		return nil
+//	}
+func (scope *Scope) Changes() map[string]FieldChange {
+	if changes, ok := scope.InstanceGet("gorm:update_changes"); ok {
+		return changes.(map[string]FieldChange)
+	}
+	return map[string]FieldChange{}
+}
+
+// Changed check if the field, by field name or DB name, is changed by current update
+func (scope *Scope) Changed(name string) bool {
+	_, ok := scope.fieldChange(name)
+	return ok
+}
+
+// MustGet get old and new values of the changed field, it panics if the field isn't changed by current update
+func (scope *Scope) MustGet(name string) FieldChange {
+	change, ok := scope.fieldChange(name)
+	if !ok {
+		panic(fmt.Sprintf("field %v isn't changed by the update", name))
+	}
+	return change
+}
+
+func (scope *Scope) fieldChange(name string) (FieldChange, bool) {
+	changes := scope.Changes()
+	if field, ok := scope.FieldByName(name); ok {
+		name = field.DBName
+	}
+	change, ok := changes[name]
+	return change, ok
+}
+
+// setFieldChange record the field's change of current update
+func (scope *Scope) setFieldChange(dbName string, old interface{}, new interface{}) {
+	changes, ok := scope.InstanceGet("gorm:update_changes")
+	if !ok {
+		changes = map[string]FieldChange{}
+		scope.InstanceSet("gorm:update_changes", changes)
+	}
+	changes.(map[string]FieldChange)[dbName] = FieldChange{Old: old, New: new}
+}
+
+// recordUpdateChanges record changes of updating with the attrs before they are assigned to fields,
+// attrs which are equal to current values are skipped unless updating columns directly
+func (scope *Scope) recordUpdateChanges(attrs map[string]interface{}, updateColumn bool) {
+	fields := scope.Fields()
+	for key, value := range attrs {
+		field, ok := fields[ToDBName(key)]
+		if !ok {
+			if field, ok = scope.FieldByName(key); !ok {
+				continue
+			}
+		}
+		if !scope.changeableDBColumn(field.DBName) {
+			continue
+		}
+
+		var old interface{}
+		if field.Field.IsValid() {
+			old = field.Field.Interface()
+		}
+		if _, isExpr := value.(*expr); isExpr || updateColumn || !equalAsString(old, value) {
+			scope.setFieldChange(field.DBName, old, value)
+		}
+	}
+}
+
+// recordSaveChanges record all fields saved by Save, their old values are unknown
+func (scope *Scope) recordSaveChanges() {
+	for _, field := range scope.Fields() {
+		if scope.changeableField(field) && !field.IsPrimaryKey && field.IsNormal && (!field.IsBlank || !field.HasDefaultValue) {
+			scope.setFieldChange(field.DBName, nil, field.Field.Interface())
+		}
+	}
+}
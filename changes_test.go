@@ -0,0 +1,55 @@
+package gorm
+
+import (
+	"database/sql"
+	"testing"
+)
+
+type changedUser struct {
+	Id      int64
+	Name    string
+	Age     int64
+	changes map[string]FieldChange
+}
+
+func (user *changedUser) BeforeUpdate(scope *Scope) {
+	user.changes = scope.Changes()
+	if scope.Changed("Name") && scope.MustGet("name").New == "forbidden_name" {
+		scope.Err(sql.ErrNoRows)
+	}
+}
+
+func TestUpdateChanges(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	user := changedUser{Id: 1, Name: "old_name", Age: 18}
+	if err := db.Model(&user).Updates(map[string]interface{}{"name": "new_name", "age": 18}).Error; err != nil {
+		t.Fatalf("failed to update, got %v", err)
+	}
+	if len(user.changes) != 1 || user.changes["name"] != (FieldChange{Old: "old_name", New: "new_name"}) {
+		t.Errorf("only changed fields should be recorded with old and new values, but got %v", user.changes)
+	}
+
+	if err := db.Model(&user).Update("name", "forbidden_name").Error; err == nil {
+		t.Errorf("update callbacks should refuse changes by new values")
+	}
+
+	user = changedUser{Id: 1, Name: "saved_name"}
+	if err := db.Save(&user).Error; err != nil {
+		t.Fatalf("failed to save, got %v", err)
+	}
+	if len(user.changes) != 2 || user.changes["name"] != (FieldChange{New: "saved_name"}) {
+		t.Errorf("all saved fields should be recorded without old values, but got %v", user.changes)
+	}
+
+	scope := db.NewScope(&user)
+	if scope.Changed("Name") {
+		t.Errorf("fields should not be changed out of updates")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustGet should panic for unchanged fields")
+		}
+	}()
+	scope.MustGet("Name")
+}
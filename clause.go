@@ -0,0 +1,75 @@
+package gorm
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// positions of custom clauses in the statement
+const (
+	// ClauseBeforeJoins is right after the table name of SELECT, or SET of UPDATE, e.g. `SAMPLE BY`, index hints
+	ClauseBeforeJoins = "before_joins"
+	// ClauseBeforeGroup is after WHERE conditions
+	ClauseBeforeGroup = "before_group"
+	// ClauseEnd is the end of statement, before RETURNING of INSERT, e.g. `ON CONFLICT`, `FOR UPDATE`
+	ClauseEnd = "end"
+)
+
+type clauseBuilder struct {
+	position string
+	build    func(scope *Scope, args ...interface{}) string
+}
+
+var clauseBuilders = struct {
+	m map[string]*clauseBuilder
+	l sync.RWMutex
+}{m: map[string]*clauseBuilder{}}
+
+// RegisterClause register a custom clause, it is rendered at position when used with `db.Clause(name, args...)`,
+// build could add vars with scope.AddToVars, and return blank to skip the clause, e.g:
+//
+//	gorm.RegisterClause("sample", gorm.ClauseBeforeJoins, func(scope *gorm.Scope, args ...interface{}) string {
+//		return fmt.Sprintf("SAMPLE %v", scope.AddToVars(args[0]))
+//	})
+//	db.Clause("sample", 0.1).Find(&events)
+//	//// SELECT * FROM events SAMPLE 0.1
+func RegisterClause(name string, position string, build func(scope *Scope, args ...interface{}) string) {
+	switch position {
+	case ClauseBeforeJoins, ClauseBeforeGroup, ClauseEnd:
+	default:
+		panic(fmt.Sprintf("invalid position %v for clause %v", position, name))
+	}
+	clauseBuilders.l.Lock()
+	defer clauseBuilders.l.Unlock()
+	clauseBuilders.m[name] = &clauseBuilder{position: position, build: build}
+}
+
+func registeredClause(name string) (*clauseBuilder, bool) {
+	clauseBuilders.l.RLock()
+	defer clauseBuilders.l.RUnlock()
+	builder, ok := clauseBuilders.m[name]
+	return builder, ok
+}
+
+func (scope *Scope) clausesSql(position string) string {
+	var sqls []string
+	for _, clause := range scope.Search.clauses {
+		name := clause["name"].(string)
+		builder, ok := registeredClause(name)
+		if !ok {
+			scope.Err(fmt.Errorf("clause %v is not registered", name))
+			continue
+		}
+		if builder.position == position {
+			if sql := builder.build(scope, clause["args"].([]interface{})...); sql != "" {
+				sqls = append(sqls, sql)
+			}
+		}
+	}
+
+	if len(sqls) == 0 {
+		return ""
+	}
+	return " " + strings.Join(sqls, " ") + " "
+}
@@ -0,0 +1,44 @@
+package gorm_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"golib/gorm"
+)
+
+func init() {
+	gorm.RegisterClause("test_on_duplicate_update", gorm.ClauseEnd, func(scope *gorm.Scope, args ...interface{}) string {
+		return fmt.Sprintf("ON DUPLICATE KEY UPDATE %v = VALUES(%v)", scope.Quote(args[0].(string)), scope.Quote(args[0].(string)))
+	})
+
+	gorm.RegisterClause("test_min_age", gorm.ClauseBeforeGroup, func(scope *gorm.Scope, args ...interface{}) string {
+		return fmt.Sprintf("AND age >= %v", scope.AddToVars(args[0]))
+	})
+}
+
+func TestCustomClause(t *testing.T) {
+	user := User{Name: "CustomClauseUser", Age: 10}
+	DB.Save(&user)
+
+	// ON DUPLICATE KEY UPDATE is only supported by mysql
+	if dialect := os.Getenv("GORM_DIALECT"); dialect == "mysql" {
+		DB.Clause("test_on_duplicate_update", "name").Create(&User{Id: user.Id, Name: "CustomClauseUser2", Age: 10})
+		var result User
+		DB.First(&result, user.Id)
+		if result.Name != "CustomClauseUser2" {
+			t.Errorf("Custom clause should be added to insert, but got %v", result.Name)
+		}
+	}
+
+	var count int
+	DB.Model(&User{}).Where("id = ?", user.Id).Clause("test_min_age", 20).Count(&count)
+	if count != 0 {
+		t.Errorf("Custom clause should be added after where conditions, but got %v", count)
+	}
+
+	if err := DB.Clause("not_registered").Find(&[]User{}).Error; err == nil {
+		t.Errorf("Should get error when use unregistered clause")
+	}
+}
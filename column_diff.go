@@ -0,0 +1,311 @@
+package gorm
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DifferingColumnsError is returned by AutoMigrate when existing columns differ from fields' definitions and
+// AlterColumns isn't enabled, other operations are performed
+type DifferingColumnsError struct {
+	Columns []string
+}
+
+func (err *DifferingColumnsError) Error() string {
+	return fmt.Sprintf("columns differ from fields, use AlterColumns(true) to alter them: %v", strings.Join(err.Columns, "; "))
+}
+
+// columnDefinition is a parsed column definition, e.g. `varchar(100) CHARACTER SET utf8mb4 NOT NULL DEFAULT 'a'`
+type columnDefinition struct {
+	Type          string
+	Size          string
+	Scale         string
+	Unsigned      bool
+	NotNull       bool
+	AutoIncrement bool
+	HasDefault    bool
+	Default       string
+	Charset       string
+	Collate       string
+}
+
+// aliases of column types, they are normalized to the types reported by databases
+var columnTypeAliases = map[string]string{
+	"integer":   "int",
+	"bool":      "tinyint",
+	"boolean":   "tinyint",
+	"numeric":   "decimal",
+	"dec":       "decimal",
+	"real":      "double",
+	"int2":      "smallint",
+	"int4":      "int",
+	"int8":      "bigint",
+	"float8":    "double",
+	"character": "char",
+}
+
+// serial types of Postgres are integers with sequences' defaults
+var serialColumnTypes = map[string]string{"smallserial": "smallint", "serial": "int", "bigserial": "bigint"}
+
+// display widths of integer types, e.g. int(11), don't change what columns hold, except tinyint(1), which is the type of
+// bool and boolean columns
+var integerColumnTypes = map[string]bool{"tinyint": true, "smallint": true, "mediumint": true, "int": true, "bigint": true}
+
+// parseColumnDefinition parse column definitions generated from tags or built from columns in the live schema
+func parseColumnDefinition(definition string) columnDefinition {
+	var column columnDefinition
+	tokens := splitColumnDefinition(definition)
+	if len(tokens) == 0 {
+		return column
+	}
+
+	column.Type = strings.ToLower(tokens[0])
+	tokens = tokens[1:]
+	// types of two words, e.g. double precision, character varying(100)
+	if len(tokens) > 0 {
+		next := strings.ToLower(tokens[0])
+		if column.Type == "double" && next == "precision" {
+			tokens = tokens[1:]
+		} else if column.Type == "character" && strings.HasPrefix(next, "varying") {
+			column.Type = "varchar" + strings.TrimPrefix(next, "varying")
+			tokens = tokens[1:]
+		}
+	}
+	if i := strings.Index(column.Type, "("); i > 0 && strings.HasSuffix(column.Type, ")") {
+		sizes := strings.Split(column.Type[i+1:len(column.Type)-1], ",")
+		column.Type = column.Type[:i]
+		column.Size = strings.TrimSpace(sizes[0])
+		if len(sizes) > 1 {
+			column.Scale = strings.TrimSpace(sizes[1])
+		}
+	}
+	if serial, ok := serialColumnTypes[column.Type]; ok {
+		column.Type, column.AutoIncrement = serial, true
+	}
+	if alias, ok := columnTypeAliases[column.Type]; ok {
+		if column.Type == "bool" || column.Type == "boolean" {
+			column.Size = "1"
+		}
+		column.Type = alias
+	}
+	if integerColumnTypes[column.Type] && !(column.Type == "tinyint" && column.Size == "1") {
+		column.Size = ""
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		next := func() string {
+			if i+1 < len(tokens) {
+				i++
+				return tokens[i]
+			}
+			return ""
+		}
+
+		switch columnKeyword(tokens[i]) {
+		case "UNSIGNED":
+			column.Unsigned = true
+		case "NOT":
+			if strings.EqualFold(next(), "NULL") {
+				column.NotNull = true
+			}
+		case "AUTO_INCREMENT", "AUTOINCREMENT", "IDENTITY":
+			column.AutoIncrement = true
+		case "PRIMARY":
+			next()
+			column.NotNull = true
+		case "CHARACTER":
+			next()
+			column.Charset = strings.ToLower(next())
+		case "CHARSET":
+			column.Charset = strings.ToLower(next())
+		case "COLLATE":
+			column.Collate = strings.ToLower(next())
+		case "ON":
+			// ON UPDATE CURRENT_TIMESTAMP
+			next()
+			next()
+		case "DEFAULT":
+			column.HasDefault = true
+			column.Default = strings.Join(tokens[i+1:], " ")
+			i = len(tokens)
+		}
+	}
+	if column.AutoIncrement {
+		column.NotNull = true
+	}
+	if column.HasDefault {
+		column.Default = normalizeColumnDefault(column.Default)
+		column.HasDefault = column.Default != "NULL"
+		if !column.HasDefault {
+			column.Default = ""
+		}
+	}
+	return column
+}
+
+// columnInfoDefinition build the definition of a column in the live schema to compare with the one generated from
+// tags, defaults of auto incremented columns are generated by databases, e.g. nextval('users_id_seq'::regclass)
+func columnInfoDefinition(column ColumnInfo) string {
+	definition := column.ColumnType
+	if column.Charset != "" {
+		definition += " CHARACTER SET " + column.Charset
+	}
+	if column.Collation != "" {
+		definition += " COLLATE " + column.Collation
+	}
+	if column.AutoIncrement {
+		definition += " AUTO_INCREMENT"
+	}
+	if !column.Nullable || column.PrimaryKey {
+		definition += " NOT NULL"
+	}
+	if column.Default.Valid && !column.AutoIncrement {
+		definition += " DEFAULT " + column.Default.String
+	}
+	return definition
+}
+
+// keywords ending the type part of column definitions
+var columnConstraintKeywords = map[string]bool{
+	"NOT": true, "NULL": true, "DEFAULT": true, "PRIMARY": true, "UNIQUE": true, "AUTO_INCREMENT": true,
+	"AUTOINCREMENT": true, "IDENTITY": true, "CHECK": true, "REFERENCES": true, "ON": true, "GENERATED": true,
+}
+
+// splitColumnType split the column definition into the type, e.g. `varchar(100) COLLATE "C"`, and the default
+// value as it is written, hasDefault is false if there is no DEFAULT clause
+func splitColumnType(definition string) (sqlType string, defaultValue string, hasDefault bool) {
+	tokens := splitColumnDefinition(definition)
+	i := 0
+	for i < len(tokens) && (i == 0 || !columnConstraintKeywords[columnKeyword(tokens[i])]) {
+		i++
+	}
+	sqlType = strings.Join(tokens[:i], " ")
+	for ; i < len(tokens); i++ {
+		if strings.EqualFold(tokens[i], "DEFAULT") {
+			return sqlType, strings.Join(tokens[i+1:], " "), true
+		}
+	}
+	return sqlType, "", false
+}
+
+// columnKeyword get the upper case keyword of the token without arguments, e.g. IDENTITY(1,1) of MSSQL
+func columnKeyword(token string) string {
+	if i := strings.Index(token, "("); i > 0 {
+		token = token[:i]
+	}
+	return strings.ToUpper(token)
+}
+
+// splitColumnDefinition split the definition by spaces outside of quotes and parentheses
+func splitColumnDefinition(definition string) []string {
+	var tokens []string
+	var token []rune
+	var quote rune
+	var depth int
+	for _, r := range definition {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case depth == 0 && (r == ' ' || r == '\t' || r == '\n'):
+			if len(token) > 0 {
+				tokens = append(tokens, string(token))
+				token = nil
+			}
+			continue
+		}
+		token = append(token, r)
+	}
+	if len(token) > 0 {
+		tokens = append(tokens, string(token))
+	}
+	return tokens
+}
+
+// casts of Postgres defaults, e.g. 'a'::character varying
+var columnDefaultCastRegexp = regexp.MustCompile(`::[a-zA-Z ]+(\(\d+(,\s*\d+)?\))?(\[\])?$`)
+
+// normalizeColumnDefault unquote default values and normalize keywords, databases report `DEFAULT 'a'` as `a`,
+// `'a'::character varying` or `('a')`, `DEFAULT now()` as `CURRENT_TIMESTAMP`, `DEFAULT true` as `1`
+func normalizeColumnDefault(value string) string {
+	value = strings.TrimSpace(value)
+	for len(value) >= 2 && value[0] == '(' && value[len(value)-1] == ')' {
+		value = strings.TrimSpace(value[1 : len(value)-1])
+	}
+	value = columnDefaultCastRegexp.ReplaceAllString(value, "")
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		quote := string(value[0])
+		return normalizeNumber(strings.Replace(value[1:len(value)-1], quote+quote, quote, -1))
+	}
+
+	switch strings.ToUpper(value) {
+	case "NULL", "":
+		return "NULL"
+	case "TRUE":
+		return "1"
+	case "FALSE":
+		return "0"
+	case "CURRENT_TIMESTAMP", "CURRENT_TIMESTAMP()", "NOW()", "LOCALTIMESTAMP", "LOCALTIMESTAMP()", "GETDATE()", "SYSDATETIME()":
+		return "CURRENT_TIMESTAMP"
+	}
+	return normalizeNumber(value)
+}
+
+// normalizeNumber format numbers in the shortest form, e.g. 1.50 => 1.5
+func normalizeNumber(value string) string {
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	return value
+}
+
+// diffColumnDefinition get differences between the column definition generated from tags and the column in
+// database, sizes, charset and collation are only compared when they are declared
+func diffColumnDefinition(declared columnDefinition, column columnDefinition) (differences []string) {
+	if declared.Type != column.Type {
+		differences = append(differences, fmt.Sprintf("type %v => %v", column.Type, declared.Type))
+	}
+	// the size of a changed type comes with the type, e.g. int => tinyint(1)
+	if declared.Type == column.Type && declared.Size != "" && (declared.Size != column.Size || (declared.Scale != "" && declared.Scale != column.Scale)) {
+		differences = append(differences, fmt.Sprintf("size %v => %v", columnSize(column), columnSize(declared)))
+	}
+	if declared.Unsigned != column.Unsigned {
+		differences = append(differences, fmt.Sprintf("unsigned %v => %v", column.Unsigned, declared.Unsigned))
+	}
+	if declared.NotNull != column.NotNull {
+		differences = append(differences, fmt.Sprintf("not null %v => %v", column.NotNull, declared.NotNull))
+	}
+	if declared.HasDefault != column.HasDefault || declared.Default != column.Default {
+		differences = append(differences, fmt.Sprintf("default %v => %v", columnDefault(column), columnDefault(declared)))
+	}
+	if declared.Charset != "" && declared.Charset != column.Charset {
+		differences = append(differences, fmt.Sprintf("charset %v => %v", column.Charset, declared.Charset))
+	}
+	if declared.Collate != "" && declared.Collate != column.Collate {
+		differences = append(differences, fmt.Sprintf("collate %v => %v", column.Collate, declared.Collate))
+	}
+	return
+}
+
+func columnSize(column columnDefinition) string {
+	if column.Scale != "" {
+		return column.Size + "," + column.Scale
+	}
+	return column.Size
+}
+
+func columnDefault(column columnDefinition) string {
+	if !column.HasDefault {
+		return "none"
+	}
+	return column.Default
+}
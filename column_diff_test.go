@@ -0,0 +1,81 @@
+package gorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseColumnDefinition(t *testing.T) {
+	tt := assert.New(t)
+
+	tt.Equal(columnDefinition{Type: "varchar", Size: "100", NotNull: true, HasDefault: true, Default: "it's", Charset: "utf8mb4", Collate: "utf8mb4_bin"},
+		parseColumnDefinition("varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL DEFAULT 'it''s'"))
+	tt.Equal(columnDefinition{Type: "int", Unsigned: true, NotNull: true, AutoIncrement: true},
+		parseColumnDefinition("int(10) unsigned auto_increment NOT NULL"))
+	tt.Equal(columnDefinition{Type: "decimal", Size: "10", Scale: "2", HasDefault: true, Default: "1.5"},
+		parseColumnDefinition("numeric(10, 2) DEFAULT 1.50"))
+	tt.Equal(columnDefinition{Type: "timestamp", HasDefault: true, Default: "CURRENT_TIMESTAMP"},
+		parseColumnDefinition("timestamp NULL DEFAULT_GENERATED on update CURRENT_TIMESTAMP DEFAULT now()"))
+	tt.Equal(columnDefinition{Type: "tinyint", Size: "1"}, parseColumnDefinition("boolean DEFAULT NULL"))
+	tt.Equal(columnDefinition{Type: "varchar", Size: "100", HasDefault: true, Default: "a"},
+		parseColumnDefinition("character varying(100) DEFAULT 'a'::character varying"))
+	tt.Equal(columnDefinition{Type: "bigint", NotNull: true, AutoIncrement: true}, parseColumnDefinition("bigserial"))
+	tt.Equal(columnDefinition{Type: "int", NotNull: true, AutoIncrement: true}, parseColumnDefinition("int IDENTITY(1,1)"))
+	tt.Equal(columnDefinition{Type: "datetime2", HasDefault: true, Default: "CURRENT_TIMESTAMP"}, parseColumnDefinition("datetime2 DEFAULT (getdate())"))
+}
+
+func TestDiffColumnDefinition(t *testing.T) {
+	tests := []struct {
+		declared, column string
+		differences      int
+	}{
+		{"int", "int(11)", 0},
+		{"integer NOT NULL", "int(11) NOT NULL", 0},
+		{"bigint AUTO_INCREMENT", "bigint(20) NOT NULL auto_increment", 0},
+		{"boolean DEFAULT true", "tinyint(1) DEFAULT 1", 0},
+		{"varchar(255) DEFAULT 'a'", "varchar(255) DEFAULT a", 0},
+		{"decimal", "decimal(10,0)", 0},
+		{"varchar(100)", "varchar(100) CHARACTER SET utf8 COLLATE utf8_general_ci", 0},
+		{"varchar(100)", "varchar(255)", 1},
+		{"varchar(255) NOT NULL", "varchar(255)", 1},
+		{"varchar(255)", "varchar(255) NOT NULL", 1},
+		{"int DEFAULT 1", "int(11)", 1},
+		{"int unsigned", "int(10)", 1},
+		{"bigint", "int(11) NOT NULL", 2},
+		{"bigserial", "bigint NOT NULL AUTO_INCREMENT", 0},
+		{"varchar(100) DEFAULT 'a'", "character varying(100) DEFAULT 'a'::character varying", 0},
+		{"timestamp with time zone", "timestamp with time zone", 0},
+		{"double precision", "real", 0},
+		{"bigint IDENTITY(1,1)", "bigint NOT NULL", 0},
+	}
+
+	for _, test := range tests {
+		differences := diffColumnDefinition(parseColumnDefinition(test.declared), parseColumnDefinition(test.column))
+		if len(differences) != test.differences {
+			t.Errorf("column %v should have %v differences with %v, but got %v", test.column, test.differences, test.declared, differences)
+		}
+	}
+}
+
+type diffedColumns struct {
+	Id      int64
+	Age     int `sql:"not null;default:18"`
+	Enabled bool
+}
+
+func TestCompareFieldAndColumn(t *testing.T) {
+	tt := assert.New(t)
+
+	db := newFakeDB("mysql", "")
+	scope := db.NewScope(&diffedColumns{})
+	id, _ := scope.FieldByName("Id")
+	age, _ := scope.FieldByName("Age")
+	enabled, _ := scope.FieldByName("Enabled")
+
+	tt.True(scope.compareFieldAndColumn(id.StructField, "bigint(20) auto_increment NOT NULL"))
+	tt.True(scope.compareFieldAndColumn(age.StructField, "int(11) NOT NULL DEFAULT 18"))
+	tt.False(scope.compareFieldAndColumn(age.StructField, "int(11) NOT NULL DEFAULT 20"))
+	tt.True(scope.compareFieldAndColumn(enabled.StructField, "tinyint(1)"))
+	tt.Equal([]string{"type int => tinyint"}, scope.diffFieldAndColumn(enabled.StructField, "int(11)"))
+}
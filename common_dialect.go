@@ -10,14 +10,117 @@ import (
 
 type commonDialect struct{}
 
-func (commonDialect) BinVar(i int) string {
-	return "$$" // ?
+func (commonDialect) PlaceholderStyle() PlaceholderStyle {
+	return QuestionPlaceholder
+}
+
+// CollationSql render column's charset and collation, e.g. `CHARACTER SET utf8mb4 COLLATE utf8mb4_bin`
+func (commonDialect) CollationSql(charset string, collation string) string {
+	var sqls []string
+	if charset != "" {
+		sqls = append(sqls, "CHARACTER SET "+charset)
+	}
+	if collation != "" {
+		sqls = append(sqls, "COLLATE "+collation)
+	}
+	return strings.Join(sqls, " ")
+}
+
+// ServerTimestampSql is the column definition of server maintained timestamps, tagged with `server_timestamp`
+func (commonDialect) ServerTimestampSql() string {
+	return "DEFAULT CURRENT_TIMESTAMP"
 }
 
 func (commonDialect) SupportLastInsertId() bool {
 	return true
 }
 
+// SupportInterval durations are saved as integers for databases without interval columns
+func (commonDialect) SupportInterval() bool {
+	return false
+}
+
+// SupportArray slices are saved as JSON for databases without array columns
+func (commonDialect) SupportArray() bool {
+	return false
+}
+
+func (commonDialect) CallProcSql(name string, args []string) string {
+	return fmt.Sprintf("CALL %v(%v)", name, strings.Join(args, ", "))
+}
+
+// OutParamVar is blank for drivers supporting sql.Out, or a session variable receiving the OUT parameter
+func (commonDialect) OutParamVar(i int) string {
+	return ""
+}
+
+func (commonDialect) SelectOutParamsSql(variables []string) string {
+	return "SELECT " + strings.Join(variables, ", ")
+}
+
+func (commonDialect) CreateViewSql(view string, query string, replace bool) string {
+	if replace {
+		return fmt.Sprintf("CREATE OR REPLACE VIEW %v AS %v", view, query)
+	}
+	return fmt.Sprintf("CREATE VIEW %v AS %v", view, query)
+}
+
+// TableOptionsSql return options appended to CREATE TABLE, engines and charsets of tables are only supported by MySQL
+func (commonDialect) TableOptionsSql(engine string, charset string) string {
+	return ""
+}
+
+// ExplainSql return sql to get the query plan of sql, it is blank if the database can't explain with a query
+func (commonDialect) ExplainSql(sql string) string {
+	return "EXPLAIN " + sql
+}
+
+// MaxBindVars is the max count of bind vars in a statement, 0 if it is unknown
+func (commonDialect) MaxBindVars() int {
+	return 0
+}
+
+func (commonDialect) SupportLargeObject() bool {
+	return false
+}
+
+func (commonDialect) SupportPartialIndex() bool {
+	return false
+}
+
+func (commonDialect) SupportMaterializedView() bool {
+	return false
+}
+
+func (commonDialect) SupportStoredFunction() bool {
+	return true
+}
+
+// SupportOnUpdateTimestamp is true if columns could be refreshed by the database on update, like `ON UPDATE CURRENT_TIMESTAMP`,
+// otherwise server maintained timestamps are set to CURRENT_TIMESTAMP by update statements
+func (commonDialect) SupportOnUpdateTimestamp() bool {
+	return false
+}
+
+// SupportAddForeignKey is true if foreign keys could be added to existing tables with ALTER TABLE
+func (commonDialect) SupportAddForeignKey() bool {
+	return true
+}
+
+func (commonDialect) SubstringSql(column string, from string, length string) string {
+	return fmt.Sprintf("SUBSTRING(%v, %v, %v)", column, from, length)
+}
+
+// AppendBlobSql is the SET clause appending value to a binary column in place, it is blank if the database could only
+// rewrite the whole column, like CONCAT, so writing blobs in chunks is not supported
+func (commonDialect) AppendBlobSql(column string, value string) string {
+	return ""
+}
+
+func (commonDialect) JsonSqlTag() string {
+	return "text"
+}
+
 func (commonDialect) HasTop() bool {
 	return false
 }
@@ -47,6 +150,9 @@ func (commonDialect) SqlTag(value reflect.Value, size int, autoIncrease bool) st
 		if _, ok := value.Interface().(time.Time); ok {
 			return "TIMESTAMP"
 		}
+		if _, ok := value.Interface().(Point); ok {
+			return "POINT"
+		}
 	default:
 		if _, ok := value.Interface().([]byte); ok {
 			if size > 0 && size < 65532 {
@@ -62,6 +168,80 @@ func (commonDialect) ReturningStr(tableName, key string) string {
 	return ""
 }
 
+func (commonDialect) ILikeSql(column string, value string) string {
+	return fmt.Sprintf("LOWER(%v) LIKE LOWER(%v)", column, value)
+}
+
+func (commonDialect) GeometryVar(wkt string) string {
+	return fmt.Sprintf("ST_GeomFromText(%v)", wkt)
+}
+
+func (commonDialect) WithinDistanceSql(column string, point string, meters string) string {
+	return fmt.Sprintf("ST_Distance_Sphere(%v, %v) <= %v", column, point, meters)
+}
+
+func (c commonDialect) BoundingBoxSql(column string, polygon string) string {
+	return fmt.Sprintf("MBRContains(%v, %v)", c.GeometryVar(polygon), column)
+}
+
+// FullTextSearchSql fallback to match any column containing query
+func (c commonDialect) FullTextSearchSql(scope *Scope, columns []string, query string) string {
+	var sqls []string
+	for _, column := range columns {
+		sqls = append(sqls, c.ILikeSql(column, scope.AddToVars("%"+query+"%")))
+	}
+	return strings.Join(sqls, " OR ")
+}
+
+// FullTextIndexSql return blank as full-text index is not supported
+func (commonDialect) FullTextIndexSql(indexName string, tableName string, columns []string) string {
+	return ""
+}
+
+// UpdateFromSql render `UPDATE ... SET ... FROM ... WHERE`, the join condition is merged into conditions
+func (commonDialect) UpdateFromSql(tableName string, sets []string, fromTable string, on string, conditions string) string {
+	if conditions == "" {
+		conditions = "WHERE " + on
+	} else {
+		conditions = fmt.Sprintf("WHERE (%v) AND (%v)", on, strings.TrimPrefix(conditions, "WHERE "))
+	}
+	return fmt.Sprintf("UPDATE %v SET %v FROM %v %v", tableName, strings.Join(sets, ", "), fromTable, conditions)
+}
+
+// DeleteUsingSql render `DELETE ... WHERE EXISTS (...)`, which is supported by most databases
+func (commonDialect) DeleteUsingSql(tableName string, usingTable string, on string, conditions string) string {
+	if conditions != "" {
+		on = fmt.Sprintf("(%v) AND (%v)", on, strings.TrimPrefix(conditions, "WHERE "))
+	}
+	return fmt.Sprintf("DELETE FROM %v WHERE EXISTS (SELECT 1 FROM %v WHERE %v)", tableName, usingTable, on)
+}
+
+// UpsertSql updates on conflicts of any unique key, conflict columns are only used to ignore conflicting records
+// without update columns, as INSERT IGNORE ignores other errors too
+func (commonDialect) UpsertSql(conflictColumns []string, updateColumns []string) string {
+	if len(updateColumns) == 0 {
+		if len(conflictColumns) == 0 {
+			return ""
+		}
+		return fmt.Sprintf("ON DUPLICATE KEY UPDATE %v=%v", conflictColumns[0], conflictColumns[0])
+	}
+
+	var sets []string
+	for _, column := range updateColumns {
+		sets = append(sets, fmt.Sprintf("%v=VALUES(%v)", column, column))
+	}
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(sets, ",")
+}
+
+func (commonDialect) ForUpdateSql() string {
+	return "FOR UPDATE"
+}
+
+// AlterColumnSql render `ALTER TABLE ... MODIFY` changing the whole column definition
+func (commonDialect) AlterColumnSql(quotedTableName string, quotedColumn string, definition string) []string {
+	return []string{fmt.Sprintf("ALTER TABLE %v MODIFY %v %v", quotedTableName, quotedColumn, strings.TrimSpace(definition))}
+}
+
 func (commonDialect) SelectFromDummyTable() string {
 	return ""
 }
@@ -70,6 +250,11 @@ func (commonDialect) Quote(key string) string {
 	return fmt.Sprintf(`"%s"`, key)
 }
 
+// IsReservedWord check the word is reserved, so it must be quoted as an identifier
+func (commonDialect) IsReservedWord(word string) bool {
+	return reservedWords[strings.ToUpper(word)]
+}
+
 func (commonDialect) databaseName(scope *Scope) string {
 	from := strings.Index(scope.db.parent.source, "/") + 1
 	to := strings.Index(scope.db.parent.source, "?")
@@ -79,6 +264,49 @@ func (commonDialect) databaseName(scope *Scope) string {
 	return scope.db.parent.source[from:to]
 }
 
+// EstimatedCount read table's row count from statistics, it might be inaccurate
+func (c commonDialect) EstimatedCount(scope *Scope, tableName string) (int64, bool) {
+	var count sql.NullInt64
+	dbName, realTableName := DBName(tableName)
+	if dbName == "" {
+		dbName = c.databaseName(scope)
+	}
+	err := scope.NewDB().Raw("SELECT TABLE_ROWS FROM INFORMATION_SCHEMA.TABLES WHERE table_name = ? AND table_schema = ?", realTableName, dbName).Row().Scan(&count)
+	return count.Int64, err == nil && count.Valid
+}
+
+// PrepareTransactionSql is blank for databases don't support two-phase commit
+func (commonDialect) PrepareTransactionSql(id string) string {
+	return ""
+}
+
+func (commonDialect) CommitPreparedSql(id string) string {
+	return ""
+}
+
+func (commonDialect) RollbackPreparedSql(id string) string {
+	return ""
+}
+
+// CreateSequenceSql is blank for databases don't support sequences, auto increment is used instead
+func (commonDialect) CreateSequenceSql(name string, start int64, increment int64) string {
+	return ""
+}
+
+func (commonDialect) NextSequenceValSql(name string) string {
+	return ""
+}
+
+// SyncIdentitySql is blank as auto increment counters follow inserted values
+func (commonDialect) SyncIdentitySql(quotedTableName string, column string) string {
+	return ""
+}
+
+// ReplicationLag is unknown for databases without replication status
+func (commonDialect) ReplicationLag(replica *sql.DB) (time.Duration, bool) {
+	return 0, false
+}
+
 func (c commonDialect) HasTable(scope *Scope, tableName string) bool {
 	var count int
 	dbName, realTableName := DBName(tableName)
@@ -110,52 +338,35 @@ func (c commonDialect) HasIndex(scope *Scope, tableName string, indexName string
 	return count > 0
 }
 
-func (c commonDialect) IndexColumnCountMap(scope *Scope, tableName string) map[string]int {
+func (c commonDialect) HasTrigger(scope *Scope, tableName string, triggerName string) bool {
+	var count int
 	dbName, realTableName := DBName(tableName)
 	if dbName == "" {
 		dbName = c.databaseName(scope)
 	}
-	rows, err := scope.NewDB().Raw("SELECT INDEX_NAME, COUNT(INDEX_NAME) FROM INFORMATION_SCHEMA.STATISTICS WHERE table_name = ? and table_schema = ? GROUP BY INDEX_NAME", realTableName, dbName).Rows()
-	if err != nil {
-		panic(err.Error())
-	}
-
-	indexColumnMap := make(map[string]int, 16)
-	for rows.Next() {
-		var indexName string
-		var indexCount int
-		if err = rows.Scan(&indexName, &indexCount); err != nil {
-			panic(err.Error())
-		}
-		indexColumnMap[indexName] = indexCount
-	}
-	return indexColumnMap
+	scope.NewDB().Raw("SELECT count(*) FROM INFORMATION_SCHEMA.TRIGGERS WHERE event_object_table = ? AND trigger_name = ? AND trigger_schema = ?", realTableName, triggerName, dbName).Row().Scan(&count)
+	return count > 0
 }
 
-func (c commonDialect) IndexColumnMap(scope *Scope, tableName string, NonUnique int) map[string][]string {
-	dbName, realTableName := DBName(tableName)
-	if dbName == "" {
-		dbName = c.databaseName(scope)
-	}
-	rows, err := scope.NewDB().Raw("SELECT INDEX_NAME, SEQ_IN_INDEX, COLUMN_NAME FROM INFORMATION_SCHEMA.STATISTICS WHERE NON_UNIQUE = ? AND table_name = ? and table_schema = ?",
-		NonUnique, realTableName, dbName).Rows()
-	if err != nil {
-		panic(err)
-	}
+func (c commonDialect) HasFunction(scope *Scope, functionName string) bool {
+	var count int
+	scope.NewDB().Raw("SELECT count(*) FROM INFORMATION_SCHEMA.ROUTINES WHERE routine_type = 'FUNCTION' AND routine_name = ? AND routine_schema = ?", functionName, c.databaseName(scope)).Row().Scan(&count)
+	return count > 0
+}
 
-	indexCountMap := c.IndexColumnCountMap(scope, tableName)
+func (commonDialect) DropTriggerSql(triggerName string, quotedTableName string) string {
+	return fmt.Sprintf("DROP TRIGGER IF EXISTS %v", triggerName)
+}
 
-	indexColumnMap := make(map[string][]string, 16)
-	for rows.Next() {
-		var indexName, columnName string
-		var seqInIndex int
-		if err = rows.Scan(&indexName, &seqInIndex, &columnName); err != nil {
-			panic(err)
-		}
-		if _, ok := indexColumnMap[indexName]; !ok {
-			indexColumnMap[indexName] = make([]string, indexCountMap[indexName], indexCountMap[indexName])
+// IndexColumnMap get columns of unique indexes if isUnique is 0, or other indexes if it is 1, the primary key is an
+// unique index named PRIMARY
+func (c commonDialect) IndexColumnMap(scope *Scope, tableName string, isUnique int) map[string][]string {
+	indexes, _ := scope.Dialect().IndexInfos(scope, tableName)
+	indexColumnMap := make(map[string][]string, len(indexes))
+	for _, index := range indexes {
+		if index.Unique == (isUnique == 0) {
+			indexColumnMap[index.Name] = index.Columns
 		}
-		indexColumnMap[indexName][seqInIndex-1] = columnName
 	}
 	return indexColumnMap
 }
@@ -164,40 +375,50 @@ func (commonDialect) RemoveIndex(scope *Scope, indexName string) {
 	scope.NewDB().Exec(fmt.Sprintf("DROP INDEX %v ON %v", indexName, scope.QuotedTableName()))
 }
 
-func (c commonDialect) Columns(scope *Scope, tableName string) map[string]string {
+func (c commonDialect) TableInfos(scope *Scope) ([]string, error) {
+	return informationSchemaTables(scope, "table_type = 'BASE TABLE' AND table_schema = ?", c.databaseName(scope))
+}
+
+func (c commonDialect) ColumnInfos(scope *Scope, tableName string) ([]ColumnInfo, error) {
 	dbName, realTableName := DBName(tableName)
 	if dbName == "" {
 		dbName = c.databaseName(scope)
 	}
-	rows, err := scope.NewDB().Raw(
-		"SELECT COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_DEFAULT, EXTRA FROM INFORMATION_SCHEMA.COLUMNS WHERE table_schema = ? AND table_name = ?",
-		dbName, realTableName,
-	).Rows()
+	return informationSchemaColumns(scope, realTableName, "c.column_type", "c.extra LIKE '%auto_increment%'", "c.table_schema = ?", dbName)
+}
+
+func (c commonDialect) IndexInfos(scope *Scope, tableName string) ([]IndexInfo, error) {
+	dbName, realTableName := DBName(tableName)
+	if dbName == "" {
+		dbName = c.databaseName(scope)
+	}
+	rows, err := scope.NewDB().Raw("SELECT INDEX_NAME, COLUMN_NAME, NON_UNIQUE = 0, INDEX_NAME = 'PRIMARY' FROM INFORMATION_SCHEMA.STATISTICS WHERE table_name = ? AND table_schema = ? ORDER BY INDEX_NAME, SEQ_IN_INDEX",
+		realTableName, dbName).Rows()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
+	return scanIndexInfos(rows)
+}
 
-	columns := make(map[string]string, 32)
-	for rows.Next() {
-		var columnName, columnType, isNullable, extra string
-		var columnDefault sql.NullString
-		if err = rows.Scan(&columnName, &columnType, &isNullable, &columnDefault, &extra); err != nil {
-			panic(err)
-		}
-
-		column := columnType
-		if extra != "" {
-			column += " " + extra
-		}
-
-		if isNullable == "NO" {
-			column += " NOT NULL"
-		}
+func (c commonDialect) ForeignKeyInfos(scope *Scope, tableName string) ([]ForeignKeyInfo, error) {
+	dbName, realTableName := DBName(tableName)
+	if dbName == "" {
+		dbName = c.databaseName(scope)
+	}
+	rows, err := scope.NewDB().Raw("SELECT CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE WHERE table_name = ? AND table_schema = ? AND REFERENCED_TABLE_NAME IS NOT NULL ORDER BY CONSTRAINT_NAME, ORDINAL_POSITION",
+		realTableName, dbName).Rows()
+	if err != nil {
+		return nil, err
+	}
+	return scanForeignKeyInfos(rows)
+}
 
-		if columnDefault.Valid {
-			column += " DEFAULT " + columnDefault.String
-		}
-		columns[columnName] = column
+// Columns get definitions of columns in the live schema by names, e.g. `varchar(100) NOT NULL DEFAULT 'a'`
+func (c commonDialect) Columns(scope *Scope, tableName string) map[string]string {
+	columnInfos, _ := scope.Dialect().ColumnInfos(scope, tableName)
+	columns := make(map[string]string, len(columnInfos))
+	for _, column := range columnInfos {
+		columns[column.Name] = columnInfoDefinition(column)
 	}
 	return columns
 }
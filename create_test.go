@@ -1,9 +1,15 @@
 package gorm_test
 
 import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"os"
 	"reflect"
 	"testing"
 	"time"
+
+	"golib/gorm"
 )
 
 func TestCreate(t *testing.T) {
@@ -157,3 +163,356 @@ func TestOmitWithCreate(t *testing.T) {
 		t.Errorf("Should not create omited relationships")
 	}
 }
+
+type ArchivedEmail struct {
+	Id         int16
+	UserId     int
+	Email      string
+	ArchivedAt *time.Time
+}
+
+func TestCreateFromSelect(t *testing.T) {
+	DB.DropTableIfExists(&ArchivedEmail{})
+	DB.AutoMigrate(&ArchivedEmail{})
+
+	DB.Save(&Email{UserId: 1001, Email: "archive1@example.com"}).Save(&Email{UserId: 1001, Email: "archive2@example.com"}).Save(&Email{UserId: 1002, Email: "archive3@example.com"})
+
+	query := DB.Model(&Email{}).Where("user_id = ?", 1001)
+	if err := DB.Model(&ArchivedEmail{}).CreateFromSelect(query).Error; err != nil {
+		t.Errorf("No error should happen when create from select, but got %v", err)
+	}
+
+	var archived []ArchivedEmail
+	DB.Where("user_id = ?", 1001).Order("id").Find(&archived)
+	if len(archived) != 2 || archived[0].Email != "archive1@example.com" || archived[0].Id == 0 {
+		t.Errorf("Should copy matched rows with their columns, but got %+v", archived)
+	}
+}
+
+type Device struct {
+	gorm.UUIDModel
+	Name string
+}
+
+type Heartbeat struct {
+	gorm.UnixTimeModel
+	Device string
+}
+
+type Coupon struct {
+	gorm.TimestampModel
+	Code string
+}
+
+func TestBaseModels(t *testing.T) {
+	DB.DropTableIfExists(&Device{}).DropTableIfExists(&Heartbeat{}).DropTableIfExists(&Coupon{})
+	DB.AutoMigrate(&Device{}, &Heartbeat{}, &Coupon{})
+
+	device := Device{Name: "sensor"}
+	DB.Create(&device)
+	if len(device.ID) != 36 || device.CreatedAt.IsZero() {
+		t.Errorf("UUID and timestamps should be assigned when create, but got %+v", device)
+	}
+	DB.Delete(&device)
+	if !DB.First(&Device{}, "id = ?", device.ID).RecordNotFound() || DB.Unscoped().First(&Device{}, "id = ?", device.ID).RecordNotFound() {
+		t.Errorf("UUIDModel should be soft deleted")
+	}
+
+	heartbeat := Heartbeat{Device: device.ID}
+	DB.Create(&heartbeat)
+	if heartbeat.CreatedAt == 0 || heartbeat.UpdatedAt < heartbeat.CreatedAt {
+		t.Errorf("unix timestamps should be assigned when create, but got %+v", heartbeat)
+	}
+
+	var found Heartbeat
+	DB.First(&found, heartbeat.ID)
+	if found.CreatedAt != heartbeat.CreatedAt {
+		t.Errorf("unix timestamps should be saved, but got %+v", found)
+	}
+
+	coupon := Coupon{Code: "SAVE10"}
+	DB.Create(&coupon)
+	DB.Delete(&coupon)
+	if !DB.Unscoped().First(&Coupon{}, coupon.ID).RecordNotFound() {
+		t.Errorf("TimestampModel should be deleted from database")
+	}
+}
+
+type Contact struct {
+	Id        int64
+	FirstName string
+	LastName  string
+	FullName  string `gorm:"->;type:varchar(255) GENERATED ALWAYS AS (concat(first_name, ' ', last_name)) STORED"`
+}
+
+func TestGeneratedColumn(t *testing.T) {
+	DB.DropTableIfExists(&Contact{})
+	if err := DB.AutoMigrate(&Contact{}).Error; err != nil {
+		t.Errorf("generated column should be created, but got %v", err)
+	}
+
+	contact := Contact{FirstName: "Jinzhu", LastName: "Zhang", FullName: "ignored"}
+	if err := DB.Create(&contact).Error; err != nil {
+		t.Errorf("generated column should not be inserted, but got %v", err)
+	}
+
+	contact.LastName = "Z"
+	if err := DB.Save(&contact).Error; err != nil {
+		t.Errorf("generated column should not be updated, but got %v", err)
+	}
+	if err := DB.Model(&contact).Updates(map[string]interface{}{"first_name": "J", "full_name": "ignored"}).Error; err != nil {
+		t.Errorf("generated column should be skipped in Updates, but got %v", err)
+	}
+
+	var found Contact
+	DB.First(&found, contact.Id)
+	if found.FullName != "J Z" {
+		t.Errorf("generated column should be queried, but got %v", found.FullName)
+	}
+}
+
+type Receipt struct {
+	Id         int64
+	Amount     int64
+	ModifiedAt time.Time `gorm:"server_timestamp"`
+}
+
+func TestServerTimestamp(t *testing.T) {
+	DB.DropTableIfExists(&Receipt{})
+	if err := DB.AutoMigrate(&Receipt{}).Error; err != nil {
+		t.Errorf("server timestamp column should be created, but got %v", err)
+	}
+
+	receipt := Receipt{Amount: 10}
+	if err := DB.Create(&receipt).Error; err != nil {
+		t.Errorf("server timestamp should not be inserted, but got %v", err)
+	}
+
+	var found Receipt
+	DB.First(&found, receipt.Id)
+	if found.ModifiedAt.IsZero() {
+		t.Errorf("server timestamp should be set by database")
+	}
+
+	receipt.Amount = 20
+	if err := DB.Save(&receipt).Error; err != nil {
+		t.Errorf("server timestamp should not be updated, but got %v", err)
+	}
+	DB.First(&found, receipt.Id)
+	if found.Amount != 20 || found.ModifiedAt.IsZero() {
+		t.Errorf("record should be updated with server timestamp, but got %+v", found)
+	}
+}
+
+type Counter struct {
+	Id    int64
+	Value uint64
+}
+
+func TestUnsignedBigint(t *testing.T) {
+	if dialect := os.Getenv("GORM_DIALECT"); dialect != "mysql" {
+		t.Skip("only mysql has unsigned bigint columns, others need `type:numeric(20)`")
+	}
+	DB.DropTableIfExists(&Counter{})
+	DB.AutoMigrate(&Counter{})
+
+	counter := Counter{Value: math.MaxUint64}
+	if err := DB.Create(&counter).Error; err != nil {
+		t.Errorf("uint64 above math.MaxInt64 should be inserted, but got %v", err)
+	}
+
+	var found Counter
+	if err := DB.Where("value = ?", uint64(math.MaxUint64)).First(&found).Error; err != nil || found.Value != math.MaxUint64 {
+		t.Errorf("uint64 above math.MaxInt64 should be queried, but got %v, %v", found.Value, err)
+	}
+}
+
+type Job struct {
+	Id      int64
+	Timeout time.Duration `gorm:"duration:ms"`
+	Elapsed time.Duration
+}
+
+func TestDurationField(t *testing.T) {
+	DB.DropTableIfExists(&Job{})
+	DB.AutoMigrate(&Job{})
+
+	job := Job{Timeout: 1500 * time.Millisecond, Elapsed: time.Minute}
+	if err := DB.Create(&job).Error; err != nil {
+		t.Errorf("duration fields should be saved, but got %v", err)
+	}
+
+	var timeout int64
+	DB.Table("jobs").Where("id = ?", job.Id).Select("timeout").Row().Scan(&timeout)
+	if timeout != 1500 {
+		t.Errorf("duration should be saved in milliseconds, but got %v", timeout)
+	}
+
+	DB.Model(&job).Updates(map[string]interface{}{"timeout": 2 * time.Second})
+	var found Job
+	DB.First(&found, job.Id)
+	if found.Timeout != 2*time.Second || found.Elapsed != time.Minute {
+		t.Errorf("duration fields should be queried, but got %v, %v", found.Timeout, found.Elapsed)
+	}
+}
+
+type Preference struct {
+	Id       int64
+	Settings map[string]string
+	Scores   []int
+}
+
+func TestJsonFields(t *testing.T) {
+	DB.DropTableIfExists(&Preference{})
+	if err := DB.AutoMigrate(&Preference{}).Error; err != nil {
+		t.Errorf("JSON columns should be created, but got %v", err)
+	}
+
+	preference := Preference{Settings: map[string]string{"theme": "dark"}, Scores: []int{1, 2, 3}}
+	if err := DB.Create(&preference).Error; err != nil {
+		t.Errorf("map and slice fields should be saved as JSON, but got %v", err)
+	}
+
+	preference.Scores = append(preference.Scores, 4)
+	DB.Save(&preference)
+
+	var found Preference
+	DB.First(&found, preference.Id)
+	if found.Settings["theme"] != "dark" || !reflect.DeepEqual(found.Scores, []int{1, 2, 3, 4}) {
+		t.Errorf("JSON columns should be decoded, but got %v, %v", found.Settings, found.Scores)
+	}
+}
+
+type Bookmark struct {
+	Id   int64
+	Tags []string `gorm:"serializer:comma"`
+}
+
+func TestDelimitedFields(t *testing.T) {
+	DB.DropTableIfExists(&Bookmark{})
+	DB.AutoMigrate(&Bookmark{})
+
+	bookmark := Bookmark{Tags: []string{"go", "orm", "a,b"}}
+	if err := DB.Create(&bookmark).Error; err != nil {
+		t.Errorf("delimited field should be saved, but got %v", err)
+	}
+
+	var tags string
+	DB.Table("bookmarks").Where("id = ?", bookmark.Id).Select("tags").Row().Scan(&tags)
+	if tags != `go,orm,a\,b` {
+		t.Errorf("tags should be saved as comma separated values, but got %v", tags)
+	}
+
+	var found Bookmark
+	DB.First(&found, bookmark.Id)
+	if !reflect.DeepEqual(found.Tags, bookmark.Tags) {
+		t.Errorf("tags should be split, but got %#v", found.Tags)
+	}
+}
+
+type Attachment struct {
+	Id      int64
+	Name    string
+	Content []byte `sql:"type:longblob"`
+}
+
+func TestBlobStream(t *testing.T) {
+	DB.DropTableIfExists(&Attachment{})
+	DB.AutoMigrate(&Attachment{})
+
+	attachment := Attachment{Name: "large.bin"}
+	DB.Create(&attachment)
+
+	content := bytes.Repeat([]byte("0123456789abcdef"), 100*1024)
+	if err := DB.WriteBlob(&attachment, "content", bytes.NewReader(content)).Error; err == gorm.BlobStreamNotSupported {
+		DB.Model(&attachment).UpdateColumn("content", content)
+	} else if err != nil {
+		t.Errorf("blob should be written in chunks, but got %v", err)
+	}
+
+	r, err := DB.OpenBlob(&attachment, "content")
+	if err != nil {
+		t.Errorf("blob should be opened, but got %v", err)
+	}
+	if read, err := ioutil.ReadAll(r); err != nil || !bytes.Equal(read, content) {
+		t.Errorf("blob should be read in chunks, but got %v bytes, %v", len(read), err)
+	}
+
+	if err := DB.WriteBlob(&attachment, "unknown", bytes.NewReader(content)).Error; err == nil {
+		t.Errorf("should return error for unknown column")
+	}
+}
+
+func TestOperationResult(t *testing.T) {
+	user := User{Name: "operation_result", Age: 18}
+	result := DB.Create(&user).Result()
+	if result.Error != nil || result.RowsAffected != 1 {
+		t.Errorf("create should affect one row, but got %+v", result)
+	}
+	if dialect := os.Getenv("GORM_DIALECT"); (dialect == "" || dialect == "mysql") && result.LastInsertId != user.Id {
+		t.Errorf("last insert id should be reported, but got %v, user id %v", result.LastInsertId, user.Id)
+	}
+
+	updated := DB.Model(&user).Update("age", 20).Result()
+	deleted := DB.Delete(&user).Result()
+	if updated.RowsAffected != 1 || deleted.RowsAffected != 1 || result.RowsAffected != 1 {
+		t.Errorf("results of operations should be kept separately, but got %+v, %+v, %+v", result, updated, deleted)
+	}
+}
+
+type Subscription struct {
+	Id     int64
+	UserId int64  `sql:"unique_index:uix_subscriptions_user_topic"`
+	Topic  string `sql:"size:100;unique_index:uix_subscriptions_user_topic"`
+	Level  int
+}
+
+func TestUpsert(t *testing.T) {
+	if dialect := os.Getenv("GORM_DIALECT"); dialect == "mssql" || dialect == "foundation" {
+		if err := DB.Upsert(&Subscription{UserId: 1, Topic: "news"}, "uix_subscriptions_user_topic").Error; err != gorm.UpsertNotSupported {
+			t.Errorf("upsert should not be supported, but got %v", err)
+		}
+		return
+	}
+
+	DB.DropTableIfExists(&Subscription{})
+	DB.AutoMigrate(&Subscription{})
+
+	if err := DB.Upsert(&Subscription{UserId: 1, Topic: "news", Level: 1}, "uix_subscriptions_user_topic").Error; err != nil {
+		t.Errorf("No error should happen when upsert a new record, but got %v", err)
+	}
+	if err := DB.Upsert(&Subscription{UserId: 1, Topic: "news", Level: 2}, "user_id", "topic").Error; err != nil {
+		t.Errorf("No error should happen when upsert a conflicting record, but got %v", err)
+	}
+
+	var subscriptions []Subscription
+	DB.Where("user_id = ?", 1).Find(&subscriptions)
+	if len(subscriptions) != 1 || subscriptions[0].Level != 2 {
+		t.Errorf("conflicting record should be updated, but got %+v", subscriptions)
+	}
+}
+
+func TestCreateIgnoreDuplicate(t *testing.T) {
+	if dialect := os.Getenv("GORM_DIALECT"); dialect == "mssql" || dialect == "foundation" {
+		return
+	}
+
+	DB.DropTableIfExists(&Subscription{})
+	DB.AutoMigrate(&Subscription{})
+
+	subscription := Subscription{UserId: 3, Topic: "news", Level: 1}
+	if result := DB.CreateIgnoreDuplicate(&subscription, "uix_subscriptions_user_topic"); result.Error != nil || result.RowsAffected != 1 || subscription.Id == 0 {
+		t.Errorf("new record should be inserted, but got %v, %v", result.Error, result.RowsAffected)
+	}
+
+	duplicate := Subscription{UserId: 3, Topic: "news", Level: 2}
+	if result := DB.CreateIgnoreDuplicate(&duplicate, "uix_subscriptions_user_topic"); result.Error != nil || result.RowsAffected != 0 {
+		t.Errorf("duplicated record should be ignored, but got %v, %v", result.Error, result.RowsAffected)
+	}
+
+	var count int
+	DB.Model(&Subscription{}).Where("user_id = ? AND level = ?", 3, 1).Count(&count)
+	if count != 1 {
+		t.Errorf("existing record should not be changed, but got count %v", count)
+	}
+}
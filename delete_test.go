@@ -66,3 +66,22 @@ func TestSoftDelete(t *testing.T) {
 		t.Errorf("Can't find permanently deleted record")
 	}
 }
+
+func TestDeleteUsing(t *testing.T) {
+	user1 := User{Name: "delete_using1", Emails: []Email{{Email: "delete_using1@example.com"}}}
+	user2 := User{Name: "delete_using2", Emails: []Email{{Email: "delete_using2@example.com"}}}
+	DB.Save(&user1).Save(&user2)
+
+	err := DB.DeleteUsing("users", "users.id = emails.user_id").Where("users.name = ?", user1.Name).Delete(&Email{}).Error
+	if err != nil {
+		t.Errorf("No error should happen when delete with joined table, but got %v", err)
+	}
+
+	if !DB.Where("email = ?", "delete_using1@example.com").First(&Email{}).RecordNotFound() {
+		t.Errorf("Email of matched user should be deleted")
+	}
+
+	if DB.Where("email = ?", "delete_using2@example.com").First(&Email{}).RecordNotFound() {
+		t.Errorf("Email of other users should not be deleted")
+	}
+}
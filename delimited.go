@@ -0,0 +1,109 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// slice fields tagged with `serializer:comma` are saved as comma separated values, commas and backslashes in elements are escaped with backslash, e.g:
+//
+//	Tags []string `gorm:"serializer:comma"` // a,b\,c
+const delimiter = ','
+
+func (scope *Scope) isDelimitedField(field *StructField) bool {
+	if field == nil || field.Struct.Type.Kind() != reflect.Slice {
+		return false
+	}
+	if serializer := ParseTagSetting(field.Tag)["SERIALIZER"]; strings.ToLower(serializer) != "comma" {
+		return false
+	}
+	kind := field.Struct.Type.Elem().Kind()
+	return kind == reflect.String || isIntKind(kind)
+}
+
+// delimitedVar join elements of delimited fields
+func (scope *Scope) delimitedVar(field *StructField, value interface{}) interface{} {
+	reflectValue := reflect.ValueOf(value)
+	if !scope.isDelimitedField(field) || reflectValue.Kind() != reflect.Slice {
+		return value
+	}
+
+	var elems []string
+	for i := 0; i < reflectValue.Len(); i++ {
+		elem := fmt.Sprint(reflectValue.Index(i).Interface())
+		elem = strings.Replace(elem, `\`, `\\`, -1)
+		elems = append(elems, strings.Replace(elem, string(delimiter), `\`+string(delimiter), -1))
+	}
+	return strings.Join(elems, string(delimiter))
+}
+
+func splitDelimited(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	var elems []string
+	var elem []byte
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value):
+			i++
+			elem = append(elem, value[i])
+		case value[i] == delimiter:
+			elems = append(elems, string(elem))
+			elem = elem[:0]
+		default:
+			elem = append(elem, value[i])
+		}
+	}
+	return append(elems, string(elem))
+}
+
+// delimitedScanner split comma separated values into slice fields
+type delimitedScanner struct {
+	field reflect.Value
+}
+
+func (s delimitedScanner) Scan(src interface{}) error {
+	var value string
+	switch v := src.(type) {
+	case nil:
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		return fmt.Errorf("can't scan %T into %v", src, s.field.Type())
+	}
+
+	elems := splitDelimited(value)
+	slice := reflect.MakeSlice(s.field.Type(), 0, len(elems))
+	elemType := s.field.Type().Elem()
+	for _, elem := range elems {
+		elemValue := reflect.New(elemType).Elem()
+		switch elemType.Kind() {
+		case reflect.String:
+			elemValue.SetString(elem)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := strconv.ParseInt(elem, 10, elemType.Bits())
+			if err != nil {
+				return fmt.Errorf("can't scan %q into %v: %v", elem, elemType, err)
+			}
+			elemValue.SetInt(n)
+		default:
+			n, err := strconv.ParseUint(elem, 10, elemType.Bits())
+			if err != nil {
+				return fmt.Errorf("can't scan %q into %v: %v", elem, elemType, err)
+			}
+			elemValue.SetUint(n)
+		}
+		slice = reflect.Append(slice, elemValue)
+	}
+	if len(elems) == 0 {
+		slice = reflect.Zero(s.field.Type())
+	}
+	s.field.Set(slice)
+	return nil
+}
@@ -0,0 +1,52 @@
+package gorm
+
+import (
+	"reflect"
+	"testing"
+)
+
+type delimitedTag struct {
+	Tags   []string `gorm:"serializer:comma"`
+	Ids    []int64  `gorm:"serializer:comma;size:1024"`
+	Scores []int
+}
+
+func TestDelimitedField(t *testing.T) {
+	db := newFakeDB("mysql", "")
+	scope := db.NewScope(&delimitedTag{})
+	tags, _ := scope.FieldByName("Tags")
+	ids, _ := scope.FieldByName("Ids")
+	scores, _ := scope.FieldByName("Scores")
+
+	if !scope.isDelimitedField(tags.StructField) || scope.isJsonField(tags.StructField) || scope.isDelimitedField(scores.StructField) {
+		t.Errorf("only slices tagged with serializer:comma should be delimited")
+	}
+	if tag := scope.generateSqlTag(ids.StructField); tag != "varchar(1024)" {
+		t.Errorf("delimited fields should be varchar, but got %v", tag)
+	}
+	if value := scope.columnVar(tags.StructField, []string{"a", `b,c`, `d\`}); value != `a,b\,c,d\\` {
+		t.Errorf("elements should be joined with escaped commas, but got %#v", value)
+	}
+	if value := scope.columnVar(ids.StructField, []int64{1, 2}); value != "1,2" {
+		t.Errorf("integers should be joined with commas, but got %#v", value)
+	}
+}
+
+func TestDelimitedScanner(t *testing.T) {
+	var tags []string
+	if err := (delimitedScanner{reflect.ValueOf(&tags).Elem()}).Scan([]byte(`a,b\,c,d\\,`)); err != nil || !reflect.DeepEqual(tags, []string{"a", "b,c", `d\`, ""}) {
+		t.Errorf("delimited values should be split, but got %#v, %v", tags, err)
+	}
+
+	var ids []int64
+	scanner := delimitedScanner{reflect.ValueOf(&ids).Elem()}
+	if err := scanner.Scan("1,2"); err != nil || !reflect.DeepEqual(ids, []int64{1, 2}) {
+		t.Errorf("delimited integers should be parsed, but got %#v, %v", ids, err)
+	}
+	if err := scanner.Scan(nil); err != nil || ids != nil {
+		t.Errorf("NULL should be scanned as nil slice, but got %#v, %v", ids, err)
+	}
+	if err := scanner.Scan("1,a"); err == nil {
+		t.Errorf("should return error for invalid integers")
+	}
+}
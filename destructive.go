@@ -0,0 +1,75 @@
+package gorm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AllowDestructive allow AutoMigrate to drop columns missing in models, and to narrow columns altered with AlterColumns, e.g.
+// shorten varchar or change bigint to int, which might lose data, they are refused by default, e.g:
+//
+//	db.AllowDestructive(true).AlterColumns(true).AutoMigrate(&User{})
+func (s *DB) AllowDestructive(allow bool) *DB {
+	return s.Set("gorm:allow_destructive", allow)
+}
+
+// DestructiveMigrationError is returned by AutoMigrate when it refuses destructive operations, other
+// operations are performed
+type DestructiveMigrationError struct {
+	Operations []string
+}
+
+func (err *DestructiveMigrationError) Error() string {
+	return fmt.Sprintf("refused destructive migrations, use AllowDestructive(true) to perform them: %v", strings.Join(err.Operations, "; "))
+}
+
+func (scope *Scope) allowDestructive() bool {
+	allow, ok := scope.Get("gorm:allow_destructive")
+	return ok && allow.(bool)
+}
+
+// ranks of types in the same family, a lower rank holds less data, types are normalized by parseColumnDefinition,
+// e.g. integer => int, real => double
+var columnTypeRanks = map[string][2]int{
+	"tinyint": {1, 1}, "smallint": {1, 2}, "mediumint": {1, 3}, "int": {1, 4}, "bigint": {1, 5},
+	"char": {2, 1}, "varchar": {2, 1}, "nchar": {2, 1}, "nvarchar": {2, 1}, "tinytext": {2, 2}, "text": {2, 3}, "ntext": {2, 3},
+	"mediumtext": {2, 4}, "longtext": {2, 5},
+	"float": {3, 1}, "double": {3, 2},
+	"tinyblob": {4, 1}, "blob": {4, 2}, "mediumblob": {4, 3}, "longblob": {4, 4},
+}
+
+// isNarrowingColumn check if changing the column from definition to definition might lose data, e.g. shorter varchar,
+// less decimal scale, smaller integer, changing signedness or to another family is narrowing too, e.g. varchar to int
+func isNarrowingColumn(from string, to string) bool {
+	fromColumn, toColumn := parseColumnDefinition(from), parseColumnDefinition(to)
+	if fromColumn.Type == "" || toColumn.Type == "" {
+		return fromColumn.Type != toColumn.Type
+	}
+
+	fromRank, fromRanked := columnTypeRanks[fromColumn.Type]
+	toRank, toRanked := columnTypeRanks[toColumn.Type]
+	if fromColumn.Type != toColumn.Type && (!fromRanked || !toRanked || fromRank[0] != toRank[0]) {
+		return true
+	}
+	if fromRank[1] != toRank[1] {
+		return toRank[1] < fromRank[1]
+	}
+	if fromColumn.Unsigned != toColumn.Unsigned && fromRank[0] == 1 {
+		return true
+	}
+	return isSmallerColumnSize(fromColumn.Size, toColumn.Size) || isSmallerColumnSize(fromColumn.Scale, toColumn.Scale)
+}
+
+// isSmallerColumnSize compare sizes declared by both columns, `max` of MSSQL is the largest size
+func isSmallerColumnSize(from string, to string) bool {
+	if from == "" || to == "" || from == to {
+		return false
+	}
+	if strings.EqualFold(from, "max") || strings.EqualFold(to, "max") {
+		return strings.EqualFold(from, "max")
+	}
+	fromSize, _ := strconv.Atoi(from)
+	toSize, _ := strconv.Atoi(to)
+	return toSize < fromSize
+}
@@ -0,0 +1,149 @@
+package gorm
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+func TestIsNarrowingColumn(t *testing.T) {
+	tests := []struct {
+		from, to  string
+		narrowing bool
+	}{
+		{"varchar(255)", "varchar(100) NOT NULL", true},
+		{"varchar(100) CHARACTER SET utf8", "varchar(255)", false},
+		{"varchar(255)", "text", false},
+		{"longtext", "varchar(255)", true},
+		{"bigint(20) NOT NULL", "int", true},
+		{"int(11)", "bigint", false},
+		{"int(11)", "int", false},
+		{"decimal(10,2)", "decimal(12,2)", false},
+		{"decimal(10,2)", "decimal(12,1)", true},
+		{"varchar(20)", "int", true},
+		{"double", "float", true},
+		{"tinyint(1)", "boolean NOT NULL", false},
+		{"real", "double precision", false},
+		{"double precision", "float", true},
+		{"character varying(100)", "varchar(50)", true},
+		{"integer", "bigint", false},
+		{"bigserial", "int", true},
+		{"int(10) unsigned", "int", true},
+		{"nvarchar(max)", "nvarchar(100)", true},
+		{"nvarchar(100)", "nvarchar(max)", false},
+	}
+
+	for _, test := range tests {
+		if narrowing := isNarrowingColumn(test.from, test.to); narrowing != test.narrowing {
+			t.Errorf("changing column from %v to %v should be narrowing %v, but got %v", test.from, test.to, test.narrowing, narrowing)
+		}
+	}
+}
+
+type destructiveUser struct {
+	Id   int64
+	Name string `sql:"size:100"`
+}
+
+func TestAutoMigrateRefusesDestructiveOperations(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	fakeResults = map[string][][]driver.Value{
+		"SELECT count(*)": {{int64(1)}},
+		"SELECT c.column_name": {
+			{"id", "bigint", nil, int64(19), int64(0), "NO", nil, nil, nil, "bigint(20)", int64(1), int64(1)},
+			{"name", "varchar", int64(255), nil, nil, "YES", nil, nil, nil, "varchar(255)", int64(0), int64(0)},
+			{"legacy", "varchar", int64(10), nil, nil, "YES", nil, nil, nil, "varchar(10)", int64(0), int64(0)},
+		},
+	}
+
+	fakeStatements = nil
+	err, ok := db.AlterColumns(true).AutoMigrate(&destructiveUser{}).Error.(*DestructiveMigrationError)
+	if !ok || len(err.Operations) != 2 || err.Operations[0] != "drop column destructive_users.legacy" ||
+		!strings.HasPrefix(err.Operations[1], "narrow column destructive_users.name from varchar(255) to varchar(100)") {
+		t.Errorf("destructive operations should be refused, but got %v", db.AlterColumns(true).AutoMigrate(&destructiveUser{}).Error)
+	}
+	for _, statement := range fakeStatements {
+		if strings.Contains(statement, "DROP COLUMN") || strings.Contains(statement, "MODIFY") {
+			t.Errorf("destructive operations should not be executed, but got %v", statement)
+		}
+	}
+	if _, ok := db.AlterColumns(true).Migrator().AutoMigrate(&destructiveUser{}).(*DestructiveMigrationError); !ok {
+		t.Errorf("destructive operations should be refused by Migrator too")
+	}
+
+	fakeStatements = nil
+	if err := db.AllowDestructive(true).AlterColumns(true).AutoMigrate(&destructiveUser{}).Error; err != nil {
+		t.Errorf("destructive operations should be allowed, but got %v", err)
+	}
+	if statements := strings.Join(fakeStatements, "\n"); !strings.Contains(statements, "DROP COLUMN `legacy`") || !strings.Contains(statements, "MODIFY `name` varchar(100)") {
+		t.Errorf("destructive operations should be executed, but got %v", statements)
+	}
+}
+
+func TestAutoMigrateAltersColumnsOnlyWhenEnabled(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	fakeResults = map[string][][]driver.Value{
+		"SELECT count(*)": {{int64(1)}},
+		"SELECT c.column_name": {
+			{"id", "bigint", nil, int64(19), int64(0), "NO", nil, nil, nil, "bigint(20)", int64(1), int64(1)},
+			{"name", "varchar", int64(50), nil, nil, "YES", nil, nil, nil, "varchar(50)", int64(0), int64(0)},
+		},
+	}
+
+	fakeStatements = nil
+	err, ok := db.AutoMigrate(&destructiveUser{}).Error.(*DifferingColumnsError)
+	if !ok || len(err.Columns) != 1 || err.Columns[0] != "destructive_users.name (size 50 => 100)" {
+		t.Errorf("differing columns should be returned, but got %v", db.AutoMigrate(&destructiveUser{}).Error)
+	}
+	for _, statement := range fakeStatements {
+		if strings.Contains(statement, "MODIFY") {
+			t.Errorf("columns should not be altered without AlterColumns, but got %v", statement)
+		}
+	}
+
+	fakeStatements = nil
+	if err := db.AlterColumns(true).AutoMigrate(&destructiveUser{}).Error; err != nil {
+		t.Errorf("columns should be altered, but got %v", err)
+	}
+	if statements := strings.Join(fakeStatements, "\n"); !strings.Contains(statements, "MODIFY `name` varchar(100)") || strings.Contains(statements, "MODIFY `id`") {
+		t.Errorf("only the widened column should be altered, but got %v", statements)
+	}
+}
+
+type externalAccount struct {
+	Id   int64
+	Name string `sql:"size:100"`
+}
+
+func (externalAccount) IgnoreMigrate() bool { return true }
+
+func TestAutoMigrateCantAlterColumnsOfSqlite3(t *testing.T) {
+	db := newFakeDB("sqlite3", "")
+
+	fakeResults = map[string][][]driver.Value{
+		"SELECT count(*)": {{int64(1)}},
+		"PRAGMA table_info": {
+			{int64(0), "id", "integer", int64(1), nil, int64(1)},
+			{int64(1), "name", "varchar(50)", int64(0), nil, int64(0)},
+		},
+	}
+
+	if err := db.AlterColumns(true).AutoMigrate(&destructiveUser{}).Error; err != AlterColumnNotSupported {
+		t.Errorf("sqlite3 should not alter columns, but got %v", err)
+	}
+}
+
+func TestAutoMigrateSkipsModelsIgnoringMigrate(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	fakeResults = map[string][][]driver.Value{"SELECT count(*)": {{int64(0)}}}
+	fakeStatements = nil
+	if err := db.AllowDestructive(true).AlterColumns(true).AutoMigrate(&externalAccount{}).Error; err != nil {
+		t.Errorf("models ignoring migrate should be skipped, but got %v", err)
+	}
+	if len(fakeStatements) != 0 {
+		t.Errorf("models ignoring migrate should not be migrated, but got %v", fakeStatements)
+	}
+}
@@ -1,42 +1,127 @@
 package gorm
 
 import (
+	"database/sql"
 	"fmt"
 	"reflect"
+	"time"
 )
 
+// PlaceholderStyle is the style of bind vars used by a database
+type PlaceholderStyle int
+
+const (
+	// QuestionPlaceholder is `?`, used by MySQL and SQLite
+	QuestionPlaceholder PlaceholderStyle = iota
+	// DollarPlaceholder is `$1`, `$2`..., used by PostgreSQL
+	DollarPlaceholder
+	// AtPlaceholder is `@p1`, `@p2`..., used by MSSQL
+	AtPlaceholder
+	// ColonPlaceholder is `:1`, `:2`..., used by Oracle
+	ColonPlaceholder
+)
+
+// questionPlaceholderMark is written for `?` placeholders while building sql, and replaced in Scope.Raw
+const questionPlaceholderMark = "$$"
+
+// BindVar return the placeholder of the i-th var, i starts from 1
+func (style PlaceholderStyle) BindVar(i int) string {
+	switch style {
+	case DollarPlaceholder:
+		return fmt.Sprintf("$%v", i)
+	case AtPlaceholder:
+		return fmt.Sprintf("@p%v", i)
+	case ColonPlaceholder:
+		return fmt.Sprintf(":%v", i)
+	}
+	return "?"
+}
+
 type Dialect interface {
-	BinVar(i int) string
+	PlaceholderStyle() PlaceholderStyle
 	SupportLastInsertId() bool
+	SupportInterval() bool
+	SupportArray() bool
+	SupportLargeObject() bool
+	SupportPartialIndex() bool
+	SupportMaterializedView() bool
+	SupportStoredFunction() bool
+	SupportOnUpdateTimestamp() bool
+	SupportAddForeignKey() bool
 	HasTop() bool
 	SqlTag(value reflect.Value, size int, autoIncrease bool) string
+	JsonSqlTag() string
+	CollationSql(charset string, collation string) string
+	ServerTimestampSql() string
 	ReturningStr(tableName, key string) string
 	SelectFromDummyTable() string
 	Quote(key string) string
+	IsReservedWord(word string) bool
 	HasTable(scope *Scope, tableName string) bool
+	EstimatedCount(scope *Scope, tableName string) (int64, bool)
+	ReplicationLag(replica *sql.DB) (time.Duration, bool)
+	PrepareTransactionSql(id string) string
+	CommitPreparedSql(id string) string
+	RollbackPreparedSql(id string) string
+	CreateSequenceSql(name string, start int64, increment int64) string
+	NextSequenceValSql(name string) string
+	SyncIdentitySql(quotedTableName string, column string) string
 	HasColumn(scope *Scope, tableName string, columnName string) bool
 	HasIndex(scope *Scope, tableName string, indexName string) bool
+	HasTrigger(scope *Scope, tableName string, triggerName string) bool
+	HasFunction(scope *Scope, functionName string) bool
+	DropTriggerSql(triggerName string, quotedTableName string) string
 	RemoveIndex(scope *Scope, indexName string)
 	IndexColumnMap(scope *Scope, tableName string, isUnique int) map[string][]string
 	Columns(scope *Scope, tableName string) map[string]string
+	TableInfos(scope *Scope) ([]string, error)
+	ColumnInfos(scope *Scope, tableName string) ([]ColumnInfo, error)
+	IndexInfos(scope *Scope, tableName string) ([]IndexInfo, error)
+	ForeignKeyInfos(scope *Scope, tableName string) ([]ForeignKeyInfo, error)
+	UpdateFromSql(tableName string, sets []string, fromTable string, on string, conditions string) string
+	ILikeSql(column string, value string) string
+	SubstringSql(column string, from string, length string) string
+	CallProcSql(name string, args []string) string
+	OutParamVar(i int) string
+	SelectOutParamsSql(variables []string) string
+	ExplainSql(sql string) string
+	CreateViewSql(view string, query string, replace bool) string
+	TableOptionsSql(engine string, charset string) string
+	MaxBindVars() int
+	AppendBlobSql(column string, value string) string
+	GeometryVar(wkt string) string
+	WithinDistanceSql(column string, point string, meters string) string
+	BoundingBoxSql(column string, polygon string) string
+	FullTextSearchSql(scope *Scope, columns []string, query string) string
+	FullTextIndexSql(indexName string, tableName string, columns []string) string
+	DeleteUsingSql(tableName string, usingTable string, on string, conditions string) string
+	UpsertSql(conflictColumns []string, updateColumns []string) string
+	ForUpdateSql() string
+	AlterColumnSql(quotedTableName string, quotedColumn string, definition string) []string
 }
 
 func NewDialect(driver string) Dialect {
-	var d Dialect
+	d, ok := supportedDialect(driver)
+	if !ok {
+		fmt.Printf("`%v` is not officially supported, running under compatibility mode.\n", driver)
+		d = &commonDialect{}
+	}
+	return d
+}
+
+// supportedDialect get the dialect of the driver, returns false if the driver isn't officially supported
+func supportedDialect(driver string) (Dialect, bool) {
 	switch driver {
-	case "postgres":
-		d = &postgres{}
+	case "postgres", "pgx":
+		return &postgres{}, true
 	case "foundation":
-		d = &foundation{}
+		return &foundation{}, true
 	case "mysql":
-		d = &mysql{}
+		return &mysql{}, true
 	case "sqlite3":
-		d = &sqlite3{}
+		return &sqlite3{}, true
 	case "mssql":
-		d = &mssql{}
-	default:
-		fmt.Printf("`%v` is not officially supported, running under compatibility mode.\n", driver)
-		d = &commonDialect{}
+		return &mssql{}, true
 	}
-	return d
+	return nil, false
 }
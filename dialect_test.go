@@ -0,0 +1,151 @@
+package gorm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlaceholderStyle(t *testing.T) {
+	cases := map[string]string{
+		"mysql":    "(name = ? AND age > ?)",
+		"sqlite3":  "(name = ? AND age > ?)",
+		"postgres": "(name = $1 AND age > $2)",
+		"mssql":    "(name = @p1 AND age > @p2)",
+	}
+
+	for dialect, expected := range cases {
+		db := newFakeDB(dialect, "")
+		scope := db.NewScope(nil)
+		scope.Raw(scope.buildWhereCondition(map[string]interface{}{"query": "name = ? AND age > ?", "args": []interface{}{"jinzhu", 18}}))
+		if scope.Sql != expected || len(scope.SqlVars) != 2 {
+			t.Errorf("sql for %v should be %v, but got %v", dialect, expected, scope.Sql)
+		}
+	}
+
+	if ColonPlaceholder.BindVar(3) != ":3" {
+		t.Errorf("colon placeholder should be :3, but got %v", ColonPlaceholder.BindVar(3))
+	}
+}
+
+func TestRawKeepsDollarQuotesForPositionalPlaceholders(t *testing.T) {
+	db := newFakeDB("postgres", "")
+	sql := "CREATE FUNCTION one() RETURNS int AS $$ SELECT 1 $$ LANGUAGE sql"
+	if scope := db.NewScope(nil).Raw(sql); scope.Sql != sql {
+		t.Errorf("dollar quotes should be kept for postgres, but got %v", scope.Sql)
+	}
+}
+
+type sequencedOrder struct {
+	Id     int64 `gorm:"primary_key;sequence:order_id_seq;sequence_start:1000;sequence_increment:10"`
+	Number int64 `gorm:"sequence"`
+}
+
+func TestSequence(t *testing.T) {
+	db := newFakeDB("postgres", "")
+	scope := db.NewScope(&sequencedOrder{})
+	id, _ := scope.FieldByName("Id")
+	number, _ := scope.FieldByName("Number")
+
+	seq, ok := scope.sequenceOf(id.StructField)
+	if !ok || seq != (sequence{Name: "order_id_seq", Start: 1000, Increment: 10}) {
+		t.Errorf("sequence should be parsed from tags, but got %+v", seq)
+	}
+	if seq, _ := scope.sequenceOf(number.StructField); seq.Name != "sequenced_orders_number_seq" || seq.Start != 1 || seq.Increment != 1 {
+		t.Errorf("sequence should have default name, start and increment, but got %+v", seq)
+	}
+
+	if sql := db.dialect.CreateSequenceSql(seq.Name, seq.Start, seq.Increment); sql != `CREATE SEQUENCE IF NOT EXISTS "order_id_seq" START WITH 1000 INCREMENT BY 10` {
+		t.Errorf("wrong create sequence sql, got %v", sql)
+	}
+	if sql := db.dialect.NextSequenceValSql(seq.Name); sql != `SELECT nextval('"order_id_seq"')` {
+		t.Errorf("wrong next sequence value sql, got %v", sql)
+	}
+	if tag := scope.generateSqlTag(id.StructField); tag != "bigint" {
+		t.Errorf("sequence primary key should not be serial, but got %v", tag)
+	}
+
+	db.dialect = &mysql{}
+	if _, ok := db.NewScope(&sequencedOrder{}).sequenceOf(id.StructField); ok {
+		t.Errorf("mysql doesn't support sequences")
+	}
+}
+
+func TestBlobChunkSql(t *testing.T) {
+	cases := []struct {
+		dialect   Dialect
+		substring string
+		append    string
+	}{
+		{&mysql{}, "SUBSTRING(data, ?, ?)", ""},
+		{&postgres{}, "SUBSTRING(data FROM $1 FOR $2)", ""},
+		{&sqlite3{}, "substr(data, ?, ?)", ""},
+		{&mssql{}, "SUBSTRING(data, @p1, @p2)", "data.WRITE(@p1, NULL, NULL)"},
+	}
+
+	for _, c := range cases {
+		from, length := c.dialect.PlaceholderStyle().BindVar(1), c.dialect.PlaceholderStyle().BindVar(2)
+		if sql := c.dialect.SubstringSql("data", from, length); sql != c.substring {
+			t.Errorf("substring sql for %T should be %v, but got %v", c.dialect, c.substring, sql)
+		}
+		if sql := c.dialect.AppendBlobSql("data", from); sql != c.append {
+			t.Errorf("append blob sql for %T should be %v, but got %v", c.dialect, c.append, sql)
+		}
+	}
+}
+
+func TestCallProcSql(t *testing.T) {
+	if sql := (&mysql{}).CallProcSql("sp_stats", []string{"?", "@gorm_out_2"}); sql != "CALL sp_stats(?, @gorm_out_2)" {
+		t.Errorf("wrong call sql for mysql, got %v", sql)
+	}
+	if (&mysql{}).OutParamVar(2) != "@gorm_out_2" || (&postgres{}).OutParamVar(2) != "" {
+		t.Errorf("only mysql should pass OUT parameters with session variables")
+	}
+	if sql := (&mssql{}).CallProcSql("sp_stats", []string{"@p1"}); sql != "sp_stats" {
+		t.Errorf("mssql should call procedure with RPC, but got %v", sql)
+	}
+}
+
+func TestCreateViewSql(t *testing.T) {
+	cases := map[Dialect]string{
+		&mysql{}:    "CREATE OR REPLACE VIEW v AS SELECT 1",
+		&postgres{}: "CREATE OR REPLACE VIEW v AS SELECT 1",
+		&mssql{}:    "CREATE OR ALTER VIEW v AS SELECT 1",
+		&sqlite3{}:  "DROP VIEW IF EXISTS v; CREATE VIEW v AS SELECT 1",
+	}
+	for dialect, expected := range cases {
+		if sql := dialect.CreateViewSql("v", "SELECT 1", true); sql != expected {
+			t.Errorf("wrong sql to replace view for %T, got %v", dialect, sql)
+		}
+		if sql := dialect.CreateViewSql("v", "SELECT 1", false); sql != "CREATE VIEW v AS SELECT 1" {
+			t.Errorf("wrong sql to create view for %T, got %v", dialect, sql)
+		}
+	}
+}
+
+func TestDropTriggerSql(t *testing.T) {
+	if sql := (&mysql{}).DropTriggerSql("trg", "orders"); sql != "DROP TRIGGER IF EXISTS trg" {
+		t.Errorf("wrong sql to drop trigger for mysql, got %v", sql)
+	}
+	if sql := (&postgres{}).DropTriggerSql("trg", "orders"); sql != "DROP TRIGGER IF EXISTS trg ON orders" {
+		t.Errorf("triggers should be dropped with their tables for postgres, got %v", sql)
+	}
+}
+
+func TestAlterColumnSql(t *testing.T) {
+	cases := map[Dialect]string{
+		&mysql{}:    "ALTER TABLE users MODIFY name varchar(100) NOT NULL DEFAULT 'a'",
+		&postgres{}: "ALTER TABLE users ALTER COLUMN name TYPE varchar(100) USING name::varchar(100); ALTER TABLE users ALTER COLUMN name SET NOT NULL; ALTER TABLE users ALTER COLUMN name SET DEFAULT 'a'",
+		&mssql{}:    "ALTER TABLE users ALTER COLUMN name varchar(100) NOT NULL",
+		&sqlite3{}:  "",
+	}
+	for dialect, expected := range cases {
+		if sql := strings.Join(dialect.AlterColumnSql("users", "name", "varchar(100) NOT NULL DEFAULT 'a' "), "; "); sql != expected {
+			t.Errorf("wrong sql to alter column for %T, got %v", dialect, sql)
+		}
+	}
+
+	expected := "ALTER TABLE users ALTER COLUMN id TYPE bigint USING id::bigint; ALTER TABLE users ALTER COLUMN id SET NOT NULL"
+	if sql := strings.Join((&postgres{}).AlterColumnSql("users", "id", "bigserial"), "; "); sql != expected {
+		t.Errorf("sequences of serial columns should be kept, got %v", sql)
+	}
+}
@@ -0,0 +1,199 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// fakeStatements are statements executed by fakeConn, with BEGIN, COMMIT and ROLLBACK of transactions
+var fakeStatements []string
+
+// fakeQueries are queries received by fakeConn
+var fakeQueries []string
+
+// fakeResults are rows returned by fakeConn for queries starting with the keys, the longest key matches
+var fakeResults = map[string][][]driver.Value{}
+
+// fakeColumns are names of columns of fakeResults with the same keys, columns are unnamed if not set
+var fakeColumns = map[string][]string{}
+
+// fakeDeadlines are timeouts of contexts of statements and queries received by fakeConn, 0 if there is no deadline
+var fakeDeadlines = map[string]time.Duration{}
+
+// fakeOpenFailures is the count of connecting to fail with the `unreachable` option, fakeOpens counts connecting
+var fakeOpenFailures, fakeOpens int
+
+// fakeDriver is the database driver `gorm_fake_test` of unit tests, it is configured by options of the data source
+// name separated by commas:
+//
+//	transactions: transactions could be started, they are recorded into fakeStatements
+//	identity: every statement inserts a row with id 99
+//	upserted: every statement updates a conflicting row as MySQL reports, 2 affected rows with the last insert id 99
+//	unreachable: connecting fails fakeOpenFailures times
+//
+// other words of the data source name only tell databases apart, statements with the argument "fail" fail
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	conn := fakeConn{}
+	for _, option := range strings.Split(name, ",") {
+		switch option {
+		case "transactions":
+			conn.transactions = true
+		case "identity":
+			conn.identity = true
+		case "upserted":
+			conn.upserted = true
+		case "unreachable":
+			fakeOpens++
+			if fakeOpens <= fakeOpenFailures {
+				return nil, errors.New("dial tcp: lookup db: no such host")
+			}
+		}
+	}
+	return conn, nil
+}
+
+type fakeConn struct {
+	transactions bool
+	identity     bool
+	upserted     bool
+}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (fakeConn) Close() error {
+	return nil
+}
+
+func (conn fakeConn) Begin() (driver.Tx, error) {
+	if !conn.transactions {
+		return nil, errors.New("not supported")
+	}
+	fakeStatements = append(fakeStatements, "BEGIN")
+	return conn, nil
+}
+
+func (fakeConn) Commit() error {
+	fakeStatements = append(fakeStatements, "COMMIT")
+	return nil
+}
+
+func (fakeConn) Rollback() error {
+	fakeStatements = append(fakeStatements, "ROLLBACK")
+	return nil
+}
+
+func recordDeadline(ctx context.Context, query string) {
+	if deadline, ok := ctx.Deadline(); ok {
+		fakeDeadlines[query] = time.Until(deadline)
+	} else {
+		fakeDeadlines[query] = 0
+	}
+}
+
+func (conn fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	recordDeadline(ctx, query)
+	fakeStatements = append(fakeStatements, query)
+	for _, arg := range args {
+		if arg.Value == "fail" {
+			return nil, errors.New("failed")
+		}
+	}
+	if conn.identity {
+		return identityResult{}, nil
+	}
+	if conn.upserted {
+		return upsertedResult{}, nil
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	recordDeadline(ctx, query)
+	fakeQueries = append(fakeQueries, query)
+	matched := ""
+	for prefix := range fakeResults {
+		if strings.HasPrefix(query, prefix) && len(prefix) >= len(matched) {
+			matched = prefix
+		}
+	}
+	if values, ok := fakeResults[matched]; ok {
+		return &fakeRows{columns: fakeColumns[matched], values: values}, nil
+	}
+	return &fakeRows{}, nil
+}
+
+// identityResult report a row inserted with id 99
+type identityResult struct{}
+
+func (identityResult) LastInsertId() (int64, error) {
+	return 99, nil
+}
+
+func (identityResult) RowsAffected() (int64, error) {
+	return 1, nil
+}
+
+// upsertedResult report a conflicting row updated by MySQL upserting, the last insert id isn't its id
+type upsertedResult struct{}
+
+func (upsertedResult) LastInsertId() (int64, error) {
+	return 99, nil
+}
+
+func (upsertedResult) RowsAffected() (int64, error) {
+	return 2, nil
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (rows *fakeRows) Columns() []string {
+	if rows.columns != nil {
+		return rows.columns
+	}
+	if len(rows.values) == 0 {
+		return []string{"name"}
+	}
+	return make([]string, len(rows.values[0]))
+}
+
+func (rows *fakeRows) Close() error {
+	return nil
+}
+
+func (rows *fakeRows) Next(dest []driver.Value) error {
+	if len(rows.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, rows.values[0])
+	rows.values = rows.values[1:]
+	return nil
+}
+
+func init() {
+	sql.Register("gorm_fake_test", fakeDriver{})
+}
+
+// newFakeDB open a DB of the dialect on a fresh `gorm_fake_test` database with options of the data source name, sql
+// is logged
+func newFakeDB(dialect string, options string) *DB {
+	fakeStatements, fakeQueries = nil, nil
+	fakeResults, fakeColumns = map[string][][]driver.Value{}, map[string][]string{}
+
+	sqlDB, _ := sql.Open("gorm_fake_test", options)
+	db := newDB(dialect, "gorm:gorm@/gorm", sqlDB)
+	db.parent = &db
+	db.logMode = 1
+	return &db
+}
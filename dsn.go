@@ -0,0 +1,147 @@
+package gorm
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Config build the dialect and DSN to open a database with OpenConfig
+type Config interface {
+	Dialect() string
+	DSN() string
+}
+
+// MysqlConfig build DSN for the mysql driver, e.g:
+//
+//	gorm.MysqlConfig{User: "gorm", Password: "p@ss:word", Host: "localhost", DBName: "gorm", Params: map[string]string{"parseTime": "True"}}.DSN()
+//	//// gorm:p@ss:word@tcp(localhost:3306)/gorm?parseTime=True
+type MysqlConfig struct {
+	User     string
+	Password string
+	// Net is `tcp` by default, set it to `unix` to connect with Socket
+	Net    string
+	Host   string
+	Port   int
+	Socket string
+	DBName string
+	Params map[string]string
+	// SessionStatements are executed on every new connection, e.g. `SET time_zone = '+00:00'`
+	SessionStatements []string
+}
+
+func (MysqlConfig) Dialect() string {
+	return "mysql"
+}
+
+func (config MysqlConfig) sessionStatements() []string {
+	return config.SessionStatements
+}
+
+func (config MysqlConfig) DSN() string {
+	var dsn string
+	if config.User != "" {
+		dsn = config.User
+		if config.Password != "" {
+			dsn += ":" + config.Password
+		}
+		dsn += "@"
+	}
+
+	if config.Net == "unix" {
+		dsn += fmt.Sprintf("unix(%v)", config.Socket)
+	} else {
+		network, host, port := config.Net, config.Host, config.Port
+		if network == "" {
+			network = "tcp"
+		}
+		if host == "" {
+			host = "127.0.0.1"
+		}
+		if port == 0 {
+			port = 3306
+		}
+		dsn += fmt.Sprintf("%v(%v)", network, net.JoinHostPort(host, strconv.Itoa(port)))
+	}
+
+	dsn += "/" + url.PathEscape(config.DBName)
+	if len(config.Params) > 0 {
+		dsn += "?" + encodeParams(config.Params)
+	}
+	return dsn
+}
+
+// PostgresConfig build DSN in URL format for postgres drivers, lib/pq and pgx both accept it
+type PostgresConfig struct {
+	User     string
+	Password string
+	Host     string
+	Port     int
+	DBName   string
+	// SSLMode is `disable`, `require`, `verify-ca` or `verify-full`, it is left to the driver if blank
+	SSLMode string
+	Params  map[string]string
+	// SessionStatements are executed on every new connection, e.g. `SET application_name = 'billing'`
+	SessionStatements []string
+}
+
+func (PostgresConfig) Dialect() string {
+	return "postgres"
+}
+
+func (config PostgresConfig) sessionStatements() []string {
+	return config.SessionStatements
+}
+
+func (config PostgresConfig) DSN() string {
+	dsn := url.URL{Scheme: "postgres", Host: config.Host, Path: "/" + config.DBName}
+	if config.Port != 0 {
+		dsn.Host = net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+	}
+	if config.User != "" {
+		if config.Password != "" {
+			dsn.User = url.UserPassword(config.User, config.Password)
+		} else {
+			dsn.User = url.User(config.User)
+		}
+	}
+
+	params := map[string]string{}
+	for key, value := range config.Params {
+		params[key] = value
+	}
+	if config.SSLMode != "" {
+		params["sslmode"] = config.SSLMode
+	}
+	dsn.RawQuery = encodeParams(params)
+	return dsn.String()
+}
+
+// encodeParams escape params' values and sort them by key, so the DSN is stable
+func encodeParams(params map[string]string) string {
+	var keys, pairs []string
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		pairs = append(pairs, url.QueryEscape(key)+"="+url.QueryEscape(params[key]))
+	}
+	return strings.Join(pairs, "&")
+}
+
+// OpenConfig open database with the config's dialect and DSN, e.g:
+//
+//	db, err := gorm.OpenConfig(gorm.PostgresConfig{User: "gorm", Host: "localhost", DBName: "gorm", SSLMode: "disable"})
+func OpenConfig(config Config) (DB, error) {
+	var statements []string
+	if session, ok := config.(interface {
+		sessionStatements() []string
+	}); ok {
+		statements = session.sessionStatements()
+	}
+	return OpenWithSession(config.Dialect(), config.DSN(), statements...)
+}
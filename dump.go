@@ -0,0 +1,123 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DumpSchema generate DDL of models for the dialect without connecting to database, including sequences, tables,
+// join tables, history tables, indexes and foreign keys of belongs to relationships and join tables, so the
+// canonical schema could be committed into the repository, e.g:
+//
+//	schema, err := gorm.DumpSchema("mysql", &User{}, &Email{}, &Order{})
+//	ioutil.WriteFile("schema.sql", []byte(schema), 0644)
+func DumpSchema(dialect string, models ...interface{}) (string, error) {
+	if _, ok := supportedDialect(dialect); !ok {
+		return "", fmt.Errorf("can't dump schema for dialect %v, it isn't supported", dialect)
+	}
+	db := newDB(dialect, "", nil)
+	db.logMode = 1
+
+	var statements []string
+	joinTables := map[string]bool{}
+	for _, model := range models {
+		scope := db.NewScope(model)
+		if scope.GetModelStruct().IsView {
+			continue
+		}
+		statements = append(statements, scope.schemaSql(joinTables)...)
+		if scope.HasError() {
+			return "", scope.db.Error
+		}
+	}
+
+	var schema string
+	for _, statement := range statements {
+		schema += strings.TrimSuffix(strings.TrimSpace(statement), ";") + ";\n"
+	}
+	return schema, nil
+}
+
+// schemaSql get sql to create current model's table with everything created by AutoMigrate, and its foreign keys,
+// join tables already in joinTables are skipped, e.g. dumped with the other side of many to many relationships
+func (scope *Scope) schemaSql(joinTables map[string]bool) (statements []string) {
+	for _, field := range scope.GetStructFields() {
+		if seq, ok := scope.sequenceOf(field); ok && field.IsNormal {
+			if sql := scope.Dialect().CreateSequenceSql(seq.Name, seq.Start, seq.Increment); sql != "" {
+				statements = append(statements, sql)
+			}
+		}
+	}
+
+	statements = append(statements, scope.createTableSql())
+	var newJoinTables []*StructField
+	for _, field := range scope.GetStructFields() {
+		if relationship := field.Relationship; relationship != nil && relationship.JoinTableHandler != nil {
+			if joinTable := relationship.JoinTableHandler.Table(scope.db); !joinTables[joinTable] {
+				joinTables[joinTable] = true
+				newJoinTables = append(newJoinTables, field)
+			}
+		}
+	}
+	for _, field := range newJoinTables {
+		if sql := scope.joinTableSql(field); sql != "" {
+			statements = append(statements, sql)
+		}
+	}
+	if scope.isVersioned() {
+		statements = append(statements, scope.historyTableSql())
+	}
+
+	indexes, uniqueIndexes, fullTextIndexes := scope.tagIndexes()
+	for _, name := range sortedIndexNames(indexes) {
+		statements = append(statements, scope.indexSql(Index{Name: name, Parts: indexes[name]}))
+	}
+	for _, name := range sortedIndexNames(uniqueIndexes) {
+		statements = append(statements, scope.indexSql(Index{Name: name, Parts: uniqueIndexes[name], Unique: true}))
+	}
+	for _, name := range sortedIndexNames(fullTextIndexes) {
+		if sql := scope.Dialect().FullTextIndexSql(name, scope.QuotedTableName(), scope.quoteColumns(fullTextIndexes[name])); sql != "" {
+			statements = append(statements, sql)
+		}
+	}
+	for _, index := range scope.modelIndexes() {
+		if index.Where != "" && !scope.Dialect().SupportPartialIndex() {
+			scope.Err(PartialIndexNotSupported)
+			return
+		}
+		statements = append(statements, scope.indexSql(index))
+	}
+
+	for _, field := range scope.GetStructFields() {
+		if relationship := field.Relationship; relationship != nil && relationship.Kind == "belongs_to" {
+			fieldType := field.Struct.Type
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			toScope := scope.New(reflect.New(fieldType).Interface())
+			keyName := fmt.Sprintf("%s_%s_foreign", scope.TableName(), relationship.ForeignDBName)
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %v ADD CONSTRAINT %v FOREIGN KEY (%v) REFERENCES %v(%v)",
+				scope.QuotedTableName(), keyName, scope.Quote(relationship.ForeignDBName), toScope.QuotedTableName(), scope.Quote(toScope.PrimaryKey())))
+		}
+	}
+	// foreign keys of join tables are created with them if they can't be added later
+	if scope.Dialect().SupportAddForeignKey() {
+		for _, field := range newJoinTables {
+			for _, foreignKey := range scope.joinTableForeignKeys(field) {
+				statements = append(statements, fmt.Sprintf("ALTER TABLE %v ADD %v", scope.Quote(field.Relationship.JoinTableHandler.Table(scope.db)), foreignKey.Sql))
+			}
+		}
+	}
+	return
+}
+
+func sortedIndexNames(indexes map[string][]string) []string {
+	var names []string
+	for name := range indexes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,127 @@
+package gorm
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type dumpCompany struct {
+	Id   int64
+	Name string `sql:"size:100;unique_index"`
+}
+
+type dumpEmployee struct {
+	Id        int64
+	Email     string `sql:"size:100;index:idx_dump_employees_email"`
+	CompanyId int64
+	Company   dumpCompany
+	DeletedAt *time.Time
+}
+
+func (dumpEmployee) Indexes() []Index {
+	return []Index{{Name: "uix_dump_employees_lower_email", Parts: []string{"lower(email)"}, Unique: true, Where: "deleted_at IS NULL"}}
+}
+
+func TestDumpSchema(t *testing.T) {
+	schema, err := DumpSchema("postgres", &dumpCompany{}, &dumpEmployee{})
+	if err != nil {
+		t.Fatalf("failed to dump schema, got %v", err)
+	}
+
+	expected := []string{
+		`CREATE TABLE "dump_companies"`,
+		`CREATE UNIQUE INDEX uix_dump_companies_name ON "dump_companies"("name");`,
+		`CREATE TABLE "dump_employees"`,
+		`CREATE INDEX idx_dump_employees_email ON "dump_employees"("email");`,
+		`CREATE UNIQUE INDEX uix_dump_employees_lower_email ON "dump_employees"((lower(email))) WHERE deleted_at IS NULL;`,
+		`ALTER TABLE "dump_employees" ADD CONSTRAINT dump_employees_company_id_foreign FOREIGN KEY ("company_id") REFERENCES "dump_companies"("id");`,
+	}
+	position := 0
+	for _, statement := range expected {
+		index := strings.Index(schema[position:], statement)
+		if index < 0 {
+			t.Fatalf("schema should contain %v in order, but got %v", statement, schema)
+		}
+		position += index + len(statement)
+	}
+
+	if strings.Contains(schema, "ENGINE=") {
+		t.Errorf("table options of mysql should not be dumped for postgres, but got %v", schema)
+	}
+	if schema, err := DumpSchema("mysql", &dumpCompany{}); err != nil || !strings.Contains(schema, ") ENGINE=InnoDB DEFAULT CHARSET=utf8;") {
+		t.Errorf("tables of mysql should be dumped with engine and charset, but got %v, %v", schema, err)
+	}
+
+	if _, err := DumpSchema("mysql", &dumpEmployee{}); err != PartialIndexNotSupported {
+		t.Errorf("partial index should not be dumped for mysql, but got %v", err)
+	}
+
+	if _, err := DumpSchema("oracle", &dumpCompany{}); err == nil {
+		t.Errorf("schema should not be dumped for unknown dialects")
+	}
+}
+
+type dumpSkill struct {
+	Id   int64
+	Name string
+}
+
+type dumpMember struct {
+	Id      int64
+	Skills  []dumpSkill `gorm:"many2many:dump_member_skills;constraint:OnDelete:CASCADE"`
+	Mentors []dumpSkill `gorm:"many2many:dump_member_mentors;jointable_key:unique;constraint:-"`
+}
+
+func TestDumpJoinTableKeys(t *testing.T) {
+	schema, err := DumpSchema("postgres", &dumpSkill{}, &dumpMember{})
+	if err != nil {
+		t.Fatalf("failed to dump schema, got %v", err)
+	}
+
+	for _, statement := range []string{
+		`CREATE TABLE "dump_member_skills" ("dump_member_id" bigint,"dump_skill_id" bigint,PRIMARY KEY ("dump_member_id","dump_skill_id"));`,
+		`ALTER TABLE "dump_member_skills" ADD CONSTRAINT dump_member_skills_dump_member_id_foreign FOREIGN KEY ("dump_member_id") REFERENCES "dump_members"("id") ON DELETE CASCADE;`,
+		`ALTER TABLE "dump_member_skills" ADD CONSTRAINT dump_member_skills_dump_skill_id_foreign FOREIGN KEY ("dump_skill_id") REFERENCES "dump_skills"("id") ON DELETE CASCADE;`,
+		`CREATE TABLE "dump_member_mentors" ("dump_member_id" bigint,"dump_skill_id" bigint,UNIQUE ("dump_member_id","dump_skill_id"));`,
+	} {
+		if !strings.Contains(schema, statement) {
+			t.Errorf("schema should contain %v, but got %v", statement, schema)
+		}
+	}
+	if strings.Contains(schema, "dump_member_mentors_dump_member_id_foreign") {
+		t.Errorf("foreign keys should not be created with constraint:-, but got %v", schema)
+	}
+
+	schema, _ = DumpSchema("sqlite3", &dumpMember{})
+	if expected := "PRIMARY KEY (\"dump_member_id\",\"dump_skill_id\"),CONSTRAINT dump_member_skills_dump_member_id_foreign FOREIGN KEY"; !strings.Contains(schema, expected) {
+		t.Errorf("sqlite3 should create foreign keys with join tables, but got %v", schema)
+	}
+}
+
+type dumpProject struct {
+	Id         int64
+	Developers []dumpDeveloper `gorm:"many2many:dump_project_developers"`
+}
+
+type dumpDeveloper struct {
+	Id       int64
+	Projects []dumpProject `gorm:"many2many:dump_project_developers"`
+}
+
+func TestDumpJoinTableOnce(t *testing.T) {
+	schema, err := DumpSchema("postgres", &dumpProject{}, &dumpDeveloper{})
+	if err != nil {
+		t.Fatalf("failed to dump schema, got %v", err)
+	}
+
+	for _, statement := range []string{
+		`CREATE TABLE "dump_project_developers"`,
+		`ADD CONSTRAINT dump_project_developers_dump_project_id_foreign`,
+		`ADD CONSTRAINT dump_project_developers_dump_developer_id_foreign`,
+	} {
+		if count := strings.Count(schema, statement); count != 1 {
+			t.Errorf("join table should be dumped once with both sides, but got %v %v times in %v", statement, count, schema)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// time.Duration fields are saved as integers of nanoseconds, the unit could be changed with tag,
+// or use `interval` for dialects supporting interval columns, e.g:
+//
+//	Timeout time.Duration `gorm:"duration:ms"`
+//	Elapsed time.Duration `gorm:"duration:interval"`
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+// durationSetting return the unit of duration fields, unit is 0 for interval columns
+func (scope *Scope) durationSetting(field *StructField) (unit time.Duration, ok bool) {
+	if field == nil || field.Struct.Type != durationType {
+		return 0, false
+	}
+
+	value := strings.ToLower(ParseTagSetting(field.Tag)["DURATION"])
+	if value == "interval" && scope.Dialect().SupportInterval() {
+		return 0, true
+	}
+	if unit, ok := durationUnits[value]; ok {
+		return unit, true
+	}
+	return time.Nanosecond, true
+}
+
+// durationVar convert durations to the stored form of field
+func (scope *Scope) durationVar(field *StructField, value interface{}) interface{} {
+	duration, isDuration := value.(time.Duration)
+	unit, ok := scope.durationSetting(field)
+	if !isDuration || !ok {
+		return value
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%d microseconds", duration/time.Microsecond)
+	}
+	return int64(duration / unit)
+}
+
+// durationScanner scan integers of the unit or intervals into duration fields
+type durationScanner struct {
+	field reflect.Value
+	unit  time.Duration
+}
+
+func (s durationScanner) Scan(src interface{}) error {
+	var duration time.Duration
+	switch v := src.(type) {
+	case nil:
+	case int64:
+		duration = time.Duration(v) * s.unit
+	case float64:
+		duration = time.Duration(v * float64(s.unit))
+	case []byte:
+		return s.Scan(string(v))
+	case string:
+		if s.unit == 0 {
+			var err error
+			if duration, err = parseInterval(v); err != nil {
+				return err
+			}
+		} else {
+			value, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return fmt.Errorf("can't scan %q into duration: %v", v, err)
+			}
+			duration = time.Duration(value) * s.unit
+		}
+	default:
+		return fmt.Errorf("can't scan %T into duration", src)
+	}
+	s.field.SetInt(int64(duration))
+	return nil
+}
+
+var intervalRegexp = regexp.MustCompile(`^(?:(-?\d+) years? ?)?(?:(-?\d+) mons? ?)?(?:(-?\d+) days? ?)?(?:([+-])?(\d+):(\d+):(\d+(?:\.\d+)?))?$`)
+
+// parseInterval parse intervals in postgres output style, like `1 day 02:03:04.5`, months are 30 days and years are 365 days
+func parseInterval(value string) (time.Duration, error) {
+	matches := intervalRegexp.FindStringSubmatch(strings.TrimSpace(value))
+	if matches == nil {
+		return 0, fmt.Errorf("can't parse interval %q", value)
+	}
+
+	var duration time.Duration
+	for i, unit := range []time.Duration{365 * 24 * time.Hour, 30 * 24 * time.Hour, 24 * time.Hour} {
+		if matches[i+1] != "" {
+			n, _ := strconv.ParseInt(matches[i+1], 10, 64)
+			duration += time.Duration(n) * unit
+		}
+	}
+
+	if matches[5] != "" {
+		hours, _ := strconv.ParseInt(matches[5], 10, 64)
+		minutes, _ := strconv.ParseInt(matches[6], 10, 64)
+		seconds, _ := strconv.ParseFloat(matches[7], 64)
+		clock := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds*float64(time.Second))
+		if matches[4] == "-" {
+			clock = -clock
+		}
+		duration += clock
+	}
+	return duration, nil
+}
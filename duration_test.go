@@ -0,0 +1,70 @@
+package gorm
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type durationTag struct {
+	Timeout time.Duration `gorm:"duration:ms"`
+	Elapsed time.Duration `gorm:"duration:interval"`
+	Wait    time.Duration
+}
+
+func TestDurationVar(t *testing.T) {
+	db := newFakeDB("mysql", "")
+	scope := db.NewScope(&durationTag{})
+	timeout, _ := scope.FieldByName("Timeout")
+	elapsed, _ := scope.FieldByName("Elapsed")
+	wait, _ := scope.FieldByName("Wait")
+
+	if value := scope.durationVar(timeout.StructField, 1500*time.Millisecond); value != int64(1500) {
+		t.Errorf("duration should be saved in milliseconds, but got %#v", value)
+	}
+	if value := scope.durationVar(wait.StructField, time.Second); value != int64(time.Second) {
+		t.Errorf("duration should be saved in nanoseconds by default, but got %#v", value)
+	}
+	if value := scope.durationVar(elapsed.StructField, time.Second); value != int64(time.Second) {
+		t.Errorf("interval should fall back to nanoseconds for mysql, but got %#v", value)
+	}
+	if tag := scope.generateSqlTag(elapsed.StructField); tag != "bigint" {
+		t.Errorf("interval should fall back to bigint for mysql, but got %v", tag)
+	}
+
+	db.dialect = &postgres{}
+	if value := scope.durationVar(elapsed.StructField, 90*time.Minute); value != "5400000000 microseconds" {
+		t.Errorf("duration should be saved as interval for postgres, but got %#v", value)
+	}
+	if tag := scope.generateSqlTag(elapsed.StructField); tag != "interval" {
+		t.Errorf("duration should be interval column for postgres, but got %v", tag)
+	}
+}
+
+func TestDurationScanner(t *testing.T) {
+	var duration time.Duration
+	field := reflect.ValueOf(&duration).Elem()
+
+	if err := (durationScanner{field, time.Millisecond}).Scan([]byte("1500")); err != nil || duration != 1500*time.Millisecond {
+		t.Errorf("should scan milliseconds, but got %v, %v", duration, err)
+	}
+
+	cases := map[string]time.Duration{
+		"01:30:00":                    90 * time.Minute,
+		"1 day 02:03:04.5":            26*time.Hour + 3*time.Minute + 4500*time.Millisecond,
+		"-00:00:01":                   -time.Second,
+		"1 year 2 mons 3 days":        (365 + 60 + 3) * 24 * time.Hour,
+		"2 days":                      48 * time.Hour,
+		"00:00:00.000001":             time.Microsecond,
+		"1 year 1 mon 1 day 01:01:01": (365+30+1)*24*time.Hour + time.Hour + time.Minute + time.Second,
+	}
+	for interval, expected := range cases {
+		if err := (durationScanner{field, 0}).Scan(interval); err != nil || duration != expected {
+			t.Errorf("interval %v should be scanned as %v, but got %v, %v", interval, expected, duration, err)
+		}
+	}
+
+	if _, err := parseInterval("P1D"); err == nil {
+		t.Errorf("should return error for unsupported interval style")
+	}
+}
@@ -1,11 +1,122 @@
 package gorm
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
 
 var (
-	RecordNotFound       = errors.New("record not found")
-	InvalidSql           = errors.New("invalid sql")
-	NoNewAttrs           = errors.New("no new attributes")
-	NoValidTransaction   = errors.New("no valid transaction")
-	CantStartTransaction = errors.New("can't start transaction")
+	RecordNotFound           = errors.New("record not found")
+	InvalidSql               = errors.New("invalid sql")
+	NoNewAttrs               = errors.New("no new attributes")
+	NoValidTransaction       = errors.New("no valid transaction")
+	CantStartTransaction     = errors.New("can't start transaction")
+	TwoPhaseNotSupported     = errors.New("two-phase commit is not supported by the dialect")
+	LargeObjectNotSupported  = errors.New("large object is not supported by the dialect")
+	BlobStreamNotSupported   = errors.New("writing blobs in chunks is not supported by the dialect")
+	ExplainNotSupported      = errors.New("explain is not supported by the dialect")
+	PartialIndexNotSupported = errors.New("partial index is not supported by the dialect")
+	PointNotSupported        = errors.New("geographic point is not supported by the dialect")
+	TooManyRows              = errors.New("too many rows")
+	BlankCondition           = errors.New("blank condition")
+	ViewNotWritable          = errors.New("view is not writable")
+
+	MaterializedViewNotSupported = errors.New("materialized view is not supported by the dialect")
+	StoredFunctionNotSupported   = errors.New("stored function is not supported by the dialect")
+	UpsertNotSupported           = errors.New("upsert is not supported by the dialect")
+	LockNotSupported             = errors.New("locking records is not supported by the dialect")
+	LockWithoutTransaction       = errors.New("locking records requires a transaction, call it on the db returned by Begin")
+	AlterColumnNotSupported      = errors.New("altering columns is not supported by the dialect")
+	ZeroForeignKey               = errors.New("refused to save associations of the record without primary key, their foreign keys would be zero")
+	NullForeignKeyNotAllowed     = errors.New("removing has one or has many associations sets foreign keys of removed records to NULL, use AllowNullForeignKeys(true) or the orphan tag to allow it")
+	OrphanedAssociations         = errors.New("refused to remove associated records, the orphan policy of the relationship is error")
+
+	UniqueViolation     = errors.New("unique violation")
+	ForeignKeyViolation = errors.New("foreign key violation")
+	NotNullViolation    = errors.New("not null violation")
+	CheckViolation      = errors.New("check violation")
 )
+
+var sqlStateErrors = map[string]error{
+	"23505": UniqueViolation,
+	"23503": ForeignKeyViolation,
+	"23502": NotNullViolation,
+	"23514": CheckViolation,
+}
+
+// DatabaseError is a driver's error translated with its SQLSTATE code, so it could be checked without
+// importing the driver, e.g: errors.Is(db.Error, gorm.UniqueViolation)
+type DatabaseError struct {
+	Kind error
+	Code string
+	Err  error
+}
+
+func (err *DatabaseError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *DatabaseError) Unwrap() error {
+	return err.Err
+}
+
+func (err *DatabaseError) Is(target error) bool {
+	return err.Kind == target
+}
+
+// translateError translate errors of pgx and lib/pq into DatabaseError if their codes are known
+func translateError(err error) error {
+	var code string
+	switch e := err.(type) {
+	case interface{ SQLState() string }:
+		code = e.SQLState()
+	case interface{ Get(byte) string }:
+		code = e.Get('C')
+	}
+
+	if kind, ok := sqlStateErrors[code]; ok {
+		return &DatabaseError{Kind: kind, Code: code, Err: err}
+	}
+	return err
+}
+
+// CallbackPanic is returned as error when a callback panics, Stack is the stack trace of the panic
+type CallbackPanic struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (err *CallbackPanic) Error() string {
+	return fmt.Sprintf("callback panic: %v\n%s", err.Value, err.Stack)
+}
+
+// UnmappedColumns is returned by queries with StrictScan when some queried columns don't have matching fields
+type UnmappedColumns struct {
+	Type    reflect.Type
+	Columns []string
+}
+
+func (err *UnmappedColumns) Error() string {
+	return fmt.Sprintf("columns %v don't have matching fields in %v", strings.Join(err.Columns, ", "), err.Type)
+}
+
+// BatchError is returned by batch writes with BatchChunkTransactions when a chunk fails, chunks before it are committed
+type BatchError struct {
+	Chunk        int   // index of the failed chunk
+	Row          int   // index of the failed record in the slice, -1 if it is unknown, e.g. a chunk inserted with one statement
+	RowsAffected int64 // rows affected by committed chunks
+	Err          error
+}
+
+func (err *BatchError) Error() string {
+	if err.Row < 0 {
+		return fmt.Sprintf("batch chunk %v failed: %v", err.Chunk, err.Err)
+	}
+	return fmt.Sprintf("batch chunk %v failed at row %v: %v", err.Chunk, err.Row, err.Err)
+}
+
+func (err *BatchError) Unwrap() error {
+	return err.Err
+}
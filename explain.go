@@ -0,0 +1,147 @@
+package gorm
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SlowQueryPolicy decide which queries are logged as slow, and whether their plans are logged too
+type SlowQueryPolicy struct {
+	// Threshold queries taking longer than Threshold are logged with level `slow`, 0 disables it
+	Threshold time.Duration
+	// Explain log plans of slow SELECT queries with level `explain`, they are explained on a replica if there is any
+	Explain bool
+}
+
+// SetSlowQueryPolicy log queries slower than the threshold even if sql logs are disabled, e.g:
+//
+//	db.SetSlowQueryPolicy(gorm.SlowQueryPolicy{Threshold: time.Second, Explain: true})
+func (s *DB) SetSlowQueryPolicy(policy SlowQueryPolicy) {
+	s.parent.slowQueryPolicy = &policy
+}
+
+// Explain get the query plan of current chain with the dialect's EXPLAIN, out could be *[]string to get
+// rows of the plan as text, or structs and slices having fields of the plan's columns, e.g:
+//
+//	var plan []string
+//	db.Model(&User{}).Where("name = ?", "jinzhu").Explain(&plan)
+//	//// EXPLAIN SELECT * FROM users WHERE (name = 'jinzhu')
+func (s *DB) Explain(out interface{}, where ...interface{}) *DB {
+	return s.clone().NewScope(s.Value).inlineCondition(where...).explain(out).db
+}
+
+func (scope *Scope) explain(out interface{}) *Scope {
+	defer scope.Trace(NowFunc())
+	scope.callCallbacks(scope.db.parent.callback.rowQueries)
+	scope.prepareQuerySql()
+	if scope.HasError() {
+		return scope
+	}
+
+	explainSql := scope.Dialect().ExplainSql(scope.Sql)
+	if explainSql == "" {
+		scope.Err(ExplainNotSupported)
+		return scope
+	}
+	scope.Raw(explainSql)
+
+	rows, err := scope.SqlDB().Query(scope.Sql, scope.SqlVars...)
+	if scope.Err(err) != nil {
+		return scope
+	}
+	defer rows.Close()
+
+	if lines, ok := out.(*[]string); ok {
+		*lines, err = explainLines(rows)
+		scope.Err(err)
+	} else {
+		scope.scanRows(rows, reflect.Indirect(reflect.ValueOf(out)))
+		scope.Err(rows.Err())
+	}
+	return scope
+}
+
+// explainLines format rows of a plan as text, columns are separated by tabs, NULLs are blank
+func explainLines(rows *sql.Rows) ([]string, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for rows.Next() {
+		values := make([]sql.RawBytes, len(columns))
+		dests := make([]interface{}, len(columns))
+		for i := range values {
+			dests[i] = &values[i]
+		}
+		if err := rows.Scan(dests...); err != nil {
+			return lines, err
+		}
+
+		cells := make([]string, len(values))
+		for i, value := range values {
+			cells[i] = string(value)
+		}
+		lines = append(lines, strings.Join(cells, "\t"))
+	}
+	return lines, rows.Err()
+}
+
+// traceSlowQuery log the query if it is slower than the threshold of SlowQueryPolicy, with its plan if required
+func (scope *Scope) traceSlowQuery(elapsed time.Duration) {
+	policy := scope.db.parent.slowQueryPolicy
+	if policy == nil || policy.Threshold <= 0 || elapsed < policy.Threshold || scope.db.logMode == 1 {
+		return
+	}
+	scope.db.print("slow", fileWithLineNum(), elapsed, scope.Sql, maskSensitiveVars(scope.SqlVars))
+
+	if !policy.Explain || !isSelectSql(scope.Sql) {
+		return
+	}
+	explainSql := scope.Dialect().ExplainSql(scope.Sql)
+	if explainSql == "" {
+		return
+	}
+
+	// explain out of current transaction, so it won't interfere with rows being read
+	var db *sql.DB
+	if replicas := scope.db.parent.replicas; replicas != nil && len(replicas.replicas) > 0 {
+		db = replicas.choose(scope.Dialect())
+	}
+	if db == nil {
+		db, _ = scope.db.parent.db.(*sql.DB)
+	}
+	if db == nil {
+		return
+	}
+
+	rows, err := db.Query(explainSql, scope.SqlVars...)
+	if err != nil {
+		scope.db.print("explain", fileWithLineNum(), err)
+		return
+	}
+	defer rows.Close()
+	lines, err := explainLines(rows)
+	if err != nil {
+		scope.db.print("explain", fileWithLineNum(), err)
+		return
+	}
+	scope.db.print("explain", fileWithLineNum(), fmt.Sprintf("plan of %v\n%v", scope.Sql, strings.Join(lines, "\n")))
+}
+
+// isSelectSql check sql is a SELECT, leading comments like labels are skipped
+func isSelectSql(sql string) bool {
+	sql = strings.TrimSpace(sql)
+	for strings.HasPrefix(sql, "/*") {
+		end := strings.Index(sql, "*/")
+		if end < 0 {
+			return false
+		}
+		sql = strings.TrimSpace(sql[end+2:])
+	}
+	return len(sql) >= 6 && strings.EqualFold(sql[:6], "SELECT")
+}
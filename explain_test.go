@@ -0,0 +1,63 @@
+package gorm
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExplainSql(t *testing.T) {
+	if sql := (&mysql{}).ExplainSql("SELECT 1"); sql != "EXPLAIN SELECT 1" {
+		t.Errorf("wrong explain sql for mysql, got %v", sql)
+	}
+	if sql := (&sqlite3{}).ExplainSql("SELECT 1"); sql != "EXPLAIN QUERY PLAN SELECT 1" {
+		t.Errorf("wrong explain sql for sqlite3, got %v", sql)
+	}
+	if sql := (&mssql{}).ExplainSql("SELECT 1"); sql != "" {
+		t.Errorf("mssql can't explain with a query, got %v", sql)
+	}
+}
+
+func TestIsSelectSql(t *testing.T) {
+	for sql, expected := range map[string]bool{
+		"SELECT * FROM users":                             true,
+		" select 1":                                       true,
+		"/* label:report */ SELECT * FROM users":          true,
+		"/* label:report */ UPDATE users SET name = 'a'":  false,
+		"INSERT INTO users (name) SELECT name FROM users": false,
+		"/* unclosed SELECT":                              false,
+	} {
+		if isSelectSql(sql) != expected {
+			t.Errorf("isSelectSql(%q) should be %v", sql, expected)
+		}
+	}
+}
+
+func TestTraceSlowQuery(t *testing.T) {
+	var buf bytes.Buffer
+	db := newFakeDB("mysql", "")
+	db.logMode, db.logger = 0, Logger{log.New(&buf, "", 0)}
+	db.SetSlowQueryPolicy(SlowQueryPolicy{Threshold: time.Second})
+
+	scope := db.NewScope(nil)
+	scope.Raw("SELECT * FROM users WHERE name = " + scope.AddToVars("jinzhu"))
+
+	scope.traceSlowQuery(time.Millisecond)
+	if buf.Len() != 0 {
+		t.Errorf("fast queries should not be logged, but got %v", buf.String())
+	}
+
+	scope.traceSlowQuery(2 * time.Second)
+	if output := buf.String(); !strings.Contains(output, "SELECT * FROM users WHERE name = 'jinzhu'") || !strings.Contains(output, "[2000.00ms]") {
+		t.Errorf("slow queries should be logged, but got %v", output)
+	}
+
+	buf.Reset()
+	db.SetSlowQueryPolicy(SlowQueryPolicy{})
+	scope.traceSlowQuery(2 * time.Second)
+	if buf.Len() != 0 {
+		t.Errorf("slow query log should be disabled with blank threshold, but got %v", buf.String())
+	}
+}
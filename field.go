@@ -42,7 +42,7 @@ func (field *Field) Set(value interface{}) error {
 			reflectValue = reflect.ValueOf(value)
 		}
 
-		if reflectValue.Kind() == reflect.Struct && (field.Field.Kind() == reflect.Int64 || field.Field.Kind() == reflect.Uint64) {
+		if reflectValue.Kind() == reflect.Struct && isIntKind(field.Field.Kind()) {
 			if t, ok := reflectValue.Interface().(time.Time); ok {
 				reflectValue = reflect.ValueOf(t.Unix())
 			}
@@ -58,6 +58,17 @@ func (field *Field) Set(value interface{}) error {
 	return nil
 }
 
+// fieldVar get field's value to be saved
+func (scope *Scope) fieldVar(field *Field) interface{} {
+	return scope.columnVar(field.StructField, field.Field.Interface())
+}
+
+// columnVar convert value of the field to its stored form, like durations, delimited values and JSON,
+// values of sensitive fields are wrapped to be masked in logs
+func (scope *Scope) columnVar(field *StructField, value interface{}) interface{} {
+	return sensitiveVar(field, scope.jsonVar(field, scope.delimitedVar(field, scope.durationVar(field, value))))
+}
+
 // Fields get value's fields
 func (scope *Scope) Fields() map[string]*Field {
 	if scope.fields == nil {
@@ -10,8 +10,8 @@ type foundation struct {
 	commonDialect
 }
 
-func (foundation) BinVar(i int) string {
-	return fmt.Sprintf("$%v", i)
+func (foundation) PlaceholderStyle() PlaceholderStyle {
+	return DollarPlaceholder
 }
 
 func (foundation) SupportLastInsertId() bool {
@@ -43,6 +43,9 @@ func (foundation) SqlTag(value reflect.Value, size int, autoIncrease bool) strin
 		if _, ok := value.Interface().(time.Time); ok {
 			return "datetime"
 		}
+		if _, ok := value.Interface().(Point); ok {
+			return ""
+		}
 	default:
 		if _, ok := value.Interface().([]byte); ok {
 			return "blob"
@@ -51,10 +54,40 @@ func (foundation) SqlTag(value reflect.Value, size int, autoIncrease bool) strin
 	panic(fmt.Sprintf("invalid sql type %s (%s) for foundation", value.Type().Name(), value.Kind().String()))
 }
 
+func (foundation) JsonSqlTag() string {
+	return "clob"
+}
+
+func (foundation) UpsertSql(conflictColumns []string, updateColumns []string) string {
+	return ""
+}
+
+func (foundation) AlterColumnSql(quotedTableName string, quotedColumn string, definition string) []string {
+	return nil
+}
+
 func (f foundation) ReturningStr(tableName, key string) string {
 	return fmt.Sprintf("RETURNING %v.%v", f.Quote(tableName), key)
 }
 
+func (foundation) TableInfos(scope *Scope) ([]string, error) {
+	return informationSchemaTables(scope, "table_type = 'TABLE' AND table_schema = current_schema")
+}
+
+func (foundation) ColumnInfos(scope *Scope, tableName string) ([]ColumnInfo, error) {
+	return informationSchemaColumns(scope, tableName, "", "", "c.table_schema = current_schema")
+}
+
+func (foundation) IndexInfos(scope *Scope, tableName string) ([]IndexInfo, error) {
+	rows, err := scope.NewDB().Raw(`SELECT i.index_name, c.column_name, i.is_unique = 'YES', i.index_type = 'PRIMARY' FROM INFORMATION_SCHEMA.indexes i
+	JOIN INFORMATION_SCHEMA.index_columns c ON c.index_table_schema = i.table_schema AND c.index_table_name = i.table_name AND c.index_name = i.index_name
+	WHERE i.table_schema = current_schema AND i.table_name = ? ORDER BY i.index_name, c.ordinal_position`, tableName).Rows()
+	if err != nil {
+		return nil, err
+	}
+	return scanIndexInfos(rows)
+}
+
 func (foundation) HasTable(scope *Scope, tableName string) bool {
 	var count int
 	scope.NewDB().Raw("SELECT count(*) FROM INFORMATION_SCHEMA.tables WHERE table_schema = current_schema AND table_type = 'TABLE' AND table_name = ?", tableName).Row().Scan(&count)
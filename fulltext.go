@@ -0,0 +1,11 @@
+package gorm
+
+// fullTextSearch is the condition added by FullTextSearch, columns could be indexed with tag `fulltext`
+type fullTextSearch struct {
+	columns []string
+	query   string
+}
+
+func (f *fullTextSearch) toSql(scope *Scope) string {
+	return scope.Dialect().FullTextSearchSql(scope, scope.quoteColumns(f.columns), f.query)
+}
@@ -0,0 +1,42 @@
+package gorm_test
+
+import (
+	"os"
+	"testing"
+)
+
+type Article struct {
+	Id    int64
+	Title string `sql:"fulltext"`
+	Body  string `sql:"type:text;fulltext"`
+}
+
+func TestFullTextSearch(t *testing.T) {
+	DB.DropTableIfExists(&Article{})
+	if err := DB.AutoMigrate(&Article{}).Error; err != nil {
+		t.Errorf("No error should happen when migrate full-text index, but got %v", err)
+	}
+
+	// only mysql and postgres have full-text indexes, others search with LIKE
+	dialect := os.Getenv("GORM_DIALECT")
+	hasFullTextIndex := dialect == "mysql" || dialect == "postgres"
+
+	scope := DB.NewScope(&Article{})
+	if hasFullTextIndex && !scope.Dialect().HasIndex(scope, scope.TableName(), "ftx_articles") {
+		t.Errorf("Article should have full-text index ftx_articles")
+	}
+
+	DB.Save(&Article{Title: "gorm release", Body: "window functions and unions"}).
+		Save(&Article{Title: "weekly notes", Body: "nothing about databases"})
+
+	var articles []Article
+	DB.FullTextSearch([]string{"title", "body"}, "unions").Find(&articles)
+	if len(articles) != 1 || articles[0].Title != "gorm release" {
+		t.Errorf("Should find articles matching the query, but got %+v", articles)
+	}
+
+	DB.AutoMigrate(&Article{})
+	if hasFullTextIndex && !scope.Dialect().HasIndex(scope, scope.TableName(), "ftx_articles") {
+		t.Errorf("Full-text index should be kept when migrate again")
+	}
+}
@@ -0,0 +1,280 @@
+package gorm
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+// GenerateModels introspect tables of current database and generate go source of model structs with sql tags of
+// types, sizes, not null, defaults, primary keys and indexes, belongs to relationships are inferred from foreign keys
+// referencing generated tables, all tables are generated if no table given, e.g:
+//
+//	source, err := db.GenerateModels("models", "users", "emails")
+//	ioutil.WriteFile("models/models.go", []byte(source), 0644)
+func (s *DB) GenerateModels(pkg string, tableNames ...string) (string, error) {
+	if len(tableNames) == 0 {
+		tables, err := s.Migrator().Tables()
+		if err != nil {
+			return "", err
+		}
+		tableNames = tables
+	}
+
+	var models []generatedModel
+	for _, tableName := range tableNames {
+		model := generatedModel{TableName: tableName}
+		var err error
+		if model.Columns, err = s.Migrator().Columns(tableName); err != nil {
+			return "", err
+		}
+		if model.Indexes, err = s.Migrator().Indexes(tableName); err != nil {
+			return "", err
+		}
+		if model.ForeignKeys, err = s.Migrator().ForeignKeys(tableName); err != nil {
+			return "", err
+		}
+		models = append(models, model)
+	}
+	return generateModelsSource(pkg, models)
+}
+
+// generatedModel is the live schema of a table to generate model struct
+type generatedModel struct {
+	TableName   string
+	Columns     []ColumnInfo
+	Indexes     []IndexInfo
+	ForeignKeys []ForeignKeyInfo
+}
+
+func generateModelsSource(pkg string, models []generatedModel) (string, error) {
+	primaryKeys := map[string]string{}
+	for _, model := range models {
+		var keys []string
+		for _, column := range model.Columns {
+			if column.PrimaryKey {
+				keys = append(keys, column.Name)
+			}
+		}
+		if len(keys) == 1 {
+			primaryKeys[model.TableName] = keys[0]
+		}
+	}
+
+	var body bytes.Buffer
+	var importTime bool
+	for _, model := range models {
+		source, usesTime := model.source(primaryKeys)
+		body.WriteString(source)
+		importTime = importTime || usesTime
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "package %v\n\n", pkg)
+	if importTime {
+		buf.WriteString("import \"time\"\n\n")
+	}
+	buf.Write(body.Bytes())
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", err
+	}
+	return string(source), nil
+}
+
+// source generate model struct of the table, primaryKeys are single column primary keys of generated tables
+func (model generatedModel) source(primaryKeys map[string]string) (source string, usesTime bool) {
+	structName := generatedStructName(model.TableName)
+	settings := map[string][]string{}
+	for _, index := range model.Indexes {
+		if index.Primary || !hasColumns(index.Columns) {
+			continue
+		}
+		key := "index"
+		if index.Unique {
+			key = "unique_index"
+		}
+		for i, column := range index.Columns {
+			if len(index.Columns) == 1 {
+				settings[column] = append(settings[column], fmt.Sprintf("%v:%v", key, index.Name))
+			} else {
+				settings[column] = append(settings[column], fmt.Sprintf("%v:%v[%v]", key, index.Name, i))
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	fieldNames := map[string]bool{}
+	fmt.Fprintf(&buf, "// %v is generated from table %v\ntype %v struct {\n", structName, model.TableName, structName)
+	for _, column := range model.Columns {
+		fieldName := generatedFieldName(column.Name)
+		fieldNames[fieldName] = true
+
+		fieldType, sqlType := generatedFieldType(column)
+		if fieldType == "time.Time" || fieldType == "time.Duration" {
+			usesTime = true
+		}
+		if column.Nullable && !column.PrimaryKey && fieldType != "[]byte" {
+			fieldType = "*" + fieldType
+		}
+
+		var tags []string
+		if ToDBName(fieldName) != column.Name {
+			tags = append(tags, "column:"+column.Name)
+		}
+		if column.PrimaryKey && primaryKeys[model.TableName] != "id" {
+			tags = append(tags, "primary_key")
+		}
+		if fieldType == "time.Duration" {
+			tags = append(tags, "duration:interval")
+		}
+		if sqlType != "" {
+			tags = append(tags, "type:"+sqlType)
+		} else if strings.HasSuffix(fieldType, "string") && column.Size > 0 {
+			tags = append(tags, fmt.Sprintf("size:%v", column.Size))
+		}
+		if !column.Nullable && !column.PrimaryKey {
+			tags = append(tags, "not null")
+		}
+		if column.Default.Valid && !column.PrimaryKey && !strings.ContainsAny(column.Default.String, "\";`") {
+			tags = append(tags, "default:"+column.Default.String)
+		}
+		tags = append(tags, settings[column.Name]...)
+
+		if len(tags) > 0 {
+			fmt.Fprintf(&buf, "\t%v %v `sql:\"%v\"`\n", fieldName, fieldType, strings.Join(tags, ";"))
+		} else {
+			fmt.Fprintf(&buf, "\t%v %v\n", fieldName, fieldType)
+		}
+	}
+
+	var relationships []string
+	for _, foreignKey := range model.ForeignKeys {
+		referencedKey, ok := primaryKeys[foreignKey.ReferencedTable]
+		if !ok || (foreignKey.ReferencedColumn != "" && foreignKey.ReferencedColumn != referencedKey) {
+			continue
+		}
+
+		foreignFieldName := generatedFieldName(foreignKey.Column)
+		fieldName := strings.TrimSuffix(foreignFieldName, "Id")
+		if fieldName == foreignFieldName || fieldName == "" {
+			fieldName = generatedStructName(foreignKey.ReferencedTable)
+		}
+		if fieldNames[fieldName] {
+			continue
+		}
+		fieldNames[fieldName] = true
+
+		if foreignFieldName == fieldName+"Id" {
+			relationships = append(relationships, fmt.Sprintf("\t%v %v\n", fieldName, generatedStructName(foreignKey.ReferencedTable)))
+		} else {
+			relationships = append(relationships, fmt.Sprintf("\t%v %v `gorm:\"foreignkey:%v\"`\n", fieldName, generatedStructName(foreignKey.ReferencedTable), foreignFieldName))
+		}
+	}
+	sort.Strings(relationships)
+	if len(relationships) > 0 {
+		buf.WriteString("\n")
+		for _, relationship := range relationships {
+			buf.WriteString(relationship)
+		}
+	}
+	buf.WriteString("}\n\n")
+
+	if pluralTableName(ToDBName(structName)) != model.TableName {
+		fmt.Fprintf(&buf, "func (%v) TableName() string {\n\treturn %q\n}\n\n", structName, model.TableName)
+	}
+	return buf.String(), usesTime
+}
+
+func hasColumns(columns []string) bool {
+	for _, column := range columns {
+		if column == "" {
+			return false
+		}
+	}
+	return len(columns) > 0
+}
+
+// generatedFieldName camel case the column name, e.g. user_id => UserId
+func generatedFieldName(name string) string {
+	var fieldName string
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == ' ' || r == '-' }) {
+		fieldName += strings.Title(strings.ToLower(part))
+	}
+	if fieldName == "" || fieldName[0] < 'A' || fieldName[0] > 'Z' {
+		fieldName = "F" + fieldName
+	}
+	return fieldName
+}
+
+// generatedStructName singularize and camel case the table name, e.g. user_addresses => UserAddress
+func generatedStructName(tableName string) string {
+	name := strings.ToLower(tableName)
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		name = strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"), strings.HasSuffix(name, "xes"):
+		name = strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss"):
+		name = strings.TrimSuffix(name, "s")
+	}
+	return generatedFieldName(name)
+}
+
+// generatedFieldType get go type of the column, and sql type if the go type can't keep it, e.g. text, decimal(10,2),
+// types are matched by names normalized by parseColumnDefinition, e.g. integer => int, boolean => tinyint(1)
+func generatedFieldType(column ColumnInfo) (fieldType string, sqlType string) {
+	columnType := strings.ToLower(column.ColumnType)
+	if columnType == "" {
+		columnType = strings.ToLower(column.Type)
+	}
+	definition := parseColumnDefinition(columnType)
+
+	if definition.Unsigned {
+		switch definition.Type {
+		case "tinyint":
+			return "uint8", "tinyint unsigned"
+		case "smallint":
+			return "uint16", "smallint unsigned"
+		case "mediumint", "int":
+			return "uint32", definition.Type + " unsigned"
+		case "bigint":
+			return "uint64", ""
+		}
+	}
+
+	switch definition.Type {
+	case "interval":
+		return "time.Duration", ""
+	case "tinyint":
+		if definition.Size == "1" {
+			return "bool", ""
+		}
+		return "int8", ""
+	case "bit":
+		if definition.Size == "" || definition.Size == "1" {
+			return "bool", ""
+		}
+		return "[]byte", columnType
+	case "smallint":
+		return "int16", ""
+	case "mediumint", "int":
+		return "int", ""
+	case "bigint":
+		return "int64", ""
+	case "decimal", "money":
+		return "float64", columnType
+	case "float", "double":
+		return "float64", ""
+	case "char", "varchar", "nchar", "nvarchar":
+		return "string", ""
+	case "date", "datetime", "datetime2", "datetimeoffset", "smalldatetime", "timestamp", "timestamptz":
+		return "time.Time", ""
+	case "tinyblob", "blob", "mediumblob", "longblob", "binary", "varbinary", "bytea", "image":
+		return "[]byte", ""
+	}
+	return "string", columnType
+}
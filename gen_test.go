@@ -0,0 +1,151 @@
+package gorm
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+func TestGenerateModelsSource(t *testing.T) {
+	models := []generatedModel{
+		{
+			TableName: "users",
+			Columns: []ColumnInfo{
+				{Name: "id", Type: "bigint", PrimaryKey: true},
+				{Name: "name", Type: "varchar", Size: 100},
+				{Name: "bio", Type: "text", Nullable: true},
+				{Name: "birthday", Type: "datetime", Nullable: true},
+			},
+			Indexes: []IndexInfo{
+				{Name: "PRIMARY", Columns: []string{"id"}, Unique: true, Primary: true},
+				{Name: "uix_users_name", Columns: []string{"name"}, Unique: true},
+				{Name: "idx_users_lower_name", Columns: []string{""}},
+			},
+		},
+		{
+			TableName: "addresses",
+			Columns: []ColumnInfo{
+				{Name: "code", Type: "char", Size: 8, PrimaryKey: true},
+				{Name: "user_id", Type: "bigint"},
+				{Name: "city", Type: "varchar", Size: 50, Default: sql.NullString{String: "'Shanghai'", Valid: true}},
+			},
+			Indexes: []IndexInfo{
+				{Name: "idx_addresses_user_city", Columns: []string{"user_id", "city"}},
+			},
+			ForeignKeys: []ForeignKeyInfo{
+				{Name: "addresses_user_id_foreign", Column: "user_id", ReferencedTable: "users", ReferencedColumn: "id"},
+				{Name: "addresses_city_foreign", Column: "city", ReferencedTable: "cities", ReferencedColumn: "name"},
+			},
+		},
+		{
+			TableName: "person",
+			Columns:   []ColumnInfo{{Name: "id", Type: "integer", PrimaryKey: true}, {Name: "avatar", Type: "bytea", Nullable: true}},
+		},
+	}
+
+	source, err := generateModelsSource("models", models)
+	if err != nil {
+		t.Fatalf("should generate models source, but got %v", err)
+	}
+
+	for _, expected := range []string{
+		"package models\n\nimport \"time\"\n",
+		"type User struct {",
+		"\tId       int64\n",
+		"\tName     string  `sql:\"size:100;not null;unique_index:uix_users_name\"`",
+		"\tBio      *string `sql:\"type:text\"`",
+		"\tBirthday *time.Time\n",
+		"type Address struct {",
+		"\tCode   string `sql:\"primary_key;size:8\"`",
+		"\tUserId int64  `sql:\"not null;index:idx_addresses_user_city[0]\"`",
+		"\tCity   string `sql:\"size:50;not null;default:'Shanghai';index:idx_addresses_user_city[1]\"`",
+		"\n\tUser User\n}",
+		"type Person struct {",
+		"\tAvatar []byte\n",
+		"func (Person) TableName() string {\n\treturn \"person\"\n}",
+	} {
+		if !strings.Contains(source, expected) {
+			t.Errorf("generated source should contain %q, but got\n%v", expected, source)
+		}
+	}
+
+	for _, unexpected := range []string{"idx_users_lower_name", "City City", "func (User) TableName", "func (Address) TableName"} {
+		if strings.Contains(source, unexpected) {
+			t.Errorf("generated source should not contain %q, but got\n%v", unexpected, source)
+		}
+	}
+}
+
+func TestGenerateModelsForeignKeyField(t *testing.T) {
+	models := []generatedModel{
+		{TableName: "users", Columns: []ColumnInfo{{Name: "id", Type: "int", PrimaryKey: true}}},
+		{
+			TableName:   "posts",
+			Columns:     []ColumnInfo{{Name: "id", Type: "int", PrimaryKey: true}, {Name: "author", Type: "int"}},
+			ForeignKeys: []ForeignKeyInfo{{Column: "author", ReferencedTable: "users"}},
+		},
+	}
+
+	source, err := generateModelsSource("models", models)
+	if err != nil || !strings.Contains(source, "User User `gorm:\"foreignkey:Author\"`") {
+		t.Errorf("should name relationships after referenced tables with foreign key tags, but got\n%v, %v", source, err)
+	}
+}
+
+func TestGenerateModels(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	fakeResults = map[string][][]driver.Value{
+		"SELECT table_name": {{"emails"}},
+		"SELECT c.column_name": {
+			{"id", "bigint", nil, int64(19), int64(0), "NO", nil, nil, nil, "bigint(20)", int64(1), int64(1)},
+			{"email", "varchar", int64(100), nil, nil, "NO", nil, "utf8mb4", "utf8mb4_general_ci", "varchar(100)", int64(0), int64(0)},
+		},
+		"SELECT CONSTRAINT_NAME": {{"emails_user_id_foreign", "user_id", "users", "id"}},
+	}
+
+	source, err := db.GenerateModels("models")
+	if err != nil {
+		t.Fatalf("should generate models, but got %v", err)
+	}
+	if !strings.Contains(source, "type Email struct {\n\tId    int64\n\tEmail string `sql:\"size:100;not null\"`\n}") {
+		t.Errorf("should generate models of all tables, relationships to tables not generated are skipped, but got\n%v", source)
+	}
+}
+
+func TestGeneratedFieldType(t *testing.T) {
+	cases := []struct {
+		column    ColumnInfo
+		fieldType string
+		sqlType   string
+	}{
+		{ColumnInfo{Type: "tinyint", ColumnType: "tinyint(1)"}, "bool", ""},
+		{ColumnInfo{Type: "tinyint", ColumnType: "tinyint(4)"}, "int8", ""},
+		{ColumnInfo{Type: "tinyint", ColumnType: "tinyint(3) unsigned"}, "uint8", "tinyint unsigned"},
+		{ColumnInfo{Type: "int", ColumnType: "int(10) unsigned"}, "uint32", "int unsigned"},
+		{ColumnInfo{Type: "bigint", ColumnType: "bigint(20) unsigned"}, "uint64", ""},
+		{ColumnInfo{Type: "integer", ColumnType: "integer"}, "int", ""},
+		{ColumnInfo{Type: "boolean", ColumnType: "boolean"}, "bool", ""},
+		{ColumnInfo{Type: "bit"}, "bool", ""},
+		{ColumnInfo{Type: "interval", ColumnType: "interval"}, "time.Duration", ""},
+		{ColumnInfo{Type: "point", ColumnType: "point"}, "string", "point"},
+		{ColumnInfo{Type: "decimal", ColumnType: "decimal(10,2)"}, "float64", "decimal(10,2)"},
+		{ColumnInfo{Type: "character varying", ColumnType: "character varying(100)"}, "string", ""},
+		{ColumnInfo{Type: "timestamp with time zone", ColumnType: "timestamp with time zone"}, "time.Time", ""},
+	}
+
+	for _, c := range cases {
+		if fieldType, sqlType := generatedFieldType(c.column); fieldType != c.fieldType || sqlType != c.sqlType {
+			t.Errorf("column %+v should be generated as %v (%v), but got %v (%v)", c.column, c.fieldType, c.sqlType, fieldType, sqlType)
+		}
+	}
+
+	source, err := generateModelsSource("models", []generatedModel{{
+		TableName: "tasks",
+		Columns:   []ColumnInfo{{Name: "id", Type: "int", PrimaryKey: true}, {Name: "timeout", Type: "interval"}},
+	}})
+	if err != nil || !strings.Contains(source, "import \"time\"") || !strings.Contains(source, "Timeout time.Duration `sql:\"duration:interval;not null\"`") {
+		t.Errorf("interval columns should be generated as durations, but got\n%v, %v", source, err)
+	}
+}
@@ -0,0 +1,108 @@
+package gorm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Point is a geographic location, saved as POINT in MySQL and geography(Point,4326) in Postgres
+type Point struct {
+	Lng float64
+	Lat float64
+}
+
+var pointType = reflect.TypeOf(Point{})
+
+// String get point's WKT, e.g: POINT(116.397 39.908)
+func (p Point) String() string {
+	return fmt.Sprintf("POINT(%v %v)", p.Lng, p.Lat)
+}
+
+// Scan scan WKB, EWKB (Postgres, maybe hex encoded) or MySQL's internal format (SRID + WKB) into point
+func (p *Point) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*p = Point{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("can't scan %T into point", value)
+	}
+
+	if decoded, err := hex.DecodeString(string(data)); err == nil {
+		data = decoded
+	} else if len(data) == 25 {
+		// skip MySQL's SRID
+		data = data[4:]
+	}
+
+	if len(data) < 21 {
+		return errors.New("invalid WKB point")
+	}
+
+	var byteOrder binary.ByteOrder = binary.LittleEndian
+	if data[0] == 0 {
+		byteOrder = binary.BigEndian
+	}
+	geometryType := byteOrder.Uint32(data[1:5])
+	offset := 5
+	if geometryType&0x20000000 != 0 {
+		// EWKB with SRID
+		offset += 4
+	}
+	if geometryType&0xff != 1 || len(data) < offset+16 {
+		return errors.New("invalid WKB point")
+	}
+
+	p.Lng = math.Float64frombits(byteOrder.Uint64(data[offset : offset+8]))
+	p.Lat = math.Float64frombits(byteOrder.Uint64(data[offset+8 : offset+16]))
+	return nil
+}
+
+// WithinDistance is a condition of records whose point column is within meters of a point, made by Within
+type WithinDistance struct {
+	column string
+	point  Point
+	meters float64
+}
+
+// Within find records whose point column is within meters of point, e.g:
+//
+//	db.Where(gorm.Within("location", gorm.Point{Lng: 116.397, Lat: 39.908}, 1000)).Find(&shops)
+//	//// MySQL: SELECT * FROM shops WHERE (ST_Distance_Sphere(`location`, ST_GeomFromText('POINT(116.397 39.908)')) <= 1000)
+//	//// Postgres: SELECT * FROM shops WHERE (ST_DWithin("location", ST_GeogFromText('POINT(116.397 39.908)'), 1000))
+func Within(column string, point Point, meters float64) *WithinDistance {
+	return &WithinDistance{column: column, point: point, meters: meters}
+}
+
+func (w *WithinDistance) toSql(scope *Scope) string {
+	return scope.Dialect().WithinDistanceSql(scope.quoteColumns([]string{w.column})[0], scope.AddToVars(w.point), scope.AddToVars(w.meters))
+}
+
+// BoundingBox is a condition of records whose point column is inside a box, made by InBoundingBox
+type BoundingBox struct {
+	column    string
+	southWest Point
+	northEast Point
+}
+
+// InBoundingBox find records whose point column is inside the box between southWest and northEast
+func InBoundingBox(column string, southWest Point, northEast Point) *BoundingBox {
+	return &BoundingBox{column: column, southWest: southWest, northEast: northEast}
+}
+
+func (b *BoundingBox) toSql(scope *Scope) string {
+	var polygon bytes.Buffer
+	sw, ne := b.southWest, b.northEast
+	fmt.Fprintf(&polygon, "POLYGON((%v %v,%v %v,%v %v,%v %v,%v %v))", sw.Lng, sw.Lat, ne.Lng, sw.Lat, ne.Lng, ne.Lat, sw.Lng, ne.Lat, sw.Lng, sw.Lat)
+	return scope.Dialect().BoundingBoxSql(scope.quoteColumns([]string{b.column})[0], scope.AddToVars(polygon.String()))
+}
@@ -0,0 +1,68 @@
+package gorm_test
+
+import (
+	"os"
+	"testing"
+
+	"golib/gorm"
+)
+
+type Shop struct {
+	Id       int64
+	Name     string
+	Location gorm.Point
+}
+
+func TestPointScan(t *testing.T) {
+	var point gorm.Point
+	// MySQL's internal format: SRID 0 + little endian WKB of POINT(1 2)
+	mysqlValue := []byte{0, 0, 0, 0, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f, 0, 0, 0, 0, 0, 0, 0, 0x40}
+	if err := point.Scan(mysqlValue); err != nil || point.Lng != 1 || point.Lat != 2 {
+		t.Errorf("Should scan MySQL's point, but got %+v, %v", point, err)
+	}
+
+	// Postgres' hex encoded EWKB with SRID 4326 of POINT(1 2)
+	if err := point.Scan([]byte("0101000020E6100000000000000000F03F0000000000000040")); err != nil || point.Lng != 1 || point.Lat != 2 {
+		t.Errorf("Should scan Postgres' point, but got %+v, %v", point, err)
+	}
+
+	if err := point.Scan([]byte("invalid")); err == nil {
+		t.Errorf("Should get error when scan invalid point")
+	}
+}
+
+func TestGeoQuery(t *testing.T) {
+	DB.DropTableIfExists(&Shop{})
+	if dialect := os.Getenv("GORM_DIALECT"); dialect != "mysql" && dialect != "postgres" {
+		if err := DB.AutoMigrate(&Shop{}).Error; err != gorm.PointNotSupported {
+			t.Errorf("Should get error when create table with point, but got %v", err)
+		}
+		t.Skip("Skipping this because only mysql and postgres support geographic points")
+	}
+
+	if err := DB.AutoMigrate(&Shop{}).Error; err != nil {
+		t.Errorf("No error should happen when create table with point, but got %v", err)
+	}
+
+	tiananmen := gorm.Point{Lng: 116.3975, Lat: 39.9087}
+	DB.Save(&Shop{Name: "near", Location: gorm.Point{Lng: 116.3990, Lat: 39.9090}}).
+		Save(&Shop{Name: "far", Location: gorm.Point{Lng: 121.4737, Lat: 31.2304}})
+
+	var shop Shop
+	DB.Where("name = ?", "near").First(&shop)
+	if shop.Location.Lng != 116.3990 || shop.Location.Lat != 39.9090 {
+		t.Errorf("Should save and scan point, but got %+v", shop.Location)
+	}
+
+	var shops []Shop
+	DB.Where(gorm.Within("location", tiananmen, 1000)).Find(&shops)
+	if len(shops) != 1 || shops[0].Name != "near" {
+		t.Errorf("Should find shops within distance, but got %+v", shops)
+	}
+
+	shops = []Shop{}
+	DB.Where(gorm.InBoundingBox("location", gorm.Point{Lng: 121, Lat: 31}, gorm.Point{Lng: 122, Lat: 32})).Find(&shops)
+	if len(shops) != 1 || shops[0].Name != "far" {
+		t.Errorf("Should find shops in bounding box, but got %+v", shops)
+	}
+}
@@ -0,0 +1,41 @@
+package gorm
+
+import (
+	"strings"
+)
+
+type conditionGroup struct {
+	operator   string
+	conditions []interface{}
+}
+
+// And group conditions with AND, the group is parenthesized, so it could be nested in Or and Not, conditions
+// could be Expr, strings, structs, maps or other conditions like ILike, e.g:
+//
+//	db.Where(gorm.Or(gorm.And(gorm.Expr("age > ?", 18), gorm.Expr("role = ?", "admin")), gorm.Expr("name = ?", "jinzhu"))).Find(&users)
+//	//// SELECT * FROM users WHERE (((age > 18) AND (role = 'admin')) OR (name = 'jinzhu'))
+func And(conditions ...interface{}) *conditionGroup {
+	return &conditionGroup{operator: " AND ", conditions: conditions}
+}
+
+// Or group conditions with OR, the group is parenthesized, so other conditions of the chain are not mixed into it, e.g:
+//
+//	db.Where("status = ?", "active").Where(gorm.Or(gorm.Expr("age < ?", 18), gorm.Expr("age > ?", 60))).Find(&users)
+//	//// SELECT * FROM users WHERE (status = 'active') AND ((age < 18) OR (age > 60))
+func Or(conditions ...interface{}) *conditionGroup {
+	return &conditionGroup{operator: " OR ", conditions: conditions}
+}
+
+func (g *conditionGroup) toSql(scope *Scope) string {
+	var sqls []string
+	for _, condition := range g.conditions {
+		clause := map[string]interface{}{"query": condition}
+		if e, ok := condition.(*expr); ok {
+			clause = map[string]interface{}{"query": e.expr, "args": e.args}
+		}
+		if sql := scope.buildWhereCondition(clause); sql != "" {
+			sqls = append(sqls, sql)
+		}
+	}
+	return strings.Join(sqls, g.operator)
+}
@@ -0,0 +1,27 @@
+package gorm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConditionGroups(t *testing.T) {
+	db := newFakeDB("sqlite3", "")
+
+	scope := db.NewScope(&conditionUser{})
+	group := Or(And(Expr("age > ?", 18), Expr("role = ?", "admin")), Expr("name = ?", "jinzhu"))
+	sql := scope.buildWhereCondition(map[string]interface{}{"query": group})
+	if sql != "(((age > $$) AND (role = $$)) OR (name = $$))" || !reflect.DeepEqual(scope.SqlVars, []interface{}{18, "admin", "jinzhu"}) {
+		t.Errorf("nested groups should be parenthesized, but got %v, %v", sql, scope.SqlVars)
+	}
+
+	scope = db.NewScope(&conditionUser{})
+	if sql := scope.buildNotCondition(map[string]interface{}{"query": Or(conditionUser{Name: "jinzhu"}, "age IS NULL")}); sql != `NOT (("name" = $$) OR (age IS NULL))` {
+		t.Errorf("groups should be negated as a whole, but got %v", sql)
+	}
+
+	scope = db.NewScope(&conditionUser{})
+	if sql := scope.buildWhereCondition(map[string]interface{}{"query": And(Or(), conditionUser{})}); sql != "" {
+		t.Errorf("empty groups should be skipped, but got %v", sql)
+	}
+}
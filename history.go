@@ -0,0 +1,195 @@
+package gorm
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// models implementing Versioned will save their previous state into `<table>_history`
+// before every update and delete, e.g:
+//
+//	func (User) Versioned() bool { return true }
+type versioned interface {
+	Versioned() bool
+}
+
+const (
+	historyValidToColumn   = "history_valid_to"
+	historyOperationColumn = "history_operation"
+)
+
+func (scope *Scope) isVersioned() bool {
+	// updates of tables without models have no model type
+	modelType := scope.GetModelStruct().ModelType
+	if modelType == nil {
+		return false
+	}
+	if versioned, ok := reflect.New(modelType).Interface().(versioned); ok {
+		return versioned.Versioned()
+	}
+	return false
+}
+
+// HistoryTableName get the history table name of current model
+func (scope *Scope) HistoryTableName() string {
+	return scope.TableName() + "_history"
+}
+
+func (scope *Scope) historyColumns(prefix string) []string {
+	var columns []string
+	for _, field := range scope.GetStructFields() {
+		if field.IsNormal && !field.IsIgnored {
+			columns = append(columns, prefix+scope.Quote(field.DBName))
+		}
+	}
+	return columns
+}
+
+func (scope *Scope) saveHistory(operation string) {
+	if scope.HasError() || !scope.isVersioned() {
+		return
+	}
+
+	historyScope := scope.New(scope.Value)
+	historyScope.Search = scope.Search.clone()
+	columns := historyScope.historyColumns("")
+	historyScope.Raw(fmt.Sprintf(
+		"INSERT INTO %v (%v, %v, %v) SELECT %v, %v, %v FROM %v %v",
+		historyScope.Quote(historyScope.HistoryTableName()),
+		strings.Join(columns, ","),
+		historyScope.Quote(historyValidToColumn),
+		historyScope.Quote(historyOperationColumn),
+		strings.Join(columns, ","),
+		historyScope.AddToVars(NowFunc()),
+		historyScope.AddToVars(operation),
+		historyScope.QuotedTableName(),
+		historyScope.CombinedConditionSql(),
+	)).Exec()
+	scope.Err(historyScope.db.Error)
+}
+
+// SaveHistoryWhenUpdate save the rows' current state to history table before update
+func SaveHistoryWhenUpdate(scope *Scope) {
+	scope.saveHistory("update")
+}
+
+// SaveHistoryWhenDelete save the rows' current state to history table before delete
+func SaveHistoryWhenDelete(scope *Scope) {
+	scope.saveHistory("delete")
+}
+
+// QueryHistoryAsOf replace the queried table with the state of versioned model at the time set by AsOf
+func QueryHistoryAsOf(scope *Scope) {
+	value, ok := scope.Get("gorm:history_as_of")
+	if !ok || scope.HasError() || !scope.isVersioned() {
+		return
+	}
+	asOf := value.(time.Time)
+
+	table := scope.QuotedTableName()
+	history := scope.Quote(scope.HistoryTableName())
+	primaryKey := scope.Quote(scope.PrimaryKey())
+	validTo := scope.Quote(historyValidToColumn)
+
+	var createdAt string
+	if field, ok := scope.FieldByName("CreatedAt"); ok && field.IsNormal {
+		createdAt = scope.Quote(field.DBName)
+	}
+
+	// rows never changed after the time
+	currentSql := fmt.Sprintf(
+		"SELECT %v FROM %v WHERE NOT EXISTS (SELECT 1 FROM %v h WHERE h.%v = %v.%v AND h.%v > %v)",
+		strings.Join(scope.historyColumns(""), ","), table,
+		history, primaryKey, table, primaryKey, validTo, scope.AddToVars(asOf),
+	)
+	if createdAt != "" {
+		currentSql += fmt.Sprintf(" AND %v <= %v", createdAt, scope.AddToVars(asOf))
+	}
+
+	// the earliest version replaced after the time
+	historySql := fmt.Sprintf(
+		"SELECT %v FROM %v h WHERE h.%v > %v AND NOT EXISTS (SELECT 1 FROM %v h2 WHERE h2.%v = h.%v AND h2.%v > %v AND h2.%v < h.%v)",
+		strings.Join(scope.historyColumns("h."), ","), history, validTo, scope.AddToVars(asOf),
+		history, primaryKey, primaryKey, validTo, scope.AddToVars(asOf), validTo, validTo,
+	)
+	if createdAt != "" {
+		historySql += fmt.Sprintf(" AND h.%v <= %v", createdAt, scope.AddToVars(asOf))
+	}
+
+	scope.Search.Table(fmt.Sprintf("(%v UNION ALL %v) %v", currentSql, historySql, table))
+}
+
+// AsOf query versioned models' state at the given time
+//
+//	db.AsOf(time.Now().Add(-24 * time.Hour)).Find(&users)
+func (s *DB) AsOf(t time.Time) *DB {
+	return s.Set("gorm:history_as_of", t)
+}
+
+func (scope *Scope) autoMigrateHistory() *Scope {
+	historyScope := scope.New(scope.Value)
+	historyTable := scope.HistoryTableName()
+	historyScope.Search.Table(historyTable)
+
+	if !scope.Dialect().HasTable(historyScope, historyTable) {
+		historyScope.Raw(scope.historyTableSql()).Exec()
+	} else {
+		for _, field := range scope.GetStructFields() {
+			if field.IsNormal && !field.IsIgnored && !scope.Dialect().HasColumn(historyScope, historyTable, field.DBName) {
+				historyScope.Raw(fmt.Sprintf("ALTER TABLE %v ADD %v %v;", scope.Quote(historyTable), scope.Quote(field.DBName), historyScope.historySqlTag(field))).Exec()
+			}
+		}
+	}
+	scope.Err(historyScope.db.Error)
+	return scope
+}
+
+func (scope *Scope) historyTableSql() string {
+	var tags []string
+	for _, field := range scope.GetStructFields() {
+		if field.IsNormal && !field.IsIgnored {
+			tags = append(tags, scope.Quote(field.DBName)+" "+scope.historySqlTag(field))
+		}
+	}
+
+	timeType := scope.Dialect().SqlTag(reflect.ValueOf(time.Time{}), 0, false)
+	tags = append(tags, scope.Quote(historyValidToColumn)+" "+timeType, scope.Quote(historyOperationColumn)+" "+scope.Dialect().SqlTag(reflect.ValueOf(""), 10, false))
+	return fmt.Sprintf("CREATE TABLE %v (%v)%v", scope.Quote(scope.HistoryTableName()), strings.Join(tags, ","),
+		addExtraSpaceIfExist(scope.Dialect().TableOptionsSql(scope.Engine(), scope.Charset())))
+}
+
+// history tables keep every version of a row, so the columns don't have primary key, auto increment or default values
+func (scope *Scope) historySqlTag(field *StructField) string {
+	if value, ok := ParseTagSetting(field.Tag)["TYPE"]; ok {
+		// generated columns are saved as normal columns
+		return generatedColumnRegexp.Split(value, 2)[0]
+	}
+	if scope.isJsonField(field) {
+		return scope.Dialect().JsonSqlTag()
+	}
+
+	structType := field.Struct.Type
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	reflectValue := reflect.Indirect(reflect.New(structType))
+	if scope.isDelimitedField(field) {
+		reflectValue = reflect.ValueOf("")
+	}
+	for field.IsScanner && reflectValue.Kind() == reflect.Struct && reflectValue.Type() != pointType {
+		if _, isScanner := reflect.New(reflectValue.Type()).Interface().(sql.Scanner); !isScanner {
+			break
+		}
+		reflectValue = reflectValue.Field(0)
+	}
+
+	size := 255
+	if value, ok := ParseTagSetting(field.Tag)["SIZE"]; ok {
+		size, _ = strconv.Atoi(value)
+	}
+	return scope.Dialect().SqlTag(reflectValue, size, false)
+}
@@ -0,0 +1,56 @@
+package gorm_test
+
+import (
+	"testing"
+	"time"
+)
+
+type VersionedProduct struct {
+	Id        int64
+	Code      string
+	Price     int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (VersionedProduct) Versioned() bool {
+	return true
+}
+
+func TestHistoryAsOf(t *testing.T) {
+	DB.DropTableIfExists(&VersionedProduct{})
+	DB.Exec("DROP TABLE versioned_products_history")
+	if err := DB.AutoMigrate(&VersionedProduct{}).Error; err != nil {
+		t.Errorf("No error should happen when auto migrate versioned model, but got %v", err)
+	}
+
+	product := VersionedProduct{Code: "history", Price: 100}
+	DB.Save(&product)
+	time.Sleep(time.Second)
+	beforeUpdate := time.Now()
+	time.Sleep(time.Second)
+
+	DB.Model(&product).Update("price", 200)
+
+	var count int
+	DB.Table("versioned_products_history").Where("id = ?", product.Id).Count(&count)
+	if count != 1 {
+		t.Errorf("Previous state should be saved into history table, but got %v rows", count)
+	}
+
+	var current, previous VersionedProduct
+	DB.First(&current, product.Id)
+	if current.Price != 200 {
+		t.Errorf("Current price should be updated, but got %v", current.Price)
+	}
+
+	if err := DB.AsOf(beforeUpdate).First(&previous, product.Id).Error; err != nil || previous.Price != 100 {
+		t.Errorf("Should find previous state with AsOf, but got %v, %v", previous.Price, err)
+	}
+
+	DB.Delete(&product)
+	var deleted VersionedProduct
+	if DB.AsOf(beforeUpdate).First(&deleted, product.Id).Error != nil || deleted.Price != 100 {
+		t.Errorf("Should find deleted record with AsOf")
+	}
+}
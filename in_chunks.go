@@ -0,0 +1,178 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// InListChunkSize is the max length of slices in where conditions of Find, conditions with longer slices are
+// queried chunk by chunk, results of all chunks are merged, 0 disables it. It is lowered to fit the max count of
+// bind vars of the dialect, e.g. 999 for sqlite3, 2100 for mssql
+var InListChunkSize = 10000
+
+// reservedBindVars is kept for bind vars of other conditions when chunking with the dialect's max count
+const reservedBindVars = 100
+
+func inListChunkSize(dialect Dialect) int {
+	size := InListChunkSize
+	if max := dialect.MaxBindVars(); max > 0 && size > max-reservedBindVars {
+		size = max - reservedBindVars
+	}
+	return size
+}
+
+// inListRegexp match conditions of one positive IN list, e.g. `id IN (?)`
+var inListRegexp = regexp.MustCompile(`(?i)^\s*[\w.` + "`" + `"]+\s+IN\s*\(\s*\?\s*\)\s*$`)
+
+// longInList find the slice longer than chunkSize in where conditions, returns index of the condition, index of the
+// arg, -1 if the slice is the query itself, e.g. primary keys, and the slice. Only a single `col IN (?)` condition could
+// be chunked, conditionIndex is -1 if there are other long slices, as chunks of them couldn't be combined
+func longInList(conditions []map[string]interface{}, chunkSize int) (conditionIndex int, argIndex int, list reflect.Value) {
+	conditionIndex = -1
+	isLongList := func(value interface{}) (reflect.Value, bool) {
+		if _, ok := value.([]byte); ok {
+			return reflect.Value{}, false
+		}
+		v := reflect.ValueOf(value)
+		return v, v.Kind() == reflect.Slice && v.Len() > chunkSize
+	}
+
+	var found int
+	for i, condition := range conditions {
+		args, _ := condition["args"].([]interface{})
+		if v, ok := isLongList(condition["query"]); ok {
+			conditionIndex, argIndex, list = i, -1, v
+			found++
+		}
+		for j, arg := range args {
+			if v, ok := isLongList(arg); ok {
+				if query, isString := condition["query"].(string); !isString || len(args) != 1 || !inListRegexp.MatchString(query) {
+					return -1, 0, reflect.Value{}
+				}
+				conditionIndex, argIndex, list = i, j, v
+				found++
+			}
+		}
+	}
+	if found > 1 {
+		return -1, 0, reflect.Value{}
+	}
+	return
+}
+
+// isPlainSelect check selects only pick columns, e.g. no aggregates, so rows of chunks could be merged
+func (s *search) isPlainSelect() bool {
+	if len(s.selects) == 0 {
+		return true
+	}
+	var columns []string
+	switch query := s.selects["query"].(type) {
+	case string:
+		columns = strings.Split(query, ",")
+	case []string:
+		columns = query
+	default:
+		return false
+	}
+	for _, column := range columns {
+		column = strings.TrimSpace(column)
+		if column != "*" && !strings.HasSuffix(column, ".*") && !plainColumnRegexp.MatchString(column) {
+			return false
+		}
+	}
+	return true
+}
+
+// uniqueByPrimaryKeys remove records found more than once from merged results, e.g. by duplicated values in the list
+func (scope *Scope) uniqueByPrimaryKeys(results reflect.Value) reflect.Value {
+	if len(scope.GetModelStruct().PrimaryFields) == 0 {
+		return results
+	}
+
+	seen := map[string]bool{}
+	unique := reflect.MakeSlice(results.Type(), 0, results.Len())
+	for i := 0; i < results.Len(); i++ {
+		record := results.Index(i)
+		if record.Kind() != reflect.Ptr {
+			record = record.Addr()
+		}
+		// records without primary keys can't be told apart, they are kept
+		var keys []interface{}
+		blank := true
+		fields := scope.New(record.Interface()).Fields()
+		for _, primaryField := range scope.GetModelStruct().PrimaryFields {
+			field := fields[primaryField.DBName]
+			keys = append(keys, field.Field.Interface())
+			blank = blank && field.IsBlank
+		}
+		if !blank {
+			key := fmt.Sprintf("%#v", keys)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		unique = reflect.Append(unique, results.Index(i))
+	}
+	return unique
+}
+
+// findInChunks find records into slice chunk by chunk if a where condition has a slice longer than InListChunkSize,
+// as databases limit the count of bind vars, returns false if the query doesn't need to be chunked. Records found by
+// more than one chunk are merged by primary keys
+func (scope *Scope) findInChunks() bool {
+	search := scope.Search
+	if search.raw || search.limit != "" || search.offset != "" || len(search.orders) > 0 || len(search.groupConditions) > 0 ||
+		len(search.havingConditions) > 0 || len(search.orConditions) > 0 || len(search.compounds) > 0 || search.fromQuery != nil ||
+		search.distinct || !search.isPlainSelect() {
+		return false
+	}
+
+	dest := reflect.Indirect(reflect.ValueOf(scope.Value))
+	chunkSize := inListChunkSize(scope.Dialect())
+	if dest.Kind() != reflect.Slice || !dest.CanSet() || chunkSize <= 0 {
+		return false
+	}
+
+	conditionIndex, argIndex, list := longInList(search.whereConditions, chunkSize)
+	if conditionIndex < 0 {
+		return false
+	}
+
+	results := reflect.MakeSlice(dest.Type(), 0, list.Len())
+	for start := 0; start < list.Len(); start += chunkSize {
+		end := start + chunkSize
+		if end > list.Len() {
+			end = list.Len()
+		}
+
+		condition := map[string]interface{}{"query": search.whereConditions[conditionIndex]["query"], "args": search.whereConditions[conditionIndex]["args"]}
+		if argIndex < 0 {
+			condition["query"] = list.Slice(start, end).Interface()
+		} else {
+			args := append([]interface{}{}, condition["args"].([]interface{})...)
+			args[argIndex] = list.Slice(start, end).Interface()
+			condition["args"] = args
+		}
+
+		chunkDB := scope.db.clone()
+		chunkDB.search = search.clone()
+		chunkDB.search.db = chunkDB
+		chunkDB.search.whereConditions = append([]map[string]interface{}{}, search.whereConditions...)
+		chunkDB.search.whereConditions[conditionIndex] = condition
+
+		chunk := reflect.New(dest.Type())
+		found := chunkDB.Find(chunk.Interface())
+		if scope.Err(found.Error) != nil {
+			return true
+		}
+		results = reflect.AppendSlice(results, chunk.Elem())
+	}
+
+	results = scope.uniqueByPrimaryKeys(results)
+	dest.Set(results)
+	scope.db.RowsAffected = int64(results.Len())
+	return true
+}
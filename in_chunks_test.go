@@ -0,0 +1,207 @@
+package gorm
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInListChunkSize(t *testing.T) {
+	defer func(size int) { InListChunkSize = size }(InListChunkSize)
+	InListChunkSize = 10000
+
+	if size := inListChunkSize(&mysql{}); size != 10000 {
+		t.Errorf("chunk size should be InListChunkSize for mysql, got %v", size)
+	}
+	if size := inListChunkSize(&sqlite3{}); size != 899 {
+		t.Errorf("chunk size should fit max bind vars of sqlite3, got %v", size)
+	}
+	if size := inListChunkSize(&mssql{}); size != 2000 {
+		t.Errorf("chunk size should fit max bind vars of mssql, got %v", size)
+	}
+	if size := inListChunkSize(&commonDialect{}); size != 10000 {
+		t.Errorf("chunk size should be InListChunkSize for unknown dialects, got %v", size)
+	}
+}
+
+func TestLongInList(t *testing.T) {
+	conditions := []map[string]interface{}{
+		{"query": "name = ?", "args": []interface{}{"jinzhu"}},
+		{"query": "role IN (?)", "args": []interface{}{[]string{"a", "b", "c", "d"}}},
+		{"query": "data = ?", "args": []interface{}{[]byte("long bytes")}},
+		{"query": "age IN (?)", "args": []interface{}{[]int{1, 2}}},
+	}
+	conditionIndex, argIndex, list := longInList(conditions, 2)
+	if conditionIndex != 1 || argIndex != 0 || list.Len() != 4 {
+		t.Errorf("should find the long slice arg, got %v, %v, %v", conditionIndex, argIndex, list)
+	}
+
+	if conditionIndex, _, _ = longInList(conditions, 5); conditionIndex != -1 {
+		t.Errorf("should not find slices not longer than chunk size, got %v", conditionIndex)
+	}
+
+	keys := map[string]interface{}{"query": []int64{1, 2, 3, 4, 5}, "args": []interface{}{}}
+	if conditionIndex, argIndex, _ = longInList([]map[string]interface{}{conditions[0], keys}, 2); conditionIndex != 1 || argIndex != -1 {
+		t.Errorf("should find slice of primary keys as query, got %v, %v", conditionIndex, argIndex)
+	}
+	if conditionIndex, _, _ = longInList(append(conditions, keys), 2); conditionIndex != -1 {
+		t.Errorf("should not chunk more than one long slice, got %v", conditionIndex)
+	}
+
+	for _, query := range []string{"role NOT IN (?)", "role IN (?) OR name = ?", "id IN (?) AND role IN (?)", "id IN (?) OR role IN (?)"} {
+		condition := map[string]interface{}{"query": query, "args": []interface{}{[]string{"a", "b", "c"}, "b"}}
+		if conditionIndex, _, _ = longInList([]map[string]interface{}{condition}, 2); conditionIndex != -1 {
+			t.Errorf("should only chunk a single positive IN condition, but chunked %v", query)
+		}
+	}
+}
+
+func TestIsPlainSelect(t *testing.T) {
+	for query, plain := range map[interface{}]bool{
+		"id, name":        true,
+		"users.*":         true,
+		"count(*)":        false,
+		"name, sum(age)":  false,
+		"DISTINCT name":   false,
+		"age + 1 as next": false,
+	} {
+		if s := new(search).Select(query); s.isPlainSelect() != plain {
+			t.Errorf("select %v should be plain %v", query, plain)
+		}
+	}
+	if !new(search).isPlainSelect() {
+		t.Errorf("no selects should be plain")
+	}
+}
+
+type chunkedUser struct {
+	Id   int64
+	Name string
+}
+
+func TestUniqueByPrimaryKeys(t *testing.T) {
+	db := newFakeDB("mysql", "")
+	scope := db.NewScope(&[]chunkedUser{})
+	users := []chunkedUser{{Id: 1, Name: "a"}, {Id: 2}, {Id: 1, Name: "b"}, {Name: "no key"}, {Name: "no key"}}
+	unique := scope.uniqueByPrimaryKeys(reflect.ValueOf(users)).Interface().([]chunkedUser)
+	if len(unique) != 4 || unique[0].Name != "a" || unique[1].Id != 2 {
+		t.Errorf("records should be unique by primary keys, but got %+v", unique)
+	}
+
+	pointers := []*chunkedUser{{Id: 1}, {Id: 1}}
+	if unique := scope.uniqueByPrimaryKeys(reflect.ValueOf(pointers)); unique.Len() != 1 {
+		t.Errorf("pointers to records should be unique by primary keys, but got %v", unique.Len())
+	}
+}
+
+type batchedUser struct {
+	Id     int64
+	Orders []batchedOrder
+}
+
+type batchedOrder struct {
+	Id            int64
+	BatchedUserId int64
+}
+
+func TestPreloadBatchSize(t *testing.T) {
+	db := newFakeDB("mysql", "")
+	fakeResults = map[string][][]driver.Value{"SELECT": {{int64(1)}}}
+
+	users := []batchedUser{{Id: 1}, {Id: 2}, {Id: 3}, {Id: 4}, {Id: 5}}
+	scope := db.PreloadBatchSize(2).Preload("Orders").NewScope(&users)
+	fakeQueries = nil
+	if Preload(scope); scope.HasError() {
+		t.Fatalf("failed to preload in batches, got %v", scope.db.Error)
+	}
+	if len(fakeQueries) != 3 {
+		t.Errorf("orders should be preloaded in 3 batches, but got %v", fakeQueries)
+	}
+
+	scope = db.Preload("Orders").NewScope(&users)
+	fakeQueries = nil
+	if Preload(scope); len(fakeQueries) != 1 {
+		t.Errorf("orders should be preloaded in one query by default, but got %v", fakeQueries)
+	}
+}
+
+type batchedTag struct {
+	Id   int64
+	Name string
+}
+
+type batchedPost struct {
+	Id   int64
+	Tags []batchedTag `gorm:"many2many:batched_post_tags"`
+}
+
+func TestPreloadManyToManyInBatches(t *testing.T) {
+	db := newFakeDB("mysql", "")
+	fakeResults = map[string][][]driver.Value{"SELECT": {
+		{int64(1), "go", int64(1)}, {int64(2), "sql", int64(1)}, {int64(1), "go", int64(3)},
+	}}
+	fakeColumns = map[string][]string{"SELECT": {"id", "name", preloadSourceKeyColumn}}
+	defer func() { fakeColumns = map[string][]string{} }()
+
+	posts := []batchedPost{{Id: 1}, {Id: 2}, {Id: 3}}
+	scope := db.Preload("Tags").NewScope(&posts)
+	fakeQueries = nil
+	if Preload(scope); scope.HasError() {
+		t.Fatalf("failed to preload many to many, got %v", scope.db.Error)
+	}
+	expected := "SELECT  `batched_tags`.*, `batched_post_tags`.`batched_post_id` AS gorm_preload_source_key FROM `batched_tags` " +
+		"INNER JOIN batched_post_tags ON batched_post_tags.`batched_tag_id` = `batched_tags`.`id` WHERE (batched_post_tags.`batched_post_id` IN (?,?,?))"
+	if len(fakeQueries) != 1 || fakeQueries[0] != expected {
+		t.Errorf("tags of all posts should be preloaded in one query, but got %v", fakeQueries)
+	}
+	if len(posts[0].Tags) != 2 || posts[0].Tags[1].Name != "sql" || posts[1].Tags == nil || len(posts[1].Tags) != 0 ||
+		len(posts[2].Tags) != 1 || posts[2].Tags[0].Name != "go" {
+		t.Errorf("tags should be distributed to posts by the source key, but got %+v", posts)
+	}
+
+	scope = db.PreloadBatchSize(2).Preload("Tags").NewScope(&posts)
+	fakeQueries = nil
+	if Preload(scope); len(fakeQueries) != 2 {
+		t.Errorf("tags should be preloaded in 2 batches, but got %v", fakeQueries)
+	}
+}
+
+type countedTag struct {
+	Id int64
+}
+
+type countedPost struct {
+	Id            int64
+	Comments      []batchedOrder `gorm:"foreignkey:BatchedUserId"`
+	CommentsCount int            `sql:"-"`
+	Tags          []countedTag   `gorm:"many2many:counted_post_tags;jointable_soft_delete"`
+	TagsCount     uint           `sql:"-"`
+}
+
+func TestPreloadCount(t *testing.T) {
+	db := newFakeDB("mysql", "")
+	fakeResults = map[string][][]driver.Value{
+		"SELECT  `batched_orders`":    {{"1", int64(3)}},
+		"SELECT  `counted_post_tags`": {{"2", int64(5)}},
+	}
+
+	posts := []countedPost{{Id: 1}, {Id: 2, CommentsCount: 9}}
+	scope := db.PreloadCount("Comments", "id > ?", 1).PreloadCount("Tags").NewScope(&posts)
+	fakeQueries = nil
+	if PreloadCount(scope); scope.HasError() {
+		t.Fatalf("failed to preload counts, got %v", scope.db.Error)
+	}
+	if posts[0].CommentsCount != 3 || posts[1].CommentsCount != 0 || posts[0].TagsCount != 0 || posts[1].TagsCount != 5 {
+		t.Errorf("counts of associations should be set, but got %+v", posts)
+	}
+	if len(fakeQueries) != 2 || !strings.HasSuffix(fakeQueries[0], "AND (id > ?) GROUP BY `batched_orders`.`batched_user_id`") ||
+		!strings.Contains(fakeQueries[1], "`counted_post_tags`.`deleted_at` IS NULL") {
+		t.Errorf("associations should be counted with GROUP BY queries, but got %v", fakeQueries)
+	}
+
+	scope = db.PreloadCount("Id").NewScope(&posts)
+	if PreloadCount(scope); !scope.HasError() {
+		t.Errorf("counting fields not associations should return error")
+	}
+}
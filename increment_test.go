@@ -0,0 +1,34 @@
+package gorm
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+type hitCounter struct {
+	Id   int64
+	Hits int
+}
+
+func TestIncrement(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	fakeStatements = nil
+	fakeResults = map[string][][]driver.Value{"SELECT": {{int64(15)}}}
+	counter := hitCounter{Id: 1, Hits: 3}
+	if err := db.Model(&counter).Increment("Hits", 5).Error; err != nil {
+		t.Errorf("No error should happen when increment, but got %v", err)
+	}
+
+	if len(fakeStatements) == 0 || !strings.Contains(fakeStatements[len(fakeStatements)-1], "SET `hits` = `hits` + ?") {
+		t.Errorf("should increment the column in database, but got %v", fakeStatements)
+	}
+	if counter.Hits != 15 {
+		t.Errorf("field should be refreshed with the increased value, but got %v", counter.Hits)
+	}
+
+	if err := db.Model(&counter).Decrement("unknown", 1).Error; err == nil {
+		t.Errorf("should get error when decrement unknown fields")
+	}
+}
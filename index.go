@@ -0,0 +1,97 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Index is an index declared by models implementing Indexes, its parts could be columns or expressions,
+// with Where, it is a partial index, which is only supported by Postgres, MSSQL and sqlite3
+type Index struct {
+	Name   string
+	Parts  []string
+	Unique bool
+	Where  string
+}
+
+// models implementing indexer declare indexes with expressions, they are created by AutoMigrate, e.g:
+//
+//	func (User) Indexes() []gorm.Index {
+//		return []gorm.Index{
+//			{Name: "uix_users_lower_email", Parts: []string{"lower(email)"}, Unique: true},
+//			{Name: "idx_users_city", Parts: []string{"company_id", "(data->>'city')"}},
+//			{Name: "uix_users_name", Parts: []string{"name"}, Unique: true, Where: "deleted_at IS NULL"},
+//		}
+//	}
+type indexer interface {
+	Indexes() []Index
+}
+
+var columnPartRegexp = regexp.MustCompile(`^\w+$`)
+
+// modelIndexes get indexes declared by Indexes of the model, and partial indexes declared with tags
+func (scope *Scope) modelIndexes() []Index {
+	var indexes []Index
+	value := reflect.New(scope.GetModelStruct().ModelType).Interface()
+	if indexer, ok := value.(indexer); ok {
+		indexes = indexer.Indexes()
+	}
+	return append(indexes, scope.tagPartialIndexes()...)
+}
+
+// indexPartsSql quote column parts, and parenthesize expression parts as databases require
+func (scope *Scope) indexPartsSql(parts []string) string {
+	var sqls []string
+	for _, part := range parts {
+		if columnPartRegexp.MatchString(part) {
+			sqls = append(sqls, scope.Quote(part))
+		} else {
+			sqls = append(sqls, fmt.Sprintf("(%v)", part))
+		}
+	}
+	return strings.Join(sqls, ", ")
+}
+
+func (scope *Scope) createIndex(index Index) *Scope {
+	if index.Where != "" && !scope.Dialect().SupportPartialIndex() {
+		scope.Err(PartialIndexNotSupported)
+		return scope
+	}
+	if scope.Dialect().HasIndex(scope, scope.TableName(), index.Name) {
+		return scope
+	}
+
+	return scope.Raw(scope.indexSql(index)).Exec()
+}
+
+func (scope *Scope) indexSql(index Index) string {
+	sqlCreate := "CREATE INDEX"
+	if index.Unique {
+		sqlCreate = "CREATE UNIQUE INDEX"
+	}
+	var whereSql string
+	if index.Where != "" {
+		whereSql = " WHERE " + index.Where
+	}
+	return fmt.Sprintf("%s %v ON %v(%v)%v;", sqlCreate, index.Name, scope.QuotedTableName(), scope.indexPartsSql(index.Parts), whereSql)
+}
+
+// sameIndexParts compare columns of an existing index with parts of the declared one, databases don't
+// report expressions as columns, so an expression part only matches a blank column
+func sameIndexParts(columns []string, parts []string) bool {
+	if len(columns) != len(parts) {
+		return false
+	}
+	for i, part := range parts {
+		if columnPartRegexp.MatchString(part) {
+			if columns[i] != part {
+				return false
+			}
+		} else if columns[i] != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package gorm
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type indexedUser struct {
+	Id    int64
+	Email string
+}
+
+func (indexedUser) Indexes() []Index {
+	return []Index{{Name: "uix_indexed_users_lower_email", Parts: []string{"lower(email)"}, Unique: true}}
+}
+
+func TestIndexPartsSql(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	scope := db.NewScope(&indexedUser{})
+	if sql := scope.indexPartsSql([]string{"company_id", "lower(email)"}); sql != "`company_id`, (lower(email))" {
+		t.Errorf("columns should be quoted and expressions should be parenthesized, but got %v", sql)
+	}
+
+	if indexes := scope.modelIndexes(); len(indexes) != 1 || indexes[0].Name != "uix_indexed_users_lower_email" {
+		t.Errorf("should get indexes declared by the model, but got %v", indexes)
+	}
+}
+
+func TestSameIndexParts(t *testing.T) {
+	for _, c := range []struct {
+		columns  []string
+		parts    []string
+		expected bool
+	}{
+		{[]string{"company_id", ""}, []string{"company_id", "lower(email)"}, true},
+		{[]string{"company_id", "email"}, []string{"company_id", "lower(email)"}, false},
+		{[]string{""}, []string{"company_id", "lower(email)"}, false},
+		{[]string{"email"}, []string{"email"}, true},
+	} {
+		if sameIndexParts(c.columns, c.parts) != c.expected {
+			t.Errorf("comparing %v with %v should be %v", c.columns, c.parts, c.expected)
+		}
+	}
+}
+
+func TestPartialIndexNotSupported(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	if err := db.Model(&indexedUser{}).AddUniquePartialIndex("uix_indexed_users_email", "deleted_at IS NULL", "email").Error; err != PartialIndexNotSupported {
+		t.Errorf("partial index should not be supported by mysql, but got %v", err)
+	}
+}
+
+type partiallyIndexedUser struct {
+	Id        int64
+	Email     string `sql:"size:100;unique_index:uix_partially_indexed_users_email;where:deleted_at IS NULL"`
+	Name      string `sql:"size:100;index"`
+	DeletedAt *time.Time
+}
+
+func TestTagPartialIndexes(t *testing.T) {
+	db := newFakeDB("sqlite3", "")
+
+	fakeResults = map[string][][]driver.Value{
+		"SELECT count(*) FROM sqlite_master WHERE type='table'": {{int64(1)}},
+		"PRAGMA table_info": {
+			{int64(0), "id", "integer", int64(1), nil, int64(1)},
+			{int64(1), "email", "varchar(100)", int64(0), nil, int64(0)},
+			{int64(2), "name", "varchar(100)", int64(0), nil, int64(0)},
+			{int64(3), "deleted_at", "datetime", int64(0), nil, int64(0)},
+		},
+		"PRAGMA index_list": {{int64(0), "uix_partially_indexed_users_email", int64(1), "c", int64(1)}},
+		"PRAGMA index_info": {{int64(0), int64(1), "email"}},
+	}
+
+	fakeStatements = nil
+	if err := db.AutoMigrate(&partiallyIndexedUser{}).Error; err != nil {
+		t.Errorf("should migrate partial indexes of tags, but got %v", err)
+	}
+	expected := []string{
+		`CREATE INDEX idx_partially_indexed_users_name ON "partially_indexed_users"("name");`,
+		`CREATE UNIQUE INDEX uix_partially_indexed_users_email ON "partially_indexed_users"("email") WHERE deleted_at IS NULL;`,
+	}
+	if !reflect.DeepEqual(fakeStatements, expected) {
+		t.Errorf("partial index of tags should be created with the condition, but got %v", fakeStatements)
+	}
+
+	mysqlDB := newFakeDB("mysql", "")
+	if err := mysqlDB.AutoMigrate(&partiallyIndexedUser{}).Error; err != PartialIndexNotSupported {
+		t.Errorf("partial index of tags should not be supported by mysql, but got %v", err)
+	}
+}
+
+type searchablePost struct {
+	Id    int64
+	Title string `sql:"fulltext"`
+	Body  string `sql:"fulltext"`
+}
+
+func TestKeepFullTextExpressionIndex(t *testing.T) {
+	db := newFakeDB("postgres", "")
+
+	fakeResults = map[string][][]driver.Value{
+		"SELECT i.relname":                {{"ftx_searchable_posts", nil, false, false}},
+		"SELECT count(*) FROM pg_indexes": {{int64(1)}},
+	}
+
+	if err := db.NewScope(&searchablePost{}).autoIndex().db.Error; err != nil {
+		t.Errorf("no error should happen when migrate full-text index, but got %v", err)
+	}
+	if len(fakeStatements) != 0 {
+		t.Errorf("full-text index of tsvector expression should be kept, but got %v", fakeStatements)
+	}
+}
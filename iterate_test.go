@@ -0,0 +1,45 @@
+package gorm
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+type iteratedEvent struct {
+	Id    int64
+	Score *int64
+}
+
+func TestIterateByNullValues(t *testing.T) {
+	db := newFakeDB("mysql", "")
+	fakeResults = map[string][][]driver.Value{
+		"SELECT  * FROM `iterated_events`  WHERE (`score` IS NULL) ORDER BY":              {{int64(1), nil}, {int64(3), nil}},
+		"SELECT  * FROM `iterated_events`  WHERE (`score` IS NULL) AND":                   {{int64(4), nil}},
+		"SELECT  * FROM `iterated_events`  WHERE (`score` IS NOT NULL) ORDER BY":          {{int64(2), int64(5)}, {int64(5), int64(5)}},
+		"SELECT  * FROM `iterated_events`  WHERE (`score` IS NOT NULL) AND ((`score` > ?": {},
+	}
+	for query := range fakeResults {
+		fakeColumns[query] = []string{"id", "score"}
+	}
+	defer func() { fakeColumns = map[string][]string{} }()
+	fakeQueries = nil
+
+	var events []iteratedEvent
+	var ids []int64
+	err := db.Model(&events).IterateBy("score", 2, func() error {
+		for _, event := range events {
+			ids = append(ids, event.Id)
+		}
+		return nil
+	}).Error
+	if err != nil {
+		t.Fatalf("failed to iterate, got %v", err)
+	}
+	if !reflect.DeepEqual(ids, []int64{1, 3, 4, 2, 5}) {
+		t.Errorf("rows with NULL should be iterated first by primary key, then rows with values, but got %v", ids)
+	}
+	if len(fakeQueries) != 4 {
+		t.Errorf("rows should be iterated in 4 chunks, but got %q", fakeQueries)
+	}
+}
@@ -12,6 +12,8 @@ type JoinTableHandlerInterface interface {
 	Table(db *DB) string
 	Add(db *DB, source interface{}, destination interface{}) error
 	Delete(db *DB, sources ...interface{}) error
+	// JoinWith join rows of the source, the source is a slice of pointers to records when preloading records of
+	// many sources in one query
 	JoinWith(db *DB, source interface{}) *DB
 }
 
@@ -29,8 +31,16 @@ type JoinTableHandler struct {
 	TableName   string          `sql:"-"`
 	Source      JoinTableSource `sql:"-"`
 	Destination JoinTableSource `sql:"-"`
+	// SoftDelete mark join rows deleted with `deleted_at` instead of deleting them, they are kept as history,
+	// it is enabled by tag `jointable_soft_delete`
+	SoftDelete bool `sql:"-"`
+	// NoKey the join table has no primary key or unique index of references, it is enabled by tag `jointable_key:none`
+	NoKey bool `sql:"-"`
 }
 
+// joinTableDeletedAtColumn is the column marking soft deleted join rows
+const joinTableDeletedAtColumn = "deleted_at"
+
 func (s *JoinTableHandler) Setup(relationship *Relationship, tableName string, source reflect.Type, destination reflect.Type) {
 	s.TableName = tableName
 
@@ -110,6 +120,10 @@ func (s JoinTableHandler) Add(db *DB, source1 interface{}, source2 interface{})
 		conditions = append(conditions, fmt.Sprintf("%v = ?", scope.Quote(key)))
 		values = append(values, value)
 	}
+	// soft deleted rows are history, a new row is added
+	if s.SoftDelete {
+		conditions = append(conditions, fmt.Sprintf("%v IS NULL", scope.Quote(joinTableDeletedAtColumn)))
+	}
 
 	for _, value := range values {
 		values = append(values, value)
@@ -129,6 +143,88 @@ func (s JoinTableHandler) Add(db *DB, source1 interface{}, source2 interface{})
 	return db.Exec(sql, values...).Error
 }
 
+// AddAll add join rows of the source and destinations, duplicated destinations are skipped, rows linked already are
+// skipped by the key of the join table in multi-row INSERTs ignoring conflicts, rows of each INSERT fit the max count of
+// bind vars like conditions of Find, join tables without keys or with soft deleted rows, and dialects without upsert,
+// add each row with `INSERT ... SELECT ... WHERE NOT EXISTS` as Add
+func (s JoinTableHandler) AddAll(db *DB, source interface{}, destinations ...interface{}) error {
+	scope := db.NewScope("")
+	sourceValues := s.GetSearchMap(db, source)
+	var sourceColumns, quotedColumns []string
+	var sourceArgs []interface{}
+	for _, foreignKey := range s.Source.ForeignKeys {
+		sourceColumns = append(sourceColumns, foreignKey.DBName)
+		quotedColumns = append(quotedColumns, scope.Quote(foreignKey.DBName))
+		sourceArgs = append(sourceArgs, sourceValues[foreignKey.DBName])
+	}
+	var destinationColumns []string
+	for _, foreignKey := range s.Destination.ForeignKeys {
+		destinationColumns = append(destinationColumns, foreignKey.DBName)
+		quotedColumns = append(quotedColumns, scope.Quote(foreignKey.DBName))
+	}
+
+	var rows [][]interface{}
+	var linkedDestinations []interface{}
+	links := map[string]bool{}
+	for _, destination := range destinations {
+		destinationValues := s.GetSearchMap(db, destination)
+		var row []interface{}
+		var key []string
+		for _, column := range destinationColumns {
+			row = append(row, destinationValues[column])
+			key = append(key, fmt.Sprintf("%v", destinationValues[column]))
+		}
+		if !links[strings.Join(key, ",")] {
+			links[strings.Join(key, ",")] = true
+			rows = append(rows, row)
+			linkedDestinations = append(linkedDestinations, destination)
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	onConflict := ""
+	if !s.SoftDelete && !s.NoKey {
+		onConflict = scope.Dialect().UpsertSql(quotedColumns, nil)
+	}
+	if onConflict == "" {
+		for _, destination := range linkedDestinations {
+			if err := s.Add(db, source, destination); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	columns := append(append([]string{}, sourceColumns...), destinationColumns...)
+	var values []interface{}
+	var rowSqls []string
+	rowsPerInsert := inListChunkSize(scope.Dialect()) / len(columns)
+	if rowsPerInsert <= 0 {
+		rowsPerInsert = len(rows)
+	}
+	insert := func() error {
+		if len(rowSqls) == 0 {
+			return nil
+		}
+		insertSql := fmt.Sprintf("INSERT INTO %v (%v) VALUES %v %v", s.Table(db), strings.Join(columns, ","), strings.Join(rowSqls, ","), onConflict)
+		err := db.Exec(insertSql, values...).Error
+		values, rowSqls = nil, nil
+		return err
+	}
+	for _, row := range rows {
+		values = append(append(values, sourceArgs...), row...)
+		rowSqls = append(rowSqls, "("+strings.Repeat("?,", len(columns)-1)+"?)")
+		if len(rowSqls) >= rowsPerInsert {
+			if err := insert(); err != nil {
+				return err
+			}
+		}
+	}
+	return insert()
+}
+
 func (s JoinTableHandler) Delete(db *DB, sources ...interface{}) error {
 	var conditions []string
 	var values []interface{}
@@ -138,6 +234,11 @@ func (s JoinTableHandler) Delete(db *DB, sources ...interface{}) error {
 		values = append(values, value)
 	}
 
+	if s.SoftDelete {
+		deletedAt := db.NewScope(nil).Quote(joinTableDeletedAtColumn)
+		return db.Table(s.Table(db)).Where(strings.Join(conditions, " AND "), values...).
+			Where(fmt.Sprintf("%v IS NULL", deletedAt)).UpdateColumn(joinTableDeletedAtColumn, NowFunc()).Error
+	}
 	return db.Table(s.Table(db)).Where(strings.Join(conditions, " AND "), values...).Delete("").Error
 }
 
@@ -155,9 +256,40 @@ func (s JoinTableHandler) JoinWith(db *DB, source interface{}) *DB {
 			joinConditions = append(joinConditions, fmt.Sprintf("%v.%v = %v.%v", quotedTable, scope.Quote(foreignKey.DBName), destinationTableName, scope.Quote(foreignKey.AssociationDBName)))
 		}
 
-		for _, foreignKey := range s.Source.ForeignKeys {
-			queryConditions = append(queryConditions, fmt.Sprintf("%v.%v = ?", quotedTable, scope.Quote(foreignKey.DBName)))
-			values = append(values, scope.Fields()[foreignKey.AssociationDBName].Field.Interface())
+		if sources := scope.IndirectValue(); sources.Kind() == reflect.Slice {
+			// rows of all sources are joined at once when preloading
+			var keys []interface{}
+			var sourceConditions []string
+			for i := 0; i < sources.Len(); i++ {
+				source := sources.Index(i)
+				if source.Kind() != reflect.Ptr {
+					source = source.Addr()
+				}
+				fields := scope.New(source.Interface()).Fields()
+				var keyConditions []string
+				for _, foreignKey := range s.Source.ForeignKeys {
+					keyConditions = append(keyConditions, fmt.Sprintf("%v.%v = ?", quotedTable, scope.Quote(foreignKey.DBName)))
+					keys = append(keys, fields[foreignKey.AssociationDBName].Field.Interface())
+				}
+				sourceConditions = append(sourceConditions, "("+strings.Join(keyConditions, " AND ")+")")
+			}
+			if len(keys) == 0 {
+				queryConditions = append(queryConditions, "1 <> 1")
+			} else if len(s.Source.ForeignKeys) == 1 {
+				queryConditions = append(queryConditions, fmt.Sprintf("%v.%v IN (?)", quotedTable, scope.Quote(s.Source.ForeignKeys[0].DBName)))
+				values = append(values, keys)
+			} else {
+				queryConditions = append(queryConditions, "("+strings.Join(sourceConditions, " OR ")+")")
+				values = append(values, keys...)
+			}
+		} else {
+			for _, foreignKey := range s.Source.ForeignKeys {
+				queryConditions = append(queryConditions, fmt.Sprintf("%v.%v = ?", quotedTable, scope.Quote(foreignKey.DBName)))
+				values = append(values, scope.Fields()[foreignKey.AssociationDBName].Field.Interface())
+			}
+		}
+		if s.SoftDelete {
+			queryConditions = append(queryConditions, fmt.Sprintf("%v.%v IS NULL", quotedTable, scope.Quote(joinTableDeletedAtColumn)))
 		}
 		return db.Joins(fmt.Sprintf("INNER JOIN %v ON %v", quotedTable, strings.Join(joinConditions, " AND "))).
 			Where(strings.Join(queryConditions, " AND "), values...)
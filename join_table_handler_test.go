@@ -0,0 +1,191 @@
+package gorm
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type handledTag struct {
+	Id   int64
+	Name string
+}
+
+type handledPost struct {
+	Id   int64
+	Tags []handledTag `gorm:"many2many:handled_post_tags"`
+}
+
+type handledPostTag struct {
+	JoinTableHandler
+	HandledPostId int64
+	HandledTagId  int64
+}
+
+func (handler *handledPostTag) JoinWith(db *DB, source interface{}) *DB {
+	return handler.JoinTableHandler.JoinWith(db, source).Where("handled_post_tags.deleted_at IS NULL")
+}
+
+func TestSetJoinTableHandler(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	fakeResults = map[string][][]driver.Value{
+		"SELECT count(*)":          {{int64(1)}},
+		"SELECT  `handled_tags`.*": {{int64(1), int64(1)}, {int64(2), int64(1)}, {int64(1), int64(2)}},
+	}
+	fakeColumns = map[string][]string{"SELECT  `handled_tags`.*": {"id", preloadSourceKeyColumn}}
+	defer func() { fakeColumns = map[string][]string{} }()
+	if err := db.SetJoinTableHandler(&handledPost{}, "Name", &handledPostTag{}); err == nil {
+		t.Errorf("should not set join table handler of fields not many to many")
+	}
+	if err := db.SetJoinTableHandler(&handledPost{}, "Tags", &handledPostTag{}); err != nil {
+		t.Fatalf("failed to set join table handler, got %v", err)
+	}
+
+	fakeQueries = nil
+	posts := []handledPost{{Id: 1}, {Id: 2}}
+	scope := db.Preload("Tags").NewScope(&posts)
+	if Preload(scope); scope.HasError() {
+		t.Errorf("failed to preload many to many, got %v", scope.db.Error)
+	}
+	var preloads []string
+	for _, query := range fakeQueries {
+		if strings.Contains(query, "INNER JOIN handled_post_tags") {
+			preloads = append(preloads, query)
+		}
+	}
+	if len(preloads) != 1 || !strings.Contains(preloads[0], "handled_post_tags.`handled_post_id` IN (?,?)") ||
+		!strings.Contains(preloads[0], "handled_post_tags.deleted_at IS NULL") {
+		t.Errorf("tags of all posts should be preloaded in one query with the custom handler, but got %v", preloads)
+	}
+	if len(posts[0].Tags) != 2 || len(posts[1].Tags) != 1 || posts[1].Tags[0].Id != 1 {
+		t.Errorf("preloaded tags should be distributed to posts by the source key, but got %+v", posts)
+	}
+}
+
+type softTag struct {
+	Id   int64
+	Name string
+}
+
+type softPost struct {
+	Id   int64
+	Tags []softTag `gorm:"many2many:soft_post_tags;jointable_soft_delete"`
+}
+
+func TestJoinTableSoftDelete(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	post, tag := &softPost{Id: 1}, &softTag{Id: 2}
+	field, _ := db.NewScope(post).FieldByName("Tags")
+	handler := field.Relationship.JoinTableHandler
+
+	fakeStatements = nil
+	handler.Add(db, post, tag)
+	if len(fakeStatements) != 1 || !strings.HasSuffix(fakeStatements[0], "AND `deleted_at` IS NULL)") {
+		t.Errorf("join rows should be added unless not deleted ones exist, but got %v", fakeStatements)
+	}
+
+	fakeStatements = nil
+	db.Model(post).Association("Tags").Delete(tag)
+	if len(fakeStatements) != 1 || !strings.HasPrefix(fakeStatements[0], "UPDATE `soft_post_tags` SET `deleted_at` = ?") ||
+		!strings.Contains(fakeStatements[0], "(`deleted_at` IS NULL)") {
+		t.Errorf("join rows should be soft deleted, but got %v", fakeStatements)
+	}
+
+	fakeQueries = nil
+	db.Model(post).Association("Tags").Count()
+	if len(fakeQueries) != 1 || !strings.Contains(fakeQueries[0], "soft_post_tags.`deleted_at` IS NULL") {
+		t.Errorf("soft deleted join rows should be filtered, but got %v", fakeQueries)
+	}
+
+	schema, _ := DumpSchema("postgres", &softPost{})
+	if expected := `CREATE TABLE "soft_post_tags" ("soft_post_id" bigint,"soft_tag_id" bigint,"deleted_at" timestamp with time zone);`; !strings.Contains(schema, expected) {
+		t.Errorf("join table should have deleted_at without key, but got %v", schema)
+	}
+}
+
+func TestJoinTableSoftDeleteOfKeyedTable(t *testing.T) {
+	db := newFakeDB("mysql", "")
+	scope := db.NewScope(&softPost{})
+	field, _ := scope.FieldByName("Tags")
+
+	fakeResults = map[string][][]driver.Value{
+		"SELECT count(*) FROM INFORMATION_SCHEMA.TABLES":  {{int64(1)}},
+		"SELECT count(*) FROM INFORMATION_SCHEMA.COLUMNS": {{int64(0)}},
+		"SELECT INDEX_NAME": {
+			{"PRIMARY", "soft_post_id", int64(1), int64(1)},
+			{"PRIMARY", "soft_tag_id", int64(1), int64(1)},
+		},
+	}
+	defer func() { fakeResults = map[string][][]driver.Value{} }()
+
+	fakeStatements = nil
+	scope.createJoinTable(field.StructField)
+	if scope.db.Error == nil || !strings.Contains(scope.db.Error.Error(), "PRIMARY") || len(fakeStatements) != 0 {
+		t.Errorf("soft deleting join rows of a keyed join table should be refused, but got %v, %v", scope.db.Error, fakeStatements)
+	}
+}
+
+type sharedTag struct {
+	Id   int64
+	Name string
+}
+
+type sharedPost struct {
+	Id       int64
+	Tags     []*sharedTag `gorm:"many2many:shared_post_tags"`
+	Author   *sharedTag
+	AuthorId int64
+}
+
+func TestSharePreloaded(t *testing.T) {
+	db := newFakeDB("mysql", "")
+	fakeResults = map[string][][]driver.Value{
+		"SELECT  `shared_tags`.*": {{int64(1), int64(1)}, {int64(1), int64(2)}},
+		"SELECT":                  {{int64(1)}},
+	}
+	fakeColumns = map[string][]string{"SELECT  `shared_tags`.*": {"id", preloadSourceKeyColumn}}
+	defer func() { fakeColumns = map[string][]string{} }()
+
+	posts := []sharedPost{{Id: 1}, {Id: 2}}
+	if Preload(db.Preload("Tags").Preload("Author").NewScope(&posts)); posts[0].Tags[0] == posts[1].Tags[0] {
+		t.Errorf("many to many records should not be shared by default")
+	}
+	if posts[0].Author == nil || posts[0].Author != posts[1].Author {
+		t.Errorf("belongs to parents of pointers should be shared, but got %v, %v", posts[0].Author, posts[1].Author)
+	}
+
+	posts = []sharedPost{{Id: 1}, {Id: 2}}
+	if Preload(db.SharePreloaded(true).Preload("Tags").NewScope(&posts)); posts[0].Tags[0] != posts[1].Tags[0] {
+		t.Errorf("many to many records with the same primary key should be shared")
+	}
+}
+
+func TestJoinTableAddAll(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	post := &sharedPost{Id: 1}
+	field, _ := db.NewScope(post).FieldByName("Tags")
+	handler := field.Relationship.JoinTableHandler.(*JoinTableHandler)
+
+	fakeStatements = nil
+	if err := handler.AddAll(db, post, &sharedTag{Id: 1}, &sharedTag{Id: 2}, &sharedTag{Id: 3}, &sharedTag{Id: 1}); err != nil {
+		t.Fatalf("failed to add join rows, got %v", err)
+	}
+	expected := []string{"INSERT INTO shared_post_tags (shared_post_id,shared_tag_id) VALUES (?,?),(?,?),(?,?) ON DUPLICATE KEY UPDATE `shared_post_id`=`shared_post_id`"}
+	if !reflect.DeepEqual(fakeStatements, expected) {
+		t.Errorf("join rows should be added in one statement ignoring existing links without duplicated links, but got %v", fakeStatements)
+	}
+
+	softPost := &softPost{Id: 1}
+	field, _ = db.NewScope(softPost).FieldByName("Tags")
+	fakeStatements = nil
+	if err := field.Relationship.JoinTableHandler.(*JoinTableHandler).AddAll(db, softPost, &softTag{Id: 1}, &softTag{Id: 2}); err != nil {
+		t.Fatalf("failed to add join rows, got %v", err)
+	}
+	if len(fakeStatements) != 2 || !strings.Contains(fakeStatements[0], "WHERE NOT EXISTS") || !strings.HasSuffix(fakeStatements[1], "AND `deleted_at` IS NULL)") {
+		t.Errorf("join rows of join tables without keys should be added unless existing, but got %v", fakeStatements)
+	}
+}
@@ -66,6 +66,11 @@ func TestJoinTable(t *testing.T) {
 		t.Errorf("Found two addresses with Unscoped")
 	}
 
+	var preloadedPerson Person
+	if DB.Preload("Addresses").First(&preloadedPerson, person.Id); len(preloadedPerson.Addresses) != 1 {
+		t.Errorf("Should preload one address with the join table handler, but got %v", len(preloadedPerson.Addresses))
+	}
+
 	if DB.Model(person).Association("Addresses").Clear(); DB.Model(person).Association("Addresses").Count() != 0 {
 		t.Errorf("Should deleted all addresses")
 	}
@@ -0,0 +1,61 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// Joins with a belongs_to or has_one association's name join its table, aliased with the name, and select its columns as `<name>__<column>`,
+// so the association is scanned in the same query, an alternative to Preload, e.g:
+//
+//	db.Joins("Company").Find(&users)
+//	//// SELECT `users`.*, `Company`.`id` AS `Company__id`, `Company`.`name` AS `Company__name` FROM `users`
+//	//// LEFT JOIN `companies` `Company` ON `Company`.`id` = `users`.`company_id`
+var associationNameRegexp = regexp.MustCompile(`^\w+$`)
+
+func (scope *Scope) joinedAssociation(name string) (*Relationship, *Scope, bool) {
+	field, ok := scope.FieldByName(name)
+	if !ok || field.Relationship == nil || (field.Relationship.Kind != "belongs_to" && field.Relationship.Kind != "has_one") {
+		scope.Err(fmt.Errorf("%v doesn't have belongs_to or has_one association %v", scope.GetModelStruct().ModelType, name))
+		return nil, nil, false
+	}
+
+	fieldType := field.Struct.Type
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	return field.Relationship, scope.New(reflect.New(fieldType).Interface()), true
+}
+
+func (scope *Scope) joinAssociationSql(name string) string {
+	relationship, toScope, ok := scope.joinedAssociation(name)
+	if !ok {
+		return ""
+	}
+
+	alias, table := scope.Quote(name), scope.QuotedTableName()
+	var on string
+	if relationship.Kind == "belongs_to" {
+		on = fmt.Sprintf("%v.%v = %v.%v", alias, scope.Quote(toScope.PrimaryKey()), table, scope.Quote(relationship.ForeignDBName))
+	} else {
+		on = fmt.Sprintf("%v.%v = %v.%v", alias, scope.Quote(relationship.ForeignDBName), table, scope.Quote(scope.PrimaryKey()))
+		if relationship.PolymorphicDBName != "" {
+			on += fmt.Sprintf(" AND %v.%v = %v", alias, scope.Quote(relationship.PolymorphicDBName), scope.AddToVars(scope.TableName()))
+		}
+	}
+	return fmt.Sprintf("LEFT JOIN %v %v ON %v", toScope.QuotedTableName(), alias, on)
+}
+
+func (scope *Scope) joinAssociationColumns() (columns []string) {
+	for _, name := range scope.Search.joinAssociations {
+		if _, toScope, ok := scope.joinedAssociation(name); ok {
+			for _, field := range toScope.GetStructFields() {
+				if field.IsNormal && !field.IsIgnored {
+					columns = append(columns, fmt.Sprintf("%v.%v AS %v", scope.Quote(name), scope.Quote(field.DBName), scope.Quote(name+"__"+field.DBName)))
+				}
+			}
+		}
+	}
+	return
+}
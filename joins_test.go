@@ -0,0 +1,36 @@
+package gorm
+
+import (
+	"strings"
+	"testing"
+)
+
+type joinedCompany struct {
+	Id   int64
+	Name string
+}
+
+type joinedUser struct {
+	Id              int64
+	Name            string
+	JoinedCompanyId int64
+	JoinedCompany   joinedCompany
+}
+
+func TestJoinAssociationSql(t *testing.T) {
+	db := newFakeDB("mysql", "")
+	scope := db.Joins("JoinedCompany").Where(1).NewScope(&joinedUser{})
+	scope.prepareQuerySql()
+
+	expected := "SELECT `joined_users`.*, `JoinedCompany`.`id` AS `JoinedCompany__id`, `JoinedCompany`.`name` AS `JoinedCompany__name` FROM `joined_users` " +
+		"LEFT JOIN `joined_companies` `JoinedCompany` ON `JoinedCompany`.`id` = `joined_users`.`joined_company_id` WHERE (`joined_users`.`id` = ?)"
+	if strings.Join(strings.Fields(scope.Sql), " ") != expected {
+		t.Errorf("association should be joined with its columns selected, but got %v", scope.Sql)
+	}
+
+	scope = db.Joins("Name").NewScope(&joinedUser{})
+	scope.prepareQuerySql()
+	if scope.db.Error == nil {
+		t.Errorf("should return error when joining a non-association field")
+	}
+}
@@ -0,0 +1,87 @@
+package gorm
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// map and slice fields are saved as JSON, unless the dialect supports arrays (only for slices) or the type implements sql.Scanner,
+// tag `json` or `serializer:json` forces it, e.g:
+//
+//	Labels map[string]string
+//	Scores []int `gorm:"json"` // JSON in Postgres too
+func (scope *Scope) isJsonField(field *StructField) bool {
+	if field == nil {
+		return false
+	}
+	fieldType := field.Struct.Type
+	if _, isScanner := reflect.New(fieldType).Interface().(sql.Scanner); isScanner {
+		return false
+	}
+	if _, isValuer := reflect.New(fieldType).Elem().Interface().(driver.Valuer); isValuer {
+		return false
+	}
+	settings := ParseTagSetting(field.Tag)
+	if serializer, ok := settings["SERIALIZER"]; ok {
+		return strings.ToLower(serializer) == "json"
+	}
+	if _, ok := settings["JSON"]; ok {
+		return true
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Map:
+		return true
+	case reflect.Slice:
+		return fieldType.Elem().Kind() != reflect.Uint8 && !scope.Dialect().SupportArray()
+	}
+	return false
+}
+
+// jsonVar encode values of JSON fields
+func (scope *Scope) jsonVar(field *StructField, value interface{}) interface{} {
+	if !scope.isJsonField(field) {
+		return value
+	}
+	switch reflectValue := reflect.ValueOf(value); reflectValue.Kind() {
+	case reflect.String:
+		// already encoded
+		return value
+	case reflect.Map, reflect.Slice, reflect.Ptr:
+		if reflectValue.IsNil() {
+			return nil
+		}
+	}
+	bytes, err := json.Marshal(value)
+	if scope.Err(err) != nil {
+		return nil
+	}
+	return string(bytes)
+}
+
+// jsonScanner decode JSON columns into fields
+type jsonScanner struct {
+	field reflect.Value
+}
+
+func (s jsonScanner) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		s.field.Set(reflect.Zero(s.field.Type()))
+		return nil
+	case string:
+		return s.Scan([]byte(v))
+	case []byte:
+		value := reflect.New(s.field.Type())
+		if err := json.Unmarshal(v, value.Interface()); err != nil {
+			return err
+		}
+		s.field.Set(value.Elem())
+		return nil
+	}
+	return fmt.Errorf("can't scan %T into %v", src, s.field.Type())
+}
@@ -0,0 +1,57 @@
+package gorm
+
+import (
+	"reflect"
+	"testing"
+)
+
+type jsonTag struct {
+	Labels map[string]string
+	Scores []int
+	Tags   []string `gorm:"json"`
+	Data   []byte
+}
+
+func TestJsonField(t *testing.T) {
+	db := newFakeDB("mysql", "")
+	scope := db.NewScope(&jsonTag{})
+	labels, _ := scope.FieldByName("Labels")
+	scores, _ := scope.FieldByName("Scores")
+	tags, _ := scope.FieldByName("Tags")
+	data, _ := scope.FieldByName("Data")
+
+	if !scope.isJsonField(labels.StructField) || !scope.isJsonField(scores.StructField) || scope.isJsonField(data.StructField) {
+		t.Errorf("maps and slices except []byte should be saved as JSON")
+	}
+	if tag := scope.generateSqlTag(scores.StructField); tag != "json" {
+		t.Errorf("JSON fields should be json column in mysql, but got %v", tag)
+	}
+	if value := scope.jsonVar(labels.StructField, map[string]string{"a": "b"}); value != `{"a":"b"}` {
+		t.Errorf("map should be encoded as JSON, but got %#v", value)
+	}
+	if value := scope.jsonVar(scores.StructField, []int(nil)); value != nil {
+		t.Errorf("nil slice should be saved as NULL, but got %#v", value)
+	}
+
+	db.dialect = &postgres{}
+	if scope.isJsonField(scores.StructField) || !scope.isJsonField(tags.StructField) {
+		t.Errorf("slices should be arrays in postgres unless tagged with json")
+	}
+	if tag := scope.generateSqlTag(labels.StructField); tag != "jsonb" {
+		t.Errorf("JSON fields should be jsonb column in postgres, but got %v", tag)
+	}
+}
+
+func TestJsonScanner(t *testing.T) {
+	var labels map[string]string
+	scanner := jsonScanner{reflect.ValueOf(&labels).Elem()}
+	if err := scanner.Scan([]byte(`{"a":"b"}`)); err != nil || labels["a"] != "b" {
+		t.Errorf("JSON should be decoded into map, but got %v, %v", labels, err)
+	}
+	if err := scanner.Scan(nil); err != nil || labels != nil {
+		t.Errorf("NULL should be scanned as nil map, but got %v, %v", labels, err)
+	}
+	if err := scanner.Scan([]byte(`[1]`)); err == nil {
+		t.Errorf("should return error for mismatched JSON")
+	}
+}
@@ -0,0 +1,50 @@
+package gorm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// escape character used for wildcards in Contains, StartsWith and EndsWith,
+// set explicitly since databases have different defaults
+const likeEscape = "!"
+
+var likeEscaper = strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_")
+
+type like struct {
+	column  string
+	pattern string
+	escaped bool
+}
+
+// ILike match column with pattern case-insensitively, it could be used in Where, Or and Not, e.g:
+//
+//	db.Where(gorm.ILike("name", "%jinzhu%")).Find(&users)
+//	//// Postgres: SELECT * FROM users WHERE ("name" ILIKE '%jinzhu%')
+//	//// MySQL: SELECT * FROM users WHERE (LOWER(`name`) LIKE LOWER('%jinzhu%'))
+func ILike(column string, pattern string) *like {
+	return &like{column: column, pattern: pattern}
+}
+
+// Contains match column containing str case-insensitively, wildcards in str are escaped
+func Contains(column string, str string) *like {
+	return &like{column: column, pattern: "%" + likeEscaper.Replace(str) + "%", escaped: true}
+}
+
+// StartsWith match column starting with str case-insensitively, wildcards in str are escaped
+func StartsWith(column string, str string) *like {
+	return &like{column: column, pattern: likeEscaper.Replace(str) + "%", escaped: true}
+}
+
+// EndsWith match column ending with str case-insensitively, wildcards in str are escaped
+func EndsWith(column string, str string) *like {
+	return &like{column: column, pattern: "%" + likeEscaper.Replace(str), escaped: true}
+}
+
+func (l *like) toSql(scope *Scope) string {
+	sql := scope.Dialect().ILikeSql(scope.quoteColumns([]string{l.column})[0], scope.AddToVars(l.pattern))
+	if l.escaped {
+		sql += fmt.Sprintf(" ESCAPE '%v'", likeEscape)
+	}
+	return sql
+}
@@ -0,0 +1,50 @@
+package gorm
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+// lockTx pretends to be a transaction to lock records
+type lockTx struct {
+	*sql.DB
+}
+
+func (lockTx) Commit() error {
+	return nil
+}
+
+func (lockTx) Rollback() error {
+	return nil
+}
+
+type lockedOrder struct {
+	Id     int64
+	UserId int64
+}
+
+func TestLockForUpdate(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	var orders []lockedOrder
+	if err := db.LockForUpdate(&orders, "user_id = ?", 1).Error; err != LockWithoutTransaction {
+		t.Errorf("should get error when locking outside of transactions, but got %v", err)
+	}
+
+	tx := db.clone()
+	tx.db = lockTx{db.DB()}
+	fakeQueries = nil
+	if err := tx.LockForUpdate(&orders, "user_id = ?", 1).Error; err != nil {
+		t.Errorf("No error should happen when locking in transactions, but got %v", err)
+	}
+	if len(fakeQueries) != 1 || !strings.HasSuffix(fakeQueries[0], "WHERE (user_id = ?) FOR UPDATE") {
+		t.Errorf("records should be locked for update, but got %v", fakeQueries)
+	}
+
+	sqliteDB := newFakeDB("sqlite3", "")
+	sqliteDB.db = lockTx{sqliteDB.DB()}
+	if err := sqliteDB.LockForUpdate(&orders).Error; err != LockNotSupported {
+		t.Errorf("should get error when the dialect can't lock records, but got %v", err)
+	}
+}
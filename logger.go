@@ -2,11 +2,15 @@ package gorm
 
 import (
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"reflect"
 	"regexp"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -25,43 +29,170 @@ var sqlRegexp = regexp.MustCompile(`(\$\d+)|\?`)
 
 func (logger Logger) Print(values ...interface{}) {
 	if len(values) > 1 {
-		level := values[0]
-		currentTime := "\n\033[33m[" + NowFunc().Format("2006-01-02 15:04:05") + "]\033[0m"
-		source := fmt.Sprintf("\033[35m(%v)\033[0m", values[1])
-		messages := []interface{}{source, currentTime}
-
-		if level == "sql" {
-			// duration
-			messages = append(messages, fmt.Sprintf(" \033[36;1m[%.2fms]\033[0m ", float64(values[2].(time.Duration).Nanoseconds()/1e4)/100.0))
-			// sql
-			var formatedValues []interface{}
-			for _, value := range values[4].([]interface{}) {
-				indirectValue := reflect.Indirect(reflect.ValueOf(value))
-				if indirectValue.IsValid() {
-					value = indirectValue.Interface()
-					if t, ok := value.(time.Time); ok {
-						formatedValues = append(formatedValues, fmt.Sprintf("'%v'", t.Format(time.RFC3339)))
-					} else if b, ok := value.([]byte); ok {
-						formatedValues = append(formatedValues, fmt.Sprintf("'%v'", string(b)))
-					} else if r, ok := value.(driver.Valuer); ok {
-						if value, err := r.Value(); err == nil && value != nil {
-							formatedValues = append(formatedValues, fmt.Sprintf("'%v'", value))
-						} else {
-							formatedValues = append(formatedValues, "NULL")
-						}
-					} else {
-						formatedValues = append(formatedValues, fmt.Sprintf("'%v'", value))
-					}
-				} else {
+		logger.Println(textLogMessages(values, true)...)
+	}
+}
+
+// textLogMessages format values passed to loggers as human readable messages, values are
+// level, source, then duration, sql and vars for sql and slow logs, or messages for others
+func textLogMessages(values []interface{}, colorful bool) []interface{} {
+	color := func(code string, value interface{}) string {
+		if colorful {
+			return fmt.Sprintf("\033[%vm%v\033[0m", code, value)
+		}
+		return fmt.Sprint(value)
+	}
+
+	level := values[0]
+	currentTime := "\n" + color("33", "["+NowFunc().Format("2006-01-02 15:04:05")+"]")
+	source := color("35", fmt.Sprintf("(%v)", values[1]))
+	messages := []interface{}{source, currentTime}
+
+	if (level == "sql" || level == "slow") && len(values) > 4 {
+		// duration
+		messages = append(messages, " "+color("36;1", fmt.Sprintf("[%.2fms]", logDurationMs(values[2])))+" ")
+		// sql
+		messages = append(messages, fmt.Sprintf(sqlRegexp.ReplaceAllString(fmt.Sprint(values[3]), "%v"), formatLogVars(values[4])...))
+	} else if colorful {
+		messages = append(messages, "\033[31;1m")
+		messages = append(messages, values[2:]...)
+		messages = append(messages, "\033[0m")
+	} else {
+		messages = append(messages, values[2:]...)
+	}
+	return messages
+}
+
+func logDurationMs(value interface{}) float64 {
+	if duration, ok := value.(time.Duration); ok {
+		return float64(duration.Nanoseconds()/1e4) / 100.0
+	}
+	return 0
+}
+
+// formatLogVars format bind vars of sql logs as sql literals
+func formatLogVars(vars interface{}) []interface{} {
+	values, _ := vars.([]interface{})
+	var formatedValues []interface{}
+	for _, value := range values {
+		indirectValue := reflect.Indirect(reflect.ValueOf(value))
+		if indirectValue.IsValid() {
+			value = indirectValue.Interface()
+			if t, ok := value.(time.Time); ok {
+				formatedValues = append(formatedValues, fmt.Sprintf("'%v'", t.Format(time.RFC3339)))
+			} else if b, ok := value.([]byte); ok {
+				formatedValues = append(formatedValues, fmt.Sprintf("'%v'", string(b)))
+			} else if r, ok := value.(driver.Valuer); ok {
+				if value, err := r.Value(); err == nil && value != nil {
 					formatedValues = append(formatedValues, fmt.Sprintf("'%v'", value))
+				} else {
+					formatedValues = append(formatedValues, "NULL")
 				}
+			} else {
+				formatedValues = append(formatedValues, fmt.Sprintf("'%v'", value))
 			}
-			messages = append(messages, fmt.Sprintf(sqlRegexp.ReplaceAllString(values[3].(string), "%v"), formatedValues...))
 		} else {
-			messages = append(messages, "\033[31;1m")
-			messages = append(messages, values[2:]...)
-			messages = append(messages, "\033[0m")
+			formatedValues = append(formatedValues, fmt.Sprintf("'%v'", value))
 		}
-		logger.Println(messages...)
 	}
+	return formatedValues
+}
+
+// LogFormat is the output format of WriterLogger
+type LogFormat int
+
+const (
+	// TextLogFormat human readable text, the same as the default logger
+	TextLogFormat LogFormat = iota
+	// PlainTextLogFormat human readable text without colors, for files and terminals not supporting colors
+	PlainTextLogFormat
+	// JsonLogFormat a JSON object per line, for log collectors
+	JsonLogFormat
+)
+
+// WriterLogger write logs into io.Writer with the format, logs of some levels (sql, slow, explain, log, warning, error)
+// could be routed to other writers, e.g:
+//
+//	db.SetLogger(gorm.NewLogger(os.Stdout, gorm.JsonLogFormat).Route("error", os.Stderr))
+//	//// {"time":"2015-06-01T10:00:00Z","level":"sql","source":"/app/main.go:42","duration_ms":0.52,"sql":"SELECT * FROM users WHERE (id = ?)","vars":["1"]}
+type WriterLogger struct {
+	Writer  io.Writer
+	Format  LogFormat
+	Writers map[string]io.Writer
+	mutex   sync.Mutex
+}
+
+// NewLogger create a WriterLogger writing into w with the format
+func NewLogger(w io.Writer, format LogFormat) *WriterLogger {
+	return &WriterLogger{Writer: w, Format: format, Writers: map[string]io.Writer{}}
+}
+
+// Route write logs of the level into w
+func (logger *WriterLogger) Route(level string, w io.Writer) *WriterLogger {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	if logger.Writers == nil {
+		logger.Writers = map[string]io.Writer{}
+	}
+	logger.Writers[level] = w
+	return logger
+}
+
+func (logger *WriterLogger) Print(values ...interface{}) {
+	if len(values) < 2 {
+		return
+	}
+
+	var line []byte
+	if logger.Format == JsonLogFormat {
+		line = jsonLogLine(values)
+	} else {
+		line = []byte(fmt.Sprintln(textLogMessages(values, logger.Format == TextLogFormat)...))
+	}
+
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	w := logger.Writer
+	if routed, ok := logger.Writers[fmt.Sprint(values[0])]; ok {
+		w = routed
+	}
+	if w != nil {
+		w.Write(line)
+	}
+}
+
+// jsonLogEntry is a log line of JsonLogFormat
+type jsonLogEntry struct {
+	Time       string   `json:"time"`
+	Level      string   `json:"level"`
+	Source     string   `json:"source"`
+	DurationMs *float64 `json:"duration_ms,omitempty"`
+	Sql        string   `json:"sql,omitempty"`
+	Vars       []string `json:"vars,omitempty"`
+	Message    string   `json:"message,omitempty"`
+}
+
+func jsonLogLine(values []interface{}) []byte {
+	source := fmt.Sprint(values[1])
+	if len(source) > 1 && source[0] == '[' && source[len(source)-1] == ']' {
+		source = source[1 : len(source)-1]
+	}
+	entry := jsonLogEntry{Time: NowFunc().Format(time.RFC3339Nano), Level: fmt.Sprint(values[0]), Source: source}
+
+	if (entry.Level == "sql" || entry.Level == "slow") && len(values) > 4 {
+		duration := logDurationMs(values[2])
+		entry.DurationMs = &duration
+		entry.Sql = fmt.Sprint(values[3])
+		for _, value := range formatLogVars(values[4]) {
+			entry.Vars = append(entry.Vars, strings.TrimSuffix(strings.TrimPrefix(fmt.Sprint(value), "'"), "'"))
+		}
+	} else {
+		entry.Message = fmt.Sprint(values[2:]...)
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		line, _ = json.Marshal(jsonLogEntry{Time: entry.Time, Level: entry.Level, Source: entry.Source, Message: err.Error()})
+	}
+	return append(line, '\n')
 }
@@ -0,0 +1,68 @@
+package gorm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLogCallerLocation(t *testing.T) {
+	var buf bytes.Buffer
+	db := newFakeDB("mysql", "")
+	db.logMode, db.logger = 2, Logger{log.New(&buf, "", 0)}
+
+	trace := func() {
+		scope := db.NewScope(nil)
+		scope.Raw("SELECT 1")
+		scope.Trace(NowFunc())
+	}
+
+	trace()
+	if output := buf.String(); !strings.Contains(output, "logger_test.go:") {
+		t.Errorf("log should report location of the caller, but got %v", output)
+	}
+
+	buf.Reset()
+	reflect.ValueOf(trace).Call(nil)
+	if output := buf.String(); !strings.Contains(output, "logger_test.go:") {
+		t.Errorf("frames of reflect should be skipped, but got %v", output)
+	}
+}
+
+func TestWriterLogger(t *testing.T) {
+	var out, errOut bytes.Buffer
+	logger := NewLogger(&out, JsonLogFormat).Route("error", &errOut)
+	db := newFakeDB("mysql", "")
+	db.logMode, db.logger = 2, logger
+
+	scope := db.NewScope(nil)
+	scope.Raw("SELECT * FROM users WHERE name = " + scope.AddToVars("jinzhu"))
+	scope.Trace(NowFunc())
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatalf("sql log should be a JSON line, but got %v, %v", out.String(), err)
+	}
+	if entry["level"] != "sql" || entry["sql"] != "SELECT * FROM users WHERE name = ?" || !strings.Contains(fmt.Sprint(entry["source"]), "logger_test.go:") {
+		t.Errorf("sql log should have level, sql and source, but got %v", entry)
+	}
+	if vars, ok := entry["vars"].([]interface{}); !ok || len(vars) != 1 || vars[0] != "jinzhu" {
+		t.Errorf("sql log should have vars, but got %v", entry["vars"])
+	}
+
+	db.print("error", fileWithLineNum(), errors.New("boom"))
+	if !strings.Contains(errOut.String(), `"message":"boom"`) || strings.Contains(out.String(), "boom") {
+		t.Errorf("error logs should be routed to their writer, but got %v", errOut.String())
+	}
+
+	out.Reset()
+	NewLogger(&out, PlainTextLogFormat).Print("log", "[main.go:1]", "hello")
+	if output := out.String(); strings.Contains(output, "\033[") || !strings.Contains(output, "([main.go:1])") || !strings.Contains(output, "hello") {
+		t.Errorf("plain text log should not have colors, but got %q", output)
+	}
+}
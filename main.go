@@ -19,6 +19,9 @@ var NowFunc = func() time.Time {
 	return time.Now()
 }
 
+// EstimatedCountThreshold tables with less estimated rows are counted exactly in EstimatedCount
+var EstimatedCountThreshold int64 = 100000
+
 type DB struct {
 	Value             interface{}
 	Error             error
@@ -30,6 +33,13 @@ type DB struct {
 	logMode           int
 	logger            logger
 	dialect           Dialect
+	quotePolicy       QuotePolicy
+	replicas          *replicaSet
+	slowQueryPolicy   *SlowQueryPolicy
+	timeoutPolicy     *TimeoutPolicy
+	maxRowsPolicy     *maxRowsPolicy
+	callbackTimings   []CallbackTiming
+	lastInsertId      int64
 	singularTable     bool
 	source            string
 	values            map[string]interface{}
@@ -55,29 +65,41 @@ func Open(dialect string, args ...interface{}) (DB, error) {
 				driver = value
 				source = args[1].(string)
 			}
-			if driver == "foundation" {
-				driver = "postgres" // FoundationDB speaks a postgres-compatible protocol.
-			}
-			dbSql, err = sql.Open(driver, source)
+			dbSql, err = sql.Open(driverName(driver), source)
 		case sqlCommon:
 			source = reflect.Indirect(reflect.ValueOf(value)).FieldByName("dsn").String()
 			dbSql = value
 		}
 
-		db = DB{
-			dialect:  NewDialect(dialect),
-			logger:   defaultLogger,
-			callback: DefaultCallback,
-			source:   source,
-			values:   map[string]interface{}{},
-			db:       dbSql,
-		}
-		db.parent = &db
+		db = newDB(dialect, source, dbSql)
 	}
 
 	return db, err
 }
 
+func driverName(driver string) string {
+	if driver == "foundation" {
+		driver = "postgres" // FoundationDB speaks a postgres-compatible protocol.
+	}
+	if driver == "postgres" {
+		driver = postgresDriver()
+	}
+	return driver
+}
+
+func newDB(dialect string, source string, dbSql sqlCommon) DB {
+	db := DB{
+		dialect:  NewDialect(dialect),
+		logger:   defaultLogger,
+		callback: DefaultCallback,
+		source:   source,
+		values:   map[string]interface{}{},
+		db:       dbSql,
+	}
+	db.parent = &db
+	return db
+}
+
 func (s *DB) Close() error {
 	return s.parent.db.(*sql.DB).Close()
 }
@@ -131,6 +153,10 @@ func (s *DB) SingularTable(enable bool) {
 	s.parent.singularTable = enable
 }
 
+// Where add conditions, blank fields of struct conditions are skipped, unless fields are selected by names, e.g:
+//
+//	db.Where(User{Name: "jinzhu", Age: 0}, "Name", "Age").Find(&users)
+//	//// SELECT * FROM users WHERE (name = 'jinzhu') AND (age = 0)
 func (s *DB) Where(query interface{}, args ...interface{}) *DB {
 	return s.clone().search.Where(query, args...).db
 }
@@ -159,22 +185,69 @@ func (s *DB) Select(query interface{}, args ...interface{}) *DB {
 	return s.clone().search.Select(query, args...).db
 }
 
+// Distinct select distinct rows, columns are optional, it affects Count also
+//
+//	db.Model(&User{}).Distinct("name", "email").Count(&count)
+func (s *DB) Distinct(columns ...string) *DB {
+	return s.clone().search.Distinct(columns...).db
+}
+
+// Union combine results of another query, the two queries should select compatible columns,
+// order, limit and offset of current query are applied to the combined results
+//
+//	db.Model(&User{}).Where("age > ?", 20).Union(db.Model(&User{}).Where("role = ?", "admin")).Order("id").Find(&users)
+func (s *DB) Union(query *DB) *DB {
+	return s.clone().search.Compound("UNION", query).db
+}
+
+func (s *DB) UnionAll(query *DB) *DB {
+	return s.clone().search.Compound("UNION ALL", query).db
+}
+
+// Intersect keep results of current query that are also returned by another query, not supported by MySQL
+func (s *DB) Intersect(query *DB) *DB {
+	return s.clone().search.Compound("INTERSECT", query).db
+}
+
+// Except remove results returned by another query from current query, not supported by MySQL
+func (s *DB) Except(query *DB) *DB {
+	return s.clone().search.Compound("EXCEPT", query).db
+}
+
 func (s *DB) Omit(columns ...string) *DB {
 	return s.clone().search.Omit(columns...).db
 }
 
-func (s *DB) Group(query string) *DB {
-	return s.clone().search.Group(query).db
+// Group accept a string with optional vars or a slice of columns
+//
+//	db.Group("name").Group("date(created_at, ?)", "localtime")
+//	db.Group([]string{"name", "role"})
+func (s *DB) Group(query interface{}, values ...interface{}) *DB {
+	return s.clone().search.Group(query, values...).db
 }
 
-func (s *DB) Having(query string, values ...interface{}) *DB {
+// Having accept same conditions as Where, including map and struct, multiple conditions will be joined with AND
+func (s *DB) Having(query interface{}, values ...interface{}) *DB {
 	return s.clone().search.Having(query, values...).db
 }
 
+// FullTextSearch find records whose columns match query, using MATCH ... AGAINST in MySQL and tsvector in Postgres
+//
+//	db.FullTextSearch([]string{"title", "body"}, "hello world").Find(&posts)
+//	//// SELECT * FROM posts WHERE (MATCH (`title`,`body`) AGAINST ('hello world' IN NATURAL LANGUAGE MODE))
+func (s *DB) FullTextSearch(columns []string, query string) *DB {
+	return s.clone().search.Where(&fullTextSearch{columns: columns, query: query}).db
+}
+
 func (s *DB) Joins(query string) *DB {
 	return s.clone().search.Joins(query).db
 }
 
+// Clause use a custom clause registered with RegisterClause
+func (s *DB) Clause(name string, args ...interface{}) *DB {
+	return s.clone().search.Clause(name, args...).db
+}
+
 func (s *DB) Scopes(funcs ...func(*DB) *DB) *DB {
 	for _, f := range funcs {
 		s = f(s)
@@ -208,14 +281,140 @@ func (s *DB) Last(out interface{}, where ...interface{}) *DB {
 		inlineCondition(where...).callCallbacks(s.parent.callback.queries).db
 }
 
+// Reload select the record by its primary key again into the same struct, with associations to preload, to drop
+// stale values after triggers or concurrent updates, the struct is kept if the record can't be found, e.g:
+//
+//	db.Reload(&user, "Emails")
+func (s *DB) Reload(value interface{}, preloads ...string) *DB {
+	scope := s.clone().NewScope(value)
+	reflectValue := reflect.Indirect(reflect.ValueOf(value))
+	if reflectValue.Kind() != reflect.Struct || !reflectValue.CanSet() || scope.PrimaryKeyZero() {
+		scope.Err(errors.New("can't reload a record without primary key"))
+		return scope.db
+	}
+
+	fresh := reflect.New(reflectValue.Type())
+	fields, freshFields := scope.Fields(), s.NewScope(fresh.Interface()).Fields()
+	for _, primaryField := range scope.GetModelStruct().PrimaryFields {
+		freshFields[primaryField.DBName].Field.Set(fields[primaryField.DBName].Field)
+	}
+
+	db := s.clone()
+	for _, preload := range preloads {
+		db = db.Preload(preload)
+	}
+	if db = db.First(fresh.Interface()); db.Error == nil {
+		reflectValue.Set(fresh.Elem())
+	}
+	return db
+}
+
+// Take find one record matching conditions without ordering by primary key, use Order to decide which one to get
+func (s *DB) Take(out interface{}, where ...interface{}) *DB {
+	newScope := s.clone().NewScope(out)
+	newScope.Search.Limit(1)
+	return newScope.inlineCondition(where...).callCallbacks(s.parent.callback.queries).db
+}
+
+// Find find records matching conditions, conditions with slices longer than InListChunkSize are queried chunk by chunk
 func (s *DB) Find(out interface{}, where ...interface{}) *DB {
-	return s.clone().NewScope(out).inlineCondition(where...).callCallbacks(s.parent.callback.queries).db
+	scope := s.clone().NewScope(out).inlineCondition(where...)
+	if scope.findInChunks() {
+		return scope.db
+	}
+	return scope.callCallbacks(s.parent.callback.queries).db
+}
+
+// LockForUpdate find records matching conditions and lock them with `FOR UPDATE` until the transaction ends, it
+// should be called in a transaction, e.g:
+//
+//	tx := db.Begin()
+//	tx.LockForUpdate(&orders, "user_id = ?", 1)
+//	//// SELECT * FROM orders WHERE (user_id = 1) FOR UPDATE
+//	tx.Commit()
+func (s *DB) LockForUpdate(out interface{}, where ...interface{}) *DB {
+	scope := s.clone().NewScope(out)
+	if _, ok := s.db.(sqlTx); !ok {
+		scope.Err(LockWithoutTransaction)
+		return scope.db
+	}
+
+	forUpdate := scope.Dialect().ForUpdateSql()
+	if forUpdate == "" {
+		scope.Err(LockNotSupported)
+		return scope.db
+	}
+	if option, ok := s.Get("gorm:query_option"); ok {
+		forUpdate = fmt.Sprint(option) + " " + forUpdate
+	}
+	return s.Set("gorm:query_option", forUpdate).Find(out, where...)
+}
+
+// IterateBy find records into model's slice chunk by chunk, ordered by column and primary key, rows with NULL in
+// nullable columns come first, fn is called after each chunk is loaded, iteration stops when fn returns error, e.g:
+//
+//	var events []Event
+//	db.Model(&events).Where("kind = ?", "click").IterateBy("created_at", 500, func() error {
+//		return process(events)
+//	})
+func (s *DB) IterateBy(column string, chunk int, fn func() error) *DB {
+	return s.clone().NewScope(s.Value).iterateBy(column, chunk, fn).db
 }
 
 func (s *DB) Scan(dest interface{}) *DB {
 	return s.clone().NewScope(s.Value).InstanceSet("gorm:query_destination", dest).callCallbacks(s.parent.callback.queries).db
 }
 
+// StrictScan return UnmappedColumns error when queried columns don't have matching fields, instead of dropping them silently,
+// with warnOnly, they are only logged, e.g:
+//
+//	db.StrictScan().Raw("SELECT * FROM users").Scan(&users)
+func (s *DB) StrictScan(warnOnly ...bool) *DB {
+	return s.Set("gorm:strict_scan", len(warnOnly) > 0 && warnOnly[0])
+}
+
+// StrictConditions return BlankCondition error when struct conditions have only blank fields, instead of dropping
+// them silently and matching all records, e.g:
+//
+//	db.StrictConditions().Where(User{Age: 0}).Find(&users) // BlankCondition error
+//	db.StrictConditions().Where(User{Age: 0}, "Age").Find(&users)
+//	//// SELECT * FROM users WHERE (age = 0)
+func (s *DB) StrictConditions() *DB {
+	return s.Set("gorm:strict_conditions", true)
+}
+
+type maxRowsPolicy struct {
+	max      int64
+	warnOnly bool
+}
+
+// SetMaxRows return TooManyRows error when finding more than max records into a slice without limit,
+// so a whole table won't be loaded into memory accidentally, with warnOnly, it is only logged, 0 disables it, e.g:
+//
+//	db.SetMaxRows(10000)
+//	db.Find(&users)              // error if there are more than 10000 users
+//	db.Limit(20000).Find(&users) // queries with limit are not checked
+func (s *DB) SetMaxRows(max int64, warnOnly ...bool) {
+	s.parent.maxRowsPolicy = &maxRowsPolicy{max: max, warnOnly: len(warnOnly) > 0 && warnOnly[0]}
+}
+
+// ScanAliases scan columns into fields by aliases, nested structs' fields could be set with dots, e.g:
+//
+//	db.ScanAliases(map[string]string{"u_name": "Name", "c_name": "Company.Name"}).
+//		Raw("SELECT users.name AS u_name, companies.name AS c_name FROM users JOIN companies ON companies.id = users.company_id").Scan(&users)
+func (s *DB) ScanAliases(aliases map[string]string) *DB {
+	return s.Set("gorm:scan_aliases", aliases)
+}
+
+// Label label queries of current chain, the label is injected as a sql comment, so it could be found in logs and
+// database's process list, callbacks could get it with scope.Label to report metrics, e.g:
+//
+//	db.Label("report-export").Find(&orders)
+//	//// /* label:report-export */ SELECT * FROM orders
+func (s *DB) Label(label string) *DB {
+	return s.Set("gorm:label", label)
+}
+
 func (s *DB) Row() *sql.Row {
 	return s.NewScope(s.Value).row()
 }
@@ -232,6 +431,21 @@ func (s *DB) Count(value interface{}) *DB {
 	return s.NewScope(s.Value).count(value).db
 }
 
+// Exists check if any record matches current conditions, without counting all of them
+func (s *DB) Exists() (bool, error) {
+	scope := s.NewScope(s.Value)
+	exists := scope.exists()
+	return exists, scope.db.Error
+}
+
+// EstimatedCount get approximate rows of current model's table from database statistics, which is much faster
+// than Count for huge tables, it falls back to Count for small tables or when there are any conditions
+func (s *DB) EstimatedCount() (int64, error) {
+	scope := s.NewScope(s.Value)
+	count := scope.estimatedCount()
+	return count, scope.db.Error
+}
+
 func (s *DB) Related(value interface{}, foreignKeys ...string) *DB {
 	return s.clone().NewScope(s.Value).related(value, foreignKeys...).db
 }
@@ -262,10 +476,13 @@ func (s *DB) FirstOrCreate(out interface{}, where ...interface{}) *DB {
 	return c
 }
 
+// Update update the attribute as Updates, e.g. db.Model(&user).Update("name", "hello")
 func (s *DB) Update(attrs ...interface{}) *DB {
 	return s.Updates(toSearchableMap(attrs...), true)
 }
 
+// Updates update attributes with a struct or map, BeforeSave/BeforeUpdate/AfterUpdate/AfterSave callbacks are called,
+// `updated_at` is set and associations are saved
 func (s *DB) Updates(values interface{}, ignoreProtectedAttrs ...bool) *DB {
 	return s.clone().NewScope(s.Value).
 		Set("gorm:ignore_protected_attrs", len(ignoreProtectedAttrs) > 0).
@@ -273,24 +490,98 @@ func (s *DB) Updates(values interface{}, ignoreProtectedAttrs ...bool) *DB {
 		callCallbacks(s.parent.callback.updates).db
 }
 
+// UpdateColumn update the column as UpdateColumns, e.g. db.Model(&user).UpdateColumn("name", "hello")
 func (s *DB) UpdateColumn(attrs ...interface{}) *DB {
 	return s.UpdateColumns(toSearchableMap(attrs...))
 }
 
+// UpdateColumns update columns with a struct or map as they are, callbacks aren't called, `updated_at` isn't set
+// and associations aren't saved
 func (s *DB) UpdateColumns(values interface{}) *DB {
+	return s.updateColumns(values, false)
+}
+
+// UpdateColumnsWithTimestamps update columns as UpdateColumns, but set `updated_at` and touch parents as Updates, e.g:
+//
+//	db.Model(&user).UpdateColumnsWithTimestamps(map[string]interface{}{"name": "hello"})
+//	//// UPDATE users SET name='hello', updated_at='2013-11-17 21:34:10' WHERE id=111;
+func (s *DB) UpdateColumnsWithTimestamps(values interface{}) *DB {
+	return s.updateColumns(values, true)
+}
+
+func (s *DB) updateColumns(values interface{}, timestamps bool) *DB {
 	return s.clone().NewScope(s.Value).
 		Set("gorm:update_column", true).
+		Set("gorm:update_column_timestamps", timestamps).
 		Set("gorm:save_associations", false).
 		InstanceSet("gorm:update_interface", values).
 		callCallbacks(s.parent.callback.updates).db
 }
 
+// Increment increase the column atomically without reading it first, conditions are the model's primary key and
+// where conditions, the field of the model is refreshed with the increased value, e.g:
+//
+//	db.Model(&counter).Increment("hits", 5)
+//	//// UPDATE counters SET hits = hits + 5 WHERE id = 111;
+//	//// SELECT hits FROM counters WHERE id = 111;
+func (s *DB) Increment(column string, value interface{}) *DB {
+	return s.incrementColumn(column, "+", value)
+}
+
+// Decrement decrease the column atomically without reading it first, as Increment
+func (s *DB) Decrement(column string, value interface{}) *DB {
+	return s.incrementColumn(column, "-", value)
+}
+
+func (s *DB) incrementColumn(column string, operator string, value interface{}) *DB {
+	scope := s.clone().NewScope(s.Value)
+	field, ok := scope.FieldByName(column)
+	if !ok || !field.IsNormal {
+		scope.Err(fmt.Errorf("can't find field %v to update", column))
+		return scope.db
+	}
+
+	quotedColumn := scope.Quote(field.DBName)
+	db := s.UpdateColumn(field.DBName, Expr(fmt.Sprintf("%v %v ?", quotedColumn, operator), value))
+	if db.Error == nil && !scope.PrimaryKeyZero() && field.Field.CanAddr() {
+		db.err(s.New().Model(s.Value).Select(quotedColumn).Row().Scan(field.Field.Addr().Interface()))
+	}
+	return db
+}
+
+// UpdateFrom update current model's table with values from another table joined on condition, e.g:
+//
+//	db.Model(&Order{}).UpdateFrom("users", "users.id = orders.user_id").Where("orders.user_name = ?", "").UpdateColumn("user_name", gorm.Expr("users.name"))
+//	//// MySQL: UPDATE orders JOIN users ON users.id = orders.user_id SET orders.user_name = users.name WHERE (orders.user_name = '')
+//	//// Postgres: UPDATE orders SET user_name = users.name FROM users WHERE (users.id = orders.user_id) AND (orders.user_name = '')
+func (s *DB) UpdateFrom(table string, on string) *DB {
+	return s.clone().search.UpdateFrom(table, on).db
+}
+
+// DeleteUsing delete records matching conditions on another table joined on condition, e.g:
+//
+//	db.DeleteUsing("users", "users.id = orders.user_id").Where("users.banned = ?", true).Delete(&Order{})
+//	//// MySQL: DELETE orders FROM orders JOIN users ON users.id = orders.user_id WHERE (users.banned = true)
+//	//// Postgres: DELETE FROM orders USING users WHERE (users.id = orders.user_id) AND (users.banned = true)
+func (s *DB) DeleteUsing(table string, on string) *DB {
+	return s.clone().search.DeleteUsing(table, on).db
+}
+
+// Save insert the value if all of its primary keys are blank, otherwise update all of its fields by all primary keys,
+// including blank ones of composite keys, it doesn't insert the value if no record is updated, e.g:
+//
+//	db.Save(&User{Name: "jinzhu"})
+//	//// INSERT INTO users (name) VALUES ('jinzhu');
+//	db.Save(&UserTopic{UserId: 1, Topic: "go", Level: 2})
+//	//// UPDATE user_topics SET level = 2 WHERE (user_id = 1) AND (topic = 'go');
+//
+// use FirstOrCreate or Upsert to insert records with non auto increment keys
 func (s *DB) Save(value interface{}) *DB {
 	scope := s.clone().NewScope(value)
-	if scope.PrimaryKeyZero() {
+	if scope.PrimaryKeysZero() {
 		return scope.callCallbacks(s.parent.callback.creates).db
 	}
-	return scope.callCallbacks(s.parent.callback.updates).db
+	return scope.InstanceSet("gorm:update_by_primary_keys", true).callCallbacks(s.parent.callback.updates).db
 }
 
 func (s *DB) Create(value interface{}) *DB {
@@ -303,14 +594,26 @@ func (s *DB) CreateIgnore(value interface{}) *DB {
 	return scope.callCallbacks(s.parent.callback.creates).db
 }
 
+// BatchCreate insert records of the slice with multi-row statements in a transaction, split by BatchSize
 func (s *DB) BatchCreate(value interface{}) *DB {
-	scope := s.clone().NewScope(value).InstanceSet("gorm:insert_ignore", false)
-	return scope.callCallbacks(s.parent.callback.batch_creates).db
+	return s.batchCreate(value, func(scope *Scope) *Scope {
+		return scope.InstanceSet("gorm:insert_ignore", false)
+	})
 }
 
+// BatchCreateIgnore insert records as BatchCreate, with INSERT IGNORE
 func (s *DB) BatchCreateIgnore(value interface{}) *DB {
-	scope := s.clone().NewScope(value).InstanceSet("gorm:insert_ignore", true)
-	return scope.callCallbacks(s.parent.callback.batch_creates).db
+	return s.batchCreate(value, func(scope *Scope) *Scope {
+		return scope.InstanceSet("gorm:insert_ignore", true)
+	})
+}
+
+// CreateFromSelect insert rows selected by query into current model's table, e.g:
+//
+//	db.Model(&ArchivedOrder{}).CreateFromSelect(db.Model(&Order{}).Where("created_at < ?", lastYear))
+//	//// INSERT INTO archived_orders (id,user_id,amount) SELECT id,user_id,amount FROM orders WHERE (created_at < '2015-01-01')
+func (s *DB) CreateFromSelect(query *DB) *DB {
+	return s.clone().NewScope(s.Value).createFromSelect(query).db
 }
 
 func (s *DB) Delete(value interface{}, where ...interface{}) *DB {
@@ -342,14 +645,40 @@ func (s *DB) Table(name string) *DB {
 	return clone
 }
 
+// From select from a sub query, alias is used as the table name in conditions
+//
+//	ranked := db.Model(&Order{}).Select([]interface{}{"*", gorm.RowNumber().PartitionBy("user_id").OrderBy("created_at DESC").As("rn")})
+//	db.From(ranked, "orders").Where("rn = ?", 1).Find(&orders)
+func (s *DB) From(query *DB, alias string) *DB {
+	clone := s.clone()
+	clone.search.From(query, alias)
+	clone.Value = nil
+	return clone
+}
+
 func (s *DB) Debug() *DB {
 	return s.clone().LogMode(true)
 }
 
+// CallbackTiming is how long a callback took
+type CallbackTiming struct {
+	Name     string
+	Duration time.Duration
+}
+
+// CallbackTimings get how long each callback of the operation took in debug mode, to tell slow hooks from slow sql, e.g:
+//
+//	for _, timing := range db.Debug().Delete(&user).CallbackTimings() {
+//		fmt.Println(timing.Name, timing.Duration)
+//	}
+func (s *DB) CallbackTimings() []CallbackTiming {
+	return s.callbackTimings
+}
+
 func (s *DB) Begin() *DB {
 	c := s.clone()
 	if db, ok := c.db.(sqlDb); ok {
-		tx, err := db.Begin()
+		tx, err := c.beginTx(db)
 		c.db = interface{}(tx).(sqlCommon)
 		c.err(err)
 	} else {
@@ -376,14 +705,30 @@ func (s *DB) Rollback() *DB {
 	return s
 }
 
+// NewRecord check if the value would be inserted by Save, that is all of its primary keys are blank
 func (s *DB) NewRecord(value interface{}) bool {
-	return s.clone().NewScope(value).PrimaryKeyZero()
+	return s.clone().NewScope(value).PrimaryKeysZero()
 }
 
 func (s *DB) RecordNotFound() bool {
 	return s.Error == RecordNotFound
 }
 
+// Result is the outcome of an operation, LastInsertId is the id reported by drivers supporting it
+type Result struct {
+	RowsAffected int64
+	LastInsertId int64
+	Error        error
+}
+
+// Result get the outcome of the operation returned the DB, it is a copy, so it won't change when the DB is reused, e.g:
+//
+//	result := db.Where("age < ?", 18).Delete(&User{}).Result()
+//	fmt.Println(result.RowsAffected, result.Error)
+func (s *DB) Result() Result {
+	return Result{RowsAffected: s.RowsAffected, LastInsertId: s.lastInsertId, Error: s.Error}
+}
+
 // Migrations
 func (s *DB) CreateTable(value interface{}) *DB {
 	return s.clone().NewScope(value).createTable().db
@@ -411,6 +756,15 @@ func (s *DB) AutoMigrate(values ...interface{}) *DB {
 	return db
 }
 
+// AlterColumns let AutoMigrate alter existing columns differing from fields' definitions, e.g. changed types, sizes
+// or defaults, AutoMigrate returns *DifferingColumnsError listing them without it, narrowing columns requires
+// AllowDestructive too, e.g:
+//
+//	db.AlterColumns(true).AutoMigrate(&User{})
+func (s *DB) AlterColumns(alter bool) *DB {
+	return s.Set("gorm:alter_columns", alter)
+}
+
 func (s *DB) ModifyColumn(column string, typ string) *DB {
 	s.clone().NewScope(s.Value).modifyColumn(column, typ)
 	return s
@@ -431,6 +785,27 @@ func (s *DB) AddUniqueIndex(indexName string, column ...string) *DB {
 	return s
 }
 
+// AddPartialIndex add index only covering rows matching where, it is supported by Postgres, MSSQL and sqlite3, e.g:
+//
+//	db.Model(&User{}).AddPartialIndex("idx_users_active_name", "active = true", "name")
+func (s *DB) AddPartialIndex(indexName string, where string, column ...string) *DB {
+	return s.clone().NewScope(s.Value).createIndex(Index{Name: indexName, Parts: column, Where: where}).db
+}
+
+// AddUniquePartialIndex add unique index only covering rows matching where, so unique columns of soft deleted
+// records could be reused, e.g:
+//
+//	db.Model(&User{}).AddUniquePartialIndex("uix_users_email", "deleted_at IS NULL", "email")
+func (s *DB) AddUniquePartialIndex(indexName string, where string, column ...string) *DB {
+	return s.clone().NewScope(s.Value).createIndex(Index{Name: indexName, Parts: column, Unique: true, Where: where}).db
+}
+
+// AddFullTextIndex add full-text index used by FullTextSearch, columns should be the same with searching
+func (s *DB) AddFullTextIndex(indexName string, column ...string) *DB {
+	s.clone().NewScope(s.Value).addFullTextIndex(indexName, column...)
+	return s
+}
+
 func (s *DB) RemoveIndex(indexName string) *DB {
 	s.clone().NewScope(s.Value).removeIndex(indexName)
 	return s
@@ -471,11 +846,32 @@ func (s *DB) Preload(column string, conditions ...interface{}) *DB {
 	return s.clone().search.Preload(column, conditions...).db
 }
 
-// Set set value by name
+// PreloadCount count has one, has many or many to many associations of found records with a GROUP BY query into
+// their fields named association + `Count`, which should be ignored as columns, without loading associations, e.g:
+//
+//	type Post struct {
+//		Id            int64
+//		Comments      []Comment
+//		CommentsCount int `sql:"-"`
+//	}
+//
+//	db.PreloadCount("Comments", "approved = ?", true).Find(&posts)
+//	//// SELECT post_id, count(*) FROM comments WHERE post_id IN (1,2,3) AND approved = true GROUP BY post_id
+func (s *DB) PreloadCount(column string, conditions ...interface{}) *DB {
+	return s.clone().search.PreloadCount(column, conditions...).db
+}
+
+// Set set value by name, the value is only visible to the returned DB and chains built from it,
+// all callbacks of their operations could get it with scope.Get, e.g:
+//
+//	tenantDB := db.Set("tenant_id", 1)
+//	tenantDB.Find(&notes) // callbacks get tenant_id 1
+//	db.Find(&notes)       // callbacks don't see tenant_id
 func (s *DB) Set(name string, value interface{}) *DB {
 	return s.clone().InstantSet(name, value)
 }
 
+// InstantSet set value by name into current DB without cloning, it changes all chains built from it later
 func (s *DB) InstantSet(name string, value interface{}) *DB {
 	s.values[name] = value
 	return s
@@ -487,7 +883,21 @@ func (s *DB) Get(name string) (value interface{}, ok bool) {
 	return
 }
 
-func (s *DB) SetJoinTableHandler(source interface{}, column string, handler JoinTableHandlerInterface) {
+// GetAll get a copy of all values set to current DB
+func (s *DB) GetAll() map[string]interface{} {
+	values := make(map[string]interface{}, len(s.values))
+	for key, value := range s.values {
+		values[key] = value
+	}
+	return values
+}
+
+// SetJoinTableHandler replace the join table handler of the many to many field, so custom join table logic, like
+// soft deleted join rows or extra audit columns, is used by saving, associations and preloading, the join table is
+// migrated with the handler, e.g:
+//
+//	db.SetJoinTableHandler(&User{}, "Friends", &UserFriend{})
+func (s *DB) SetJoinTableHandler(source interface{}, column string, handler JoinTableHandlerInterface) error {
 	for _, field := range s.NewScope(source).GetModelStruct().StructFields {
 		if field.Name == column || field.DBName == column {
 			if many2many_slice, ok := ParseTagSetting(field.Tag)["MANY2MANY"]; ok {
@@ -496,10 +906,11 @@ func (s *DB) SetJoinTableHandler(source interface{}, column string, handler Join
 				destination := (&Scope{Value: reflect.New(field.Struct.Type).Interface()}).GetModelStruct().ModelType
 				handler.Setup(field.Relationship, many2many, source, destination)
 				field.Relationship.JoinTableHandler = handler
-				s.Table(handler.Table(s)).AutoMigrate(handler)
+				return s.Table(handler.Table(s)).AutoMigrate(handler).Error
 			}
 		}
 	}
+	return fmt.Errorf("%v is not a many to many field of %T", column, source)
 }
 
 /*
@@ -23,7 +23,7 @@ func (s *DB) err(err error) error {
 	if err != nil {
 		if err != RecordNotFound {
 			if s.logMode == 0 {
-				go s.print(fileWithLineNum(), err)
+				go s.print("error", fileWithLineNum(), err)
 			} else {
 				s.log(err)
 			}
@@ -45,6 +45,6 @@ func (s *DB) log(v ...interface{}) {
 
 func (s *DB) slog(sql string, t time.Time, vars ...interface{}) {
 	if s.logMode == 2 {
-		s.print("sql", fileWithLineNum(), NowFunc().Sub(t), sql, vars)
+		s.print("sql", fileWithLineNum(), NowFunc().Sub(t), sql, maskSensitiveVars(vars))
 	}
 }
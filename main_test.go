@@ -449,6 +449,26 @@ func TestHaving(t *testing.T) {
 	}
 }
 
+func TestGroupAndHavingWithMap(t *testing.T) {
+	DB.Save(&User{Name: "GroupUser1", Age: 1}).Save(&User{Name: "GroupUser1", Age: 2}).Save(&User{Name: "GroupUser2", Age: 3})
+
+	type result struct {
+		Name  string
+		Total int64
+	}
+	var results []result
+	err := DB.Model(&User{}).Select("name, count(*) as Total").Where("name LIKE ?", "GroupUser%").
+		Group([]string{"name"}).Having(map[string]interface{}{"name": "GroupUser1"}).Scan(&results).Error
+
+	if err != nil {
+		t.Errorf("Should not raise any error, but got %v", err)
+	}
+
+	if len(results) != 1 || results[0].Name != "GroupUser1" || results[0].Total != 2 {
+		t.Errorf("Should scan aggregate results into struct, but got %+v", results)
+	}
+}
+
 func DialectHasTzSupport() bool {
 	// NB: mssql and FoundationDB do not support time zones.
 	if dialect := os.Getenv("GORM_DIALECT"); dialect == "mssql" || dialect == "foundation" {
@@ -555,6 +575,29 @@ func TestSetAndGet(t *testing.T) {
 	if _, ok := DB.Get("non_existing"); ok {
 		t.Errorf("Get non existing key should return error")
 	}
+
+	scoped := DB.Set("hello", "world")
+	scoped.Set("hello", "gorm")
+	if value, _ := scoped.Get("hello"); value != "world" {
+		t.Errorf("Set should not change values of the DB it is called on, but got %v", value)
+	}
+	if _, ok := DB.Get("hello"); ok {
+		t.Errorf("Set should not leak into parent DB")
+	}
+
+	values := scoped.GetAll()
+	if values["hello"] != "world" {
+		t.Errorf("GetAll should return all values, but got %v", values)
+	}
+	values["hello"] = "changed"
+	if value, _ := scoped.Get("hello"); value != "world" {
+		t.Errorf("GetAll should return a copy of values")
+	}
+
+	var user User
+	if _, ok := DB.First(&user).Get("gorm:order_by_primary_key"); ok {
+		t.Errorf("settings of an operation should not leak into returned DB")
+	}
 }
 
 func TestCompatibilityMode(t *testing.T) {
@@ -631,3 +674,34 @@ func BenchmarkRawSql(b *testing.B) {
 		DB.Exec(deleteSql, id)
 	}
 }
+
+func TestCallProc(t *testing.T) {
+	if dialect := os.Getenv("GORM_DIALECT"); dialect != "mysql" {
+		t.Skip()
+	}
+
+	DB.Save(&User{Name: "call_proc", Age: 30})
+	DB.Exec("DROP PROCEDURE IF EXISTS gorm_users_by_name")
+	if err := DB.Exec(`CREATE PROCEDURE gorm_users_by_name(IN user_name VARCHAR(255), OUT total INT)
+BEGIN
+	SELECT * FROM users WHERE name = user_name;
+	SELECT count(*) AS count FROM users;
+	SELECT count(*) INTO total FROM users WHERE name = user_name;
+END`).Error; err != nil {
+		t.Fatalf("procedure should be created, but got %v", err)
+	}
+
+	var users []User
+	var summary struct{ Count int64 }
+	var total int64
+	if err := DB.CallProc("gorm_users_by_name", "call_proc", sql.Out{Dest: &total}).Scan(&users, &summary).Error; err != nil {
+		t.Errorf("procedure should be called, but got %v", err)
+	}
+	if len(users) != 1 || users[0].Age != 30 || summary.Count == 0 || total != 1 {
+		t.Errorf("result sets and OUT parameters should be scanned, but got %v, %v, %v", len(users), summary.Count, total)
+	}
+
+	if err := DB.CallProc("gorm_users_by_name", "call_proc", sql.Out{Dest: &total}).Exec().Error; err != nil {
+		t.Errorf("procedure should be called without scanning results, but got %v", err)
+	}
+}
@@ -2,8 +2,11 @@ package gorm_test
 
 import (
 	"fmt"
+	"os"
 	"testing"
 	"time"
+
+	"golib/gorm"
 )
 
 func runMigration() {
@@ -121,3 +124,194 @@ func TestAutoMigration(t *testing.T) {
 		t.Error("Big Emails should be saved and fetched correctly")
 	}
 }
+
+type LowerEmail struct {
+	Id    int64
+	Email string
+}
+
+func (LowerEmail) Indexes() []gorm.Index {
+	return []gorm.Index{{Name: "uix_lower_emails_email", Parts: []string{"lower(email)"}, Unique: true}}
+}
+
+func TestExpressionIndexes(t *testing.T) {
+	if dialect := os.Getenv("GORM_DIALECT"); dialect != "mysql" {
+		t.Skip("indexes are compared by AutoMigrate with mysql only")
+	}
+
+	DB.DropTableIfExists(&LowerEmail{})
+	if err := DB.AutoMigrate(&LowerEmail{}).Error; err != nil {
+		t.Errorf("Auto Migrate should not raise any error, but got %v", err)
+	}
+
+	scope := DB.NewScope(&LowerEmail{})
+	if !scope.Dialect().HasIndex(scope, scope.TableName(), "uix_lower_emails_email") {
+		t.Errorf("expression index should be created")
+	}
+
+	if err := DB.AutoMigrate(&LowerEmail{}).Error; err != nil {
+		t.Errorf("migrating again should keep the expression index, but got %v", err)
+	}
+
+	DB.Save(&LowerEmail{Email: "Jinzhu@example.org"})
+	if err := DB.Save(&LowerEmail{Email: "jinzhu@example.org"}).Error; err == nil {
+		t.Errorf("unique expression index should reject emails differing only in case")
+	}
+}
+
+type SoftDeletedEmail struct {
+	Id        int64
+	Email     string
+	DeletedAt *time.Time
+}
+
+func TestPartialIndexes(t *testing.T) {
+	if dialect := os.Getenv("GORM_DIALECT"); dialect == "mysql" || dialect == "foundation" {
+		t.Skip("partial index is not supported by mysql and foundation")
+	}
+
+	DB.DropTableIfExists(&SoftDeletedEmail{})
+	DB.AutoMigrate(&SoftDeletedEmail{})
+	if err := DB.Model(&SoftDeletedEmail{}).AddUniquePartialIndex("uix_soft_deleted_emails_email", "deleted_at IS NULL", "email").Error; err != nil {
+		t.Errorf("should add partial index, but got %v", err)
+	}
+
+	email := SoftDeletedEmail{Email: "jinzhu@example.org"}
+	DB.Save(&email)
+	if err := DB.Save(&SoftDeletedEmail{Email: "jinzhu@example.org"}).Error; err == nil {
+		t.Errorf("unique partial index should reject duplicated emails of records not deleted")
+	}
+
+	DB.Delete(&email)
+	if err := DB.Save(&SoftDeletedEmail{Email: "jinzhu@example.org"}).Error; err != nil {
+		t.Errorf("emails of soft deleted records should be reused, but got %v", err)
+	}
+
+	DB.DropTableIfExists(&TaggedPartialEmail{})
+	if err := DB.AutoMigrate(&TaggedPartialEmail{}).Error; err != nil {
+		t.Errorf("should migrate partial indexes of tags, but got %v", err)
+	}
+	if scope := DB.NewScope(&TaggedPartialEmail{}); !scope.Dialect().HasIndex(scope, scope.TableName(), "uix_tagged_partial_emails_email") {
+		t.Errorf("partial index of tags should be created")
+	}
+	tagged := TaggedPartialEmail{Email: "jinzhu@example.org"}
+	DB.Save(&tagged)
+	if err := DB.Save(&TaggedPartialEmail{Email: "jinzhu@example.org"}).Error; err == nil {
+		t.Errorf("unique partial index of tags should reject duplicated emails of records not deleted")
+	}
+	DB.Delete(&tagged)
+	if err := DB.Save(&TaggedPartialEmail{Email: "jinzhu@example.org"}).Error; err != nil {
+		t.Errorf("emails of soft deleted records should be reused, but got %v", err)
+	}
+	if err := DB.AutoMigrate(&TaggedPartialEmail{}).Error; err != nil {
+		t.Errorf("partial indexes of tags should be kept when migrate again, but got %v", err)
+	}
+}
+
+type TaggedPartialEmail struct {
+	Id        int64
+	Email     string `sql:"size:100;unique_index:uix_tagged_partial_emails_email;where:deleted_at IS NULL"`
+	DeletedAt *time.Time
+}
+
+type ActiveEmail struct {
+	_     struct{} `gorm:"view"`
+	Id    int64
+	Email string
+}
+
+func TestViews(t *testing.T) {
+	DB.AutoMigrate(&SoftDeletedEmail{})
+	DB.Save(&SoftDeletedEmail{Email: "view@example.org"})
+
+	if err := DB.Migrator().CreateView("active_emails", "SELECT id, email FROM soft_deleted_emails WHERE deleted_at IS NULL", true); err != nil {
+		t.Errorf("should create view, but got %v", err)
+	}
+	defer DB.Migrator().DropView("active_emails")
+
+	var emails []ActiveEmail
+	if err := DB.Where("email = ?", "view@example.org").Find(&emails).Error; err != nil || len(emails) == 0 {
+		t.Errorf("should find records from view, but got %v, %v", err, len(emails))
+	}
+
+	if err := DB.Save(&ActiveEmail{Email: "view@example.org"}).Error; err != gorm.ViewNotWritable {
+		t.Errorf("models backed by views should not be writable, but got %v", err)
+	}
+	if err := DB.Delete(&ActiveEmail{Id: 1}).Error; err != gorm.ViewNotWritable {
+		t.Errorf("models backed by views should not be deletable, but got %v", err)
+	}
+}
+
+func TestMaterializedViews(t *testing.T) {
+	if dialect := os.Getenv("GORM_DIALECT"); dialect != "postgres" {
+		t.Skip("materialized view is only supported by postgres")
+	}
+
+	DB.AutoMigrate(&SoftDeletedEmail{})
+	DB.Migrator().DropMaterializedView("email_counts")
+	if err := DB.Migrator().CreateMaterializedView("email_counts", "SELECT email, count(*) AS total FROM soft_deleted_emails GROUP BY email"); err != nil {
+		t.Errorf("should create materialized view, but got %v", err)
+	}
+	defer DB.Migrator().DropMaterializedView("email_counts")
+	DB.Exec("CREATE UNIQUE INDEX uix_email_counts_email ON email_counts(email)")
+
+	DB.Save(&SoftDeletedEmail{Email: "materialized@example.org"})
+	var total int64
+	DB.Table("email_counts").Where("email = ?", "materialized@example.org").Count(&total)
+	if total != 0 {
+		t.Errorf("materialized view should not be changed before refreshing")
+	}
+
+	if err := DB.Migrator().RefreshMaterializedView("email_counts", true); err != nil {
+		t.Errorf("should refresh materialized view concurrently, but got %v", err)
+	}
+	DB.Table("email_counts").Where("email = ?", "materialized@example.org").Count(&total)
+	if total != 1 {
+		t.Errorf("materialized view should be refreshed, but got %v", total)
+	}
+}
+
+func TestTriggersAndFunctions(t *testing.T) {
+	if dialect := os.Getenv("GORM_DIALECT"); dialect != "mysql" {
+		t.Skip("triggers and functions are tested with mysql syntax")
+	}
+
+	DB.AutoMigrate(&SoftDeletedEmail{})
+	DB.Model(&SoftDeletedEmail{}).Migrator().DropTrigger("trg_soft_deleted_emails_lower")
+	trigger := "CREATE TRIGGER trg_soft_deleted_emails_lower BEFORE INSERT ON soft_deleted_emails FOR EACH ROW SET NEW.email = lower(NEW.email)"
+	for i := 0; i < 2; i++ {
+		if err := DB.Model(&SoftDeletedEmail{}).Migrator().CreateTrigger("trg_soft_deleted_emails_lower", trigger); err != nil {
+			t.Errorf("should create trigger only if it doesn't exist, but got %v", err)
+		}
+	}
+	if !DB.Model(&SoftDeletedEmail{}).Migrator().HasTrigger("trg_soft_deleted_emails_lower") {
+		t.Errorf("trigger should be created")
+	}
+
+	email := SoftDeletedEmail{Email: "Trigger@Example.org"}
+	DB.Save(&email)
+	DB.First(&email, email.Id)
+	if email.Email != "trigger@example.org" {
+		t.Errorf("trigger should be run, but got %v", email.Email)
+	}
+
+	DB.Model(&SoftDeletedEmail{}).Migrator().DropTrigger("trg_soft_deleted_emails_lower")
+	if DB.Model(&SoftDeletedEmail{}).Migrator().HasTrigger("trg_soft_deleted_emails_lower") {
+		t.Errorf("trigger should be dropped")
+	}
+
+	DB.Migrator().DropFunction("email_domain")
+	function := "CREATE FUNCTION email_domain(email VARCHAR(255)) RETURNS VARCHAR(255) DETERMINISTIC RETURN substring_index(email, '@', -1)"
+	for i := 0; i < 2; i++ {
+		if err := DB.Migrator().CreateFunction("email_domain", function); err != nil {
+			t.Errorf("should create function only if it doesn't exist, but got %v", err)
+		}
+	}
+	if !DB.Migrator().HasFunction("email_domain") {
+		t.Errorf("function should be created")
+	}
+	DB.Migrator().DropFunction("email_domain")
+	if DB.Migrator().HasFunction("email_domain") {
+		t.Errorf("function should be dropped")
+	}
+}
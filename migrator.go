@@ -0,0 +1,27 @@
+package gorm
+
+// Migrator manages the schema of the database besides tables of models, like views, triggers and stored functions,
+// and introspects the live schema, options of the DB like AllowDestructive and AlterColumns are kept, e.g:
+//
+//	db.Migrator().CreateView("active_users", "SELECT id, name FROM users WHERE active = true", true)
+//	db.Model(&Order{}).Migrator().CreateTrigger("trg_orders_audit", sql)
+//	columns, err := db.Migrator().Columns("users")
+type Migrator struct {
+	db *DB
+}
+
+// Migrator get the Migrator of the DB, triggers are of the table of current model
+func (s *DB) Migrator() Migrator {
+	return Migrator{db: s}
+}
+
+// AutoMigrate run AutoMigrate for values, destructive operations are refused unless AllowDestructive is set, e.g:
+//
+//	err := db.AllowDestructive(true).AlterColumns(true).Migrator().AutoMigrate(&User{}, &Product{})
+func (m Migrator) AutoMigrate(values ...interface{}) error {
+	return m.db.AutoMigrate(values...).Error
+}
+
+func (m Migrator) newScope() *Scope {
+	return m.db.clone().NewScope(m.db.Value)
+}
@@ -1,6 +1,10 @@
 package gorm
 
-import "time"
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+)
 
 type Model struct {
 	ID        uint `gorm:"primary_key"`
@@ -8,3 +12,36 @@ type Model struct {
 	UpdatedAt time.Time
 	DeletedAt *time.Time
 }
+
+// UUIDModel is Model with UUID primary key, the ID is generated when creating if it is blank
+type UUIDModel struct {
+	ID        string `gorm:"primary_key;uuid" sql:"size:36"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+// UnixTimeModel is Model with timestamps saved as unix seconds, without soft delete
+type UnixTimeModel struct {
+	ID        uint `gorm:"primary_key"`
+	CreatedAt int64
+	UpdatedAt int64
+}
+
+// TimestampModel is Model without soft delete, records are deleted from database
+type TimestampModel struct {
+	ID        uint `gorm:"primary_key"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewUUID generate a random (version 4) UUID, like `4b0a8d3c-7f4e-4c1a-9a53-2f8e0d1c6b7a`
+func NewUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
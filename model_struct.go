@@ -44,6 +44,7 @@ type ModelStruct struct {
 	PrimaryFields    []*StructField
 	StructFields     []*StructField
 	ModelType        reflect.Type
+	IsView           bool
 	defaultTableName string
 }
 
@@ -52,36 +53,40 @@ func (s ModelStruct) TableName(db *DB) string {
 }
 
 type StructField struct {
-	DBName          string
-	Name            string
-	Names           []string
-	IsPrimaryKey    bool
-	IsNormal        bool
-	IsIgnored       bool
-	IsScanner       bool
-	HasDefaultValue bool
-	Tag             reflect.StructTag
-	Struct          reflect.StructField
-	IsForeignKey    bool
-	IsAutoIncrement bool
-	Relationship    *Relationship
+	DBName           string
+	Name             string
+	Names            []string
+	IsPrimaryKey     bool
+	IsNormal         bool
+	IsIgnored        bool
+	IsScanner        bool
+	HasDefaultValue  bool
+	Tag              reflect.StructTag
+	Struct           reflect.StructField
+	IsForeignKey     bool
+	IsAutoIncrement  bool
+	IsReadOnly       bool
+	IsMigrateIgnored bool
+	Relationship     *Relationship
 }
 
 func (structField *StructField) clone() *StructField {
 	return &StructField{
-		DBName:          structField.DBName,
-		Name:            structField.Name,
-		Names:           structField.Names,
-		IsPrimaryKey:    structField.IsPrimaryKey,
-		IsNormal:        structField.IsNormal,
-		IsIgnored:       structField.IsIgnored,
-		IsScanner:       structField.IsScanner,
-		HasDefaultValue: structField.HasDefaultValue,
-		Tag:             structField.Tag,
-		Struct:          structField.Struct,
-		IsForeignKey:    structField.IsForeignKey,
-		Relationship:    structField.Relationship,
-		IsAutoIncrement: structField.IsAutoIncrement,
+		DBName:           structField.DBName,
+		Name:             structField.Name,
+		Names:            structField.Names,
+		IsPrimaryKey:     structField.IsPrimaryKey,
+		IsNormal:         structField.IsNormal,
+		IsIgnored:        structField.IsIgnored,
+		IsScanner:        structField.IsScanner,
+		HasDefaultValue:  structField.HasDefaultValue,
+		Tag:              structField.Tag,
+		Struct:           structField.Struct,
+		IsForeignKey:     structField.IsForeignKey,
+		Relationship:     structField.Relationship,
+		IsAutoIncrement:  structField.IsAutoIncrement,
+		IsReadOnly:       structField.IsReadOnly,
+		IsMigrateIgnored: structField.IsMigrateIgnored,
 	}
 }
 
@@ -94,11 +99,22 @@ type Relationship struct {
 	AssociationForeignFieldName string
 	AssociationForeignDBName    string
 	JoinTableHandler            JoinTableHandlerInterface
+	PolymorphicTypes            map[string]string
+	OrphanPolicy                string
 }
 
 var pluralMapKeys = []*regexp.Regexp{regexp.MustCompile("ch$"), regexp.MustCompile("ss$"), regexp.MustCompile("sh$"), regexp.MustCompile("day$"), regexp.MustCompile("y$"), regexp.MustCompile("x$"), regexp.MustCompile("([^s])s?$")}
 var pluralMapValues = []string{"ches", "sses", "shes", "days", "ies", "xes", "${1}s"}
 
+func pluralTableName(name string) string {
+	for index, reg := range pluralMapKeys {
+		if reg.MatchString(name) {
+			name = reg.ReplaceAllString(name, pluralMapValues[index])
+		}
+	}
+	return name
+}
+
 func (scope *Scope) GetModelStruct() *ModelStruct {
 	var modelStruct ModelStruct
 
@@ -144,16 +160,19 @@ func (scope *Scope) GetModelStruct() *ModelStruct {
 	} else {
 		name := ToDBName(scopeType.Name())
 		if scope.db == nil || !scope.db.parent.singularTable {
-			for index, reg := range pluralMapKeys {
-				if reg.MatchString(name) {
-					name = reg.ReplaceAllString(name, pluralMapValues[index])
-				}
-			}
+			name = pluralTableName(name)
 		}
 
 		modelStruct.defaultTableName = name
 	}
 
+	// Models having a field tagged with `view`, usually `_ struct{} `gorm:"view"``, are backed by views
+	for i := 0; i < scopeType.NumField(); i++ {
+		if _, ok := ParseTagSetting(scopeType.Field(i).Tag)["VIEW"]; ok {
+			modelStruct.IsView = true
+		}
+	}
+
 	// Get all fields
 	fields := []*StructField{}
 	for i := 0; i < scopeType.NumField(); i++ {
@@ -182,10 +201,21 @@ func (scope *Scope) GetModelStruct() *ModelStruct {
 					field.IsAutoIncrement = true
 				}
 
+				// read only fields, like generated columns, are created and queried but never written
+				if _, ok := gormSettings["->"]; ok || isGeneratedColumn(gormSettings["TYPE"]) {
+					field.IsReadOnly = true
+				}
+				if _, ok := gormSettings["SERVER_TIMESTAMP"]; ok {
+					field.IsReadOnly = true
+				}
+				if _, ok := gormSettings["IGNORE_MIGRATE"]; ok {
+					field.IsMigrateIgnored = true
+				}
+
 				if value, ok := gormSettings["COLUMN"]; ok {
 					field.DBName = value
 				} else {
-					field.DBName = ToDBName(fieldStruct.Name)
+					field.DBName = ColumnNameHandler(fieldStruct.Name)
 				}
 			}
 			fields = append(fields, field)
@@ -212,17 +242,22 @@ func (scope *Scope) GetModelStruct() *ModelStruct {
 				if !field.IsNormal {
 					gormSettings := ParseTagSetting(field.Tag)
 					toScope := scope.New(reflect.New(fieldStruct.Type).Interface())
+					// has one and belongs to associations declared as interfaces are associations of registered models
+					if modelType := interfaceModelType(indirectType); modelType != nil && gormSettings["POLYMORPHIC"] == "" {
+						indirectType = modelType
+						toScope = scope.New(reflect.New(modelType).Interface())
+					}
 
 					getForeignField := func(column string, fields []*StructField) *StructField {
 						for _, field := range fields {
-							if field.Name == column || field.DBName == ToDBName(column) {
+							if field.Name == column || field.DBName == ToDBName(column) || field.DBName == ColumnNameHandler(column) {
 								return field
 							}
 						}
 						return nil
 					}
 
-					var relationship = &Relationship{}
+					var relationship = &Relationship{OrphanPolicy: strings.ToLower(gormSettings["ORPHAN"])}
 
 					foreignKey := ""
 					if _, ok := gormSettings["FOREIGNKEY"]; ok {
@@ -272,6 +307,12 @@ func (scope *Scope) GetModelStruct() *ModelStruct {
 
 								joinTableHandler := JoinTableHandler{}
 								joinTableHandler.Setup(relationship, many2many, scopeType, elemType)
+								if _, ok := gormSettings["JOINTABLE_SOFT_DELETE"]; ok {
+									joinTableHandler.SoftDelete = true
+								}
+								if strings.EqualFold(gormSettings["JOINTABLE_KEY"], "none") {
+									joinTableHandler.NoKey = true
+								}
 								relationship.JoinTableHandler = &joinTableHandler
 								field.Relationship = relationship
 							} else {
@@ -293,6 +334,7 @@ func (scope *Scope) GetModelStruct() *ModelStruct {
 							for _, toField := range toScope.GetStructFields() {
 								toField = toField.clone()
 								toField.Names = append([]string{fieldStruct.Name}, toField.Names...)
+								toField.IsMigrateIgnored = toField.IsMigrateIgnored || field.IsMigrateIgnored
 								modelStruct.StructFields = append(modelStruct.StructFields, toField)
 								if toField.IsPrimaryKey {
 									modelStruct.PrimaryFields = append(modelStruct.PrimaryFields, toField)
@@ -326,13 +368,35 @@ func (scope *Scope) GetModelStruct() *ModelStruct {
 								}
 							}
 						}
+					case reflect.Interface:
+						// polymorphic belongs to, the owner is one of models implementing the interface
+						if polymorphic, ok := gormSettings["POLYMORPHIC"]; ok {
+							if polymorphicField := getForeignField(polymorphic+"Id", fields); polymorphicField != nil {
+								if polymorphicType := getForeignField(polymorphic+"Type", fields); polymorphicType != nil {
+									relationship.Kind = "polymorphic_belongs_to"
+									relationship.ForeignFieldName = polymorphicField.Name
+									relationship.ForeignDBName = polymorphicField.DBName
+									relationship.PolymorphicType = polymorphicType.Name
+									relationship.PolymorphicDBName = polymorphicType.DBName
+									relationship.PolymorphicTypes = parsePolymorphicTypes(gormSettings["POLYMORPHIC_TYPES"])
+									polymorphicType.IsForeignKey = true
+									polymorphicField.IsForeignKey = true
+									field.Relationship = relationship
+								}
+							}
+						}
+						if field.Relationship == nil {
+							field.IsNormal = true
+						}
 					default:
 						field.IsNormal = true
 					}
 				}
 
 				if field.IsNormal {
-					if len(modelStruct.PrimaryFields) == 0 && field.DBName == "id" {
+					// column id is the default primary key, so is field Id renamed by ColumnNameHandler
+					if len(modelStruct.PrimaryFields) == 0 && (field.DBName == "id" ||
+						(ToDBName(field.Name) == "id" && field.DBName == ColumnNameHandler(field.Name))) {
 						field.IsPrimaryKey = true
 						modelStruct.PrimaryFields = append(modelStruct.PrimaryFields, field)
 					}
@@ -363,6 +427,14 @@ func (scope *Scope) generateSqlTag(field *StructField) string {
 
 	if value, ok := sqlSettings["TYPE"]; ok {
 		sqlType = value
+	} else if unit, ok := scope.durationSetting(field); ok && unit == 0 {
+		sqlType = "interval"
+	} else if scope.isJsonField(field) {
+		sqlType = scope.Dialect().JsonSqlTag()
+	} else if scope.isLargeObject(field) {
+		sqlType = "oid"
+	} else if scope.isDelimitedField(field) {
+		reflectValue = reflect.ValueOf("")
 	}
 
 	additionalType := ""
@@ -375,13 +447,15 @@ func (scope *Scope) generateSqlTag(field *StructField) string {
 	}
 	if value, ok := sqlSettings["DEFAULT"]; ok {
 		additionalType = additionalType + " DEFAULT " + value
+	} else if _, ok := sqlSettings["SERVER_TIMESTAMP"]; ok {
+		additionalType = additionalType + " " + scope.Dialect().ServerTimestampSql()
 	}
 
 	if field.IsScanner {
 		var getScannerValue func(reflect.Value)
 		getScannerValue = func(value reflect.Value) {
 			reflectValue = value
-			if _, isScanner := reflect.New(reflectValue.Type()).Interface().(sql.Scanner); isScanner && reflectValue.Kind() == reflect.Struct {
+			if _, isScanner := reflect.New(reflectValue.Type()).Interface().(sql.Scanner); isScanner && reflectValue.Kind() == reflect.Struct && reflectValue.Type() != pointType {
 				getScannerValue(reflectValue.Field(0))
 			}
 		}
@@ -399,13 +473,24 @@ func (scope *Scope) generateSqlTag(field *StructField) string {
 		if field.IsPrimaryKey {
 			autoIncrease = true
 		}
+		// values of sequence fields are fetched before insert
+		if _, ok := scope.sequenceOf(field); ok {
+			autoIncrease = false
+		}
 
 		sqlType = scope.Dialect().SqlTag(reflectValue, size, autoIncrease)
+		if sqlType == "" && reflectValue.Type() == pointType {
+			scope.Err(PointNotSupported)
+		}
 		if field.Tag.Get("sql") != "" {
 			fmt.Println(fmt.Sprintf("[warning]field[%s] sql tag has no type", field.Name))
 		}
 	}
 
+	if collation := scope.Dialect().CollationSql(sqlSettings["CHARSET"], sqlSettings["COLLATE"]); collation != "" {
+		sqlType = sqlType + " " + collation
+	}
+
 	if strings.TrimSpace(additionalType) == "" {
 		return sqlType
 	} else {
@@ -413,34 +498,35 @@ func (scope *Scope) generateSqlTag(field *StructField) string {
 	}
 }
 
+var generatedColumnRegexp = regexp.MustCompile(`(?i)(^|\s)(GENERATED\s+ALWAYS\s+)?AS\s*\(`)
+
+// isGeneratedColumn check column type like `varchar(255) GENERATED ALWAYS AS (concat(first, ' ', last)) STORED`
+func isGeneratedColumn(sqlType string) bool {
+	return generatedColumnRegexp.MatchString(sqlType)
+}
+
+// compareFieldAndColumn check if the column in database is the same as the field's definition, dialects'
+// normalizations are handled, e.g. int(11) and int, 'a' and a
 func (scope *Scope) compareFieldAndColumn(field *StructField, column string) bool {
+	return len(scope.diffFieldAndColumn(field, column)) == 0
+}
+
+// diffFieldAndColumn get differences between the column in database and the field's definition
+func (scope *Scope) diffFieldAndColumn(field *StructField, column string) []string {
+	if field.IsMigrateIgnored {
+		return nil
+	}
 	gormMap := ParseTagSetting(field.Tag)
-	if _, ok := gormMap["IGNORE_MIGRATE"]; ok {
-		return true
+	// expressions of generated columns and server timestamps' defaults are not returned with column type
+	if _, ok := gormMap["SERVER_TIMESTAMP"]; ok || isGeneratedColumn(gormMap["TYPE"]) {
+		return nil
 	}
-	sqlTag := scope.generateSqlTag(field)
-	tagParts := strings.Split(sqlTag, " ")
-	columnParts := strings.Split(column, " ")
-	i := 0
-	j := 0
-	for i < len(tagParts) && j < len(columnParts) {
-		if tagParts[i] == "" {
-			i++
-			continue
-		}
-
-		if columnParts[j] == "" {
-			j++
-			continue
-		}
 
-		if strings.ToUpper(tagParts[i]) != strings.ToUpper(columnParts[j]) {
-			return false
-		}
-		i++
-		j++
+	declared := parseColumnDefinition(scope.generateSqlTag(field))
+	if field.IsPrimaryKey {
+		declared.NotNull = true
 	}
-	return true
+	return diffColumnDefinition(declared, parseColumnDefinition(column))
 }
 
 func ParseTagSetting(tags reflect.StructTag) map[string]string {
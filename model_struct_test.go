@@ -4,6 +4,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestParseTagSetting(t *testing.T) {
@@ -12,3 +13,88 @@ func TestParseTagSetting(t *testing.T) {
 	tagSettings := ParseTagSetting(reflect.StructTag(`gorm:"column:F_enabled" sql:"type:tinyint(8) unsigned;not null;default:1;unique_index:I_organization;unique_index:I_certificate"`))
 	tt.Equal("I_organization:I_certificate", tagSettings["UNIQUE_INDEX"])
 }
+
+type collatedTag struct {
+	Name  string `sql:"size:100;charset:utf8mb4;collate:utf8mb4_bin;not null"`
+	Title string `sql:"type:varchar(100)"`
+}
+
+func TestCollationSqlTag(t *testing.T) {
+	tt := assert.New(t)
+
+	db := newFakeDB("mysql", "")
+	scope := db.NewScope(&collatedTag{})
+	name, _ := scope.FieldByName("Name")
+	title, _ := scope.FieldByName("Title")
+
+	tt.Equal("varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL ", scope.generateSqlTag(name.StructField))
+	tt.True(scope.compareFieldAndColumn(name.StructField, "varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL"))
+	tt.False(scope.compareFieldAndColumn(name.StructField, "varchar(100) CHARACTER SET utf8 COLLATE utf8_general_ci NOT NULL"))
+	tt.True(scope.compareFieldAndColumn(title.StructField, "varchar(100) CHARACTER SET utf8 COLLATE utf8_general_ci"))
+}
+
+type legacyNamedRecord struct {
+	Id        int64
+	Enabled   bool
+	Title     string `gorm:"column:F_caption"`
+	DeletedAt time.Time
+}
+
+func TestColumnNameHandler(t *testing.T) {
+	tt := assert.New(t)
+
+	defer func(handler func(string) string) { ColumnNameHandler = handler }(ColumnNameHandler)
+	ColumnNameHandler = AffixedColumnName("F_", "")
+
+	db := newFakeDB("mysql", "")
+	scope := db.NewScope(&legacyNamedRecord{})
+	enabled, _ := scope.FieldByName("Enabled")
+	title, _ := scope.FieldByName("Title")
+
+	tt.Equal("F_enabled", enabled.DBName)
+	tt.Equal("F_caption", title.DBName)
+	tt.Equal("F_id", scope.PrimaryKey())
+	tt.Contains(scope.whereSql(), "`legacy_named_records`.`F_deleted_at` IS NULL")
+	tt.True(scope.changeableDBColumn("F_enabled"))
+	tt.True(scope.New(&legacyNamedRecord{}).db.Select("Enabled").NewScope(&legacyNamedRecord{}).changeableDBColumn("F_enabled"))
+
+	tt.Equal("id", db.NewScope(&legacyKeyedRecord{}).PrimaryKey())
+	tt.Nil(db.NewScope(&legacyRenamedIdRecord{}).PrimaryField())
+}
+
+type legacyKeyedRecord struct {
+	Key  int64 `gorm:"column:id"`
+	Name string
+}
+
+type legacyRenamedIdRecord struct {
+	Id   int64 `gorm:"column:F_no"`
+	Name string
+}
+
+type sharedAudit struct {
+	CreatedBy string
+	UpdatedBy string `sql:"size:100"`
+}
+
+type auditedRecord struct {
+	Id    int64
+	Name  string      `sql:"size:100"`
+	Audit sharedAudit `gorm:"embedded;ignore_migrate"`
+}
+
+func TestIgnoreMigrateEmbeddedStruct(t *testing.T) {
+	tt := assert.New(t)
+
+	db := newFakeDB("mysql", "")
+	scope := db.NewScope(&auditedRecord{})
+	name, _ := scope.FieldByName("Name")
+	createdBy, _ := scope.FieldByName("CreatedBy")
+	updatedBy, _ := scope.FieldByName("UpdatedBy")
+
+	tt.False(name.IsMigrateIgnored)
+	tt.True(createdBy.IsMigrateIgnored)
+	tt.True(scope.compareFieldAndColumn(createdBy.StructField, "int(11)"))
+	tt.True(scope.compareFieldAndColumn(updatedBy.StructField, "text NOT NULL"))
+	tt.False(scope.compareFieldAndColumn(name.StructField, "text NOT NULL"))
+}
@@ -11,20 +11,75 @@ type mssql struct {
 	commonDialect
 }
 
+func (mssql) PlaceholderStyle() PlaceholderStyle {
+	return AtPlaceholder
+}
+
+// CollationSql only render collation, charset is part of collation in mssql
+func (mssql) CollationSql(charset string, collation string) string {
+	if collation == "" {
+		return ""
+	}
+	return "COLLATE " + collation
+}
+
+// CallProcSql is the procedure's name, the driver calls it with RPC, which supports sql.Out arguments
+func (mssql) CallProcSql(name string, args []string) string {
+	return name
+}
+
+func (mssql) CreateViewSql(view string, query string, replace bool) string {
+	if replace {
+		return fmt.Sprintf("CREATE OR ALTER VIEW %v AS %v", view, query)
+	}
+	return fmt.Sprintf("CREATE VIEW %v AS %v", view, query)
+}
+
+// SupportPartialIndex filtered indexes are created with WHERE too
+func (mssql) SupportPartialIndex() bool {
+	return true
+}
+
+func (mssql) MaxBindVars() int {
+	return 2100
+}
+
+// ExplainSql is blank, as plans are returned only after SET SHOWPLAN_TEXT ON in a separated batch
+func (mssql) ExplainSql(sql string) string {
+	return ""
+}
+
+// AppendBlobSql use the .WRITE clause, which appends to varbinary(max) in place
+func (mssql) AppendBlobSql(column string, value string) string {
+	return fmt.Sprintf("%v.WRITE(%v, NULL, NULL)", column, value)
+}
+
+func (mssql) JsonSqlTag() string {
+	return "nvarchar(max)"
+}
+
 func (mssql) HasTop() bool {
 	return true
 }
 
+var mssqlReservedWords = wordSet(`BACKUP BROWSE BULK CLUSTERED DATABASE DBCC DENY DISK DUMP EXEC EXECUTE FILE
+	FILLFACTOR IDENTITY INDEX KEY KILL OFFSETS OPEN PERCENT PLAN PRINT PROC PROCEDURE PUBLIC RULE SCHEMA TOP TRAN
+	TRANSACTION TRIGGER VIEW`)
+
+func (m mssql) IsReservedWord(word string) bool {
+	return m.commonDialect.IsReservedWord(word) || mssqlReservedWords[strings.ToUpper(word)]
+}
+
 func (mssql) SqlTag(value reflect.Value, size int, autoIncrease bool) string {
 	switch value.Kind() {
 	case reflect.Bool:
 		return "bit"
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uintptr:
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
 		if autoIncrease {
 			return "int IDENTITY(1,1)"
 		}
 		return "int"
-	case reflect.Int64, reflect.Uint64:
+	case reflect.Int64, reflect.Uint32, reflect.Uint, reflect.Uint64, reflect.Uintptr:
 		if autoIncrease {
 			return "bigint IDENTITY(1,1)"
 		}
@@ -40,6 +95,9 @@ func (mssql) SqlTag(value reflect.Value, size int, autoIncrease bool) string {
 		if _, ok := value.Interface().(time.Time); ok {
 			return "datetime2"
 		}
+		if _, ok := value.Interface().(Point); ok {
+			return ""
+		}
 	default:
 		if _, ok := value.Interface().([]byte); ok {
 			if size > 0 && size < 65532 {
@@ -62,6 +120,39 @@ func (mssql) databaseName(scope *Scope) string {
 	return ""
 }
 
+func (s mssql) TableInfos(scope *Scope) ([]string, error) {
+	return informationSchemaTables(scope, "table_type = 'BASE TABLE' AND table_catalog = ?", s.databaseName(scope))
+}
+
+func (s mssql) ColumnInfos(scope *Scope, tableName string) ([]ColumnInfo, error) {
+	return informationSchemaColumns(scope, tableName, "",
+		"COLUMNPROPERTY(OBJECT_ID(c.table_schema + '.' + c.table_name), c.column_name, 'IsIdentity') = 1", "c.table_catalog = ?", s.databaseName(scope))
+}
+
+func (mssql) IndexInfos(scope *Scope, tableName string) ([]IndexInfo, error) {
+	rows, err := scope.NewDB().Raw(`SELECT i.name, c.name, i.is_unique, i.is_primary_key FROM sys.indexes i
+	JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+	JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+	WHERE i.object_id = OBJECT_ID(?) AND i.name IS NOT NULL AND ic.is_included_column = 0 ORDER BY i.name, ic.key_ordinal`, tableName).Rows()
+	if err != nil {
+		return nil, err
+	}
+	return scanIndexInfos(rows)
+}
+
+func (mssql) ForeignKeyInfos(scope *Scope, tableName string) ([]ForeignKeyInfo, error) {
+	rows, err := scope.NewDB().Raw(`SELECT fk.name, c.name, rt.name, rc.name FROM sys.foreign_keys fk
+	JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id
+	JOIN sys.columns c ON c.object_id = fkc.parent_object_id AND c.column_id = fkc.parent_column_id
+	JOIN sys.tables rt ON rt.object_id = fkc.referenced_object_id
+	JOIN sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id
+	WHERE fk.parent_object_id = OBJECT_ID(?) ORDER BY fk.name, fkc.constraint_column_id`, tableName).Rows()
+	if err != nil {
+		return nil, err
+	}
+	return scanForeignKeyInfos(rows)
+}
+
 func (s mssql) HasTable(scope *Scope, tableName string) bool {
 	var count int
 	scope.NewDB().Raw("SELECT count(*) FROM INFORMATION_SCHEMA.tables WHERE table_name = ? AND table_catalog = ?", tableName, s.databaseName(scope)).Row().Scan(&count)
@@ -79,3 +170,55 @@ func (mssql) HasIndex(scope *Scope, tableName string, indexName string) bool {
 	scope.NewDB().Raw("SELECT count(*) FROM sys.indexes WHERE name=? AND object_id=OBJECT_ID(?)", indexName, tableName).Row().Scan(&count)
 	return count > 0
 }
+
+func (mssql) HasTrigger(scope *Scope, tableName string, triggerName string) bool {
+	var count int
+	scope.NewDB().Raw("SELECT count(*) FROM sys.triggers WHERE name = ? AND parent_id = OBJECT_ID(?)", triggerName, tableName).Row().Scan(&count)
+	return count > 0
+}
+
+func (mssql) HasFunction(scope *Scope, functionName string) bool {
+	var count int
+	scope.NewDB().Raw("SELECT count(*) FROM sys.objects WHERE name = ? AND type IN ('FN', 'IF', 'TF')", functionName).Row().Scan(&count)
+	return count > 0
+}
+
+func (s mssql) CreateSequenceSql(name string, start int64, increment int64) string {
+	return fmt.Sprintf("IF OBJECT_ID('%v', 'SO') IS NULL CREATE SEQUENCE %v START WITH %v INCREMENT BY %v", name, s.Quote(name), start, increment)
+}
+
+func (s mssql) NextSequenceValSql(name string) string {
+	return "SELECT NEXT VALUE FOR " + s.Quote(name)
+}
+
+// UpdateFromSql render `UPDATE alias SET ... FROM table JOIN ...`
+func (mssql) UpdateFromSql(tableName string, sets []string, fromTable string, on string, conditions string) string {
+	alias := tableName[strings.LastIndex(tableName, " ")+1:]
+	return fmt.Sprintf("UPDATE %v SET %v FROM %v JOIN %v ON %v %v", alias, strings.Join(sets, ", "), tableName, fromTable, on, conditions)
+}
+
+// DeleteUsingSql render `DELETE alias FROM table JOIN ...`
+func (mssql) DeleteUsingSql(tableName string, usingTable string, on string, conditions string) string {
+	alias := tableName[strings.LastIndex(tableName, " ")+1:]
+	return fmt.Sprintf("DELETE %v FROM %v JOIN %v ON %v %v", alias, tableName, usingTable, on, conditions)
+}
+
+func (mssql) UpsertSql(conflictColumns []string, updateColumns []string) string {
+	return ""
+}
+
+// ForUpdateSql is blank as MSSQL locks rows with table hints
+func (mssql) ForUpdateSql() string {
+	return ""
+}
+
+// AlterColumnSql render `ALTER TABLE ... ALTER COLUMN` with type and nullability, defaults are constraints in
+// MSSQL, they are not changed
+func (mssql) AlterColumnSql(quotedTableName string, quotedColumn string, definition string) []string {
+	sqlType, _, _ := splitColumnType(definition)
+	nullability := "NULL"
+	if parseColumnDefinition(definition).NotNull {
+		nullability = "NOT NULL"
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v %v %v", quotedTableName, quotedColumn, sqlType, nullability)}
+}
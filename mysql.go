@@ -1,8 +1,11 @@
 package gorm
 
 import (
+	"database/sql"
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -19,11 +22,18 @@ func (mysql) SqlTag(value reflect.Value, size int, autoIncrease bool) string {
 			return "int AUTO_INCREMENT"
 		}
 		return "int"
-	case reflect.Int64, reflect.Uint64:
+	case reflect.Int64:
 		if autoIncrease {
 			return "bigint AUTO_INCREMENT"
 		}
 		return "bigint"
+	case reflect.Uint64:
+		// only uint64 needs an unsigned column to hold values above math.MaxInt64, other unsigned fields keep
+		// their signed columns, so existing tables and foreign keys referencing them aren't changed
+		if autoIncrease {
+			return "bigint unsigned AUTO_INCREMENT"
+		}
+		return "bigint unsigned"
 	case reflect.Float32, reflect.Float64:
 		return "double"
 	case reflect.String:
@@ -35,6 +45,9 @@ func (mysql) SqlTag(value reflect.Value, size int, autoIncrease bool) string {
 		if _, ok := value.Interface().(time.Time); ok {
 			return "timestamp NULL"
 		}
+		if _, ok := value.Interface().(Point); ok {
+			return "point"
+		}
 	default:
 		if _, ok := value.Interface().([]byte); ok {
 			if size > 0 && size < 65532 {
@@ -46,10 +59,98 @@ func (mysql) SqlTag(value reflect.Value, size int, autoIncrease bool) string {
 	panic(fmt.Sprintf("invalid sql type %s (%s) for mysql", value.Type().Name(), value.Kind().String()))
 }
 
+func (mysql) OutParamVar(i int) string {
+	return fmt.Sprintf("@gorm_out_%d", i)
+}
+
+func (mysql) MaxBindVars() int {
+	return 65535
+}
+
+func (mysql) JsonSqlTag() string {
+	return "json"
+}
+
 func (mysql) Quote(key string) string {
 	return fmt.Sprintf("`%s`", key)
 }
 
+var mysqlReservedWords = wordSet(`DATABASE DATABASES DIV DUAL FULLTEXT INDEX INTERVAL KEY KEYS KILL LIMIT LOCK MATCH
+	MOD RANGE RANK READ REGEXP RENAME REPLACE REQUIRE SCHEMA SEPARATOR SHOW SPATIAL STRAIGHT_JOIN TRIGGER USAGE
+	WINDOW XOR`)
+
+func (m mysql) IsReservedWord(word string) bool {
+	return m.commonDialect.IsReservedWord(word) || mysqlReservedWords[strings.ToUpper(word)]
+}
+
+func (mysql) FullTextSearchSql(scope *Scope, columns []string, query string) string {
+	return fmt.Sprintf("MATCH (%v) AGAINST (%v IN NATURAL LANGUAGE MODE)", strings.Join(columns, ","), scope.AddToVars(query))
+}
+
+func (mysql) FullTextIndexSql(indexName string, tableName string, columns []string) string {
+	return fmt.Sprintf("CREATE FULLTEXT INDEX %v ON %v(%v);", indexName, tableName, strings.Join(columns, ", "))
+}
+
+// UpdateFromSql render `UPDATE ... JOIN ... SET`, updated columns are qualified to avoid ambiguity with the joined table
+func (mysql) UpdateFromSql(tableName string, sets []string, fromTable string, on string, conditions string) string {
+	alias := tableName[strings.LastIndex(tableName, " ")+1:]
+	var qualifiedSets []string
+	for _, set := range sets {
+		qualifiedSets = append(qualifiedSets, alias+"."+set)
+	}
+	return fmt.Sprintf("UPDATE %v JOIN %v ON %v SET %v %v", tableName, fromTable, on, strings.Join(qualifiedSets, ", "), conditions)
+}
+
+// DeleteUsingSql render `DELETE alias FROM ... JOIN ...`
+func (mysql) DeleteUsingSql(tableName string, usingTable string, on string, conditions string) string {
+	alias := tableName[strings.LastIndex(tableName, " ")+1:]
+	return fmt.Sprintf("DELETE %v FROM %v JOIN %v ON %v %v", alias, tableName, usingTable, on, conditions)
+}
+
+func (mysql) TableOptionsSql(engine string, charset string) string {
+	return fmt.Sprintf("ENGINE=%s DEFAULT CHARSET=%s", engine, charset)
+}
+
+func (mysql) SupportOnUpdateTimestamp() bool {
+	return true
+}
+
+func (mysql) ServerTimestampSql() string {
+	return "DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP"
+}
+
+// ReplicationLag read Seconds_Behind_Master of SHOW SLAVE STATUS, it is unknown if replication is stopped
+func (mysql) ReplicationLag(replica *sql.DB) (time.Duration, bool) {
+	rows, err := replica.Query("SHOW SLAVE STATUS")
+	if err != nil {
+		return 0, false
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil || !rows.Next() {
+		// not a replica
+		return 0, err == nil
+	}
+
+	values := make([]sql.RawBytes, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if rows.Scan(dest...) != nil {
+		return 0, false
+	}
+
+	for i, column := range columns {
+		if column == "Seconds_Behind_Master" && values[i] != nil {
+			seconds, err := strconv.Atoi(string(values[i]))
+			return time.Duration(seconds) * time.Second, err == nil
+		}
+	}
+	return 0, false
+}
+
 func (mysql) SelectFromDummyTable() string {
 	return "FROM DUAL"
 }
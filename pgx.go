@@ -0,0 +1,105 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/stdlib"
+)
+
+// postgresDriver use pgx for the postgres dialect when lib/pq is not imported,
+// both of them accept URLs like `postgres://` and `key=value` DSNs
+func postgresDriver() string {
+	for _, driver := range sql.Drivers() {
+		if driver == "postgres" {
+			return driver
+		}
+	}
+	return "pgx"
+}
+
+// arrayElem get the element type of postgres arrays for SqlTag, element types of pgtype arrays
+// like pgtype.TextArray are scanners wrapping the real value, e.g. pgtype.Text wraps a string
+func arrayElem(elemType reflect.Type) reflect.Value {
+	value := reflect.Indirect(reflect.New(elemType))
+	for value.Kind() == reflect.Struct && value.NumField() > 0 {
+		if _, isScanner := reflect.New(value.Type()).Interface().(sql.Scanner); !isScanner {
+			break
+		}
+		value = value.Field(0)
+	}
+	return value
+}
+
+// CopyFrom insert a slice of records with postgres' COPY protocol, it is much faster than Create for lots of records,
+// but callbacks are not called, and the pgx driver is required, e.g:
+//
+//	db.CopyFrom(&users)
+func (s *DB) CopyFrom(values interface{}) *DB {
+	scope := s.clone().NewScope(values)
+	scope.Err(scope.copyFrom())
+	return scope.db
+}
+
+func (scope *Scope) copyFrom() error {
+	sqlDB, ok := scope.sqlDB().(*sql.DB)
+	if !ok {
+		return errors.New("CopyFrom can't be used in transaction")
+	}
+	if _, ok := sqlDB.Driver().(*stdlib.Driver); !ok {
+		return errors.New("CopyFrom requires the pgx driver")
+	}
+	if scope.IndirectValue().Kind() != reflect.Slice {
+		return errors.New("CopyFrom requires a slice")
+	}
+
+	batchFields := scope.BatchFields()
+	if len(batchFields) == 0 {
+		return nil
+	}
+
+	// blank primary keys of the first record are left to the database
+	var columns []string
+	for _, structField := range scope.GetStructFields() {
+		if structField.IsNormal && !structField.IsIgnored && !structField.IsReadOnly {
+			if field := batchFields[0][structField.DBName]; !field.IsPrimaryKey || !field.IsBlank {
+				columns = append(columns, structField.DBName)
+			}
+		}
+	}
+
+	now := NowFunc()
+	var rows [][]interface{}
+	for _, fields := range batchFields {
+		var row []interface{}
+		for _, column := range columns {
+			field := fields[column]
+			if (field.Name == "CreatedAt" || field.Name == "UpdatedAt") && field.IsBlank {
+				field.Set(now)
+			}
+			value := scope.fieldVar(field)
+			// copied rows aren't logged, pass values of sensitive fields as they are
+			if sensitive, ok := value.(sensitiveValue); ok {
+				value = sensitive.value
+			}
+			row = append(row, value)
+		}
+		rows = append(rows, row)
+	}
+
+	ctx := context.Background()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn interface{}) error {
+		count, err := driverConn.(*stdlib.Conn).Conn().CopyFrom(ctx, pgx.Identifier{scope.TableName()}, columns, pgx.CopyFromRows(rows))
+		scope.db.RowsAffected = count
+		return err
+	})
+}
@@ -0,0 +1,72 @@
+package gorm
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type pgxError struct{ code string }
+
+func (err *pgxError) Error() string    { return "pgx error " + err.code }
+func (err *pgxError) SQLState() string { return err.code }
+
+type pqError map[byte]string
+
+func (err pqError) Error() string     { return "pq error " + err['C'] }
+func (err pqError) Get(k byte) string { return err[k] }
+
+func TestTranslateError(t *testing.T) {
+	err := translateError(&pgxError{code: "23505"})
+	if !errors.Is(err, UniqueViolation) || err.(*DatabaseError).Code != "23505" {
+		t.Errorf("unique violation of pgx should be translated, but got %#v", err)
+	}
+
+	var pgxErr *pgxError
+	if !errors.As(err, &pgxErr) {
+		t.Errorf("driver's error should be kept in translated error")
+	}
+
+	if err := translateError(pqError{'C': "23503"}); !errors.Is(err, ForeignKeyViolation) {
+		t.Errorf("foreign key violation of lib/pq should be translated, but got %#v", err)
+	}
+
+	origin := &pgxError{code: "42P01"}
+	if err := translateError(origin); err != origin {
+		t.Errorf("unknown codes should not be translated, but got %#v", err)
+	}
+}
+
+type textElem struct {
+	String string
+	Valid  bool
+}
+
+func (t *textElem) Scan(interface{}) error { return nil }
+
+func (t textElem) Value() (driver.Value, error) { return t.String, nil }
+
+type textArray struct {
+	Elements []textElem
+}
+
+func (t *textArray) Scan(interface{}) error { return nil }
+
+func TestPostgresArraySqlTag(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		expected string
+	}{
+		{[]string{}, "text[]"},
+		{[]int64{}, "bigint[]"},
+		{[]byte{}, "bytea"},
+		{textArray{}.Elements, "text[]"},
+	}
+
+	for _, c := range cases {
+		if tag := (postgres{}).SqlTag(reflect.ValueOf(c.value), 0, false); tag != c.expected {
+			t.Errorf("sql tag of %T should be %v, but got %v", c.value, c.expected, tag)
+		}
+	}
+}
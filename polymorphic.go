@@ -0,0 +1,145 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var polymorphicModels = struct {
+	m map[string]reflect.Type
+	l sync.RWMutex
+}{m: map[string]reflect.Type{}}
+
+// RegisterModel register models which might be owners of polymorphic belongs to associations, owners are resolved
+// from type discriminators by names of models, or by table names if the discriminator isn't mapped, e.g:
+//
+//	type Comment struct {
+//		Id              int64
+//		CommentableId   int64
+//		CommentableType string
+//		Commentable     Commentable `gorm:"polymorphic:Commentable;polymorphic_types:posts=Post,videos=Video"`
+//	}
+//
+//	gorm.RegisterModel(&Post{}, &Video{})
+//	db.Preload("Commentable").Find(&comments)
+func RegisterModel(values ...interface{}) {
+	polymorphicModels.l.Lock()
+	defer polymorphicModels.l.Unlock()
+	for _, value := range values {
+		modelType := reflect.TypeOf(value)
+		for modelType.Kind() == reflect.Ptr {
+			modelType = modelType.Elem()
+		}
+		polymorphicModels.m[modelType.Name()] = modelType
+	}
+}
+
+var interfaceModels = struct {
+	m map[reflect.Type]reflect.Type
+	l sync.RWMutex
+}{m: map[reflect.Type]reflect.Type{}}
+
+// RegisterInterfaceModel register the model as the concrete type of has one and belongs to associations declared as
+// the interface, which is given as a nil pointer of it, associations are preloaded as pointers of the model, it should
+// be called before the models are used, e.g:
+//
+//	type User struct {
+//		Id      int64
+//		Profile Profile
+//	}
+//
+//	gorm.RegisterInterfaceModel((*Profile)(nil), &UserProfile{})
+//	db.Preload("Profile").Find(&users)
+func RegisterInterfaceModel(iface interface{}, model interface{}) {
+	interfaceType := reflect.TypeOf(iface)
+	if interfaceType == nil || interfaceType.Kind() != reflect.Ptr || interfaceType.Elem().Kind() != reflect.Interface {
+		panic(fmt.Sprintf("%T is not a pointer of interface", iface))
+	}
+	interfaceType = interfaceType.Elem()
+	modelType := reflect.TypeOf(model)
+	for modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	if !reflect.PtrTo(modelType).Implements(interfaceType) {
+		panic(fmt.Sprintf("%v doesn't implement %v", modelType, interfaceType))
+	}
+
+	interfaceModels.l.Lock()
+	defer interfaceModels.l.Unlock()
+	interfaceModels.m[interfaceType] = modelType
+}
+
+// interfaceModelType get the model registered for the interface, nil if the type isn't a registered interface
+func interfaceModelType(interfaceType reflect.Type) reflect.Type {
+	if interfaceType.Kind() != reflect.Interface {
+		return nil
+	}
+	interfaceModels.l.RLock()
+	defer interfaceModels.l.RUnlock()
+	return interfaceModels.m[interfaceType]
+}
+
+// associationValue get the pointer of the association to save it, values of interface fields are copied to new
+// pointers unless they are pointers
+func associationValue(value reflect.Value) interface{} {
+	if value.Kind() != reflect.Interface {
+		return value.Addr().Interface()
+	}
+	if value.Elem().Kind() == reflect.Ptr {
+		return value.Elem().Interface()
+	}
+	pointer := reflect.New(value.Elem().Type())
+	pointer.Elem().Set(value.Elem())
+	return pointer.Interface()
+}
+
+// parsePolymorphicTypes parse the polymorphic_types tag, e.g. `posts=Post,videos=Video`
+func parsePolymorphicTypes(str string) map[string]string {
+	types := map[string]string{}
+	for _, pair := range strings.Split(str, ",") {
+		if values := strings.SplitN(pair, "=", 2); len(values) == 2 {
+			types[strings.TrimSpace(values[0])] = strings.TrimSpace(values[1])
+		}
+	}
+	return types
+}
+
+// implementsField check if the model, or the pointer of it, could be assigned to the interface field
+func implementsField(modelType reflect.Type, fieldType reflect.Type) bool {
+	return modelType.Implements(fieldType) || reflect.PtrTo(modelType).Implements(fieldType)
+}
+
+// polymorphicModelType get the registered model of the type discriminator
+func (scope *Scope) polymorphicModelType(field *Field, discriminator string) reflect.Type {
+	polymorphicModels.l.RLock()
+	defer polymorphicModels.l.RUnlock()
+
+	if name, ok := field.Relationship.PolymorphicTypes[discriminator]; ok {
+		if modelType, ok := polymorphicModels.m[name]; ok && implementsField(modelType, field.Struct.Type) {
+			return modelType
+		}
+		return nil
+	}
+	for _, modelType := range polymorphicModels.m {
+		if implementsField(modelType, field.Struct.Type) && scope.New(reflect.New(modelType).Interface()).TableName() == discriminator {
+			return modelType
+		}
+	}
+	return nil
+}
+
+// polymorphicDiscriminator get the type discriminator of the owner, the mapped one in polymorphic_types, or the table name
+func (scope *Scope) polymorphicDiscriminator(field *Field, owner interface{}) string {
+	modelType := reflect.TypeOf(owner)
+	for modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	for discriminator, name := range field.Relationship.PolymorphicTypes {
+		if name == modelType.Name() {
+			return discriminator
+		}
+	}
+	return scope.New(owner).TableName()
+}
@@ -1,6 +1,10 @@
 package gorm_test
 
-import "testing"
+import (
+	"testing"
+
+	"golib/gorm"
+)
 
 type Cat struct {
 	Id   int
@@ -21,6 +25,30 @@ type Toy struct {
 	OwnerType string
 }
 
+func (Toy) TableName() string {
+	return "toys"
+}
+
+type ToyOwner interface {
+	OwnerName() string
+}
+
+func (cat *Cat) OwnerName() string { return cat.Name }
+
+func (dog *Dog) OwnerName() string { return dog.Name }
+
+type OwnedToy struct {
+	Id        int
+	Name      string
+	OwnerId   int
+	OwnerType string
+	Owner     ToyOwner `gorm:"polymorphic:Owner"`
+}
+
+func (OwnedToy) TableName() string {
+	return "toys"
+}
+
 func TestPolymorphic(t *testing.T) {
 	DB.AutoMigrate(&Cat{})
 	DB.AutoMigrate(&Dog{})
@@ -54,3 +82,29 @@ func TestPolymorphic(t *testing.T) {
 		t.Errorf("Should return two polymorphic has many associations")
 	}
 }
+
+func TestPolymorphicBelongsTo(t *testing.T) {
+	gorm.RegisterModel(&Cat{}, &Dog{})
+	DB.AutoMigrate(&Cat{}, &Dog{}, &Toy{})
+
+	cat := Cat{Name: "Kitty", Toy: Toy{Name: "mouse"}}
+	dog := Dog{Name: "Rex", Toys: []Toy{{Name: "bone"}}}
+	DB.Save(&cat).Save(&dog)
+
+	var toys []OwnedToy
+	if err := DB.Preload("Owner").Where("id IN (?)", []int{cat.Toy.Id, dog.Toys[0].Id}).Order("id").Find(&toys).Error; err != nil {
+		t.Fatalf("failed to preload polymorphic owners, got %v", err)
+	}
+	if len(toys) != 2 || toys[0].Owner == nil || toys[1].Owner == nil {
+		t.Fatalf("owners of toys should be preloaded, but got %+v", toys)
+	}
+	if toys[0].Owner.OwnerName() != cat.Name || toys[1].Owner.OwnerName() != dog.Name {
+		t.Errorf("owners should be found in tables of their types, but got %v, %v", toys[0].Owner.OwnerName(), toys[1].Owner.OwnerName())
+	}
+
+	toy := OwnedToy{Name: "stick", Owner: &Dog{Name: "Buddy"}}
+	DB.Save(&toy)
+	if toy.OwnerId == 0 || toy.OwnerType != "dogs" {
+		t.Errorf("owner should be saved before the toy, but got %v %v", toy.OwnerId, toy.OwnerType)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/lib/pq/hstore"
 	"reflect"
+	"strings"
 	"time"
 )
 
@@ -13,28 +14,146 @@ type postgres struct {
 	commonDialect
 }
 
-func (postgres) BinVar(i int) string {
-	return fmt.Sprintf("$%v", i)
+func (postgres) PlaceholderStyle() PlaceholderStyle {
+	return DollarPlaceholder
+}
+
+var postgresReservedWords = wordSet(`ANALYSE ANALYZE ARRAY COLLATE CONCURRENTLY DO LIMIT OFFSET ONLY PLACING
+	RETURNING SYMMETRIC VARIADIC WINDOW`)
+
+func (p postgres) IsReservedWord(word string) bool {
+	return p.commonDialect.IsReservedWord(word) || postgresReservedWords[strings.ToUpper(word)]
+}
+
+func (postgres) ILikeSql(column string, value string) string {
+	return fmt.Sprintf("%v ILIKE %v", column, value)
+}
+
+func (postgres) GeometryVar(wkt string) string {
+	return fmt.Sprintf("ST_GeogFromText(%v)", wkt)
+}
+
+func (postgres) WithinDistanceSql(column string, point string, meters string) string {
+	return fmt.Sprintf("ST_DWithin(%v, %v, %v)", column, point, meters)
+}
+
+func (postgres) BoundingBoxSql(column string, polygon string) string {
+	return fmt.Sprintf("ST_Covers(ST_GeomFromText(%v, 4326), %v::geometry)", polygon, column)
+}
+
+func (postgres) tsvector(columns []string) string {
+	var values []string
+	for _, column := range columns {
+		values = append(values, fmt.Sprintf("coalesce(%v, '')", column))
+	}
+	return fmt.Sprintf("to_tsvector('simple', %v)", strings.Join(values, " || ' ' || "))
+}
+
+// FullTextSearchSql render the same tsvector expression with FullTextIndexSql, so the index could be used
+func (p postgres) FullTextSearchSql(scope *Scope, columns []string, query string) string {
+	return fmt.Sprintf("%v @@ plainto_tsquery('simple', %v)", p.tsvector(columns), scope.AddToVars(query))
+}
+
+func (p postgres) FullTextIndexSql(indexName string, tableName string, columns []string) string {
+	return fmt.Sprintf("CREATE INDEX %v ON %v USING gin(%v);", indexName, tableName, p.tsvector(columns))
+}
+
+// AlterColumnSql render `ALTER TABLE ... ALTER COLUMN` statements changing type, nullability and default separately
+func (postgres) AlterColumnSql(quotedTableName string, quotedColumn string, definition string) []string {
+	sqlType, defaultValue, hasDefault := splitColumnType(definition)
+	// serial types are integers with sequences' defaults, sequences are kept
+	switch strings.ToLower(sqlType) {
+	case "serial":
+		sqlType, hasDefault = "integer", true
+	case "bigserial":
+		sqlType, hasDefault = "bigint", true
+	}
+
+	alter := fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v ", quotedTableName, quotedColumn)
+	sqls := []string{fmt.Sprintf("%vTYPE %v USING %v::%v", alter, sqlType, quotedColumn, sqlType)}
+	if parseColumnDefinition(definition).NotNull {
+		sqls = append(sqls, alter+"SET NOT NULL")
+	} else {
+		sqls = append(sqls, alter+"DROP NOT NULL")
+	}
+	if defaultValue != "" {
+		sqls = append(sqls, alter+"SET DEFAULT "+defaultValue)
+	} else if !hasDefault {
+		sqls = append(sqls, alter+"DROP DEFAULT")
+	}
+	return sqls
+}
+
+// DeleteUsingSql render `DELETE ... USING ... WHERE`
+func (postgres) DeleteUsingSql(tableName string, usingTable string, on string, conditions string) string {
+	if conditions == "" {
+		conditions = "WHERE " + on
+	} else {
+		conditions = fmt.Sprintf("WHERE (%v) AND (%v)", on, strings.TrimPrefix(conditions, "WHERE "))
+	}
+	return fmt.Sprintf("DELETE FROM %v USING %v %v", tableName, usingTable, conditions)
+}
+
+func (postgres) UpsertSql(conflictColumns []string, updateColumns []string) string {
+	return excludedUpsertSql(conflictColumns, updateColumns)
+}
+
+// CollationSql only render collation, charset is decided by database in postgres
+func (postgres) CollationSql(charset string, collation string) string {
+	if collation == "" {
+		return ""
+	}
+	return fmt.Sprintf(`COLLATE "%v"`, collation)
 }
 
 func (postgres) SupportLastInsertId() bool {
 	return false
 }
 
-func (postgres) Columns(sope *Scope, tableName string) map[string]string {
-	return nil
+func (postgres) MaxBindVars() int {
+	return 65535
+}
+
+func (postgres) SupportPartialIndex() bool {
+	return true
+}
+
+func (postgres) SupportMaterializedView() bool {
+	return true
+}
+
+func (postgres) SupportInterval() bool {
+	return true
+}
+
+func (postgres) SupportArray() bool {
+	return true
+}
+
+func (postgres) SupportLargeObject() bool {
+	return true
+}
+
+func (postgres) SubstringSql(column string, from string, length string) string {
+	return fmt.Sprintf("SUBSTRING(%v FROM %v FOR %v)", column, from, length)
 }
 
-func (postgres) SqlTag(value reflect.Value, size int, autoIncrease bool) string {
+func (postgres) JsonSqlTag() string {
+	return "jsonb"
+}
+
+func (s postgres) SqlTag(value reflect.Value, size int, autoIncrease bool) string {
 	switch value.Kind() {
 	case reflect.Bool:
 		return "boolean"
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uintptr:
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
 		if autoIncrease {
 			return "serial"
 		}
 		return "integer"
-	case reflect.Int64, reflect.Uint64:
+	case reflect.Int64, reflect.Uint32, reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		// postgres has no unsigned types, uint64 keys and foreign keys are bigint, columns holding values above
+		// math.MaxInt64 should be tagged with `type:numeric(20)`
 		if autoIncrease {
 			return "bigserial"
 		}
@@ -50,14 +169,18 @@ func (postgres) SqlTag(value reflect.Value, size int, autoIncrease bool) string
 		if _, ok := value.Interface().(time.Time); ok {
 			return "timestamp with time zone"
 		}
+		if _, ok := value.Interface().(Point); ok {
+			return "geography(Point,4326)"
+		}
 	case reflect.Map:
 		if value.Type() == hstoreType {
 			return "hstore"
 		}
-	default:
+	case reflect.Slice:
 		if _, ok := value.Interface().([]byte); ok {
 			return "bytea"
 		}
+		return s.SqlTag(arrayElem(value.Type().Elem()), size, false) + "[]"
 	}
 	panic(fmt.Sprintf("invalid sql type %s (%s) for postgres", value.Type().Name(), value.Kind().String()))
 }
@@ -66,6 +189,84 @@ func (s postgres) ReturningStr(tableName, key string) string {
 	return fmt.Sprintf("RETURNING %v.%v", s.Quote(tableName), key)
 }
 
+// EstimatedCount read table's row count from pg_class, which is updated by VACUUM and ANALYZE
+func (postgres) EstimatedCount(scope *Scope, tableName string) (int64, bool) {
+	var count float64
+	err := scope.NewDB().Raw("SELECT reltuples FROM pg_class WHERE relname = ? AND relkind = 'r'", tableName).Row().Scan(&count)
+	return int64(count), err == nil && count >= 0
+}
+
+// PrepareTransactionSql requires max_prepared_transactions to be greater than 0
+func (postgres) PrepareTransactionSql(id string) string {
+	return "PREPARE TRANSACTION " + id
+}
+
+func (postgres) CommitPreparedSql(id string) string {
+	return "COMMIT PREPARED " + id
+}
+
+func (postgres) RollbackPreparedSql(id string) string {
+	return "ROLLBACK PREPARED " + id
+}
+
+func (s postgres) CreateSequenceSql(name string, start int64, increment int64) string {
+	return fmt.Sprintf("CREATE SEQUENCE IF NOT EXISTS %v START WITH %v INCREMENT BY %v", s.Quote(name), start, increment)
+}
+
+func (s postgres) NextSequenceValSql(name string) string {
+	return fmt.Sprintf("SELECT nextval('%v')", strings.Replace(s.Quote(name), "'", "''", -1))
+}
+
+// SyncIdentitySql advance the sequence of the serial or identity column to the inserted value if it's behind, the
+// value is bound twice, sequences don't follow values inserted as they are
+func (s postgres) SyncIdentitySql(quotedTableName string, column string) string {
+	sequence := fmt.Sprintf("pg_get_serial_sequence('%v', '%v')", strings.Replace(quotedTableName, "'", "''", -1), strings.Replace(column, "'", "''", -1))
+	return fmt.Sprintf("SELECT setval(%v, ?) WHERE ? > COALESCE(pg_sequence_last_value(%v::regclass), 0)", sequence, sequence)
+}
+
+// ReplicationLag is the time since the last replayed transaction, it is 0 on primary
+func (postgres) ReplicationLag(replica *sql.DB) (time.Duration, bool) {
+	var seconds float64
+	err := replica.QueryRow("SELECT COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)").Scan(&seconds)
+	return time.Duration(seconds * float64(time.Second)), err == nil
+}
+
+func (postgres) TableInfos(scope *Scope) ([]string, error) {
+	return informationSchemaTables(scope, "table_type = 'BASE TABLE' AND table_schema = current_schema()")
+}
+
+func (postgres) ColumnInfos(scope *Scope, tableName string) ([]ColumnInfo, error) {
+	return informationSchemaColumns(scope, tableName,
+		"(SELECT format_type(a.atttypid, a.atttypmod) FROM pg_attribute a WHERE a.attrelid = (quote_ident(c.table_schema) || '.' || quote_ident(c.table_name))::regclass AND a.attname = c.column_name)",
+		"c.column_default LIKE 'nextval(%'", "c.table_schema = current_schema()")
+}
+
+// IndexInfos get columns of indexes from pg_index, attribute of expression parts is 0, their columns are blank
+func (postgres) IndexInfos(scope *Scope, tableName string) ([]IndexInfo, error) {
+	rows, err := scope.NewDB().Raw(`SELECT i.relname, a.attname, ix.indisunique, ix.indisprimary FROM pg_index ix
+	JOIN pg_class t ON t.oid = ix.indrelid JOIN pg_class i ON i.oid = ix.indexrelid JOIN pg_namespace n ON n.oid = t.relnamespace
+	CROSS JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k(attnum, position)
+	LEFT JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+	WHERE t.relname = ? AND n.nspname = current_schema() ORDER BY i.relname, k.position`, tableName).Rows()
+	if err != nil {
+		return nil, err
+	}
+	return scanIndexInfos(rows)
+}
+
+func (postgres) ForeignKeyInfos(scope *Scope, tableName string) ([]ForeignKeyInfo, error) {
+	rows, err := scope.NewDB().Raw(`SELECT c.conname, a.attname, rt.relname, ra.attname FROM pg_constraint c
+	JOIN pg_class t ON t.oid = c.conrelid JOIN pg_class rt ON rt.oid = c.confrelid JOIN pg_namespace n ON n.oid = t.relnamespace
+	CROSS JOIN LATERAL unnest(c.conkey, c.confkey) WITH ORDINALITY AS k(attnum, refattnum, position)
+	JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+	JOIN pg_attribute ra ON ra.attrelid = rt.oid AND ra.attnum = k.refattnum
+	WHERE c.contype = 'f' AND t.relname = ? AND n.nspname = current_schema() ORDER BY c.conname, k.position`, tableName).Rows()
+	if err != nil {
+		return nil, err
+	}
+	return scanForeignKeyInfos(rows)
+}
+
 func (postgres) HasTable(scope *Scope, tableName string) bool {
 	var count int
 	scope.NewDB().Raw("SELECT count(*) FROM INFORMATION_SCHEMA.tables WHERE table_name = ? AND table_type = 'BASE TABLE'", tableName).Row().Scan(&count)
@@ -88,6 +289,23 @@ func (postgres) HasIndex(scope *Scope, tableName string, indexName string) bool
 	return count > 0
 }
 
+func (postgres) HasTrigger(scope *Scope, tableName string, triggerName string) bool {
+	var count int
+	scope.NewDB().Raw("SELECT count(*) FROM pg_trigger JOIN pg_class ON pg_class.oid = pg_trigger.tgrelid WHERE pg_class.relname = ? AND pg_trigger.tgname = ?", tableName, triggerName).Row().Scan(&count)
+	return count > 0
+}
+
+func (postgres) HasFunction(scope *Scope, functionName string) bool {
+	var count int
+	scope.NewDB().Raw("SELECT count(*) FROM pg_proc WHERE proname = ?", functionName).Row().Scan(&count)
+	return count > 0
+}
+
+// DropTriggerSql triggers belong to tables in postgres
+func (postgres) DropTriggerSql(triggerName string, quotedTableName string) string {
+	return fmt.Sprintf("DROP TRIGGER IF EXISTS %v ON %v", triggerName, quotedTableName)
+}
+
 var hstoreType = reflect.TypeOf(Hstore{})
 
 type Hstore map[string]*string
@@ -1,10 +1,13 @@
 package gorm
 
 import (
+	"database/sql"
 	"database/sql/driver"
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 )
 
 func getRealValue(value reflect.Value, field string) interface{} {
@@ -27,16 +30,21 @@ func Preload(scope *Scope) {
 		for key, conditions := range scope.Search.preload {
 			for _, field := range fields {
 				if field.Name == key && field.Relationship != nil {
-					results := makeSlice(field.Struct.Type)
+					resultType := field.Struct.Type
+					if modelType := interfaceModelType(resultType); modelType != nil {
+						resultType = reflect.PtrTo(modelType)
+					}
+					results := makeSlice(resultType)
 					relation := field.Relationship
 					primaryName := scope.PrimaryField().Name
-					associationPrimaryKey := scope.New(results).PrimaryField().Name
 
 					switch relation.Kind {
 					case "has_one":
 						if primaryKeys := scope.getColumnAsArray(primaryName); len(primaryKeys) > 0 {
 							condition := fmt.Sprintf("%v IN (?)", scope.Quote(relation.ForeignDBName))
-							scope.NewDB().Where(condition, primaryKeys).Find(results, conditions...)
+							if scope.findPreloaded(results, primaryKeys, func(db *DB, keys []interface{}) *DB { return db.Where(condition, keys) }, conditions...) != nil {
+								break
+							}
 
 							resultValues := reflect.Indirect(reflect.ValueOf(results))
 							for i := 0; i < resultValues.Len(); i++ {
@@ -58,7 +66,9 @@ func Preload(scope *Scope) {
 					case "has_many":
 						if primaryKeys := scope.getColumnAsArray(primaryName); len(primaryKeys) > 0 {
 							condition := fmt.Sprintf("%v IN (?)", scope.Quote(relation.ForeignDBName))
-							scope.NewDB().Where(condition, primaryKeys).Find(results, conditions...)
+							if scope.findPreloaded(results, primaryKeys, func(db *DB, keys []interface{}) *DB { return db.Where(condition, keys) }, conditions...) != nil {
+								break
+							}
 							resultValues := reflect.Indirect(reflect.ValueOf(results))
 							if isSlice {
 								for i := 0; i < resultValues.Len(); i++ {
@@ -80,7 +90,10 @@ func Preload(scope *Scope) {
 						}
 					case "belongs_to":
 						if primaryKeys := scope.getColumnAsArray(relation.ForeignFieldName); len(primaryKeys) > 0 {
-							scope.NewDB().Where(primaryKeys).Find(results, conditions...)
+							if scope.findPreloaded(results, primaryKeys, func(db *DB, keys []interface{}) *DB { return db.Where(keys) }, conditions...) != nil {
+								break
+							}
+							associationPrimaryKey := scope.New(results).PrimaryField().Name
 							resultValues := reflect.Indirect(reflect.ValueOf(results))
 							for i := 0; i < resultValues.Len(); i++ {
 								result := resultValues.Index(i)
@@ -99,7 +112,68 @@ func Preload(scope *Scope) {
 							}
 						}
 					case "many_to_many":
-						scope.Err(errors.New("not supported relation"))
+						// records of all objects are found in one query for each batch joining the join table with its handler,
+						// the source key of each join row is selected with the record to distribute it to its objects
+						if primaryKeys := scope.getColumnAsArray(primaryName); len(primaryKeys) > 0 {
+							sourceKeys := scope.newPreloadSourceKeys(primaryName)
+							if scope.findPreloaded(results, primaryKeys, scope.manyToManyPreloadQuery(field, sourceKeys), conditions...) != nil {
+								break
+							}
+							resultValues := reflect.Indirect(reflect.ValueOf(results))
+							if scope.sharePreloaded() {
+								scope.New(results).shareRecords(resultValues, map[string]reflect.Value{})
+							}
+							scope.setManyToManyPreloaded(field, resultValues, sourceKeys, primaryName)
+						}
+					case "polymorphic_belongs_to":
+						// owners are found in tables of their types, which are resolved from registered models
+						objects := []reflect.Value{scope.IndirectValue()}
+						if isSlice {
+							objects = nil
+							for i := 0; i < scope.IndirectValue().Len(); i++ {
+								objects = append(objects, reflect.Indirect(scope.IndirectValue().Index(i)))
+							}
+						}
+						var discriminators []string
+						foreignKeys := map[string][]interface{}{}
+						for _, object := range objects {
+							discriminator := fmt.Sprintf("%v", getRealValue(object, relation.PolymorphicType))
+							if discriminator == "" {
+								continue
+							}
+							if _, ok := foreignKeys[discriminator]; !ok {
+								discriminators = append(discriminators, discriminator)
+							}
+							foreignKeys[discriminator] = append(foreignKeys[discriminator], getRealValue(object, relation.ForeignFieldName))
+						}
+						sort.Strings(discriminators)
+
+						for _, discriminator := range discriminators {
+							modelType := scope.polymorphicModelType(field, discriminator)
+							if modelType == nil {
+								scope.Err(fmt.Errorf("unknown polymorphic type %v of %v, register it with RegisterModel", discriminator, field.Name))
+								break
+							}
+							ownerResults := makeSlice(reflect.PtrTo(modelType))
+							if scope.findPreloaded(ownerResults, foreignKeys[discriminator], func(db *DB, keys []interface{}) *DB { return db.Where(keys) }, conditions...) != nil {
+								break
+							}
+							ownerPrimaryKey := scope.New(ownerResults).PrimaryField().Name
+							resultValues := reflect.Indirect(reflect.ValueOf(ownerResults))
+							for i := 0; i < resultValues.Len(); i++ {
+								result := resultValues.Index(i)
+								if !result.Type().AssignableTo(field.Struct.Type) {
+									result = result.Elem()
+								}
+								value := getRealValue(result, ownerPrimaryKey)
+								for _, object := range objects {
+									if equalAsString(getRealValue(object, relation.PolymorphicType), discriminator) &&
+										equalAsString(getRealValue(object, relation.ForeignFieldName), value) {
+										object.FieldByName(field.Name).Set(result)
+									}
+								}
+							}
+						}
 					default:
 						scope.Err(errors.New("not supported relation"))
 					}
@@ -110,6 +184,263 @@ func Preload(scope *Scope) {
 	}
 }
 
+// preloadSourceKeyColumn is the alias of the join table's source key selected with preloaded many to many records
+const preloadSourceKeyColumn = "gorm_preload_source_key"
+
+// preloadSourceKeys collect source keys scanned with preloaded many to many records, one key per record, keys are
+// scanned into the type of the source's primary key to compare them with keys of sources
+type preloadSourceKeys struct {
+	keyType reflect.Type
+	keys    []interface{}
+}
+
+func (scope *Scope) newPreloadSourceKeys(primaryName string) *preloadSourceKeys {
+	field, _ := scope.GetModelStruct().ModelType.FieldByName(primaryName)
+	keyType := field.Type
+	for keyType.Kind() == reflect.Ptr {
+		keyType = keyType.Elem()
+	}
+	return &preloadSourceKeys{keyType: keyType}
+}
+
+// manyToManyPreloadQuery build the query finding many to many records of objects with the keys, the join table
+// handler joins rows of the objects, source keys of found records are collected into sourceKeys
+func (scope *Scope) manyToManyPreloadQuery(field *Field, sourceKeys *preloadSourceKeys) func(db *DB, keys []interface{}) *DB {
+	relationship := field.Relationship
+	elemType := field.Struct.Type
+	for elemType.Kind() == reflect.Slice || elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	toScope := scope.New(reflect.New(elemType).Interface())
+	sourceKey := fmt.Sprintf("%v.%v", scope.Quote(relationship.JoinTableHandler.Table(scope.db)), scope.Quote(relationship.ForeignDBName))
+	primaryName := scope.PrimaryField().Name
+	objects := scope.preloadObjects()
+
+	return func(db *DB, keys []interface{}) *DB {
+		inKeys := map[interface{}]bool{}
+		for _, key := range keys {
+			inKeys[key] = true
+		}
+		sources := reflect.MakeSlice(reflect.SliceOf(reflect.PtrTo(scope.GetModelStruct().ModelType)), 0, len(keys))
+		for _, object := range objects {
+			if inKeys[object.FieldByName(primaryName).Interface()] {
+				sources = reflect.Append(sources, object.Addr())
+			}
+		}
+		return relationship.JoinTableHandler.JoinWith(db.Set("gorm:preload_source_keys", sourceKeys), sources.Interface()).
+			Table(toScope.TableName()).Select(fmt.Sprintf("%v.*, %v AS %v", toScope.QuotedTableName(), sourceKey, preloadSourceKeyColumn))
+	}
+}
+
+// setManyToManyPreloaded distribute preloaded many to many records to objects by their source keys
+func (scope *Scope) setManyToManyPreloaded(field *Field, results reflect.Value, sourceKeys *preloadSourceKeys, primaryName string) {
+	objects := map[interface{}][]reflect.Value{}
+	for _, object := range scope.preloadObjects() {
+		associations := object.FieldByName(field.Name)
+		associations.Set(reflect.MakeSlice(associations.Type(), 0, 0))
+		if key := reflect.Indirect(object.FieldByName(primaryName)); key.IsValid() {
+			objects[key.Interface()] = append(objects[key.Interface()], object)
+		}
+	}
+	for i := 0; i < results.Len() && i < len(sourceKeys.keys); i++ {
+		for _, object := range objects[sourceKeys.keys[i]] {
+			associations := object.FieldByName(field.Name)
+			associations.Set(reflect.Append(associations, results.Index(i)))
+		}
+	}
+}
+
+// preloadObjects get addressable objects of the scope's value, which is a struct or a slice
+func (scope *Scope) preloadObjects() []reflect.Value {
+	if scope.IndirectValue().Kind() != reflect.Slice {
+		return []reflect.Value{scope.IndirectValue()}
+	}
+	var objects []reflect.Value
+	for i := 0; i < scope.IndirectValue().Len(); i++ {
+		objects = append(objects, reflect.Indirect(scope.IndirectValue().Index(i)))
+	}
+	return objects
+}
+
+// PreloadCount count associations of found records into their count fields, see DB.PreloadCount
+func PreloadCount(scope *Scope) {
+	if scope.Search.preloadCounts == nil || scope.HasError() {
+		return
+	}
+
+	var columns []string
+	for column := range scope.Search.preloadCounts {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	objects := []reflect.Value{scope.IndirectValue()}
+	if scope.IndirectValue().Kind() == reflect.Slice {
+		objects = nil
+		for i := 0; i < scope.IndirectValue().Len(); i++ {
+			objects = append(objects, reflect.Indirect(scope.IndirectValue().Index(i)))
+		}
+	}
+	primaryField := scope.PrimaryField()
+	if primaryField == nil || len(objects) == 0 {
+		return
+	}
+	primaryKeys := scope.getColumnAsArray(primaryField.Name)
+
+	for _, column := range columns {
+		field, ok := scope.FieldByName(column)
+		if !ok || field.Relationship == nil ||
+			(field.Relationship.Kind != "has_one" && field.Relationship.Kind != "has_many" && field.Relationship.Kind != "many_to_many") {
+			scope.Err(fmt.Errorf("%v is not a has one, has many or many to many association of %v", column, scope.GetModelStruct().ModelType))
+			return
+		}
+		countField, ok := scope.GetModelStruct().ModelType.FieldByName(column + "Count")
+		if !ok || !isIntKind(countField.Type.Kind()) {
+			scope.Err(fmt.Errorf("%v requires an integer field %vCount to preload count", scope.GetModelStruct().ModelType, column))
+			return
+		}
+
+		counts := map[string]int64{}
+		size := scope.preloadBatchSize()
+		if size <= 0 {
+			size = len(primaryKeys)
+		}
+		for start := 0; start < len(primaryKeys); start += size {
+			end := start + size
+			if end > len(primaryKeys) {
+				end = len(primaryKeys)
+			}
+			if scope.Err(scope.countAssociations(field, primaryKeys[start:end], scope.Search.preloadCounts[column], counts)) != nil {
+				return
+			}
+		}
+
+		for _, object := range objects {
+			count := counts[fmt.Sprintf("%v", getRealValue(object, primaryField.Name))]
+			countValue := object.FieldByName(countField.Name)
+			countValue.Set(reflect.ValueOf(count).Convert(countValue.Type()))
+		}
+	}
+}
+
+// countAssociations count associations of records with the primary keys grouped by the foreign key into counts
+func (scope *Scope) countAssociations(field *Field, primaryKeys []interface{}, conditions []interface{}, counts map[string]int64) error {
+	relationship := field.Relationship
+	elemType := field.Struct.Type
+	for elemType.Kind() == reflect.Slice || elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	toScope := scope.New(reflect.New(elemType).Interface())
+
+	db := scope.NewDB().Model(toScope.Value)
+	foreignKey := fmt.Sprintf("%v.%v", toScope.QuotedTableName(), scope.Quote(relationship.ForeignDBName))
+	if relationship.Kind == "many_to_many" {
+		joinTable := relationship.JoinTableHandler.Table(scope.db)
+		foreignKey = fmt.Sprintf("%v.%v", scope.Quote(joinTable), scope.Quote(relationship.ForeignDBName))
+		joinConditions := []string{fmt.Sprintf("%v.%v = %v.%v", scope.Quote(joinTable), scope.Quote(relationship.AssociationForeignDBName), toScope.QuotedTableName(), scope.Quote(toScope.PrimaryKey()))}
+		if handler, ok := relationship.JoinTableHandler.(*JoinTableHandler); ok && handler.SoftDelete {
+			joinConditions = append(joinConditions, fmt.Sprintf("%v.%v IS NULL", scope.Quote(joinTable), scope.Quote(joinTableDeletedAtColumn)))
+		}
+		db = db.Joins(fmt.Sprintf("INNER JOIN %v ON %v", scope.Quote(joinTable), strings.Join(joinConditions, " AND ")))
+	} else if relationship.PolymorphicType != "" {
+		db = db.Where(fmt.Sprintf("%v.%v = ?", toScope.QuotedTableName(), scope.Quote(relationship.PolymorphicDBName)), scope.TableName())
+	}
+	db = db.Select(foreignKey+", count(*)").Where(fmt.Sprintf("%v IN (?)", foreignKey), primaryKeys).Group(foreignKey)
+	if len(conditions) > 0 {
+		db = db.Where(conditions[0], conditions[1:]...)
+	}
+
+	rows, err := db.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key sql.NullString
+		var count int64
+		if err := rows.Scan(&key, &count); err != nil {
+			return err
+		}
+		counts[key.String] += count
+	}
+	return rows.Err()
+}
+
+// PreloadBatchSize split keys of preloaded associations into batches of the size, associations are found batch by
+// batch and merged, which avoids limits of bind vars and packet sizes when preloading for thousands of records, it
+// defaults to InListChunkSize, e.g:
+//
+//	db.PreloadBatchSize(500).Preload("Orders").Find(&users)
+func (s *DB) PreloadBatchSize(size int) *DB {
+	return s.Set("gorm:preload_batch_size", size)
+}
+
+func (scope *Scope) preloadBatchSize() int {
+	if size, ok := scope.Get("gorm:preload_batch_size"); ok {
+		return size.(int)
+	}
+	return inListChunkSize(scope.Dialect())
+}
+
+// findPreloaded find associations by keys into the slice results batch by batch, query adds conditions of keys to db
+func (scope *Scope) findPreloaded(results interface{}, keys []interface{}, query func(db *DB, keys []interface{}) *DB, conditions ...interface{}) error {
+	size := scope.preloadBatchSize()
+	if size <= 0 || len(keys) <= size {
+		return scope.Err(query(scope.NewDB(), keys).Find(results, conditions...).Error)
+	}
+
+	resultValues := reflect.Indirect(reflect.ValueOf(results))
+	for start := 0; start < len(keys); start += size {
+		end := start + size
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batch := reflect.New(resultValues.Type())
+		if err := scope.Err(query(scope.NewDB(), keys[start:end]).Find(batch.Interface(), conditions...).Error); err != nil {
+			return err
+		}
+		resultValues.Set(reflect.AppendSlice(resultValues, batch.Elem()))
+	}
+	return nil
+}
+
+// SharePreloaded share one instance of each preloaded record among records referencing it when associations are
+// slices of pointers, e.g. tags of many to many associations, which reduces memory of wide result sets, belongs to
+// associations of pointers always share preloaded parents, e.g:
+//
+//	db.SharePreloaded(true).Preload("Tags").Find(&posts)
+//	// posts[0].Tags[0] == posts[1].Tags[0] if both posts are tagged with the tag
+func (s *DB) SharePreloaded(share bool) *DB {
+	return s.Set("gorm:share_preloaded", share)
+}
+
+func (scope *Scope) sharePreloaded() bool {
+	share, ok := scope.Get("gorm:share_preloaded")
+	return ok && share.(bool)
+}
+
+// shareRecords replace pointers of records in the slice with shared ones having the same primary key
+func (scope *Scope) shareRecords(records reflect.Value, shared map[string]reflect.Value) {
+	primaryFields := scope.GetModelStruct().PrimaryFields
+	if len(primaryFields) == 0 || records.Type().Elem().Kind() != reflect.Ptr {
+		return
+	}
+	for i := 0; i < records.Len(); i++ {
+		if records.Index(i).IsNil() {
+			continue
+		}
+		var key string
+		for _, primaryField := range primaryFields {
+			key += fmt.Sprintf("%v,", getRealValue(records.Index(i), primaryField.Name))
+		}
+		if record, ok := shared[key]; ok {
+			records.Index(i).Set(record)
+		} else {
+			shared[key] = records.Index(i).Elem().Addr()
+		}
+	}
+}
+
 func makeSlice(typ reflect.Type) interface{} {
 	if typ.Kind() == reflect.Slice {
 		typ = typ.Elem()
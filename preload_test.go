@@ -1,6 +1,10 @@
 package gorm_test
 
-import "testing"
+import (
+	"testing"
+
+	"golib/gorm"
+)
 
 func getPreloadUser(name string) *User {
 	return getPreparedUser(name, "Preload")
@@ -85,3 +89,42 @@ func TestPreload(t *testing.T) {
 		}
 	}
 }
+
+type ProfileSummary interface {
+	Summary() string
+}
+
+type UserProfile struct {
+	Id            int64
+	Bio           string
+	ProfileUserId int64
+}
+
+func (profile *UserProfile) Summary() string { return profile.Bio }
+
+type ProfileUser struct {
+	Id      int64
+	Name    string
+	Profile ProfileSummary
+}
+
+func init() {
+	gorm.RegisterInterfaceModel((*ProfileSummary)(nil), &UserProfile{})
+}
+
+func TestPreloadInterfaceField(t *testing.T) {
+	DB.DropTable(&ProfileUser{})
+	DB.DropTable(&UserProfile{})
+	DB.AutoMigrate(&ProfileUser{}, &UserProfile{})
+
+	user := ProfileUser{Name: "interface", Profile: &UserProfile{Bio: "gopher"}}
+	if err := DB.Save(&user).Error; err != nil {
+		t.Fatalf("failed to save interface association, got %v", err)
+	}
+
+	var users []ProfileUser
+	DB.Preload("Profile").Find(&users)
+	if len(users) != 1 || users[0].Profile == nil || users[0].Profile.Summary() != "gopher" {
+		t.Errorf("interface association should be preloaded as the registered model, but got %+v", users)
+	}
+}
@@ -0,0 +1,105 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// ProcCall is a stored procedure call created by CallProc
+type ProcCall struct {
+	db   *DB
+	name string
+	args []interface{}
+}
+
+// CallProc call a stored procedure, `sql.Out` arguments receive OUT parameters,
+// MySQL doesn't support them in driver, so they are passed with session variables, e.g:
+//
+//	var total int64
+//	db.CallProc("sp_rebuild_stats", 2015, sql.Out{Dest: &total}).Scan(&stats, &summary)
+//	//// CALL sp_rebuild_stats(2015, @gorm_out_2); SELECT @gorm_out_2;
+func (s *DB) CallProc(name string, args ...interface{}) *ProcCall {
+	return &ProcCall{db: s, name: name, args: args}
+}
+
+// Exec call the procedure without reading result sets
+func (call *ProcCall) Exec() *DB {
+	return call.Scan()
+}
+
+// Scan call the procedure, its result sets are scanned into dests in order, a dest could be a struct or slice
+func (call *ProcCall) Scan(dests ...interface{}) *DB {
+	scope := call.db.clone().NewScope(nil)
+	defer scope.Trace(NowFunc())
+
+	ctx := context.Background()
+	var querier interface {
+		QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	}
+	switch db := scope.db.db.(type) {
+	case *sql.DB:
+		// session variables of OUT parameters are kept in the connection
+		conn, err := db.Conn(ctx)
+		if scope.Err(err) != nil {
+			return scope.db
+		}
+		defer conn.Close()
+		querier = conn
+	case *sql.Tx:
+		querier = db
+	default:
+		scope.Err(fmt.Errorf("CallProc can't be used with %T", db))
+		return scope.db
+	}
+
+	var placeholders []string
+	var outVars []string
+	var outs []interface{}
+	for i, arg := range call.args {
+		if out, ok := arg.(sql.Out); ok {
+			if variable := scope.Dialect().OutParamVar(i + 1); variable != "" {
+				placeholders = append(placeholders, variable)
+				outVars = append(outVars, variable)
+				outs = append(outs, out.Dest)
+				continue
+			}
+		}
+		placeholders = append(placeholders, scope.AddToVars(arg))
+	}
+	scope.Raw(scope.Dialect().CallProcSql(call.name, placeholders))
+
+	rows, err := querier.QueryContext(ctx, scope.Sql, scope.SqlVars...)
+	if scope.Err(err) != nil {
+		return scope.db
+	}
+
+	scope.db.RowsAffected = 0
+	for i, dest := range dests {
+		if i > 0 && !rows.NextResultSet() {
+			scope.Err(fmt.Errorf("procedure %v returned %v result sets, but %v dests are given", call.name, i, len(dests)))
+			break
+		}
+		scope.scanRows(rows, reflect.Indirect(reflect.ValueOf(dest)))
+	}
+	for rows.NextResultSet() {
+	}
+	scope.Err(rows.Err())
+	rows.Close()
+
+	if len(outVars) > 0 && !scope.HasError() {
+		outScope := scope.New(nil)
+		outScope.Raw(scope.Dialect().SelectOutParamsSql(outVars))
+		outRows, err := querier.QueryContext(ctx, outScope.Sql)
+		if scope.Err(err) != nil {
+			return scope.db
+		}
+		defer outRows.Close()
+		if outRows.Next() {
+			scope.Err(outRows.Scan(outs...))
+		}
+		scope.Err(outRows.Err())
+	}
+	return scope.db
+}
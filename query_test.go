@@ -1,10 +1,14 @@
 package gorm_test
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"reflect"
+	"strings"
 
 	"github.com/jinzhu/now"
+	"golib/gorm"
 
 	"testing"
 	"time"
@@ -366,6 +370,216 @@ func TestCount(t *testing.T) {
 	}
 }
 
+type Release struct {
+	Version     string    `gorm:"primary_key"`
+	PublishedAt time.Time `gorm:"sort_key"`
+}
+
+func TestFirstAndLastWithSortKey(t *testing.T) {
+	DB.DropTableIfExists(&Release{})
+	DB.AutoMigrate(&Release{})
+
+	now := time.Now()
+	DB.Create(&Release{Version: "b", PublishedAt: now.Add(-time.Hour)})
+	DB.Create(&Release{Version: "a", PublishedAt: now})
+	DB.Create(&Release{Version: "c", PublishedAt: now.Add(-2 * time.Hour)})
+
+	var first, last Release
+	DB.First(&first)
+	DB.Last(&last)
+	if first.Version != "c" || last.Version != "a" {
+		t.Errorf("First and Last should be ordered by sort key, but got %v, %v", first.Version, last.Version)
+	}
+
+	var taken Release
+	if DB.Order("version desc").Take(&taken).RecordNotFound() || taken.Version != "c" {
+		t.Errorf("Take should only use the given order, but got %v", taken.Version)
+	}
+
+	if !DB.Where("version = ?", "d").Take(&Release{}).RecordNotFound() {
+		t.Errorf("Take should return record not found error")
+	}
+
+	var preparedSql string
+	callback := DB.Callback()
+	callback.Query().After("gorm:prepare_query").Register("test:capture_take_sql", func(scope *gorm.Scope) {
+		preparedSql = scope.Sql
+	})
+	defer callback.Query().Remove("test:capture_take_sql")
+
+	DB.Take(&taken)
+	if strings.Contains(preparedSql, "ORDER BY") {
+		t.Errorf("Take should not order by primary key, but got %v", preparedSql)
+	}
+	DB.First(&first)
+	if !strings.Contains(preparedSql, "ORDER BY") {
+		t.Errorf("First should order by sort key, but got %v", preparedSql)
+	}
+}
+
+func TestIterateBy(t *testing.T) {
+	for _, age := range []int64{2, 1, 3, 1, 2} {
+		DB.Save(&User{Name: "IterateUser", Age: age})
+	}
+
+	var users []User
+	var chunks int
+	visited := map[int64]bool{}
+	lastAge := int64(0)
+	err := DB.Model(&users).Where("name = ?", "IterateUser").IterateBy("age", 2, func() error {
+		chunks++
+		for _, user := range users {
+			if visited[user.Id] || user.Age < lastAge {
+				t.Errorf("Users should be iterated once in order of age, but got %+v", user)
+			}
+			visited[user.Id], lastAge = true, user.Age
+		}
+		return nil
+	}).Error
+
+	if err != nil || chunks != 3 || len(visited) != 5 {
+		t.Errorf("Should iterate all users in 3 chunks, but got %v chunks, %v users, %v", chunks, len(visited), err)
+	}
+
+	chunks = 0
+	DB.Model(&users).Where("name = ?", "IterateUser").IterateBy("age", 2, func() error {
+		chunks++
+		return errors.New("stop")
+	})
+	if chunks != 1 {
+		t.Errorf("Should stop iterating when got error, but got %v chunks", chunks)
+	}
+}
+
+func TestExists(t *testing.T) {
+	DB.Save(&User{Name: "ExistsUser1", Age: 1})
+
+	if exists, err := DB.Model(&User{}).Where("name = ?", "ExistsUser1").Exists(); err != nil || !exists {
+		t.Errorf("Should find existing user, but got %v, %v", exists, err)
+	}
+
+	if exists, err := DB.Model(&User{}).Where("name = ?", "ExistsUser2").Exists(); err != nil || exists {
+		t.Errorf("Should not find user that doesn't exist, but got %v, %v", exists, err)
+	}
+}
+
+func TestEstimatedCount(t *testing.T) {
+	DB.Save(&User{Name: "EstimatedCountUser"})
+
+	var count int64
+	DB.Model(&User{}).Count(&count)
+	if estimated, err := DB.Model(&User{}).EstimatedCount(); err != nil || estimated != count {
+		t.Errorf("Small tables should be counted exactly, expect %v, but got %v, %v", count, estimated, err)
+	}
+
+	if estimated, err := DB.Model(&User{}).Where("name = ?", "EstimatedCountUser").EstimatedCount(); err != nil || estimated != 1 {
+		t.Errorf("Should count exactly with conditions, but got %v, %v", estimated, err)
+	}
+
+	defer func(threshold int64) { gorm.EstimatedCountThreshold = threshold }(gorm.EstimatedCountThreshold)
+	gorm.EstimatedCountThreshold = 0
+	if _, err := DB.Model(&User{}).EstimatedCount(); err != nil {
+		t.Errorf("No error should happen when read estimated count, but got %v", err)
+	}
+}
+
+func TestDistinct(t *testing.T) {
+	DB.Save(&User{Name: "DistinctUser", Age: 1}).Save(&User{Name: "DistinctUser", Age: 1}).Save(&User{Name: "DistinctUser", Age: 2})
+
+	var names []string
+	DB.Model(&User{}).Distinct().Where("name = ?", "DistinctUser").Pluck("name", &names)
+	if len(names) != 1 {
+		t.Errorf("Should find distinct names, but got %v", names)
+	}
+
+	var count1, count2 int
+	DB.Model(&User{}).Where("name = ?", "DistinctUser").Distinct("age").Count(&count1)
+	if count1 != 2 {
+		t.Errorf("Should count distinct ages, but got %v", count1)
+	}
+
+	DB.Model(&User{}).Where("name = ?", "DistinctUser").Distinct("name", "age").Count(&count2)
+	if count2 != 2 {
+		t.Errorf("Should count distinct names and ages, but got %v", count2)
+	}
+
+	var users []User
+	DB.Distinct("name", "age").Where("name = ?", "DistinctUser").Find(&users)
+	if len(users) != 2 {
+		t.Errorf("Should find distinct users, but got %v", len(users))
+	}
+}
+
+func TestUnion(t *testing.T) {
+	DB.Save(&User{Name: "UnionUser1", Age: 1}).Save(&User{Name: "UnionUser2", Age: 2}).Save(&User{Name: "UnionUser3", Age: 3})
+
+	var users []User
+	query1 := DB.Model(&User{}).Where("name = ?", "UnionUser1")
+	query2 := DB.Model(&User{}).Where("name IN (?)", []string{"UnionUser1", "UnionUser3"})
+	query1.Union(query2).Order("age desc").Find(&users)
+	if len(users) != 2 || users[0].Name != "UnionUser3" || users[1].Name != "UnionUser1" {
+		t.Errorf("Should find union results with outer order, but got %+v", users)
+	}
+
+	var count int
+	query1.UnionAll(query2).Count(&count)
+	if count != 3 {
+		t.Errorf("Should count union all results, but got %v", count)
+	}
+
+	users = []User{}
+	query1.UnionAll(query2).Order("age").Limit(1).Find(&users)
+	if len(users) != 1 || users[0].Name != "UnionUser1" {
+		t.Errorf("Limit should be applied to the combined results, but got %+v", users)
+	}
+}
+
+func TestWindowFunctionFromSubQuery(t *testing.T) {
+	DB.Save(&User{Name: "WindowUser", Age: 10, Role: Role{Name: "window"}}).Save(&User{Name: "WindowUser", Age: 30, Role: Role{Name: "window"}}).Save(&User{Name: "WindowUser", Age: 20, Role: Role{Name: "window"}})
+
+	ranked := DB.Model(&User{}).Where("role = ?", "window").Select([]interface{}{"*", gorm.RowNumber().PartitionBy("name").OrderBy("age DESC").As("age_rank")})
+
+	var users []User
+	DB.From(ranked, "ranked_users").Where("age_rank = ?", 1).Find(&users)
+	if len(users) != 1 || users[0].Age != 30 {
+		t.Errorf("Should find the oldest user of the group, but got %+v", users)
+	}
+
+	var count int
+	DB.From(ranked, "ranked_users").Where("age_rank <= ?", 2).Count(&count)
+	if count != 2 {
+		t.Errorf("Should count rows from sub query, but got %v", count)
+	}
+}
+
+func TestILikeAndPatternHelpers(t *testing.T) {
+	DB.Save(&User{Name: "PatternUser_Jinzhu"}).Save(&User{Name: "patternuserXjinzhu"})
+
+	var users []User
+	DB.Where(gorm.ILike("name", "patternuser%")).Find(&users)
+	if len(users) != 2 {
+		t.Errorf("Should find users case-insensitively, but got %v", len(users))
+	}
+
+	users = []User{}
+	DB.Where(gorm.StartsWith("name", "PATTERNUSER_")).Find(&users)
+	if len(users) != 1 || users[0].Name != "PatternUser_Jinzhu" {
+		t.Errorf("Wildcards should be escaped in StartsWith, but got %+v", users)
+	}
+
+	users = []User{}
+	DB.Where(gorm.Contains("name", "userx")).Or(gorm.EndsWith("name", "_JINZHU")).Find(&users)
+	if len(users) != 2 {
+		t.Errorf("Should find users with Contains or EndsWith, but got %v", len(users))
+	}
+
+	users = []User{}
+	DB.Where(gorm.ILike("name", "patternuser%")).Not(gorm.Contains("name", "_")).Find(&users)
+	if len(users) != 1 || users[0].Name != "patternuserXjinzhu" {
+		t.Errorf("Should exclude users with Not, but got %+v", users)
+	}
+}
+
 func TestNot(t *testing.T) {
 	DB.Create(getPreparedUser("user1", "not"))
 	DB.Create(getPreparedUser("user2", "not"))
@@ -590,3 +804,330 @@ func TestSelectWithArrayInput(t *testing.T) {
 		t.Errorf("Should have selected both age and name")
 	}
 }
+
+func TestStrictScan(t *testing.T) {
+	DB.Save(&User{Name: "strict_scan", Age: 20})
+
+	type result struct {
+		Name string
+	}
+
+	var results []result
+	err := DB.StrictScan().Raw("SELECT name, age FROM users WHERE name = ?", "strict_scan").Scan(&results).Error
+	if unmapped, ok := err.(*gorm.UnmappedColumns); !ok || !reflect.DeepEqual(unmapped.Columns, []string{"age"}) {
+		t.Errorf("should return error for unmapped columns, but got %v", err)
+	}
+
+	if err := DB.StrictScan(true).Raw("SELECT name, age FROM users WHERE name = ?", "strict_scan").Scan(&results).Error; err != nil || len(results) != 1 {
+		t.Errorf("unmapped columns should only be logged with warnOnly, but got %v", err)
+	}
+
+	var user User
+	if err := DB.StrictScan().Select("name, 1 AS unknown").Where("name = ?", "strict_scan").First(&user).Error; err == nil {
+		t.Errorf("should return error for unmapped columns when querying struct")
+	}
+
+	if err := DB.StrictScan().Raw("SELECT name FROM users WHERE name = ?", "strict_scan").Scan(&results).Error; err != nil {
+		t.Errorf("should not return error when all columns are mapped, but got %v", err)
+	}
+}
+
+func TestScanAliases(t *testing.T) {
+	company := Company{Name: "scan_aliases_company"}
+	DB.Save(&company)
+	DB.Save(&User{Name: "scan_aliases", CompanyID: company.Id})
+
+	type result struct {
+		Name    string
+		Company Company
+		Owner   *User
+	}
+
+	var results []result
+	DB.ScanAliases(map[string]string{"u_name": "Name", "c_name": "Company.Name"}).
+		Raw("SELECT users.name AS u_name, companies.name AS c_name, companies.id AS company__id, users.age AS `owner.age` FROM users JOIN companies ON companies.id = users.company_id WHERE users.name = ?", "scan_aliases").
+		Scan(&results)
+
+	if len(results) != 1 {
+		t.Fatalf("should find one result, but got %v", len(results))
+	}
+	if results[0].Name != "scan_aliases" || results[0].Company.Name != company.Name || results[0].Company.Id != company.Id {
+		t.Errorf("aliased columns should be scanned into nested struct, but got %+v", results[0])
+	}
+	if results[0].Owner == nil {
+		t.Errorf("nested struct pointer should be allocated")
+	}
+}
+
+func TestJoinsAssociation(t *testing.T) {
+	company := Company{Name: "joins_company"}
+	DB.Save(&company)
+	user := User{Name: "joins_association", CompanyID: company.Id, CreditCard: CreditCard{Number: "joins_card"}}
+	DB.Save(&user)
+	DB.Save(&User{Name: "joins_association_without_company"})
+
+	var found User
+	if err := DB.Joins("Company").Joins("CreditCard").First(&found, user.Id).Error; err != nil {
+		t.Errorf("associations should be joined, but got %v", err)
+	}
+	if found.Name != user.Name || found.Company.Name != company.Name || found.CreditCard.Number != "joins_card" {
+		t.Errorf("joined associations should be scanned, but got %v, %v", found.Company.Name, found.CreditCard.Number)
+	}
+
+	var users []User
+	DB.Joins("Company").Where("users.name LIKE ?", "joins_association%").Order("users.id").Find(&users)
+	if len(users) != 2 || users[0].Company.Name != company.Name || users[1].Company.Id != 0 {
+		t.Errorf("users without company should be found with blank company, but got %+v", users)
+	}
+}
+
+func TestResultSets(t *testing.T) {
+	if dialect := os.Getenv("GORM_DIALECT"); dialect != "postgres" {
+		t.Skip()
+	}
+
+	DB.Save(&User{Name: "result_sets", Age: 18})
+	DB.Save(&Product{Code: "result_sets"})
+
+	sets, err := DB.Raw("SELECT * FROM users WHERE name = 'result_sets'; SELECT * FROM products WHERE code = 'result_sets'").ResultSets()
+	if err != nil {
+		t.Fatalf("query should succeed, but got %v", err)
+	}
+	defer sets.Close()
+
+	var users []User
+	var product Product
+	for i, dest := range []interface{}{&users, &product} {
+		if !sets.Next() {
+			t.Fatalf("result set %v should exist", i)
+		}
+		if err := sets.Scan(dest); err != nil {
+			t.Errorf("result set %v should be scanned, but got %v", i, err)
+		}
+	}
+	if sets.Next() {
+		t.Errorf("there should be only two result sets")
+	}
+
+	if len(users) != 1 || users[0].Age != 18 || product.Code != "result_sets" {
+		t.Errorf("each result set should be scanned into its dest, but got %v, %v", users, product)
+	}
+}
+
+func TestLabel(t *testing.T) {
+	var preparedSql, label string
+	callback := DB.Callback()
+	callback.Query().After("gorm:prepare_query").Register("test:capture_label", func(scope *gorm.Scope) {
+		preparedSql, label = scope.Sql, scope.Label()
+	})
+	defer callback.Query().Remove("test:capture_label")
+
+	var users []User
+	if err := DB.Label("report-export").Where("name = ?", "label").Find(&users).Error; err != nil {
+		t.Errorf("labeled query should succeed, but got %v", err)
+	}
+	if label != "report-export" || !strings.HasPrefix(preparedSql, "/* label:report-export */ SELECT") {
+		t.Errorf("label should be injected as sql comment, but got %v, %v", label, preparedSql)
+	}
+
+	DB.Label("evil */ DROP TABLE users; /*").Find(&users)
+	if strings.Count(preparedSql, "*/") != 1 || strings.Count(preparedSql, "/*") != 1 {
+		t.Errorf("label should not close the comment early, but got %v", preparedSql)
+	}
+
+	DB.Find(&users)
+	if label != "" || strings.Contains(preparedSql, "label:") {
+		t.Errorf("label should only belong to the labeled chain, but got %v", preparedSql)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	var plan []string
+	err := DB.Model(&User{}).Where("name = ?", "explain").Explain(&plan).Error
+	if os.Getenv("GORM_DIALECT") == "mssql" {
+		if err != gorm.ExplainNotSupported {
+			t.Errorf("explain should not be supported by mssql, but got %v", err)
+		}
+		return
+	}
+
+	if err != nil {
+		t.Errorf("explain should succeed, but got %v", err)
+	}
+	if len(plan) == 0 {
+		t.Errorf("plan of the query should be returned")
+	}
+}
+
+func TestMaxRows(t *testing.T) {
+	DB.Save(&User{Name: "max_rows"}).Save(&User{Name: "max_rows"}).Save(&User{Name: "max_rows"})
+	DB.SetMaxRows(2)
+	defer DB.SetMaxRows(0)
+
+	var users []User
+	if err := DB.Where("name = ?", "max_rows").Find(&users).Error; err != gorm.TooManyRows {
+		t.Errorf("should return TooManyRows when finding more rows than max without limit, but got %v", err)
+	}
+
+	users = nil
+	if err := DB.Where("name = ?", "max_rows").Limit(3).Find(&users).Error; err != nil || len(users) != 3 {
+		t.Errorf("queries with limit should not be checked, but got %v, %v", err, len(users))
+	}
+
+	var user User
+	if err := DB.Where("name = ?", "max_rows").Find(&user).Error; err != nil {
+		t.Errorf("finding a struct should not be checked, but got %v", err)
+	}
+
+	DB.SetMaxRows(2, true)
+	users = nil
+	if err := DB.Where("name = ?", "max_rows").Find(&users).Error; err != nil || len(users) != 3 {
+		t.Errorf("rows should be found with warnOnly, but got %v, %v", err, len(users))
+	}
+}
+
+func TestFindInChunks(t *testing.T) {
+	defer func(size int) { gorm.InListChunkSize = size }(gorm.InListChunkSize)
+	gorm.InListChunkSize = 2
+
+	var ids []int64
+	for i := 0; i < 5; i++ {
+		user := User{Name: "in_chunks"}
+		DB.Save(&user)
+		ids = append(ids, user.Id)
+	}
+
+	var sqls []string
+	DB.Callback().Query().After("gorm:query").Register("test:capture_in_chunks_sql", func(scope *gorm.Scope) {
+		sqls = append(sqls, scope.Sql)
+	})
+	defer DB.Callback().Query().Remove("test:capture_in_chunks_sql")
+
+	var users []User
+	db := DB.Where("name = ?", "in_chunks").Where("id IN (?)", ids).Find(&users)
+	if db.Error != nil || len(users) != 5 || db.RowsAffected != 5 {
+		t.Errorf("should find all users chunk by chunk, but got %v, %v, %v", db.Error, len(users), db.RowsAffected)
+	}
+	if len(sqls) != 3 {
+		t.Errorf("5 ids should be queried with 3 chunks, but got %v", sqls)
+	}
+
+	users = nil
+	if err := DB.Find(&users, ids).Error; err != nil || len(users) != 5 {
+		t.Errorf("should find users by primary keys chunk by chunk, but got %v, %v", err, len(users))
+	}
+
+	sqls = nil
+	users = nil
+	if err := DB.Where("id IN (?)", ids).Limit(5).Find(&users).Error; err != nil || len(users) != 5 || len(sqls) != 1 {
+		t.Errorf("queries with limit should not be chunked, but got %v, %v, %v", err, len(users), sqls)
+	}
+
+	users = nil
+	if db := DB.Where("id IN (?)", append(ids, ids...)).Find(&users); db.Error != nil || len(users) != 5 || db.RowsAffected != 5 {
+		t.Errorf("records found by more than one chunk should be merged, but got %v, %v, %v", db.Error, len(users), db.RowsAffected)
+	}
+
+	sqls = nil
+	users = nil
+	if err := DB.Where("id NOT IN (?)", ids).Where("name = ?", "in_chunks").Find(&users).Error; err != nil || len(users) != 0 || len(sqls) != 1 {
+		t.Errorf("NOT IN conditions should not be chunked, but got %v, %v, %v", err, len(users), sqls)
+	}
+
+	sqls = nil
+	users = nil
+	if err := DB.Where("id IN (?) OR name = ?", ids, "in_chunks").Find(&users).Error; err != nil || len(users) != 5 || len(sqls) != 1 {
+		t.Errorf("OR'd conditions should not be chunked, but got %v, %v, %v", err, len(users), sqls)
+	}
+
+	sqls = nil
+	var counts []struct{ Count int }
+	if err := DB.Table("users").Select("count(*) as count").Where("id IN (?)", ids).Scan(&counts).Error; err != nil ||
+		len(counts) != 1 || counts[0].Count != 5 || len(sqls) != 1 {
+		t.Errorf("aggregate selects should not be chunked, but got %v, %v, %v", err, counts, sqls)
+	}
+}
+
+func TestStructConditionWithZeroValues(t *testing.T) {
+	DB.Save(&User{Name: "zero_age", Age: 0}).Save(&User{Name: "zero_age", Age: 20})
+
+	var users []User
+	DB.Where(&User{Name: "zero_age", Age: 0}).Find(&users)
+	if len(users) != 2 {
+		t.Errorf("zero age should be skipped by default, but got %v", len(users))
+	}
+
+	users = nil
+	DB.Where(&User{Name: "zero_age", Age: 0}, "Name", "Age").Find(&users)
+	if len(users) != 1 || users[0].Age != 0 {
+		t.Errorf("selected zero age should be queried, but got %v", users)
+	}
+
+	if err := DB.StrictConditions().Where(&User{Age: 0}).Find(&users).Error; err != gorm.BlankCondition {
+		t.Errorf("blank struct condition should return BlankCondition with StrictConditions, but got %v", err)
+	}
+}
+
+func TestConditionGroups(t *testing.T) {
+	user1, user2, user3 := User{Name: "condition_group_1", Age: 10}, User{Name: "condition_group_2", Age: 70}, User{Name: "condition_group_3", Age: 30}
+	DB.Save(&user1).Save(&user2).Save(&user3)
+	ids := []int64{user1.Id, user2.Id, user3.Id}
+
+	var users []User
+	DB.Where("id IN (?)", ids).Where(gorm.Or(gorm.Expr("age < ?", 18), gorm.Expr("age > ?", 60))).Find(&users)
+	if len(users) != 2 {
+		t.Errorf("should find users matching the or group, but got %v", len(users))
+	}
+
+	users = nil
+	DB.Where("id IN (?)", ids).Not(gorm.Or(User{Name: "condition_group_1"}, gorm.And(gorm.Expr("age > ?", 60), "age < 100"))).Find(&users)
+	if len(users) != 1 || users[0].Name != "condition_group_3" {
+		t.Errorf("should exclude users matching the group, but got %v", users)
+	}
+}
+
+func TestReload(t *testing.T) {
+	user := User{Name: "reload", Age: 10, Emails: []Email{{Email: "reload@example.com"}}}
+	DB.Save(&user)
+	DB.Model(&User{}).Where("id = ?", user.Id).UpdateColumn("age", 20)
+
+	user.Name, user.Emails = "stale", nil
+	if err := DB.Reload(&user, "Emails").Error; err != nil {
+		t.Errorf("No error should happen when reload, but got %v", err)
+	}
+	if user.Name != "reload" || user.Age != 20 || len(user.Emails) != 1 {
+		t.Errorf("record should be reloaded with preloads, but got %+v", user)
+	}
+
+	deleted := User{Id: user.Id + 1000000, Name: "deleted"}
+	if err := DB.Reload(&deleted).Error; err != gorm.RecordNotFound || deleted.Name != "deleted" {
+		t.Errorf("record should be kept if not found, but got %v, %+v", err, deleted)
+	}
+
+	if err := DB.Reload(&User{Name: "new"}).Error; err == nil {
+		t.Errorf("should get error when reload a record without primary key")
+	}
+}
+
+func TestLockForUpdate(t *testing.T) {
+	var users []User
+	if err := DB.LockForUpdate(&users).Error; err != gorm.LockWithoutTransaction {
+		t.Errorf("should get error when locking outside of transactions, but got %v", err)
+	}
+
+	DB.Save(&User{Name: "locked", Age: 1})
+	tx := DB.Begin()
+	defer tx.Rollback()
+
+	err := tx.LockForUpdate(&users, "name = ?", "locked").Error
+	switch os.Getenv("GORM_DIALECT") {
+	case "mysql", "postgres":
+		if err != nil || len(users) != 1 {
+			t.Errorf("matched records should be locked, but got %v, %v", err, len(users))
+		}
+	case "", "sqlite3", "mssql":
+		if err != gorm.LockNotSupported {
+			t.Errorf("should get error when the dialect can't lock records, but got %v", err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package gorm
+
+import (
+	"regexp"
+	"strings"
+)
+
+// QuoteStyle decide which characters are used to quote identifiers
+type QuoteStyle int
+
+const (
+	// QuoteDialect quote identifiers with current dialect's characters, it is the default style
+	QuoteDialect QuoteStyle = iota
+	// QuoteBackticks quote identifiers like `name`
+	QuoteBackticks
+	// QuoteDoubleQuotes quote identifiers like "name", it is the ANSI style, e.g. for MySQL running with ANSI_QUOTES
+	QuoteDoubleQuotes
+	// QuoteNone don't quote identifiers
+	QuoteNone
+)
+
+// QuotePolicy decide how table and column names are quoted in generated sql, e.g:
+//
+//	db.SetQuotePolicy(gorm.QuotePolicy{Style: gorm.QuoteDoubleQuotes, OnlyWhenNeeded: true})
+type QuotePolicy struct {
+	Style QuoteStyle
+	// OnlyWhenNeeded only quote identifiers that are not plain names like `user_id`, or are reserved words of
+	// the dialect like `order`
+	OnlyWhenNeeded bool
+}
+
+var plainIdentifierRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// reservedWords are reserved by SQL and most databases, dialects reserve more words
+var reservedWords = wordSet(`ADD ALL ALTER AND ANY AS ASC BETWEEN BY CASE CAST CHECK COLUMN CONSTRAINT CREATE CROSS
+	CURRENT_DATE CURRENT_TIME CURRENT_TIMESTAMP CURRENT_USER DEFAULT DELETE DESC DISTINCT DROP ELSE END EXCEPT EXISTS
+	FALSE FETCH FOR FOREIGN FROM FULL GRANT GROUP HAVING IN INNER INSERT INTERSECT INTO IS JOIN LEFT LIKE NATURAL NOT
+	NULL ON OR ORDER OUTER PRIMARY REFERENCES REVOKE RIGHT ROW SELECT SESSION_USER SET SOME TABLE THEN TO TRUE UNION
+	UNIQUE UPDATE USER USING VALUES WHEN WHERE WITH`)
+
+// wordSet make a set of upper case words separated by white spaces
+func wordSet(words string) map[string]bool {
+	set := map[string]bool{}
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+func (policy QuotePolicy) quoteChars(dialect Dialect) (string, string) {
+	switch policy.Style {
+	case QuoteBackticks:
+		return "`", "`"
+	case QuoteDoubleQuotes:
+		return `"`, `"`
+	}
+
+	quoted := dialect.Quote("")
+	if len(quoted) < 2 {
+		return `"`, `"`
+	}
+	return quoted[:len(quoted)/2], quoted[len(quoted)/2:]
+}
+
+func (policy QuotePolicy) quote(dialect Dialect, name string) string {
+	if policy.Style == QuoteNone || (policy.OnlyWhenNeeded && plainIdentifierRegexp.MatchString(name) && !dialect.IsReservedWord(name)) {
+		return name
+	}
+	if policy.Style == QuoteDialect {
+		return dialect.Quote(name)
+	}
+	left, right := policy.quoteChars(dialect)
+	return left + name + right
+}
+
+// quoteIdentifier quote name that might come from outside, quote characters inside it are escaped,
+// and it is always quoted unless it is a plain name, whatever the policy is
+func (policy QuotePolicy) quoteIdentifier(dialect Dialect, name string) string {
+	if plainIdentifierRegexp.MatchString(name) {
+		return policy.quote(dialect, name)
+	}
+	if policy.Style == QuoteNone {
+		policy.Style = QuoteDialect
+	}
+	left, right := policy.quoteChars(dialect)
+	return left + strings.Replace(name, right, right+right, -1) + right
+}
+
+// SetQuotePolicy change how identifiers are quoted for the DB and all DBs derived from it
+func (s *DB) SetQuotePolicy(policy QuotePolicy) {
+	s.parent.quotePolicy = policy
+}
+
+// QuoteIdentifier quote a table or column name safely with current dialect and quote policy,
+// so names from user's config could be used in sql, dots separate the name's parts, e.g:
+//
+//	db.QuoteIdentifier("reports.daily`stats") // `reports`.`daily``stats`
+func (s *DB) QuoteIdentifier(name string) string {
+	var parts []string
+	for _, part := range strings.Split(name, ".") {
+		parts = append(parts, s.parent.quotePolicy.quoteIdentifier(s.parent.dialect, part))
+	}
+	return strings.Join(parts, ".")
+}
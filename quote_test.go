@@ -0,0 +1,67 @@
+package gorm
+
+import "testing"
+
+func TestQuotePolicy(t *testing.T) {
+	cases := []struct {
+		policy   QuotePolicy
+		dialect  Dialect
+		name     string
+		expected string
+	}{
+		{QuotePolicy{}, &mysql{}, "user_id", "`user_id`"},
+		{QuotePolicy{}, &postgres{}, "user_id", `"user_id"`},
+		{QuotePolicy{Style: QuoteDoubleQuotes}, &mysql{}, "user_id", `"user_id"`},
+		{QuotePolicy{Style: QuoteBackticks}, &postgres{}, "user_id", "`user_id`"},
+		{QuotePolicy{Style: QuoteNone}, &mysql{}, "user_id", "user_id"},
+		{QuotePolicy{OnlyWhenNeeded: true}, &mysql{}, "user_id", "user_id"},
+		{QuotePolicy{OnlyWhenNeeded: true}, &mysql{}, "user name", "`user name`"},
+		{QuotePolicy{OnlyWhenNeeded: true}, &mysql{}, "order", "`order`"},
+		{QuotePolicy{OnlyWhenNeeded: true}, &postgres{}, "User", `"User"`},
+		{QuotePolicy{OnlyWhenNeeded: true}, &mysql{}, "key", "`key`"},
+		{QuotePolicy{OnlyWhenNeeded: true}, &postgres{}, "key", "key"},
+		{QuotePolicy{OnlyWhenNeeded: true}, &postgres{}, "limit", `"limit"`},
+	}
+
+	for _, c := range cases {
+		if result := c.policy.quote(c.dialect, c.name); result != c.expected {
+			t.Errorf("quote %v with %+v should be %v, but got %v", c.name, c.policy, c.expected, result)
+		}
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	if result := db.QuoteIdentifier("reports.daily`stats"); result != "`reports`.`daily``stats`" {
+		t.Errorf("quote characters in identifier should be escaped, but got %v", result)
+	}
+
+	db.SetQuotePolicy(QuotePolicy{Style: QuoteNone})
+	if result := db.QuoteIdentifier("users"); result != "users" {
+		t.Errorf("plain identifier should follow the policy, but got %v", result)
+	}
+	if result := db.QuoteIdentifier("users; DROP TABLE users"); result != "`users; DROP TABLE users`" {
+		t.Errorf("identifier that is not plain should always be quoted, but got %v", result)
+	}
+
+	db.SetQuotePolicy(QuotePolicy{Style: QuoteDoubleQuotes})
+	if result := db.QuoteIdentifier(`my"table`); result != `"my""table"` {
+		t.Errorf("identifier should be quoted with ANSI quotes, but got %v", result)
+	}
+}
+
+type reservedColumnRecord struct {
+	Id    int64
+	Order int
+}
+
+func TestQuoteReservedColumnWhenNeeded(t *testing.T) {
+	db := newFakeDB("mysql", "")
+	db.SetQuotePolicy(QuotePolicy{OnlyWhenNeeded: true})
+
+	db.Create(&reservedColumnRecord{Order: 1})
+	if len(fakeStatements) != 1 || fakeStatements[0] != "INSERT INTO reserved_column_records (`order`) VALUES (?)" {
+		t.Errorf("reserved column should be quoted while the plain table name is not, but got %v", fakeStatements)
+	}
+}
@@ -0,0 +1,104 @@
+package gorm
+
+import (
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ReplicaPolicy decide whether replicas could be used for reads
+type ReplicaPolicy struct {
+	// MaxLag replicas behind the primary more than MaxLag are skipped, or whose lag is unknown, 0 means lag is not checked
+	MaxLag time.Duration
+	// CheckInterval how long a checked lag is reused, it is one second by default
+	CheckInterval time.Duration
+}
+
+type replica struct {
+	db        *sql.DB
+	mutex     sync.Mutex
+	lag       time.Duration
+	lagKnown  bool
+	checkedAt time.Time
+}
+
+type replicaSet struct {
+	replicas []*replica
+	policy   ReplicaPolicy
+	next     uint32
+}
+
+// AddReplica add a read replica, queries out of transaction are sent to replicas in turn, e.g:
+//
+//	replicaDB, _ := sql.Open("mysql", "gorm:gorm@tcp(replica:3306)/gorm")
+//	db.AddReplica(replicaDB)
+func (s *DB) AddReplica(db *sql.DB) {
+	if s.parent.replicas == nil {
+		s.parent.replicas = &replicaSet{}
+	}
+	s.parent.replicas.replicas = append(s.parent.replicas.replicas, &replica{db: db})
+}
+
+// SetReplicaPolicy set the policy to check replicas before reading from them
+//
+//	db.SetReplicaPolicy(gorm.ReplicaPolicy{MaxLag: 5 * time.Second})
+func (s *DB) SetReplicaPolicy(policy ReplicaPolicy) {
+	if s.parent.replicas == nil {
+		s.parent.replicas = &replicaSet{}
+	}
+	s.parent.replicas.policy = policy
+}
+
+// ForcePrimary read from the primary database, for reading your own writes
+//
+//	db.ForcePrimary().First(&user, id)
+func (s *DB) ForcePrimary() *DB {
+	return s.Set("gorm:force_primary", true)
+}
+
+func (r *replica) usable(dialect Dialect, policy ReplicaPolicy) bool {
+	if policy.MaxLag <= 0 {
+		return true
+	}
+
+	interval := policy.CheckInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if now := time.Now(); now.Sub(r.checkedAt) >= interval {
+		r.lag, r.lagKnown = dialect.ReplicationLag(r.db)
+		r.checkedAt = now
+	}
+	return r.lagKnown && r.lag <= policy.MaxLag
+}
+
+// choose pick the next usable replica, nil if all of them are too far behind
+func (set *replicaSet) choose(dialect Dialect) *sql.DB {
+	count := len(set.replicas)
+	start := int(atomic.AddUint32(&set.next, 1))
+	for i := 0; i < count; i++ {
+		if r := set.replicas[(start+i)%count]; r.usable(dialect, set.policy) {
+			return r.db
+		}
+	}
+	return nil
+}
+
+// RouteToReplica send reads to a replica, unless it is in transaction, forced to primary, or all replicas lag too much
+func RouteToReplica(scope *Scope) {
+	replicas := scope.db.parent.replicas
+	if scope.HasError() || replicas == nil || len(replicas.replicas) == 0 || scope.db.db != scope.db.parent.db {
+		return
+	}
+	if forcePrimary, ok := scope.Get("gorm:force_primary"); ok && forcePrimary.(bool) {
+		return
+	}
+
+	if db := replicas.choose(scope.Dialect()); db != nil {
+		scope.replica = db
+	}
+}
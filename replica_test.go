@@ -0,0 +1,53 @@
+package gorm
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+type lagDialect struct {
+	commonDialect
+	lags map[*sql.DB]time.Duration
+}
+
+func (d lagDialect) ReplicationLag(replica *sql.DB) (time.Duration, bool) {
+	lag, ok := d.lags[replica]
+	return lag, ok
+}
+
+func TestRouteToReplica(t *testing.T) {
+	primary, _ := sql.Open("gorm_fake_test", "primary")
+	fresh, _ := sql.Open("gorm_fake_test", "fresh")
+	stale, _ := sql.Open("gorm_fake_test", "stale")
+
+	dialect := lagDialect{lags: map[*sql.DB]time.Duration{fresh: time.Second, stale: time.Minute}}
+	db := &DB{dialect: dialect, db: primary, values: map[string]interface{}{}, callback: &callback{}}
+	db.parent = db
+	db.AddReplica(stale)
+	db.AddReplica(fresh)
+	db.SetReplicaPolicy(ReplicaPolicy{MaxLag: 10 * time.Second})
+
+	for i := 0; i < 3; i++ {
+		scope := db.NewScope(nil)
+		RouteToReplica(scope)
+		if scope.SqlDB() != fresh {
+			t.Errorf("reads should be sent to the replica that is not lagging")
+		}
+	}
+
+	scope := db.ForcePrimary().NewScope(nil)
+	RouteToReplica(scope)
+	if scope.SqlDB() != primary {
+		t.Errorf("reads should be sent to primary with ForcePrimary")
+	}
+
+	dialect.lags[fresh] = time.Hour
+	db.SetReplicaPolicy(ReplicaPolicy{MaxLag: 10 * time.Second, CheckInterval: time.Nanosecond})
+	time.Sleep(time.Millisecond)
+	scope = db.NewScope(nil)
+	RouteToReplica(scope)
+	if scope.SqlDB() != primary {
+		t.Errorf("reads should fall back to primary when all replicas are lagging")
+	}
+}
@@ -0,0 +1,58 @@
+package gorm
+
+import (
+	"database/sql"
+	"reflect"
+)
+
+// ResultSets iterate result sets of a query, each of them could be scanned into a separate dest
+type ResultSets struct {
+	scope   *Scope
+	rows    *sql.Rows
+	started bool
+}
+
+// ResultSets run the query and return an iterator of its result sets, e.g:
+//
+//	sets, err := db.Raw("SELECT * FROM users; SELECT * FROM orders").ResultSets()
+//	defer sets.Close()
+//	for i := 0; sets.Next(); i++ {
+//		sets.Scan(dests[i])
+//	}
+func (s *DB) ResultSets() (*ResultSets, error) {
+	scope := s.clone().NewScope(s.Value)
+	rows, err := scope.rows()
+	if scope.Err(err) != nil {
+		return nil, err
+	}
+	return &ResultSets{scope: scope, rows: rows}, nil
+}
+
+// Next move to the next result set, the first call moves to the first one
+func (sets *ResultSets) Next() bool {
+	if !sets.started {
+		sets.started = true
+		return true
+	}
+	return sets.rows.NextResultSet()
+}
+
+// Scan scan rows of current result set into dest, it could be a struct or slice
+func (sets *ResultSets) Scan(dest interface{}) error {
+	scope := sets.scope.New(dest)
+	scope.scanRows(sets.rows, reflect.Indirect(reflect.ValueOf(dest)))
+	if scope.db.Error != nil {
+		return scope.db.Error
+	}
+	return sets.rows.Err()
+}
+
+// Err return the error happened during iteration
+func (sets *ResultSets) Err() error {
+	return sets.rows.Err()
+}
+
+// Close close the rows, remaining result sets are discarded
+func (sets *ResultSets) Close() error {
+	return sets.rows.Close()
+}
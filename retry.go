@@ -0,0 +1,102 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"time"
+)
+
+// RetryPolicy decide how connecting to database is retried, for new connections of the pool too,
+// so services starting before the database is ready, or meeting transient DNS failures, could recover
+type RetryPolicy struct {
+	// MaxAttempts how many times to try connecting, it is 5 by default
+	MaxAttempts int
+	// Backoff the delay before the first retry, it is doubled for later retries, 100ms by default
+	Backoff time.Duration
+	// MaxBackoff the max delay between retries, it is 10s by default
+	MaxBackoff time.Duration
+	// Lazy don't connect when opening, the first query connects, otherwise the database is pinged when opening
+	Lazy bool
+}
+
+func (policy RetryPolicy) attempts() int {
+	if policy.MaxAttempts <= 0 {
+		return 5
+	}
+	return policy.MaxAttempts
+}
+
+// delay get the delay before the n-th retry, n starts from 1
+func (policy RetryPolicy) delay(n int) time.Duration {
+	backoff, maxBackoff := policy.Backoff, policy.MaxBackoff
+	if backoff <= 0 {
+		backoff = 100 * time.Millisecond
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = 10 * time.Second
+	}
+	for i := 1; i < n && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// retryConnector connect with the connector, failed connecting is retried with backoff
+type retryConnector struct {
+	connector driver.Connector
+	policy    RetryPolicy
+}
+
+func (connector *retryConnector) Connect(ctx context.Context) (conn driver.Conn, err error) {
+	for attempt := 1; ; attempt++ {
+		if conn, err = connector.connector.Connect(ctx); err == nil || attempt >= connector.policy.attempts() {
+			return
+		}
+
+		timer := time.NewTimer(connector.policy.delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+func (connector *retryConnector) Driver() driver.Driver {
+	return connector.connector.Driver()
+}
+
+// OpenWithRetry open database like Open, connecting is retried with backoff of the policy, e.g:
+//
+//	// wait for the database to be ready, try 10 times, with 1s, 2s, 4s, 5s, 5s... between attempts
+//	db, err := gorm.OpenWithRetry("postgres", "user=gorm dbname=gorm", gorm.RetryPolicy{MaxAttempts: 10, Backoff: time.Second, MaxBackoff: 5 * time.Second})
+//
+//	// don't connect until the first query
+//	db, err := gorm.OpenWithRetry("mysql", "gorm:gorm@tcp(db:3306)/gorm", gorm.RetryPolicy{Lazy: true})
+func OpenWithRetry(dialect string, source string, policy RetryPolicy, statements ...string) (DB, error) {
+	// sql.Open won't connect, it is only used to get the registered driver
+	dbSql, err := sql.Open(driverName(dialect), source)
+	if err != nil {
+		return DB{}, err
+	}
+	sqlDriver := dbSql.Driver()
+	dbSql.Close()
+
+	connector := &retryConnector{
+		connector: &sessionConnector{driver: sqlDriver, source: source, statements: statements},
+		policy:    policy,
+	}
+	dbSql = sql.OpenDB(connector)
+	if !policy.Lazy {
+		if err := dbSql.Ping(); err != nil {
+			dbSql.Close()
+			return DB{}, err
+		}
+	}
+	return newDB(dialect, source, dbSql), nil
+}
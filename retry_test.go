@@ -0,0 +1,42 @@
+package gorm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOpenWithRetry(t *testing.T) {
+	fakeOpenFailures, fakeOpens = 2, 0
+	db, err := OpenWithRetry("gorm_fake_test", "unreachable", RetryPolicy{Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("connecting should be retried, but got %v", err)
+	}
+	db.Close()
+	if fakeOpens != 3 {
+		t.Errorf("should connect after 2 failures, but got %v attempts", fakeOpens)
+	}
+
+	fakeOpenFailures, fakeOpens = 10, 0
+	if _, err := OpenWithRetry("gorm_fake_test", "unreachable", RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}); err == nil || fakeOpens != 3 {
+		t.Errorf("should give up after max attempts, but got %v, %v attempts", err, fakeOpens)
+	}
+
+	fakeOpenFailures, fakeOpens = 10, 0
+	db, err = OpenWithRetry("gorm_fake_test", "unreachable", RetryPolicy{Lazy: true})
+	if err != nil || fakeOpens != 0 {
+		t.Errorf("lazy mode should not connect when opening, but got %v, %v attempts", err, fakeOpens)
+	}
+	db.Close()
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	for n, expected := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		if delay := policy.delay(n); delay != expected {
+			t.Errorf("delay before retry %v should be %v, but got %v", n, expected, delay)
+		}
+	}
+	if delay := (RetryPolicy{}).delay(1); delay != 100*time.Millisecond {
+		t.Errorf("default backoff should be 100ms, but got %v", delay)
+	}
+}
@@ -0,0 +1,187 @@
+package gorm
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// ColumnInfo is metadata of a column in the live schema, ColumnType is the type with sizes and modifiers as the
+// database reports it, e.g. `int(10) unsigned`, `character varying(100)`
+type ColumnInfo struct {
+	Name          string
+	Type          string
+	Size          int64
+	Nullable      bool
+	Default       sql.NullString
+	PrimaryKey    bool
+	ColumnType    string
+	AutoIncrement bool
+	Charset       string
+	Collation     string
+}
+
+// IndexInfo is metadata of an index in the live schema, columns of expression parts are blank
+type IndexInfo struct {
+	Name    string
+	Columns []string
+	Unique  bool
+	Primary bool
+}
+
+// ForeignKeyInfo is metadata of a single column foreign key in the live schema
+type ForeignKeyInfo struct {
+	Name             string
+	Column           string
+	ReferencedTable  string
+	ReferencedColumn string
+}
+
+// Tables get names of tables in current database, views are not included
+func (m Migrator) Tables() ([]string, error) {
+	scope := m.newScope()
+	return scope.Dialect().TableInfos(scope)
+}
+
+// Columns get columns of the table in the order of their positions, e.g:
+//
+//	columns, err := db.Migrator().Columns("users")
+//	for _, column := range columns {
+//		fmt.Println(column.Name, column.Type, column.Nullable)
+//	}
+func (m Migrator) Columns(tableName string) ([]ColumnInfo, error) {
+	scope := m.newScope()
+	return scope.Dialect().ColumnInfos(scope, tableName)
+}
+
+// Indexes get indexes of the table, including the primary key
+func (m Migrator) Indexes(tableName string) ([]IndexInfo, error) {
+	scope := m.newScope()
+	return scope.Dialect().IndexInfos(scope, tableName)
+}
+
+// ForeignKeys get foreign keys of the table, each column of composite foreign keys is returned separately
+func (m Migrator) ForeignKeys(tableName string) ([]ForeignKeyInfo, error) {
+	scope := m.newScope()
+	return scope.Dialect().ForeignKeyInfos(scope, tableName)
+}
+
+// informationSchemaTables get tables from INFORMATION_SCHEMA, condition selects tables of current database
+func informationSchemaTables(scope *Scope, condition string, args ...interface{}) ([]string, error) {
+	rows, err := scope.NewDB().Raw(fmt.Sprintf("SELECT table_name FROM INFORMATION_SCHEMA.TABLES WHERE %v ORDER BY table_name", condition), args...).Rows()
+	if err != nil {
+		return nil, err
+	}
+	return scanTableNames(rows)
+}
+
+func scanTableNames(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return tables, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+// informationSchemaColumns get columns from INFORMATION_SCHEMA, condition selects the schema of current database,
+// typeSql and autoIncrementSql are expressions of the column type and if the column is auto incremented, column types
+// are built from data types, sizes and precisions if typeSql is blank
+func informationSchemaColumns(scope *Scope, tableName string, typeSql string, autoIncrementSql string, condition string, args ...interface{}) ([]ColumnInfo, error) {
+	if typeSql == "" {
+		typeSql = "NULL"
+	}
+	if autoIncrementSql == "" {
+		autoIncrementSql = "1 = 0"
+	}
+	query := fmt.Sprintf(`SELECT c.column_name, c.data_type, c.character_maximum_length, c.numeric_precision, c.numeric_scale,
+	c.is_nullable, c.column_default, c.character_set_name, c.collation_name, %v, CASE WHEN %v THEN 1 ELSE 0 END,
+	(SELECT count(*) FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS t JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE k
+		ON k.constraint_name = t.constraint_name AND k.table_schema = t.table_schema AND k.table_name = t.table_name
+		WHERE t.constraint_type = 'PRIMARY KEY' AND k.table_schema = c.table_schema AND k.table_name = c.table_name AND k.column_name = c.column_name)
+	FROM INFORMATION_SCHEMA.COLUMNS c WHERE c.table_name = ? AND %v ORDER BY c.ordinal_position`, typeSql, autoIncrementSql, condition)
+	rows, err := scope.NewDB().Raw(query, append([]interface{}{tableName}, args...)...).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []ColumnInfo
+	for rows.Next() {
+		var column ColumnInfo
+		var size, precision, scale sql.NullInt64
+		var nullable string
+		var charset, collation, columnType sql.NullString
+		var autoIncrement, primaryKey int
+		if err := rows.Scan(&column.Name, &column.Type, &size, &precision, &scale, &nullable, &column.Default,
+			&charset, &collation, &columnType, &autoIncrement, &primaryKey); err != nil {
+			return columns, err
+		}
+		column.Size, column.Nullable, column.PrimaryKey = size.Int64, nullable == "YES", primaryKey > 0
+		column.Charset, column.Collation, column.AutoIncrement = charset.String, collation.String, autoIncrement > 0
+		column.ColumnType = columnType.String
+		if column.ColumnType == "" {
+			column.ColumnType = buildColumnType(column.Type, size, precision, scale)
+		}
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+// buildColumnType build the column type from the data type, the size of character types, and the precision and
+// scale of decimals, e.g. `varchar(100)`, `decimal(10,2)`, size -1 of MSSQL is `max`
+func buildColumnType(dataType string, size, precision, scale sql.NullInt64) string {
+	switch {
+	case size.Valid && size.Int64 < 0:
+		return dataType + "(max)"
+	case size.Valid && size.Int64 > 0:
+		return fmt.Sprintf("%v(%d)", dataType, size.Int64)
+	case precision.Valid && (strings.EqualFold(dataType, "decimal") || strings.EqualFold(dataType, "numeric")):
+		return fmt.Sprintf("%v(%d,%d)", dataType, precision.Int64, scale.Int64)
+	}
+	return dataType
+}
+
+// scanForeignKeyInfos scan rows of constraint name, column, referenced table and referenced column
+func scanForeignKeyInfos(rows *sql.Rows) ([]ForeignKeyInfo, error) {
+	defer rows.Close()
+
+	var foreignKeys []ForeignKeyInfo
+	for rows.Next() {
+		var foreignKey ForeignKeyInfo
+		if err := rows.Scan(&foreignKey.Name, &foreignKey.Column, &foreignKey.ReferencedTable, &foreignKey.ReferencedColumn); err != nil {
+			return foreignKeys, err
+		}
+		foreignKeys = append(foreignKeys, foreignKey)
+	}
+	return foreignKeys, rows.Err()
+}
+
+// scanIndexInfos scan rows of index name, column name, unique and primary, columns of an index should be in order
+func scanIndexInfos(rows *sql.Rows) ([]IndexInfo, error) {
+	defer rows.Close()
+
+	var indexes []IndexInfo
+	positions := map[string]int{}
+	for rows.Next() {
+		var name string
+		var column sql.NullString
+		var unique, primary bool
+		if err := rows.Scan(&name, &column, &unique, &primary); err != nil {
+			return indexes, err
+		}
+		position, ok := positions[name]
+		if !ok {
+			position = len(indexes)
+			positions[name] = position
+			indexes = append(indexes, IndexInfo{Name: name, Unique: unique, Primary: primary})
+		}
+		indexes[position].Columns = append(indexes[position].Columns, column.String)
+	}
+	return indexes, rows.Err()
+}
@@ -0,0 +1,98 @@
+package gorm
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchemaIntrospection(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	fakeResults = map[string][][]driver.Value{
+		"SELECT table_name": {{"emails"}, {"users"}},
+		"SELECT c.column_name": {
+			{"id", "bigint", nil, int64(19), int64(0), "NO", nil, nil, nil, "bigint(20) unsigned", int64(1), int64(1)},
+			{"email", "varchar", int64(100), nil, nil, "YES", "''", "utf8mb4", "utf8mb4_bin", "varchar(100)", int64(0), int64(0)},
+		},
+		"SELECT INDEX_NAME": {
+			{"PRIMARY", "id", int64(1), int64(1)},
+			{"idx_emails_user_email", "user_id", int64(0), int64(0)},
+			{"idx_emails_user_email", nil, int64(0), int64(0)},
+		},
+	}
+
+	if tables, err := db.Migrator().Tables(); err != nil || !reflect.DeepEqual(tables, []string{"emails", "users"}) {
+		t.Errorf("should get tables, but got %v, %v", tables, err)
+	}
+
+	columns, err := db.Migrator().Columns("emails")
+	expectedColumns := []ColumnInfo{
+		{Name: "id", Type: "bigint", PrimaryKey: true, ColumnType: "bigint(20) unsigned", AutoIncrement: true},
+		{Name: "email", Type: "varchar", Size: 100, Nullable: true, Default: sql.NullString{String: "''", Valid: true},
+			ColumnType: "varchar(100)", Charset: "utf8mb4", Collation: "utf8mb4_bin"},
+	}
+	if err != nil || !reflect.DeepEqual(columns, expectedColumns) {
+		t.Errorf("should get columns, but got %+v, %v", columns, err)
+	}
+
+	indexes, err := db.Migrator().Indexes("emails")
+	expectedIndexes := []IndexInfo{
+		{Name: "PRIMARY", Columns: []string{"id"}, Unique: true, Primary: true},
+		{Name: "idx_emails_user_email", Columns: []string{"user_id", ""}},
+	}
+	if err != nil || !reflect.DeepEqual(indexes, expectedIndexes) {
+		t.Errorf("should get indexes, expression parts are blank, but got %+v, %v", indexes, err)
+	}
+}
+
+func TestSqlite3ColumnInfos(t *testing.T) {
+	db := newFakeDB("sqlite3", "")
+
+	fakeResults = map[string][][]driver.Value{
+		"PRAGMA table_info": {
+			{int64(0), "id", "integer", int64(1), nil, int64(1)},
+			{int64(1), "name", "varchar(255)", int64(0), nil, int64(0)},
+		},
+	}
+
+	columns, err := db.Migrator().Columns("users")
+	expected := []ColumnInfo{
+		{Name: "id", Type: "integer", PrimaryKey: true, ColumnType: "integer"},
+		{Name: "name", Type: "varchar", Size: 255, Nullable: true, ColumnType: "varchar(255)"},
+	}
+	if err != nil || !reflect.DeepEqual(columns, expected) {
+		t.Errorf("should get columns with sizes parsed from types, but got %+v, %v", columns, err)
+	}
+}
+
+type introspectedUser struct {
+	Id    int64
+	Name  string `sql:"size:100"`
+	Email string `sql:"size:100;index:idx_introspected_users_email"`
+}
+
+func TestAutoMigrateWithSchemaIntrospection(t *testing.T) {
+	db := newFakeDB("sqlite3", "")
+
+	fakeResults = map[string][][]driver.Value{
+		"SELECT count(*)": {{int64(1)}},
+		"PRAGMA table_info": {
+			{int64(0), "id", "integer", int64(1), nil, int64(1)},
+			{int64(1), "name", "varchar(100)", int64(0), nil, int64(0)},
+			{int64(2), "email", "varchar(100)", int64(0), nil, int64(0)},
+		},
+		"PRAGMA index_list": {{int64(0), "idx_introspected_users_name", int64(0), "c", int64(0)}},
+		"PRAGMA index_info": {{int64(0), int64(1), "name"}},
+	}
+
+	fakeStatements = nil
+	if err := db.AutoMigrate(&introspectedUser{}).Error; err != nil {
+		t.Errorf("should migrate with columns and indexes of sqlite3, but got %v", err)
+	}
+	if statements := strings.Join(fakeStatements, "\n"); statements != "DROP INDEX idx_introspected_users_name" {
+		t.Errorf("only the undeclared index should be dropped, but got %v", statements)
+	}
+}
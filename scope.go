@@ -1,6 +1,7 @@
 package gorm
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -17,10 +18,14 @@ type Scope struct {
 	db              *DB
 	indirectValue   *reflect.Value
 	instanceId      string
+	context         map[string]interface{}
+	values          map[string]interface{}
+	replica         sqlCommon
 	primaryKeyField *Field
 	skipLeft        bool
 	fields          map[string]*Field
 	selectAttrs     *[]string
+	cancels         []context.CancelFunc
 }
 
 func (scope *Scope) IndirectValue() reflect.Value {
@@ -64,8 +69,17 @@ func (scope *Scope) DB() *DB {
 	return scope.db
 }
 
-// SqlDB return *sql.DB
+// SqlDB return *sql.DB, *sql.Tx or the replica to run sql, it runs with the context of WithContext,
+// or the default timeouts of TimeoutPolicy if any
 func (scope *Scope) SqlDB() sqlCommon {
+	return scope.withContext(scope.sqlDB())
+}
+
+// sqlDB return the replica or current *sql.DB, *sql.Tx without context
+func (scope *Scope) sqlDB() sqlCommon {
+	if scope.replica != nil {
+		return scope.replica
+	}
 	return scope.db.db
 }
 
@@ -76,14 +90,15 @@ func (scope *Scope) SkipLeft() {
 
 // Quote used to quote database column name according to database dialect
 func (scope *Scope) Quote(str string) string {
+	policy := scope.db.parent.quotePolicy
 	if strings.Index(str, ".") != -1 {
 		newStrs := []string{}
 		for _, str := range strings.Split(str, ".") {
-			newStrs = append(newStrs, scope.Dialect().Quote(str))
+			newStrs = append(newStrs, policy.quote(scope.Dialect(), str))
 		}
 		return strings.Join(newStrs, ".")
 	} else {
-		return scope.Dialect().Quote(str)
+		return policy.quote(scope.Dialect(), str)
 	}
 }
 
@@ -95,6 +110,7 @@ func (scope *Scope) Dialect() Dialect {
 // Err write error
 func (scope *Scope) Err(err error) error {
 	if err != nil {
+		err = translateError(err)
 		scope.db.err(err)
 	}
 	return err
@@ -146,6 +162,17 @@ func (scope *Scope) PrimaryKeyZero() bool {
 	return field == nil || field.IsBlank
 }
 
+// PrimaryKeysZero check if all primary keys are blank, composite keys are only blank when all of them are blank
+func (scope *Scope) PrimaryKeysZero() bool {
+	fields := scope.Fields()
+	for _, primaryField := range scope.GetModelStruct().PrimaryFields {
+		if field, ok := fields[primaryField.DBName]; ok && !field.IsBlank {
+			return false
+		}
+	}
+	return true
+}
+
 // PrimaryKeyValue get the primary key's value
 func (scope *Scope) PrimaryKeyValue() interface{} {
 	if field := scope.PrimaryField(); field != nil && field.Field.IsValid() {
@@ -191,33 +218,38 @@ func (scope *Scope) CallMethod(name string, checkError bool) {
 		return
 	}
 
-	call := func(value interface{}) {
-		if fm := reflect.ValueOf(value).MethodByName(name); fm.IsValid() {
-			switch f := fm.Interface().(type) {
-			case func():
-				f()
-			case func(s *Scope):
-				f(scope)
-			case func(s *DB):
-				f(scope.NewDB())
-			case func() error:
-				scope.Err(f())
-			case func(s *Scope) error:
-				scope.Err(f(scope))
-			case func(s *DB) error:
-				scope.Err(f(scope.NewDB()))
-			default:
-				scope.Err(fmt.Errorf("unsupported function %v", name))
-			}
-		}
-	}
-
 	if values := scope.IndirectValue(); values.Kind() == reflect.Slice {
 		for i := 0; i < values.Len(); i++ {
-			call(values.Index(i).Addr().Interface())
+			if value := values.Index(i); value.Kind() == reflect.Ptr {
+				scope.callMethodOf(value.Interface(), name)
+			} else {
+				scope.callMethodOf(value.Addr().Interface(), name)
+			}
 		}
 	} else {
-		call(scope.Value)
+		scope.callMethodOf(scope.Value, name)
+	}
+}
+
+// callMethodOf call value's method with current scope
+func (scope *Scope) callMethodOf(value interface{}, name string) {
+	if fm := reflect.ValueOf(value).MethodByName(name); fm.IsValid() {
+		switch f := fm.Interface().(type) {
+		case func():
+			f()
+		case func(s *Scope):
+			f(scope)
+		case func(s *DB):
+			f(scope.NewDB())
+		case func() error:
+			scope.Err(f())
+		case func(s *Scope) error:
+			scope.Err(f(scope))
+		case func(s *DB) error:
+			scope.Err(f(scope.NewDB()))
+		default:
+			scope.Err(fmt.Errorf("unsupported function %v", name))
+		}
 	}
 }
 
@@ -233,9 +265,13 @@ func (scope *Scope) AddToVars(value interface{}) string {
 			exp = strings.Replace(exp, "?", scope.AddToVars(arg), 1)
 		}
 		return exp
+	} else if point, ok := value.(Point); ok {
+		return scope.Dialect().GeometryVar(scope.AddToVars(point.String()))
+	} else if point, ok := value.(*Point); ok && point != nil {
+		return scope.Dialect().GeometryVar(scope.AddToVars(point.String()))
 	} else {
-		scope.SqlVars = append(scope.SqlVars, value)
-		return scope.Dialect().BinVar(len(scope.SqlVars))
+		scope.SqlVars = append(scope.SqlVars, unsignedVar(value))
+		return scope.bindVar(len(scope.SqlVars))
 	}
 }
 
@@ -301,8 +337,8 @@ func (scope *Scope) QuotedTableName() (name string) {
 
 // CombinedConditionSql get combined condition sql
 func (scope *Scope) CombinedConditionSql() string {
-	return scope.joinsSql() + scope.whereSql() + scope.groupSql() +
-		scope.havingSql() + scope.orderSql() + scope.limitSql() + scope.offsetSql()
+	return scope.clausesSql(ClauseBeforeJoins) + scope.joinsSql() + scope.whereSql() + scope.clausesSql(ClauseBeforeGroup) +
+		scope.groupSql() + scope.havingSql() + scope.orderSql() + scope.limitSql() + scope.offsetSql() + scope.clausesSql(ClauseEnd)
 }
 
 func (scope *Scope) FieldByName(name string) (field *Field, ok bool) {
@@ -316,7 +352,16 @@ func (scope *Scope) FieldByName(name string) (field *Field, ok bool) {
 
 // Raw set sql
 func (scope *Scope) Raw(sql string) *Scope {
-	scope.Sql = strings.Replace(sql, "$$", "?", -1)
+	if scope.Dialect().PlaceholderStyle() == QuestionPlaceholder {
+		sql = strings.Replace(sql, questionPlaceholderMark, "?", -1)
+	}
+	// a bare procedure name is called with RPC, it can't carry comments
+	if label := scope.Label(); label != "" && strings.Contains(sql, " ") {
+		if comment := labelComment(label); !strings.Contains(sql, comment) {
+			sql = comment + " " + sql
+		}
+	}
+	scope.Sql = sql
 	return scope
 }
 
@@ -335,14 +380,21 @@ func (scope *Scope) Exec() *Scope {
 	return scope
 }
 
-// Set set value by name
+// Set set value by name for current operation, it overrides the DB's setting in Get,
+// but won't leak into the DB returned by the operation
 func (scope *Scope) Set(name string, value interface{}) *Scope {
-	scope.db.InstantSet(name, value)
+	if scope.values == nil {
+		scope.values = map[string]interface{}{}
+	}
+	scope.values[name] = value
 	return scope
 }
 
-// Get get value by name
+// Get get value by name, values set to the scope come first, then the DB's settings
 func (scope *Scope) Get(name string) (interface{}, bool) {
+	if value, ok := scope.values[name]; ok {
+		return value, true
+	}
 	return scope.db.Get(name)
 }
 
@@ -354,25 +406,89 @@ func (scope *Scope) InstanceId() string {
 	return scope.instanceId
 }
 
+// InstanceSet set value into current operation's context, the context lives on the scope,
+// so all callbacks of one operation (e.g. BeforeDelete and AfterDelete) could share data
+// with it, while other operations won't see it
+//
+//	func (user *User) BeforeDelete(scope *gorm.Scope) {
+//		scope.InstanceSet("audit:reason", "expired")
+//	}
+//
+//	func (user *User) AfterDelete(scope *gorm.Scope) {
+//		reason, _ := scope.InstanceGetString("audit:reason")
+//	}
+//
+// keys starting with `gorm:` are reserved for gorm's own callbacks
 func (scope *Scope) InstanceSet(name string, value interface{}) *Scope {
-	return scope.Set(name+scope.InstanceId(), value)
+	if scope.context == nil {
+		scope.context = map[string]interface{}{}
+	}
+	scope.context[name] = value
+	return scope
 }
 
+// InstanceGet get value from current operation's context
 func (scope *Scope) InstanceGet(name string) (interface{}, bool) {
-	return scope.Get(name + scope.InstanceId())
+	value, ok := scope.context[name]
+	return value, ok
+}
+
+// InstanceDelete remove value from current operation's context
+func (scope *Scope) InstanceDelete(name string) *Scope {
+	delete(scope.context, name)
+	return scope
+}
+
+// InstanceGetString get string value from current operation's context, ok is false if it is not set or not a string
+func (scope *Scope) InstanceGetString(name string) (value string, ok bool) {
+	if v, found := scope.InstanceGet(name); found {
+		value, ok = v.(string)
+	}
+	return
+}
+
+// InstanceGetBool get bool value from current operation's context, ok is false if it is not set or not a bool
+func (scope *Scope) InstanceGetBool(name string) (value bool, ok bool) {
+	if v, found := scope.InstanceGet(name); found {
+		value, ok = v.(bool)
+	}
+	return
+}
+
+// InstanceGetInt get integer value from current operation's context, any int kinds are accepted
+func (scope *Scope) InstanceGetInt(name string) (value int64, ok bool) {
+	if v, found := scope.InstanceGet(name); found {
+		switch reflectValue := reflect.ValueOf(v); reflectValue.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return reflectValue.Int(), true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return int64(reflectValue.Uint()), true
+		}
+	}
+	return
+}
+
+// Label get the label set with DB.Label, it is empty if the operation isn't labeled
+func (scope *Scope) Label() string {
+	if value, ok := scope.Get("gorm:label"); ok {
+		label, _ := value.(string)
+		return label
+	}
+	return ""
 }
 
 // Trace print sql log
 func (scope *Scope) Trace(t time.Time) {
 	if len(scope.Sql) > 0 {
 		scope.db.slog(scope.Sql, t, scope.SqlVars...)
+		scope.traceSlowQuery(NowFunc().Sub(t))
 	}
 }
 
 // Begin start a transaction
 func (scope *Scope) Begin() *Scope {
-	if db, ok := scope.SqlDB().(sqlDb); ok {
-		if tx, err := db.Begin(); err == nil {
+	if db, ok := scope.sqlDB().(sqlDb); ok {
+		if tx, err := scope.db.beginTx(db); err == nil {
 			scope.db.db = interface{}(tx).(sqlCommon)
 			scope.InstanceSet("gorm:started_transaction", true)
 		}
@@ -382,7 +498,7 @@ func (scope *Scope) Begin() *Scope {
 
 // CommitOrRollback commit current transaction if there is no error, otherwise rollback it
 func (scope *Scope) CommitOrRollback() *Scope {
-	if _, ok := scope.InstanceGet("gorm:started_transaction"); ok {
+	if started, _ := scope.InstanceGetBool("gorm:started_transaction"); started {
 		if db, ok := scope.db.db.(sqlTx); ok {
 			if scope.HasError() {
 				db.Rollback()
@@ -418,13 +534,22 @@ func (scope *Scope) OmitAttrs() []string {
 	return scope.Search.omits
 }
 
+// isAttrColumn check the selected or omitted attr is the column, attrs could be field names or column names
+func isAttrColumn(attr, column string) bool {
+	return column == ToDBName(attr) || column == ColumnNameHandler(attr)
+}
+
 func (scope *Scope) changeableDBColumn(column string) bool {
+	if field, ok := scope.Fields()[column]; ok && field.IsReadOnly {
+		return false
+	}
+
 	selectAttrs := scope.SelectAttrs()
 	omitAttrs := scope.OmitAttrs()
 
 	if len(selectAttrs) > 0 {
 		for _, attr := range selectAttrs {
-			if column == ToDBName(attr) {
+			if isAttrColumn(attr, column) {
 				return true
 			}
 		}
@@ -432,7 +557,7 @@ func (scope *Scope) changeableDBColumn(column string) bool {
 	}
 
 	for _, attr := range omitAttrs {
-		if column == ToDBName(attr) {
+		if isAttrColumn(attr, column) {
 			return false
 		}
 	}
@@ -440,6 +565,10 @@ func (scope *Scope) changeableDBColumn(column string) bool {
 }
 
 func (scope *Scope) changeableField(field *Field) bool {
+	if field.IsReadOnly {
+		return false
+	}
+
 	selectAttrs := scope.SelectAttrs()
 	omitAttrs := scope.OmitAttrs()
 
@@ -461,6 +590,21 @@ func (scope *Scope) changeableField(field *Field) bool {
 	return !field.IsIgnored
 }
 
+// updateColumn check if columns are updated as they are, without callbacks
+func (scope *Scope) updateColumn() bool {
+	updateColumn, ok := scope.Get("gorm:update_column")
+	return ok && updateColumn.(bool)
+}
+
+// updateTimestamps check if `updated_at` should be set by the update, it is skipped when updating columns without timestamps
+func (scope *Scope) updateTimestamps() bool {
+	if !scope.updateColumn() {
+		return true
+	}
+	timestamps, ok := scope.Get("gorm:update_column_timestamps")
+	return ok && timestamps.(bool)
+}
+
 func (scope *Scope) shouldSaveAssociations() bool {
 	saveAssociations, ok := scope.Get("gorm:save_associations")
 	if ok && !saveAssociations.(bool) {
@@ -3,18 +3,46 @@ package gorm
 import (
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
+	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// bindVar return the placeholder of the i-th var with dialect's style, `?` is marked until Raw,
+// because conditions' question marks are replaced one by one
+func (scope *Scope) bindVar(i int) string {
+	if style := scope.Dialect().PlaceholderStyle(); style != QuestionPlaceholder {
+		return style.BindVar(i)
+	}
+	return questionPlaceholderMark
+}
+
 func (scope *Scope) primaryCondition(value interface{}) string {
-	return fmt.Sprintf("(%v = %v)", scope.Quote(scope.PrimaryKey()), value)
+	return fmt.Sprintf("(%v = %v)", scope.quotedPrimaryKey(), value)
+}
+
+// quotedPrimaryKey qualify primary key with table when associations are joined, to avoid ambiguous columns
+func (scope *Scope) quotedPrimaryKey() string {
+	if len(scope.Search.joinAssociations) > 0 {
+		return scope.quotedTableAlias() + "." + scope.Quote(scope.PrimaryKey())
+	}
+	return scope.Quote(scope.PrimaryKey())
+}
+
+// condition builds its own sql, like ILike and full-text search
+type condition interface {
+	toSql(scope *Scope) string
 }
 
 func (scope *Scope) buildWhereCondition(clause map[string]interface{}) (str string) {
+	// clause is shared by clones of the search, which could be used concurrently, so it is read only here
+	args, _ := clause["args"].([]interface{})
 	switch value := clause["query"].(type) {
 	case string:
 		// if string is number
@@ -27,11 +55,11 @@ func (scope *Scope) buildWhereCondition(clause map[string]interface{}) (str stri
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, sql.NullInt64:
 		return scope.primaryCondition(scope.AddToVars(value))
 	case []int, []int8, []int16, []int32, []int64, []uint, []uint8, []uint16, []uint32, []uint64, []string, []interface{}:
-		str = fmt.Sprintf("(%v in (?))", scope.Quote(scope.PrimaryKey()))
-		clause["args"] = []interface{}{value}
+		str = fmt.Sprintf("(%v in (?))", scope.quotedPrimaryKey())
+		args = []interface{}{value}
 	case map[string]interface{}:
 		var sqls []string
-		var args []interface{}
+		var mapArgs []interface{}
 		for key, vi := range value {
 			kind := reflect.TypeOf(vi).Kind()
 			v := reflect.ValueOf(vi).Interface()
@@ -41,7 +69,7 @@ func (scope *Scope) buildWhereCondition(clause map[string]interface{}) (str stri
 					sqls = append(sqls, fmt.Sprintf("(1 != 1)"))
 				} else {
 					sqls = append(sqls, fmt.Sprintf("(%v in (?))", scope.Quote(key)))
-					args = append(args, v)
+					mapArgs = append(mapArgs, v)
 					//var tmp_str = fmt.Sprintf("(%v in (?))", scope.Quote(key))
 					//scope.AddToVars(v)
 					//for i := 0; i < reflect.ValueOf(vi).Len(); i++ {
@@ -71,24 +99,26 @@ func (scope *Scope) buildWhereCondition(clause map[string]interface{}) (str stri
 					//sqls = append(sqls, fmt.Sprintf("(%v = %v)", scope.Quote(key), scope.AddToVars(v)))
 					sqls = append(sqls, fmt.Sprintf("(%v = (?))", scope.Quote(key)))
 				}
-				args = append(args, v)
+				mapArgs = append(mapArgs, v)
 			}
 		}
-		if len(args) > 0 {
-			clause["args"] = args
+		if len(mapArgs) > 0 {
+			args = mapArgs
 		}
 		str = strings.Join(sqls, " AND ")
+	case condition:
+		if sql := value.toSql(scope); sql != "" {
+			return fmt.Sprintf("(%v)", sql)
+		}
+		return
 	case interface{}:
 		var sqls []string
-		for _, field := range scope.New(value).Fields() {
-			if !field.IsBlank {
-				sqls = append(sqls, fmt.Sprintf("(%v = %v)", scope.Quote(field.DBName), scope.AddToVars(field.Field.Interface())))
-			}
+		for _, field := range scope.conditionFields(value, args) {
+			sqls = append(sqls, fmt.Sprintf("(%v = %v)", scope.Quote(field.DBName), scope.AddToVars(sensitiveVar(field.StructField, field.Field.Interface()))))
 		}
 		return strings.Join(sqls, " AND ")
 	}
 
-	args := clause["args"].([]interface{})
 	for _, arg := range args {
 		kind := reflect.TypeOf(arg).Kind()
 		switch kind {
@@ -115,7 +145,35 @@ func (scope *Scope) buildWhereCondition(clause map[string]interface{}) (str stri
 	return
 }
 
+// conditionFields get fields of a struct condition, blank fields are skipped unless they are selected by names,
+// with StrictConditions, conditions without any field return BlankCondition error
+func (scope *Scope) conditionFields(value interface{}, names []interface{}) (fields []*Field) {
+	conditionScope := scope.New(value)
+	if len(names) == 0 {
+		for _, field := range conditionScope.Fields() {
+			if !field.IsBlank {
+				fields = append(fields, field)
+			}
+		}
+	} else {
+		for _, name := range names {
+			field, ok := conditionScope.FieldByName(fmt.Sprint(name))
+			if !ok {
+				scope.Err(fmt.Errorf("can't find field %v for struct condition", name))
+				continue
+			}
+			fields = append(fields, field)
+		}
+	}
+
+	if strict, ok := scope.Get("gorm:strict_conditions"); ok && strict.(bool) && len(fields) == 0 {
+		scope.Err(BlankCondition)
+	}
+	return
+}
+
 func (scope *Scope) buildNotCondition(clause map[string]interface{}) (str string) {
+	args, _ := clause["args"].([]interface{})
 	var notEqualSql string
 	var primaryKey = scope.PrimaryKey()
 
@@ -137,7 +195,7 @@ func (scope *Scope) buildNotCondition(clause map[string]interface{}) (str string
 	case []int, []int8, []int16, []int32, []int64, []uint, []uint8, []uint16, []uint32, []uint64, []string:
 		if reflect.ValueOf(value).Len() > 0 {
 			str = fmt.Sprintf("(%v NOT IN (?))", scope.Quote(primaryKey))
-			clause["args"] = []interface{}{value}
+			args = []interface{}{value}
 		}
 		return ""
 	case map[string]interface{}:
@@ -146,17 +204,19 @@ func (scope *Scope) buildNotCondition(clause map[string]interface{}) (str string
 			sqls = append(sqls, fmt.Sprintf("(%v <> %v)", scope.Quote(key), scope.AddToVars(value)))
 		}
 		return strings.Join(sqls, " AND ")
+	case condition:
+		if sql := value.toSql(scope); sql != "" {
+			return fmt.Sprintf("NOT (%v)", sql)
+		}
+		return
 	case interface{}:
 		var sqls []string
-		for _, field := range scope.New(value).Fields() {
-			if !field.IsBlank {
-				sqls = append(sqls, fmt.Sprintf("(%v <> %v)", scope.Quote(field.DBName), scope.AddToVars(field.Field.Interface())))
-			}
+		for _, field := range scope.conditionFields(value, args) {
+			sqls = append(sqls, fmt.Sprintf("(%v <> %v)", scope.Quote(field.DBName), scope.AddToVars(sensitiveVar(field.StructField, field.Field.Interface()))))
 		}
 		return strings.Join(sqls, " AND ")
 	}
 
-	args := clause["args"].([]interface{})
 	for _, arg := range args {
 		switch reflect.ValueOf(arg).Kind() {
 		case reflect.Slice: // For where("id in (?)", []int64{1,2})
@@ -182,6 +242,18 @@ func (scope *Scope) buildSelectQuery(clause map[string]interface{}) (str string)
 		str = value
 	case []string:
 		str = strings.Join(value, ", ")
+	case *Window:
+		str = value.toSql(scope)
+	case []interface{}:
+		var columns []string
+		for _, column := range value {
+			if window, ok := column.(*Window); ok {
+				columns = append(columns, window.toSql(scope))
+			} else {
+				columns = append(columns, fmt.Sprint(column))
+			}
+		}
+		str = strings.Join(columns, ", ")
 	}
 
 	args := clause["args"].([]interface{})
@@ -204,15 +276,34 @@ func (scope *Scope) buildSelectQuery(clause map[string]interface{}) (str string)
 	return
 }
 
+// softDeleteField get the field marking records as deleted, column deleted_at or field DeletedAt renamed by ColumnNameHandler
+func (scope *Scope) softDeleteField() (*Field, bool) {
+	if field, ok := scope.Fields()["deleted_at"]; ok {
+		return field, true
+	}
+	if field, ok := scope.FieldByName("DeletedAt"); ok && field.IsNormal {
+		return field, true
+	}
+	return nil, false
+}
+
 func (scope *Scope) whereSql() (sql string) {
 	var primaryConditions, andConditions, orConditions []string
 
-	if !scope.Search.Unscoped && scope.Fields()["deleted_at"] != nil {
-		sql := fmt.Sprintf("(%v.deleted_at IS NULL OR %v.deleted_at <= '0001-01-02')", scope.QuotedTableName(), scope.QuotedTableName())
-		primaryConditions = append(primaryConditions, sql)
+	if field, ok := scope.softDeleteField(); ok && !scope.Search.Unscoped {
+		column := scope.quotedTableAlias() + "." + scope.Quote(field.DBName)
+		primaryConditions = append(primaryConditions, fmt.Sprintf("(%v IS NULL OR %v <= '0001-01-02')", column, column))
 	}
 
-	if !scope.PrimaryKeyZero() {
+	if _, ok := scope.InstanceGet("gorm:update_by_primary_keys"); ok {
+		// saved records are updated by all primary keys, so rows sharing a part of composite keys aren't updated
+		fields := scope.Fields()
+		for _, primaryField := range scope.GetModelStruct().PrimaryFields {
+			field := fields[primaryField.DBName]
+			primaryConditions = append(primaryConditions,
+				fmt.Sprintf("(%v = %v)", scope.Quote(field.DBName), scope.AddToVars(field.Field.Interface())))
+		}
+	} else if !scope.PrimaryKeyZero() {
 		primaryConditions = append(primaryConditions, scope.primaryCondition(scope.AddToVars(scope.PrimaryKeyValue())))
 	}
 
@@ -255,13 +346,53 @@ func (scope *Scope) whereSql() (sql string) {
 	return
 }
 
+// quotedTableAlias get the name used to reference the table in conditions, e.g: `u` for `users u`
+func (scope *Scope) quotedTableAlias() string {
+	quotedTableName := scope.QuotedTableName()
+	if index := strings.LastIndex(strings.TrimSpace(quotedTableName), " "); index != -1 {
+		return strings.TrimSpace(quotedTableName)[index+1:]
+	}
+	return quotedTableName
+}
+
 func (scope *Scope) selectSql() string {
+	if scope.Search.distinct {
+		if len(scope.Search.distinctColumns) > 0 {
+			return "DISTINCT " + strings.Join(scope.quoteColumns(scope.Search.distinctColumns), ", ")
+		}
+		if len(scope.Search.selects) > 0 {
+			return "DISTINCT " + scope.buildSelectQuery(scope.Search.selects)
+		}
+		return "DISTINCT *"
+	}
+
 	if len(scope.Search.selects) == 0 {
+		if len(scope.Search.joinAssociations) > 0 {
+			return scope.quotedTableAlias() + ".*, " + strings.Join(scope.joinAssociationColumns(), ", ")
+		}
 		return "*"
 	}
 	return scope.buildSelectQuery(scope.Search.selects)
 }
 
+var plainColumnRegexp = regexp.MustCompile(`^[\w.]+$`)
+
+// quoteColumns quote plain column names, expressions are kept as they are
+func (scope *Scope) quoteColumns(columns []string) []string {
+	var quoted []string
+	for _, column := range columns {
+		if plainColumnRegexp.MatchString(column) {
+			parts := strings.Split(column, ".")
+			for i, part := range parts {
+				parts[i] = scope.Quote(part)
+			}
+			column = strings.Join(parts, ".")
+		}
+		quoted = append(quoted, column)
+	}
+	return quoted
+}
+
 func (scope *Scope) orderSql() string {
 	if len(scope.Search.orders) == 0 {
 		return ""
@@ -306,29 +437,111 @@ func (scope *Scope) offsetSql() string {
 	return " OFFSET " + scope.Search.offset
 }
 
+func (scope *Scope) buildGroupCondition(clause map[string]interface{}) (str string) {
+	switch value := clause["query"].(type) {
+	case string:
+		str = value
+	case []string:
+		return strings.Join(scope.quoteColumns(value), ", ")
+	default:
+		scope.Err(InvalidSql)
+		return ""
+	}
+
+	for _, arg := range clause["args"].([]interface{}) {
+		if valuer, ok := arg.(driver.Valuer); ok {
+			arg, _ = valuer.Value()
+		}
+		str = strings.Replace(str, "?", scope.AddToVars(arg), 1)
+	}
+	return
+}
+
 func (scope *Scope) groupSql() string {
-	if len(scope.Search.group) == 0 {
+	var groups []string
+	for _, clause := range scope.Search.groupConditions {
+		if sql := scope.buildGroupCondition(clause); sql != "" {
+			groups = append(groups, sql)
+		}
+	}
+
+	if len(groups) == 0 {
 		return ""
 	}
-	return " GROUP BY " + scope.Search.group
+	return " GROUP BY " + strings.Join(groups, ", ")
 }
 
 func (scope *Scope) havingSql() string {
-	if scope.Search.havingCondition == nil {
+	var conditions []string
+	for _, clause := range scope.Search.havingConditions {
+		if sql := scope.buildWhereCondition(clause); sql != "" {
+			conditions = append(conditions, sql)
+		}
+	}
+
+	if len(conditions) == 0 {
 		return ""
 	}
-	return " HAVING " + scope.buildWhereCondition(scope.Search.havingCondition)
+	return " HAVING " + strings.Join(conditions, " AND ")
 }
 
 func (scope *Scope) joinsSql() string {
-	return scope.Search.joins + " "
+	joins := []string{scope.Search.joins}
+	for _, name := range scope.Search.joinAssociations {
+		joins = append(joins, scope.joinAssociationSql(name))
+	}
+	return strings.Join(joins, " ") + " "
+}
+
+// quoteTable quote table name unless it has an alias or is an expression
+func (scope *Scope) quoteTable(name string) string {
+	if strings.Contains(name, " ") {
+		return name
+	}
+	return scope.Quote(name)
+}
+
+// fromSql get the table to select from, which might be a sub query set by From
+func (scope *Scope) fromSql() string {
+	query := scope.Search.fromQuery
+	if query == nil {
+		return scope.QuotedTableName()
+	}
+
+	subScope := query.NewScope(query.Value)
+	// share vars to keep the bind vars' order and numbers
+	subScope.SqlVars = scope.SqlVars
+	subScope.prepareQuerySql()
+	scope.SqlVars = subScope.SqlVars
+	scope.Err(subScope.db.Error)
+	return fmt.Sprintf("(%v) %v", subScope.Sql, scope.QuotedTableName())
+}
+
+// compoundMemberSql build the query without order and limit, so it could be combined with other queries
+func (scope *Scope) compoundMemberSql() string {
+	sql := fmt.Sprintf("SELECT %v FROM %v %v", scope.selectSql(), scope.fromSql(),
+		scope.clausesSql(ClauseBeforeJoins)+scope.joinsSql()+scope.whereSql()+scope.clausesSql(ClauseBeforeGroup)+scope.groupSql()+scope.havingSql())
+
+	for _, compound := range scope.Search.compounds {
+		query := compound["query"].(*DB)
+		memberScope := query.NewScope(query.Value)
+		// share vars to keep the bind vars' order and numbers
+		memberScope.SqlVars = scope.SqlVars
+		sql = fmt.Sprintf("%v %v %v", sql, compound["operator"], memberScope.compoundMemberSql())
+		scope.SqlVars = memberScope.SqlVars
+		scope.Err(memberScope.db.Error)
+	}
+	return sql
 }
 
 func (scope *Scope) prepareQuerySql() {
 	if scope.Search.raw {
 		scope.Raw(strings.TrimSuffix(strings.TrimPrefix(scope.CombinedConditionSql(), " WHERE ("), ")"))
+	} else if len(scope.Search.compounds) > 0 {
+		scope.Raw(fmt.Sprintf("SELECT %v * FROM (%v) %v%v%v%v%v", scope.topSql(), scope.compoundMemberSql(), scope.quotedTableAlias(),
+			scope.orderSql(), scope.limitSql(), scope.offsetSql(), scope.clausesSql(ClauseEnd)))
 	} else {
-		scope.Raw(fmt.Sprintf("SELECT %v %v FROM %v %v", scope.topSql(), scope.selectSql(), scope.QuotedTableName(), scope.CombinedConditionSql()))
+		scope.Raw(fmt.Sprintf("SELECT %v %v FROM %v %v", scope.topSql(), scope.selectSql(), scope.fromSql(), scope.CombinedConditionSql()))
 	}
 	return
 }
@@ -340,10 +553,30 @@ func (scope *Scope) inlineCondition(values ...interface{}) *Scope {
 	return scope
 }
 
-func (scope *Scope) callCallbacks(funcs []*func(s *Scope)) *Scope {
+func (scope *Scope) callCallbacks(funcs []*func(s *Scope)) (result *Scope) {
+	defer scope.releaseContexts()
+	defer func() {
+		if r := recover(); r != nil {
+			scope.Err(&CallbackPanic{Value: r, Stack: debug.Stack()})
+			// left callbacks are skipped, rollback the transaction started by gorm:begin_transaction
+			scope.CommitOrRollback()
+			result = scope
+		}
+	}()
+
+	// callbacks are timed in debug mode, to find out slow hooks
+	timed := scope.db.logMode == 2
 	for _, f := range funcs {
-		(*f)(scope)
+		if timed {
+			start := time.Now()
+			(*f)(scope)
+			scope.db.callbackTimings = append(scope.db.callbackTimings, CallbackTiming{Name: scope.db.parent.callback.nameOf(f), Duration: time.Since(start)})
+		} else {
+			(*f)(scope)
+		}
 		if scope.skipLeft {
+			// gorm:commit_or_rollback_transaction is skipped too, finish the transaction started by gorm:begin_transaction
+			scope.CommitOrRollback()
 			break
 		}
 	}
@@ -414,6 +647,7 @@ func (scope *Scope) pluck(column string, value interface{}) *Scope {
 		return scope
 	}
 
+	defer scope.releaseContexts()
 	rows, err := scope.rows()
 	if scope.Err(err) == nil {
 		defer rows.Close()
@@ -427,11 +661,203 @@ func (scope *Scope) pluck(column string, value interface{}) *Scope {
 }
 
 func (scope *Scope) count(value interface{}) *Scope {
+	defer scope.releaseContexts()
+	if len(scope.Search.compounds) > 0 {
+		return scope.countSubQuery(value, "compound_rows")
+	}
+
+	if scope.Search.distinct {
+		if len(scope.Search.distinctColumns) == 1 {
+			scope.Search.distinct = false
+			scope.Search.Select(fmt.Sprintf("count(DISTINCT %v)", scope.quoteColumns(scope.Search.distinctColumns)[0]))
+			scope.Err(scope.row().Scan(value))
+			return scope
+		}
+
+		// count distinct rows with multiple columns is not portable, use sub query instead
+		return scope.countSubQuery(value, "distinct_rows")
+	}
+
 	scope.Search.Select("count(*)")
 	scope.Err(scope.row().Scan(value))
 	return scope
 }
 
+// sortKeys get columns deciding which record is the first or last, they are fields tagged `sort_key`,
+// or primary keys if there are no such fields
+func (scope *Scope) sortKeys() (columns []string) {
+	for _, field := range scope.GetStructFields() {
+		if _, ok := ParseTagSetting(field.Tag)["SORT_KEY"]; ok && field.IsNormal {
+			columns = append(columns, field.DBName)
+		}
+	}
+
+	if len(columns) == 0 {
+		if primaryFields := scope.GetModelStruct().PrimaryFields; len(primaryFields) > 1 {
+			for _, field := range primaryFields {
+				columns = append(columns, field.DBName)
+			}
+		} else if primaryKey := scope.PrimaryKey(); primaryKey != "" {
+			columns = append(columns, primaryKey)
+		}
+	}
+	return
+}
+
+// iterateBy use keyset pagination on (column, primary key), so it works with non-unique columns and
+// doesn't slow down like offset for later chunks. NULLs can't be compared and are sorted differently by dialects,
+// so rows with NULL in nullable columns are iterated first by primary key
+func (scope *Scope) iterateBy(column string, chunk int, fn func() error) *Scope {
+	slice := reflect.Indirect(reflect.ValueOf(scope.Value))
+	if slice.Kind() != reflect.Slice || !slice.CanSet() {
+		scope.Err(errors.New("IterateBy requires a pointer of slice as model"))
+		return scope
+	}
+
+	primaryKey := scope.PrimaryKey()
+	if primaryKey == "" || chunk <= 0 {
+		scope.Err(errors.New("IterateBy requires primary key and positive chunk size"))
+		return scope
+	}
+
+	var field *StructField
+	for _, structField := range scope.GetModelStruct().StructFields {
+		if structField.Name == column || structField.DBName == column {
+			field = structField
+		}
+	}
+	if field == nil {
+		scope.Err(fmt.Errorf("can't find field %v for IterateBy", column))
+		return scope
+	}
+	_, isScanner := reflect.New(field.Struct.Type).Interface().(sql.Scanner)
+	nulls := field.Struct.Type.Kind() == reflect.Ptr || isScanner
+
+	quotedColumn := scope.quoteColumns([]string{column})[0]
+	quotedKey := scope.Quote(primaryKey)
+	nullQuery := scope.db.Where(fmt.Sprintf("%v IS NULL", quotedColumn)).Order(quotedKey, true).Limit(chunk)
+	query := scope.db.Order(quotedColumn, true).Order(quotedKey).Limit(chunk)
+	if nulls {
+		query = query.Where(fmt.Sprintf("%v IS NOT NULL", quotedColumn))
+	}
+	keysetCondition := fmt.Sprintf("%v > ? OR (%v = ? AND %v > ?)", quotedColumn, quotedColumn, quotedKey)
+
+	var lastValue, lastKey interface{}
+	for {
+		slice.Set(reflect.MakeSlice(slice.Type(), 0, chunk))
+		chunkQuery := query
+		if nulls {
+			chunkQuery = nullQuery
+			if lastKey != nil {
+				chunkQuery = nullQuery.Where(fmt.Sprintf("%v > ?", quotedKey), lastKey)
+			}
+		} else if lastKey != nil {
+			chunkQuery = query.Where(keysetCondition, lastValue, lastValue, lastKey)
+		}
+		if scope.Err(chunkQuery.Find(scope.Value).Error) != nil {
+			return scope
+		}
+
+		length := slice.Len()
+		if length > 0 && scope.Err(fn()) != nil {
+			return scope
+		}
+		if length < chunk {
+			if !nulls {
+				return scope
+			}
+			// rows with NULL are done, continue with rows having values
+			nulls, lastValue, lastKey = false, nil, nil
+			continue
+		}
+
+		lastScope := scope.New(slice.Index(length - 1).Addr().Interface())
+		if slice.Type().Elem().Kind() == reflect.Ptr {
+			lastScope = scope.New(slice.Index(length - 1).Interface())
+		}
+		valueField, _ := lastScope.FieldByName(field.Name)
+		lastValue, lastKey = valueField.Field.Interface(), lastScope.PrimaryKeyValue()
+	}
+}
+
+func (scope *Scope) estimatedCount() (count int64) {
+	search := scope.Search
+	hasConditions := len(search.whereConditions) > 0 || len(search.orConditions) > 0 || len(search.notConditions) > 0 ||
+		len(search.groupConditions) > 0 || search.joins != "" || search.distinct || len(search.compounds) > 0 || search.fromQuery != nil
+	if !hasConditions && scope.PrimaryKeyZero() {
+		if estimated, ok := scope.Dialect().EstimatedCount(scope, scope.TableName()); ok && estimated >= EstimatedCountThreshold {
+			return estimated
+		}
+	}
+
+	scope.count(&count)
+	return count
+}
+
+func (scope *Scope) exists() bool {
+	defer scope.releaseContexts()
+	var one int
+	scope.Search.Select("1")
+	scope.Search.Limit(1)
+	if err := scope.row().Scan(&one); err == sql.ErrNoRows {
+		return false
+	} else if scope.Err(err) != nil {
+		return false
+	}
+	return true
+}
+
+// createFromSelect insert rows returned by query into current model's table,
+// columns are mapped by the destination model's fields
+func (scope *Scope) createFromSelect(query *DB) *Scope {
+	defer scope.Trace(NowFunc())
+
+	selectScope := query.NewScope(query.Value)
+	sourceColumns := map[string]bool{}
+	for _, field := range selectScope.GetStructFields() {
+		if field.IsNormal && !field.IsIgnored {
+			sourceColumns[field.DBName] = true
+		}
+	}
+
+	var columns []string
+	for _, field := range scope.GetStructFields() {
+		if field.IsNormal && !field.IsIgnored && !field.IsReadOnly {
+			// without Select, only copy columns exist in both models
+			if len(selectScope.Search.selects) == 0 && len(sourceColumns) > 0 && !sourceColumns[field.DBName] {
+				continue
+			}
+			columns = append(columns, scope.Quote(field.DBName))
+		}
+	}
+
+	if len(columns) == 0 {
+		scope.Err(errors.New("no columns to insert from select"))
+		return scope
+	}
+
+	if len(selectScope.Search.selects) == 0 {
+		selectScope.Search.Select(strings.Join(columns, ","))
+	}
+	selectScope.prepareQuerySql()
+	scope.Err(selectScope.db.Error)
+
+	scope.SqlVars = selectScope.SqlVars
+	scope.Raw(fmt.Sprintf("INSERT INTO %v (%v) %v", scope.QuotedTableName(), strings.Join(columns, ","), selectScope.Sql))
+	return scope.Exec()
+}
+
+// countSubQuery count rows returned by current query
+func (scope *Scope) countSubQuery(value interface{}, alias string) *Scope {
+	defer scope.Trace(NowFunc())
+	defer scope.releaseContexts()
+	scope.callCallbacks(scope.db.parent.callback.rowQueries)
+	scope.prepareQuerySql()
+	scope.Raw(fmt.Sprintf("SELECT count(*) FROM (%v) %v", scope.Sql, scope.Quote(alias)))
+	scope.Err(scope.SqlDB().QueryRow(scope.Sql, scope.SqlVars...).Scan(value))
+	return scope
+}
+
 func (scope *Scope) typeName() string {
 	value := scope.IndirectValue()
 	if value.Kind() == reflect.Slice {
@@ -496,21 +922,166 @@ func (scope *Scope) createJoinTable(field *StructField) {
 		joinTableHandler := relationship.JoinTableHandler
 		joinTable := joinTableHandler.Table(scope.db)
 		if !scope.Dialect().HasTable(scope, joinTable) {
-			toScope := &Scope{Value: reflect.New(field.Struct.Type).Interface()}
-
-			var sqlTypes []string
-			for _, s := range []*Scope{scope, toScope} {
-				for _, primaryField := range s.GetModelStruct().PrimaryFields {
-					value := reflect.Indirect(reflect.New(primaryField.Struct.Type))
-					primaryKeySqlType := scope.Dialect().SqlTag(value, 255, false)
-					dbName := ToDBName(s.GetModelStruct().ModelType.Name() + primaryField.Name)
-					sqlTypes = append(sqlTypes, scope.Quote(dbName)+" "+primaryKeySqlType)
-				}
+			scope.Err(scope.NewDB().Exec(scope.joinTableSql(field)).Error)
+		} else if isJoinTableSoftDelete(field) && !scope.Dialect().HasColumn(scope, joinTable, joinTableDeletedAtColumn) {
+			if scope.checkJoinTableKeys(joinTable) != nil {
+				return
 			}
-
-			scope.Err(scope.NewDB().Exec(fmt.Sprintf("CREATE TABLE %v (%v)", scope.Quote(joinTable), strings.Join(sqlTypes, ","))).Error)
+			scope.Err(scope.NewDB().Exec(fmt.Sprintf("ALTER TABLE %v ADD %v", scope.Quote(joinTable), scope.joinTableDeletedAtSql())).Error)
 		}
 		scope.NewDB().Table(joinTable).AutoMigrate(joinTableHandler)
+		if scope.Dialect().SupportAddForeignKey() {
+			scope.addJoinTableForeignKeys(field)
+		}
+	}
+}
+
+// checkJoinTableKeys check the existing join table has no primary key or unique index without deleted_at before
+// soft deleting join rows, as appending rows deleted before would conflict with the deleted ones
+func (scope *Scope) checkJoinTableKeys(joinTable string) error {
+	indexes, err := scope.Dialect().IndexInfos(scope, joinTable)
+	if err != nil {
+		return scope.Err(err)
+	}
+	for _, index := range indexes {
+		if (index.Primary || index.Unique) && !inStringSlice(joinTableDeletedAtColumn, index.Columns) {
+			return scope.Err(fmt.Errorf("key %v of join table %v conflicts with soft deleted rows, drop it before tagging jointable_soft_delete", index.Name, joinTable))
+		}
+	}
+	return nil
+}
+
+// joinTableSql get sql to create the join table of many to many field, it is blank for other fields, the key of the
+// join table is configured with tag `jointable_key`, `primary` by default, `unique` or `none`, join tables with soft
+// deleted rows have no key by default as they keep deleted rows of the same keys
+func (scope *Scope) joinTableSql(field *StructField) string {
+	relationship := field.Relationship
+	if relationship == nil || relationship.JoinTableHandler == nil {
+		return ""
+	}
+
+	var sqlTypes, keyColumns []string
+	for _, reference := range scope.joinTableReferences(field) {
+		for i, primaryField := range reference.Scope.GetModelStruct().PrimaryFields {
+			value := reflect.Indirect(reflect.New(primaryField.Struct.Type))
+			primaryKeySqlType := scope.Dialect().SqlTag(value, 255, false)
+			sqlTypes = append(sqlTypes, scope.Quote(reference.Columns[i])+" "+primaryKeySqlType)
+			keyColumns = append(keyColumns, scope.Quote(reference.Columns[i]))
+		}
+	}
+
+	key := ParseTagSetting(field.Tag)["JOINTABLE_KEY"]
+	if isJoinTableSoftDelete(field) {
+		sqlTypes = append(sqlTypes, scope.joinTableDeletedAtSql())
+		if key == "" {
+			key = "none"
+		}
+	}
+
+	switch strings.ToUpper(key) {
+	case "NONE":
+	case "UNIQUE":
+		sqlTypes = append(sqlTypes, fmt.Sprintf("UNIQUE (%v)", strings.Join(keyColumns, ",")))
+	default:
+		sqlTypes = append(sqlTypes, fmt.Sprintf("PRIMARY KEY (%v)", strings.Join(keyColumns, ",")))
+	}
+	// foreign keys are created with the table if they can't be added later
+	if !scope.Dialect().SupportAddForeignKey() {
+		for _, foreignKey := range scope.joinTableForeignKeys(field) {
+			sqlTypes = append(sqlTypes, foreignKey.Sql)
+		}
+	}
+	return fmt.Sprintf("CREATE TABLE %v (%v)", scope.Quote(relationship.JoinTableHandler.Table(scope.db)), strings.Join(sqlTypes, ","))
+}
+
+// isJoinTableSoftDelete check if join rows of the many to many field are soft deleted, with tag `jointable_soft_delete`
+func isJoinTableSoftDelete(field *StructField) bool {
+	_, ok := ParseTagSetting(field.Tag)["JOINTABLE_SOFT_DELETE"]
+	return ok
+}
+
+func (scope *Scope) joinTableDeletedAtSql() string {
+	return scope.Quote(joinTableDeletedAtColumn) + " " + scope.Dialect().SqlTag(reflect.ValueOf(time.Time{}), 0, false)
+}
+
+// joinTableReference is columns of the join table referencing primary keys of a side of many to many relationship
+type joinTableReference struct {
+	Scope             *Scope
+	Columns           []string
+	ReferencedColumns []string
+}
+
+func (scope *Scope) joinTableReferences(field *StructField) []joinTableReference {
+	var references []joinTableReference
+	for _, s := range []*Scope{scope, scope.New(reflect.New(field.Struct.Type).Interface())} {
+		reference := joinTableReference{Scope: s}
+		for _, primaryField := range s.GetModelStruct().PrimaryFields {
+			reference.Columns = append(reference.Columns, ToDBName(s.GetModelStruct().ModelType.Name()+primaryField.Name))
+			reference.ReferencedColumns = append(reference.ReferencedColumns, primaryField.DBName)
+		}
+		references = append(references, reference)
+	}
+	return references
+}
+
+// joinTableForeignKey is a foreign key of the join table, Sql is the constraint clause
+type joinTableForeignKey struct {
+	Name            string
+	ReferencedTable string
+	Sql             string
+}
+
+// joinTableForeignKeys get foreign keys of the join table to both sides, actions are configured with tag
+// `constraint:OnDelete:CASCADE,OnUpdate:CASCADE`, `constraint:-` creates no foreign keys
+func (scope *Scope) joinTableForeignKeys(field *StructField) (foreignKeys []joinTableForeignKey) {
+	constraint := ParseTagSetting(field.Tag)["CONSTRAINT"]
+	if constraint == "-" {
+		return nil
+	}
+	var actions string
+	for _, action := range strings.Split(constraint, ",") {
+		if parts := strings.SplitN(action, ":", 2); len(parts) == 2 {
+			switch strings.ToUpper(strings.TrimSpace(parts[0])) {
+			case "ONDELETE":
+				actions += " ON DELETE " + strings.TrimSpace(parts[1])
+			case "ONUPDATE":
+				actions += " ON UPDATE " + strings.TrimSpace(parts[1])
+			}
+		}
+	}
+
+	joinTable := field.Relationship.JoinTableHandler.Table(scope.db)
+	for _, reference := range scope.joinTableReferences(field) {
+		if len(reference.Columns) == 0 {
+			continue
+		}
+		name := fmt.Sprintf("%s_%s_foreign", joinTable, strings.Join(reference.Columns, "_"))
+		foreignKeys = append(foreignKeys, joinTableForeignKey{
+			Name:            name,
+			ReferencedTable: reference.Scope.TableName(),
+			Sql: fmt.Sprintf("CONSTRAINT %v FOREIGN KEY (%v) REFERENCES %v(%v)%v", name,
+				strings.Join(scope.quoteColumns(reference.Columns), ","), reference.Scope.QuotedTableName(),
+				strings.Join(scope.quoteColumns(reference.ReferencedColumns), ","), actions),
+		})
+	}
+	return
+}
+
+// addJoinTableForeignKeys add missing foreign keys of the join table to existing tables, foreign keys to tables
+// not created yet are added by later migrations
+func (scope *Scope) addJoinTableForeignKeys(field *StructField) {
+	joinTable := field.Relationship.JoinTableHandler.Table(scope.db)
+	existing := map[string]bool{}
+	infos, _ := scope.Dialect().ForeignKeyInfos(scope, joinTable)
+	for _, info := range infos {
+		existing[info.Name] = true
+	}
+
+	for _, foreignKey := range scope.joinTableForeignKeys(field) {
+		if existing[foreignKey.Name] || !scope.Dialect().HasTable(scope, foreignKey.ReferencedTable) {
+			continue
+		}
+		scope.Err(scope.NewDB().Exec(fmt.Sprintf("ALTER TABLE %v ADD %v", scope.Quote(joinTable), foreignKey.Sql)).Error)
 	}
 }
 
@@ -520,6 +1091,22 @@ func (scope *Scope) createDB(db string) *Scope {
 }
 
 func (scope *Scope) createTable() *Scope {
+	scope.createSequences()
+	createTableSql := scope.createTableSql()
+	scope.Raw(createTableSql).Exec()
+	if scope.HasError() {
+		fmt.Println(createTableSql)
+		return scope
+	}
+
+	// join tables are created after the table, so their foreign keys could reference it
+	for _, field := range scope.GetStructFields() {
+		scope.createJoinTable(field)
+	}
+	return scope
+}
+
+func (scope *Scope) createTableSql() string {
 	var tags []string
 	var primaryKeys []string
 	for _, field := range scope.GetStructFields() {
@@ -531,7 +1118,6 @@ func (scope *Scope) createTable() *Scope {
 		if field.IsPrimaryKey {
 			primaryKeys = append(primaryKeys, field.DBName)
 		}
-		scope.createJoinTable(field)
 	}
 
 	var primaryKeyStr string
@@ -539,13 +1125,8 @@ func (scope *Scope) createTable() *Scope {
 		primaryKeyStr = fmt.Sprintf(", PRIMARY KEY (%v)", strings.Join(primaryKeys, ","))
 	}
 
-	scope.Raw(fmt.Sprintf("CREATE TABLE %v (%v %v) ENGINE=%s DEFAULT CHARSET=%s", scope.QuotedTableName(),
-		strings.Join(tags, ","), primaryKeyStr, scope.Engine(), scope.Charset())).Exec()
-	if scope.HasError() {
-		fmt.Println(fmt.Sprintf("CREATE TABLE %v (%v %v) ENGINE=%s DEFAULT CHARSET=%s", scope.QuotedTableName(),
-			strings.Join(tags, ","), primaryKeyStr, scope.Engine(), scope.Charset()))
-	}
-	return scope
+	return fmt.Sprintf("CREATE TABLE %v (%v %v)%v", scope.QuotedTableName(), strings.Join(tags, ","), primaryKeyStr,
+		addExtraSpaceIfExist(scope.Dialect().TableOptionsSql(scope.Engine(), scope.Charset())))
 }
 
 func (scope *Scope) dropTable() *Scope {
@@ -561,7 +1142,16 @@ func (scope *Scope) dropTableIfExists() *Scope {
 }
 
 func (scope *Scope) modifyColumn(column string, typ string) {
-	scope.Raw(fmt.Sprintf("ALTER TABLE %v MODIFY %v %v", scope.QuotedTableName(), scope.Quote(column), typ)).Exec()
+	sqls := scope.Dialect().AlterColumnSql(scope.QuotedTableName(), scope.Quote(column), typ)
+	if len(sqls) == 0 {
+		scope.Err(AlterColumnNotSupported)
+		return
+	}
+	for _, alterSql := range sqls {
+		if scope.Raw(alterSql).Exec(); scope.HasError() {
+			return
+		}
+	}
 }
 
 func (scope *Scope) dropColumn(column string) {
@@ -571,21 +1161,24 @@ func (scope *Scope) dropColumn(column string) {
 	}
 }
 
+func (scope *Scope) alterColumns() bool {
+	alter, ok := scope.Get("gorm:alter_columns")
+	return ok && alter.(bool)
+}
+
 func (scope *Scope) changeColumn(columnName string, column string) {
-	scope.Raw(
-		fmt.Sprintf("ALTER TABLE %v CHANGE %v %v %s",
-			scope.QuotedTableName(), scope.Quote(columnName),
-			scope.Quote(columnName), column,
-		),
-	).Exec()
-	if scope.HasError() {
+	if len(scope.Dialect().AlterColumnSql(scope.QuotedTableName(), scope.Quote(columnName), column)) == 0 {
+		scope.Err(AlterColumnNotSupported)
+		return
+	}
+	if scope.modifyColumn(columnName, column); scope.HasError() {
 		panic(scope.db.Error)
 	}
 }
 
 func (scope *Scope) dropIndex(indexName string) {
 	realIndexName, _, _ := GetSeqInIndex(indexName)
-	scope.Raw(fmt.Sprintf("DROP INDEX %v ON %v", scope.Quote(realIndexName), scope.QuotedTableName())).Exec()
+	scope.removeIndex(realIndexName)
 }
 
 func (scope *Scope) addIndex(unique bool, indexName string, column ...string) {
@@ -606,6 +1199,16 @@ func (scope *Scope) addIndex(unique bool, indexName string, column ...string) {
 	scope.Raw(fmt.Sprintf("%s %v ON %v(%v);", sqlCreate, indexName, scope.QuotedTableName(), strings.Join(columns, ", "))).Exec()
 }
 
+func (scope *Scope) addFullTextIndex(indexName string, column ...string) {
+	if scope.Dialect().HasIndex(scope, scope.TableName(), indexName) {
+		return
+	}
+
+	if sql := scope.Dialect().FullTextIndexSql(indexName, scope.QuotedTableName(), scope.quoteColumns(column)); sql != "" {
+		scope.Raw(sql).Exec()
+	}
+}
+
 func (scope *Scope) addForeignKey(field string, dest string, onDelete string, onUpdate string) {
 	var table = scope.TableName()
 	var keyName = fmt.Sprintf("%s_%s_foreign", table, field)
@@ -617,68 +1220,158 @@ func (scope *Scope) removeIndex(indexName string) {
 	scope.Dialect().RemoveIndex(scope, indexName)
 }
 
+// models implementing migrateIgnorer are skipped by AutoMigrate, like tables owned by another service, e.g:
+//
+//	func (Account) IgnoreMigrate() bool { return true }
+type migrateIgnorer interface {
+	IgnoreMigrate() bool
+}
+
+func (scope *Scope) isMigrateIgnored() bool {
+	modelType := scope.GetModelStruct().ModelType
+	if modelType == nil {
+		return false
+	}
+	if ignorer, ok := reflect.New(modelType).Interface().(migrateIgnorer); ok {
+		return ignorer.IgnoreMigrate()
+	}
+	return false
+}
+
 func (scope *Scope) autoMigrate() *Scope {
 	tableName := scope.TableName()
 	quotedTableName := scope.QuotedTableName()
 
+	// views are created with CreateView, tables of models ignoring migrate are owned by others
+	if scope.GetModelStruct().IsView || scope.isMigrateIgnored() {
+		return scope
+	}
+
 	dbName, _ := DBName(tableName)
 	if dbName != "" {
 		scope.createDB(dbName)
 	}
 
+	var refused, differing []string
 	if !scope.Dialect().HasTable(scope, tableName) {
 		scope.createTable()
 	} else {
+		columns, err := scope.Dialect().ColumnInfos(scope, tableName)
+		if scope.Err(err) != nil {
+			return scope
+		}
+		existing := map[string]bool{}
+		for _, column := range columns {
+			existing[column.Name] = true
+		}
+
+		scope.createSequences()
 		for _, field := range scope.GetStructFields() {
-			if !scope.Dialect().HasColumn(scope, tableName, field.DBName) {
-				if field.IsNormal {
-					sqlTag := scope.generateSqlTag(field)
-					scope.Raw(fmt.Sprintf("ALTER TABLE %v ADD %v %v;", quotedTableName, field.DBName, sqlTag)).Exec()
-				}
+			if !existing[field.DBName] && field.IsNormal {
+				sqlTag := scope.generateSqlTag(field)
+				scope.Raw(fmt.Sprintf("ALTER TABLE %v ADD %v %v;", quotedTableName, field.DBName, sqlTag)).Exec()
 			}
 			scope.createJoinTable(field)
 		}
 
-		columns := scope.Dialect().Columns(scope, tableName)
-		for columnName, column := range columns {
+		for _, columnInfo := range columns {
+			columnName, column := columnInfo.Name, columnInfoDefinition(columnInfo)
 			foundField := false
 			for _, field := range scope.GetStructFields() {
 				if field.DBName != columnName {
 					continue
 				}
 				foundField = true
-				if !scope.compareFieldAndColumn(field, column) {
+				if differences := scope.diffFieldAndColumn(field, column); len(differences) > 0 {
+					sqlTag := scope.generateSqlTag(field)
+					if !scope.alterColumns() {
+						differing = append(differing, fmt.Sprintf("%v.%v (%v)", tableName, columnName, strings.Join(differences, ", ")))
+						break
+					}
+					if isNarrowingColumn(column, sqlTag) && !scope.allowDestructive() {
+						refused = append(refused, fmt.Sprintf("narrow column %v.%v from %v to %v", tableName, columnName, column, strings.TrimSpace(sqlTag)))
+						break
+					}
 					fmt.Println(
-						fmt.Sprintf("[info]change table[%s] column[%s] from %s to %s",
-							tableName, field.DBName, column, scope.generateSqlTag(field),
+						fmt.Sprintf("[info]change table[%s] column[%s] from %s to %s (%s)",
+							tableName, field.DBName, column, sqlTag, strings.Join(differences, ", "),
 						),
 					)
-					scope.changeColumn(columnName, scope.generateSqlTag(field))
+					scope.changeColumn(columnName, sqlTag)
 				}
 				break
 			}
-			if !foundField {
+			// columns of join tables are keys created with them, not fields of handlers
+			if _, isJoinTable := scope.Value.(JoinTableHandlerInterface); !foundField && !isJoinTable {
+				if !scope.allowDestructive() {
+					refused = append(refused, fmt.Sprintf("drop column %v.%v", tableName, columnName))
+					continue
+				}
 				scope.dropColumn(columnName)
 			}
 		}
 	}
 
 	scope.autoIndex()
+	if scope.isVersioned() {
+		scope.autoMigrateHistory()
+	}
+	if len(refused) > 0 {
+		sort.Strings(refused)
+		scope.Err(&DestructiveMigrationError{Operations: refused})
+	}
+	if len(differing) > 0 {
+		scope.Err(&DifferingColumnsError{Columns: differing})
+	}
 	return scope
 }
 
-func (scope *Scope) autoIndex() *Scope {
-	var indexes = map[string][]string{}
-	var uniqueIndexes = map[string][]string{}
+// tagIndexes get columns of indexes declared with tags `index`, `unique_index` and `fulltext`, partial indexes
+// are got by tagPartialIndexes
+func (scope *Scope) tagIndexes() (indexes map[string][]string, uniqueIndexes map[string][]string, fullTextIndexes map[string][]string) {
+	indexes, uniqueIndexes, fullTextIndexes, wheres := scope.parseTagIndexes()
+	for name := range wheres {
+		delete(indexes, name)
+		delete(uniqueIndexes, name)
+	}
+	return
+}
+
+// tagPartialIndexes get indexes declared with tags `index` or `unique_index` of fields tagged with `where`, the
+// condition applies to all indexes of the field, e.g:
+//
+//	Email string `sql:"unique_index:uix_users_email;where:deleted_at IS NULL"`
+func (scope *Scope) tagPartialIndexes() (partialIndexes []Index) {
+	indexes, uniqueIndexes, _, wheres := scope.parseTagIndexes()
+	for _, name := range sortedIndexNames(indexes) {
+		if where, ok := wheres[name]; ok {
+			partialIndexes = append(partialIndexes, Index{Name: name, Parts: indexes[name], Where: where})
+		}
+	}
+	for _, name := range sortedIndexNames(uniqueIndexes) {
+		if where, ok := wheres[name]; ok {
+			partialIndexes = append(partialIndexes, Index{Name: name, Parts: uniqueIndexes[name], Unique: true, Where: where})
+		}
+	}
+	return
+}
+
+// parseTagIndexes get columns of indexes declared with tags, and conditions of partial indexes by index names
+func (scope *Scope) parseTagIndexes() (indexes map[string][]string, uniqueIndexes map[string][]string, fullTextIndexes map[string][]string, wheres map[string]string) {
+	indexes, uniqueIndexes, fullTextIndexes, wheres = map[string][]string{}, map[string][]string{}, map[string][]string{}, map[string]string{}
 
 	for _, field := range scope.GetStructFields() {
 		sqlSettings := ParseTagSetting(field.Tag)
+		where, isPartial := sqlSettings["WHERE"]
 		if names, ok := sqlSettings["INDEX"]; ok {
 			for _, name := range strings.Split(names, ":") {
 				if name == "INDEX" {
 					name = fmt.Sprintf("idx_%v_%v", scope.TableName(), field.DBName)
 				}
 				realIndex, seqIndex, hasSeq := GetSeqInIndex(name)
+				if isPartial {
+					wheres[realIndex] = where
+				}
 				if !hasSeq {
 					indexes[name] = append(indexes[name], field.DBName)
 				} else {
@@ -689,12 +1382,21 @@ func (scope *Scope) autoIndex() *Scope {
 				}
 			}
 		}
+		if name, ok := sqlSettings["FULLTEXT"]; ok {
+			if name == "FULLTEXT" {
+				name = fmt.Sprintf("ftx_%v", scope.TableName())
+			}
+			fullTextIndexes[name] = append(fullTextIndexes[name], field.DBName)
+		}
 		if names, ok := sqlSettings["UNIQUE_INDEX"]; ok {
 			for _, name := range strings.Split(names, ":") {
 				if name == "UNIQUE_INDEX" {
 					name = fmt.Sprintf("uix_%v_%v", scope.TableName(), field.DBName)
 				}
 				realIndex, seqIndex, hasSeq := GetSeqInIndex(name)
+				if isPartial {
+					wheres[realIndex] = where
+				}
 				if !hasSeq {
 					uniqueIndexes[name] = append(uniqueIndexes[name], field.DBName)
 				} else {
@@ -706,20 +1408,51 @@ func (scope *Scope) autoIndex() *Scope {
 			}
 		}
 	}
+	return
+}
 
-	indexColumnMap := scope.Dialect().IndexColumnMap(scope, scope.TableName(), 1)
-	for indexName, columns := range indexColumnMap {
-		if _, ok := indexes[indexName]; !ok || !reflect.DeepEqual(columns, indexes[indexName]) {
-			scope.dropIndex(indexName)
-		}
+func (scope *Scope) autoIndex() *Scope {
+	indexes, uniqueIndexes, fullTextIndexes := scope.tagIndexes()
+
+	var expressionIndexes = map[string]Index{}
+	for _, index := range scope.modelIndexes() {
+		expressionIndexes[index.Name] = index
 	}
 
-	uniqueColumnMap := scope.Dialect().IndexColumnMap(scope, scope.TableName(), 0)
-	for indexName, columns := range uniqueColumnMap {
-		if indexName == "PRIMARY" {
+	indexInfos, err := scope.Dialect().IndexInfos(scope, scope.TableName())
+	if scope.Err(err) != nil {
+		return scope
+	}
+	// keys of join tables and unique columns are indexes of constraints, they are not declared with index tags
+	_, isJoinTable := scope.Value.(JoinTableHandlerInterface)
+	uniqueColumns := map[string]bool{}
+	for _, field := range scope.GetStructFields() {
+		if _, ok := ParseTagSetting(field.Tag)["UNIQUE"]; ok {
+			uniqueColumns[field.DBName] = true
+		}
+	}
+	for _, indexInfo := range indexInfos {
+		indexName, columns := indexInfo.Name, indexInfo.Columns
+		if indexInfo.Primary || isJoinTable || (indexInfo.Unique && len(columns) == 1 && uniqueColumns[columns[0]]) {
+			continue
+		}
+		if index, ok := expressionIndexes[indexName]; ok && index.Unique == indexInfo.Unique {
+			if !sameIndexParts(columns, index.Parts) {
+				scope.dropIndex(indexName)
+			}
 			continue
 		}
-		if _, ok := uniqueIndexes[indexName]; !ok || !reflect.DeepEqual(columns, uniqueIndexes[indexName]) {
+		expected, ok := uniqueIndexes[indexName]
+		if !indexInfo.Unique {
+			if expected, ok = indexes[indexName]; !ok {
+				expected, ok = fullTextIndexes[indexName]
+				// full-text indexes of postgres index a tsvector expression, whose only part has a blank column
+				if ok && reflect.DeepEqual(columns, []string{""}) {
+					continue
+				}
+			}
+		}
+		if !ok || !reflect.DeepEqual(columns, expected) {
 			scope.dropIndex(indexName)
 		}
 	}
@@ -732,5 +1465,62 @@ func (scope *Scope) autoIndex() *Scope {
 		scope.addIndex(true, name, columns...)
 	}
 
+	for name, columns := range fullTextIndexes {
+		scope.addFullTextIndex(name, columns...)
+	}
+
+	for _, index := range expressionIndexes {
+		scope.createIndex(index)
+	}
+
 	return scope
 }
+
+// serverTimestampFields get fields tagged with `server_timestamp`, they are maintained by database and never written by gorm
+func (scope *Scope) serverTimestampFields() (fields []*Field) {
+	if scope.IndirectValue().Kind() != reflect.Struct {
+		return
+	}
+	for _, field := range scope.Fields() {
+		if _, ok := ParseTagSetting(field.Tag)["SERVER_TIMESTAMP"]; ok && field.IsNormal {
+			fields = append(fields, field)
+		}
+	}
+	return
+}
+
+// execReturningServerTimestamps exec the update sql, server maintained timestamps of the updated record
+// are re-read with RETURNING if the dialect supports it
+func (scope *Scope) execReturningServerTimestamps() {
+	var columns []string
+	var values []interface{}
+	for _, field := range scope.serverTimestampFields() {
+		columns = append(columns, scope.Quote(field.DBName))
+		values = append(values, field.Field.Addr().Interface())
+	}
+
+	returning := scope.Dialect().ReturningStr(scope.TableName(), strings.Join(columns, ", "))
+	if len(columns) == 0 || returning == "" || scope.PrimaryKeyZero() {
+		scope.Exec()
+		return
+	}
+	defer scope.Trace(NowFunc())
+
+	scope.Raw(scope.Sql + " " + returning)
+
+	rows, err := scope.SqlDB().Query(scope.Sql, scope.SqlVars...)
+	if scope.Err(err) != nil {
+		return
+	}
+	defer rows.Close()
+
+	var affected int64
+	for rows.Next() {
+		if affected == 0 {
+			scope.Err(rows.Scan(values...))
+		}
+		affected++
+	}
+	scope.Err(rows.Err())
+	scope.db.RowsAffected = affected
+}
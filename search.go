@@ -3,28 +3,62 @@ package gorm
 import "fmt"
 
 type search struct {
-	db              *DB
-	whereConditions []map[string]interface{}
-	orConditions    []map[string]interface{}
-	notConditions   []map[string]interface{}
-	havingCondition map[string]interface{}
-	initAttrs       []interface{}
-	assignAttrs     []interface{}
-	selects         map[string]interface{}
-	omits           []string
-	orders          []string
-	joins           string
-	preload         map[string][]interface{}
-	offset          string
-	limit           string
-	group           string
-	tableName       string
-	raw             bool
-	Unscoped        bool
-}
-
+	db               *DB
+	whereConditions  []map[string]interface{}
+	orConditions     []map[string]interface{}
+	notConditions    []map[string]interface{}
+	havingConditions []map[string]interface{}
+	groupConditions  []map[string]interface{}
+	initAttrs        []interface{}
+	assignAttrs      []interface{}
+	selects          map[string]interface{}
+	omits            []string
+	orders           []string
+	joins            string
+	joinAssociations []string
+	preload          map[string][]interface{}
+	preloadCounts    map[string][]interface{}
+	offset           string
+	limit            string
+	tableName        string
+	raw              bool
+	distinct         bool
+	compounds        []map[string]interface{}
+	fromQuery        *DB
+	updateFrom       map[string]interface{}
+	deleteUsing      map[string]interface{}
+	clauses          []map[string]interface{}
+	distinctColumns  []string
+	Unscoped         bool
+}
+
+// clone copy the search, slices are capped so appending to the clone won't write into arrays shared with s,
+// maps changed in place are copied, then clones could be used concurrently
 func (s *search) clone() *search {
 	clone := *s
+	clone.whereConditions = s.whereConditions[:len(s.whereConditions):len(s.whereConditions)]
+	clone.orConditions = s.orConditions[:len(s.orConditions):len(s.orConditions)]
+	clone.notConditions = s.notConditions[:len(s.notConditions):len(s.notConditions)]
+	clone.havingConditions = s.havingConditions[:len(s.havingConditions):len(s.havingConditions)]
+	clone.groupConditions = s.groupConditions[:len(s.groupConditions):len(s.groupConditions)]
+	clone.initAttrs = s.initAttrs[:len(s.initAttrs):len(s.initAttrs)]
+	clone.assignAttrs = s.assignAttrs[:len(s.assignAttrs):len(s.assignAttrs)]
+	clone.orders = s.orders[:len(s.orders):len(s.orders)]
+	clone.joinAssociations = s.joinAssociations[:len(s.joinAssociations):len(s.joinAssociations)]
+	clone.compounds = s.compounds[:len(s.compounds):len(s.compounds)]
+	clone.clauses = s.clauses[:len(s.clauses):len(s.clauses)]
+	if s.preload != nil {
+		clone.preload = make(map[string][]interface{}, len(s.preload))
+		for column, values := range s.preload {
+			clone.preload[column] = values
+		}
+	}
+	if s.preloadCounts != nil {
+		clone.preloadCounts = make(map[string][]interface{}, len(s.preloadCounts))
+		for column, values := range s.preloadCounts {
+			clone.preloadCounts[column] = values
+		}
+	}
 	return &clone
 }
 
@@ -67,6 +101,17 @@ func (s *search) Select(query interface{}, args ...interface{}) *search {
 	return s
 }
 
+func (s *search) Distinct(columns ...string) *search {
+	s.distinct = true
+	s.distinctColumns = columns
+	return s
+}
+
+func (s *search) Compound(operator string, query *DB) *search {
+	s.compounds = append(s.compounds, map[string]interface{}{"operator": operator, "query": query})
+	return s
+}
+
 func (s *search) Omit(columns ...string) *search {
 	s.omits = columns
 	return s
@@ -82,17 +127,36 @@ func (s *search) Offset(value interface{}) *search {
 	return s
 }
 
-func (s *search) Group(query string) *search {
-	s.group = s.getInterfaceAsSql(query)
+func (s *search) Group(query interface{}, values ...interface{}) *search {
+	s.groupConditions = append(s.groupConditions, map[string]interface{}{"query": query, "args": values})
 	return s
 }
 
-func (s *search) Having(query string, values ...interface{}) *search {
-	s.havingCondition = map[string]interface{}{"query": query, "args": values}
+func (s *search) Having(query interface{}, values ...interface{}) *search {
+	s.havingConditions = append(s.havingConditions, map[string]interface{}{"query": query, "args": values})
+	return s
+}
+
+func (s *search) UpdateFrom(table string, on string) *search {
+	s.updateFrom = map[string]interface{}{"table": table, "on": on}
+	return s
+}
+
+func (s *search) DeleteUsing(table string, on string) *search {
+	s.deleteUsing = map[string]interface{}{"table": table, "on": on}
+	return s
+}
+
+func (s *search) Clause(name string, args ...interface{}) *search {
+	s.clauses = append(s.clauses, map[string]interface{}{"name": name, "args": args})
 	return s
 }
 
 func (s *search) Joins(query string) *search {
+	if associationNameRegexp.MatchString(query) {
+		s.joinAssociations = append(s.joinAssociations, query)
+		return s
+	}
 	s.joins = query
 	return s
 }
@@ -105,6 +169,14 @@ func (s *search) Preload(column string, values ...interface{}) *search {
 	return s
 }
 
+func (s *search) PreloadCount(column string, values ...interface{}) *search {
+	if s.preloadCounts == nil {
+		s.preloadCounts = map[string][]interface{}{}
+	}
+	s.preloadCounts[column] = values
+	return s
+}
+
 func (s *search) Raw(b bool) *search {
 	s.raw = b
 	return s
@@ -120,6 +192,12 @@ func (s *search) Table(name string) *search {
 	return s
 }
 
+func (s *search) From(query *DB, alias string) *search {
+	s.fromQuery = query
+	s.tableName = alias
+	return s
+}
+
 func (s *search) getInterfaceAsSql(value interface{}) (str string) {
 	switch value.(type) {
 	case string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
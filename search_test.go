@@ -1,7 +1,10 @@
 package gorm
 
 import (
+	"fmt"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -28,3 +31,96 @@ func TestCloneSearch(t *testing.T) {
 		t.Errorf("selectStr should be copied")
 	}
 }
+
+func TestCloneSearchDoesNotShareArrays(t *testing.T) {
+	s := new(search)
+	s.Where("a = ?", 1).Where("b = ?", 2).Where("c = ?", 3).Order("a").Order("b").Order("c").Preload("Emails")
+
+	s1, s2 := s.clone(), s.clone()
+	s1.Where("d = ?", 4).Order("d").Preload("Languages")
+	s2.Where("e = ?", 5).Order("e")
+
+	if s1.whereConditions[3]["query"] != "d = ?" || s1.orders[3] != "d" {
+		t.Errorf("appending to a clone should not be overwritten by other clones, but got %v, %v", s1.whereConditions[3], s1.orders)
+	}
+	if _, ok := s.preload["Languages"]; ok {
+		t.Errorf("preload of a clone should not change the original search")
+	}
+}
+
+func TestConcurrentChaining(t *testing.T) {
+	db := newFakeDB("mysql", "")
+	base := db.Table("users").Where("age > ?", 18).Where(map[string]interface{}{"role": "admin"}).Order("id")
+
+	var wg sync.WaitGroup
+	errs := make(chan string, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			scope := base.Where("name = ?", fmt.Sprint("user", i)).Order("name").NewScope(nil)
+			scope.prepareQuerySql()
+			expected := fmt.Sprint("user", i)
+			if len(scope.SqlVars) != 3 || scope.SqlVars[2] != expected || !strings.Contains(scope.Sql, "ORDER BY id,name") {
+				errs <- fmt.Sprintf("%v %v", scope.Sql, scope.SqlVars)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("chains built from a shared DB should not interfere with each other, but got %v", err)
+	}
+}
+
+type conditionUser struct {
+	Id   int64
+	Name string
+	Age  int
+}
+
+func TestStructConditionWithFields(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	scope := db.NewScope(&conditionUser{})
+	if sql := scope.buildWhereCondition(map[string]interface{}{"query": conditionUser{Name: "jinzhu"}}); sql != "(`name` = $$)" {
+		t.Errorf("blank fields should be skipped, but got %v", sql)
+	}
+
+	scope = db.NewScope(&conditionUser{})
+	sql := scope.buildWhereCondition(map[string]interface{}{"query": conditionUser{Name: "jinzhu"}, "args": []interface{}{"Name", "age"}})
+	if sql != "(`name` = $$) AND (`age` = $$)" || !reflect.DeepEqual(scope.SqlVars, []interface{}{"jinzhu", 0}) {
+		t.Errorf("selected fields should be used even if they are blank, but got %v, %v", sql, scope.SqlVars)
+	}
+
+	scope = db.NewScope(&conditionUser{})
+	if sql := scope.buildNotCondition(map[string]interface{}{"query": conditionUser{}, "args": []interface{}{"Age"}}); sql != "(`age` <> $$)" {
+		t.Errorf("selected fields should be used by not conditions, but got %v", sql)
+	}
+
+	scope = db.NewScope(&conditionUser{})
+	scope.buildWhereCondition(map[string]interface{}{"query": conditionUser{}, "args": []interface{}{"unknown"}})
+	if scope.db.Error == nil || !strings.Contains(scope.db.Error.Error(), "unknown") {
+		t.Errorf("unknown fields should return error, but got %v", scope.db.Error)
+	}
+}
+
+func TestStrictConditions(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	scope := db.NewScope(&conditionUser{})
+	if scope.buildWhereCondition(map[string]interface{}{"query": conditionUser{}}); scope.db.Error != nil {
+		t.Errorf("blank struct conditions should be dropped without StrictConditions, but got %v", scope.db.Error)
+	}
+
+	scope = db.StrictConditions().NewScope(&conditionUser{})
+	if scope.buildWhereCondition(map[string]interface{}{"query": conditionUser{}}); scope.db.Error != BlankCondition {
+		t.Errorf("blank struct conditions should return BlankCondition with StrictConditions, but got %v", scope.db.Error)
+	}
+
+	scope = db.StrictConditions().NewScope(&conditionUser{})
+	if scope.buildWhereCondition(map[string]interface{}{"query": conditionUser{}, "args": []interface{}{"Age"}}); scope.db.Error != nil {
+		t.Errorf("selected zero fields are not blank conditions, but got %v", scope.db.Error)
+	}
+}
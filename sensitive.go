@@ -0,0 +1,48 @@
+package gorm
+
+import (
+	"database/sql/driver"
+)
+
+// maskedVar is logged instead of values of sensitive fields
+const maskedVar = "***"
+
+// sensitiveValue wrap value of fields tagged with `sensitive`, it is sent to database as it is,
+// but logged as ***, e.g:
+//
+//	Password string `gorm:"sensitive"`
+type sensitiveValue struct {
+	value interface{}
+}
+
+func (v sensitiveValue) Value() (driver.Value, error) {
+	return driver.DefaultParameterConverter.ConvertValue(v.value)
+}
+
+func isSensitiveField(field *StructField) bool {
+	if field == nil {
+		return false
+	}
+	_, ok := ParseTagSetting(field.Tag)["SENSITIVE"]
+	return ok
+}
+
+// sensitiveVar wrap value of sensitive fields, so it won't be logged
+func sensitiveVar(field *StructField, value interface{}) interface{} {
+	if isSensitiveField(field) {
+		return sensitiveValue{value}
+	}
+	return value
+}
+
+// maskSensitiveVars replace values of sensitive fields with *** for logging
+func maskSensitiveVars(vars []interface{}) []interface{} {
+	masked := make([]interface{}, len(vars))
+	for i, v := range vars {
+		if _, ok := v.(sensitiveValue); ok {
+			v = maskedVar
+		}
+		masked[i] = v
+	}
+	return masked
+}
@@ -0,0 +1,48 @@
+package gorm
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+type credential struct {
+	Id       int64
+	Login    string
+	Password string `gorm:"sensitive"`
+}
+
+func TestSensitiveVarsMaskedInLog(t *testing.T) {
+	var buf bytes.Buffer
+	db := newFakeDB("mysql", "")
+	db.logMode, db.logger = 2, Logger{log.New(&buf, "", 0)}
+
+	scope := db.NewScope(&credential{Login: "jinzhu", Password: "secret"})
+	fields := scope.Fields()
+	scope.Raw("INSERT INTO credentials (login, password) VALUES (" + scope.AddToVars(scope.fieldVar(fields["login"])) + ", " + scope.AddToVars(scope.fieldVar(fields["password"])) + ")")
+
+	if value, err := scope.SqlVars[1].(sensitiveValue).Value(); err != nil || value != "secret" {
+		t.Errorf("sensitive value should be sent as it is, but got %v, %v", value, err)
+	}
+
+	scope.Trace(NowFunc())
+	if output := buf.String(); !strings.Contains(output, "'jinzhu'") || !strings.Contains(output, "'***'") || strings.Contains(output, "secret") {
+		t.Errorf("sensitive value should be masked in log, but got %v", output)
+	}
+}
+
+func TestSensitiveVarsInStructCondition(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	scope := db.NewScope(&credential{})
+	scope.buildWhereCondition(map[string]interface{}{"query": credential{Login: "jinzhu", Password: "secret"}})
+	masked := maskSensitiveVars(scope.SqlVars)
+	for i, value := range masked {
+		if value == "secret" {
+			t.Errorf("sensitive value of condition should be masked, but got %v", masked)
+		} else if value != maskedVar && scope.SqlVars[i] != "jinzhu" {
+			t.Errorf("other values should be kept, but got %v", masked)
+		}
+	}
+}
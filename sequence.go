@@ -0,0 +1,81 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// sequence fields are declared with tags, the name defaults to `<table>_<column>_seq`, e.g:
+//
+//	type Order struct {
+//	  Id int64 `gorm:"primary_key;sequence:order_id_seq;sequence_start:1000;sequence_increment:10"`
+//	}
+//
+// dialects without sequences, like MySQL, keep using auto increment
+type sequence struct {
+	Name      string
+	Start     int64
+	Increment int64
+}
+
+func (scope *Scope) sequenceOf(field *StructField) (sequence, bool) {
+	settings := ParseTagSetting(field.Tag)
+	name, ok := settings["SEQUENCE"]
+	if !ok {
+		return sequence{}, false
+	}
+	if name == "SEQUENCE" {
+		name = fmt.Sprintf("%v_%v_seq", scope.TableName(), field.DBName)
+	}
+	if scope.Dialect().NextSequenceValSql(name) == "" {
+		return sequence{}, false
+	}
+
+	seq := sequence{Name: name, Start: 1, Increment: 1}
+	if value, ok := settings["SEQUENCE_START"]; ok {
+		seq.Start, _ = strconv.ParseInt(value, 10, 64)
+	}
+	if value, ok := settings["SEQUENCE_INCREMENT"]; ok {
+		seq.Increment, _ = strconv.ParseInt(value, 10, 64)
+	}
+	return seq, true
+}
+
+func (scope *Scope) createSequences() *Scope {
+	for _, field := range scope.GetStructFields() {
+		if seq, ok := scope.sequenceOf(field); ok && field.IsNormal {
+			scope.Err(scope.NewDB().Exec(scope.Dialect().CreateSequenceSql(seq.Name, seq.Start, seq.Increment)).Error)
+		}
+	}
+	return scope
+}
+
+// AssignSequence fetch next values of sequences for blank fields tagged with `sequence`
+func AssignSequence(scope *Scope) {
+	if scope.HasError() {
+		return
+	}
+
+	batchFields := []map[string]*Field{}
+	if scope.IndirectValue().Kind() == reflect.Slice {
+		batchFields = scope.BatchFields()
+	} else {
+		batchFields = append(batchFields, scope.Fields())
+	}
+
+	for _, fields := range batchFields {
+		for _, field := range fields {
+			seq, ok := scope.sequenceOf(field.StructField)
+			if !ok || !field.IsBlank || !field.Field.IsValid() {
+				continue
+			}
+
+			var value int64
+			if scope.Err(scope.NewDB().Raw(scope.Dialect().NextSequenceValSql(seq.Name)).Row().Scan(&value)) != nil {
+				return
+			}
+			scope.Err(field.Set(value))
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+)
+
+// sessionConnector open connections with the driver, and run session statements on every new connection,
+// like `SET application_name = 'billing'` or `SET time_zone = '+00:00'`
+type sessionConnector struct {
+	driver     driver.Driver
+	source     string
+	statements []string
+}
+
+func (connector *sessionConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	var conn driver.Conn
+	var err error
+	if driverContext, ok := connector.driver.(driver.DriverContext); ok {
+		var c driver.Connector
+		if c, err = driverContext.OpenConnector(connector.source); err == nil {
+			conn, err = c.Connect(ctx)
+		}
+	} else {
+		conn, err = connector.driver.Open(connector.source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, statement := range connector.statements {
+		if err := execSessionStatement(ctx, conn, statement); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+func (connector *sessionConnector) Driver() driver.Driver {
+	return connector.driver
+}
+
+func execSessionStatement(ctx context.Context, conn driver.Conn, statement string) error {
+	if execer, ok := conn.(driver.ExecerContext); ok {
+		if _, err := execer.ExecContext(ctx, statement, nil); err != driver.ErrSkip {
+			return err
+		}
+	} else if execer, ok := conn.(driver.Execer); ok {
+		if _, err := execer.Exec(statement, nil); err != driver.ErrSkip {
+			return err
+		}
+	}
+
+	stmt, err := conn.Prepare(statement)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(nil)
+	return err
+}
+
+// OpenWithSession open database like Open, and run statements on every new connection of the pool,
+// so settings like application_name and time_zone are the same for all connections, e.g:
+//
+//	db, err := gorm.OpenWithSession("postgres", "user=gorm dbname=gorm", "SET application_name = 'billing'", "SET TIME ZONE 'UTC'")
+func OpenWithSession(dialect string, source string, statements ...string) (DB, error) {
+	if len(statements) == 0 {
+		return Open(dialect, source)
+	}
+
+	// sql.Open won't connect, it is only used to get the registered driver
+	dbSql, err := sql.Open(driverName(dialect), source)
+	if err != nil {
+		return DB{}, err
+	}
+	sqlDriver := dbSql.Driver()
+	dbSql.Close()
+
+	connector := &sessionConnector{driver: sqlDriver, source: source, statements: statements}
+	return newDB(dialect, source, sql.OpenDB(connector)), nil
+}
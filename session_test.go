@@ -0,0 +1,28 @@
+package gorm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOpenWithSession(t *testing.T) {
+	fakeStatements = nil
+	statements := []string{"SET application_name = 'gorm'", "SET TIME ZONE 'UTC'"}
+	db, err := OpenWithSession("gorm_fake_test", "", statements...)
+	if err != nil {
+		t.Fatalf("failed to open database, got %v", err)
+	}
+	defer db.Close()
+
+	if err := db.DB().Ping(); err != nil {
+		t.Errorf("failed to connect, got %v", err)
+	}
+	if !reflect.DeepEqual(fakeStatements, statements) {
+		t.Errorf("session statements should be executed on new connection, but got %v", fakeStatements)
+	}
+
+	db.DB().Exec("SELECT 1")
+	if len(fakeStatements) != 3 {
+		t.Errorf("session statements should not be executed again for the same connection, but got %v", fakeStatements)
+	}
+}
@@ -1,8 +1,12 @@
 package gorm
 
 import (
+	"database/sql"
 	"fmt"
 	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -10,6 +14,14 @@ type sqlite3 struct {
 	commonDialect
 }
 
+// CollationSql only render collation like `NOCASE`, sqlite doesn't have column charset
+func (sqlite3) CollationSql(charset string, collation string) string {
+	if collation == "" {
+		return ""
+	}
+	return "COLLATE " + collation
+}
+
 func (sqlite3) SqlTag(value reflect.Value, size int, autoIncrease bool) string {
 	switch value.Kind() {
 	case reflect.Bool:
@@ -32,6 +44,9 @@ func (sqlite3) SqlTag(value reflect.Value, size int, autoIncrease bool) string {
 		if _, ok := value.Interface().(time.Time); ok {
 			return "datetime"
 		}
+		if _, ok := value.Interface().(Point); ok {
+			return ""
+		}
 	default:
 		if _, ok := value.Interface().([]byte); ok {
 			return "blob"
@@ -40,6 +55,152 @@ func (sqlite3) SqlTag(value reflect.Value, size int, autoIncrease bool) string {
 	panic(fmt.Sprintf("invalid sql type %s (%s) for sqlite3", value.Type().Name(), value.Kind().String()))
 }
 
+var sqlite3ReservedWords = wordSet(`ABORT AUTOINCREMENT INDEX INDEXED ISNULL LIMIT NOTNULL OFFSET PRAGMA RAISE
+	REINDEX RENAME REPLACE VACUUM`)
+
+func (s sqlite3) IsReservedWord(word string) bool {
+	return s.commonDialect.IsReservedWord(word) || sqlite3ReservedWords[strings.ToUpper(word)]
+}
+
+func (sqlite3) SubstringSql(column string, from string, length string) string {
+	return fmt.Sprintf("substr(%v, %v, %v)", column, from, length)
+}
+
+// ExplainSql use EXPLAIN QUERY PLAN, as EXPLAIN returns bytecode of the statement
+func (sqlite3) ExplainSql(sql string) string {
+	return "EXPLAIN QUERY PLAN " + sql
+}
+
+// CreateViewSql drop the view before creating it to replace it, as sqlite3 doesn't support CREATE OR REPLACE VIEW
+func (sqlite3) CreateViewSql(view string, query string, replace bool) string {
+	if replace {
+		return fmt.Sprintf("DROP VIEW IF EXISTS %v; CREATE VIEW %v AS %v", view, view, query)
+	}
+	return fmt.Sprintf("CREATE VIEW %v AS %v", view, query)
+}
+
+func (sqlite3) SupportPartialIndex() bool {
+	return true
+}
+
+// SupportStoredFunction is false, functions are registered by the driver
+func (sqlite3) SupportStoredFunction() bool {
+	return false
+}
+
+// SupportAddForeignKey is false as sqlite3 only creates foreign keys with tables
+func (sqlite3) SupportAddForeignKey() bool {
+	return false
+}
+
+// MaxBindVars is SQLITE_MAX_VARIABLE_NUMBER of sqlite before 3.32
+func (sqlite3) MaxBindVars() int {
+	return 999
+}
+
+func (sqlite3) TableInfos(scope *Scope) ([]string, error) {
+	rows, err := scope.NewDB().Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name").Rows()
+	if err != nil {
+		return nil, err
+	}
+	return scanTableNames(rows)
+}
+
+// ColumnInfos get columns with PRAGMA table_info, column types are declared types, sizes are parsed from them
+func (s sqlite3) ColumnInfos(scope *Scope, tableName string) ([]ColumnInfo, error) {
+	rows, err := scope.NewDB().Raw(fmt.Sprintf("PRAGMA table_info(%v)", s.Quote(tableName))).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []ColumnInfo
+	for rows.Next() {
+		var column ColumnInfo
+		var cid, notNull, primaryKey int
+		if err := rows.Scan(&cid, &column.Name, &column.Type, &notNull, &column.Default, &primaryKey); err != nil {
+			return columns, err
+		}
+		column.ColumnType = column.Type
+		if matches := sqliteSizeRegexp.FindStringSubmatch(column.Type); matches != nil {
+			column.Type = strings.TrimSpace(matches[1])
+			column.Size, _ = strconv.ParseInt(matches[2], 10, 64)
+		}
+		column.Nullable, column.PrimaryKey = notNull == 0 && primaryKey == 0, primaryKey > 0
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+var sqliteSizeRegexp = regexp.MustCompile(`^(.+)\((\d+)\)$`)
+
+// IndexInfos get indexes with PRAGMA index_list and index_info, the primary key of rowid tables is not an index
+func (s sqlite3) IndexInfos(scope *Scope, tableName string) ([]IndexInfo, error) {
+	rows, err := scope.NewDB().Raw(fmt.Sprintf("PRAGMA index_list(%v)", s.Quote(tableName))).Rows()
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes []IndexInfo
+	for rows.Next() {
+		var seq, unique, partial int
+		var index IndexInfo
+		var origin string
+		if err := rows.Scan(&seq, &index.Name, &unique, &origin, &partial); err != nil {
+			rows.Close()
+			return indexes, err
+		}
+		index.Unique, index.Primary = unique > 0, origin == "pk"
+		indexes = append(indexes, index)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return indexes, err
+	}
+
+	for i := range indexes {
+		columnRows, err := scope.NewDB().Raw(fmt.Sprintf("PRAGMA index_info(%v)", s.Quote(indexes[i].Name))).Rows()
+		if err != nil {
+			return indexes, err
+		}
+		for columnRows.Next() {
+			var seqno, cid int
+			var column sql.NullString
+			if err := columnRows.Scan(&seqno, &cid, &column); err != nil {
+				columnRows.Close()
+				return indexes, err
+			}
+			indexes[i].Columns = append(indexes[i].Columns, column.String)
+		}
+		columnRows.Close()
+	}
+	return indexes, nil
+}
+
+// ForeignKeyInfos get foreign keys with PRAGMA foreign_key_list, sqlite3 doesn't name them, they are named by their ids
+func (s sqlite3) ForeignKeyInfos(scope *Scope, tableName string) ([]ForeignKeyInfo, error) {
+	rows, err := scope.NewDB().Raw(fmt.Sprintf("PRAGMA foreign_key_list(%v)", s.Quote(tableName))).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var foreignKeys []ForeignKeyInfo
+	for rows.Next() {
+		var id, seq int
+		var foreignKey ForeignKeyInfo
+		var to sql.NullString
+		var onUpdate, onDelete, match string
+		if err := rows.Scan(&id, &seq, &foreignKey.ReferencedTable, &foreignKey.Column, &to, &onUpdate, &onDelete, &match); err != nil {
+			return foreignKeys, err
+		}
+		// referenced column is NULL when it references the primary key implicitly
+		foreignKey.Name, foreignKey.ReferencedColumn = fmt.Sprintf("fk_%v_%v", tableName, id), to.String
+		foreignKeys = append(foreignKeys, foreignKey)
+	}
+	return foreignKeys, rows.Err()
+}
+
 func (sqlite3) HasTable(scope *Scope, tableName string) bool {
 	var count int
 	scope.NewDB().Raw("SELECT count(*) FROM sqlite_master WHERE type='table' AND name=?", tableName).Row().Scan(&count)
@@ -58,6 +219,31 @@ func (sqlite3) HasIndex(scope *Scope, tableName string, indexName string) bool {
 	return count > 0
 }
 
+func (sqlite3) HasTrigger(scope *Scope, tableName string, triggerName string) bool {
+	var count int
+	scope.NewDB().Raw("SELECT count(*) FROM sqlite_master WHERE type = 'trigger' AND tbl_name = ? AND name = ?", tableName, triggerName).Row().Scan(&count)
+	return count > 0
+}
+
+// HasFunction sqlite3 doesn't have stored functions, functions are registered by the driver
+func (sqlite3) HasFunction(scope *Scope, functionName string) bool {
+	return false
+}
+
 func (sqlite3) RemoveIndex(scope *Scope, indexName string) {
 	scope.NewDB().Exec(fmt.Sprintf("DROP INDEX %v", indexName))
 }
+
+func (sqlite3) UpsertSql(conflictColumns []string, updateColumns []string) string {
+	return excludedUpsertSql(conflictColumns, updateColumns)
+}
+
+// ForUpdateSql is blank as sqlite3 locks the whole database instead of rows
+func (sqlite3) ForUpdateSql() string {
+	return ""
+}
+
+// AlterColumnSql is blank as sqlite3 can't alter columns without recreating the table
+func (sqlite3) AlterColumnSql(quotedTableName string, quotedColumn string, definition string) []string {
+	return nil
+}
@@ -0,0 +1,139 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// TimeoutPolicy is the default timeouts of operations without context passed by WithContext,
+// as a safety net against unbounded queries, SELECT queries use Read, others use Write, 0 means no timeout
+type TimeoutPolicy struct {
+	Read  time.Duration
+	Write time.Duration
+}
+
+// SetTimeoutPolicy set default timeouts of operations, e.g:
+//
+//	db.SetTimeoutPolicy(gorm.TimeoutPolicy{Read: 5 * time.Second, Write: 30 * time.Second})
+//
+// rows returned by Row and Rows are read after returning, their timeouts are released at the deadline
+func (s *DB) SetTimeoutPolicy(policy TimeoutPolicy) {
+	s.parent.timeoutPolicy = &policy
+}
+
+// WithContext run sql of the chain with ctx, default timeouts of TimeoutPolicy are not applied to it, e.g:
+//
+//	db.WithContext(ctx).Where("name = ?", "jinzhu").Find(&users)
+func (s *DB) WithContext(ctx context.Context) *DB {
+	return s.Set("gorm:context", ctx)
+}
+
+type sqlContextCommon interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// withContext wrap db to run with the context of WithContext, or default timeouts, db is returned as it is if none of them is set
+func (scope *Scope) withContext(db sqlCommon) sqlCommon {
+	contextDB, ok := db.(sqlContextCommon)
+	if !ok {
+		return db
+	}
+	if value, ok := scope.Get("gorm:context"); ok {
+		if ctx, ok := value.(context.Context); ok && ctx != nil {
+			return contextCommon{db: contextDB, ctx: ctx}
+		}
+	}
+	if policy := scope.db.parent.timeoutPolicy; policy != nil && (policy.Read > 0 || policy.Write > 0) {
+		return contextCommon{db: contextDB, ctx: context.Background(), policy: policy, cancels: &scope.cancels}
+	}
+	return db
+}
+
+// releaseContexts cancel timeouts of queries run by the scope, it is called after their rows are read
+func (scope *Scope) releaseContexts() {
+	for _, cancel := range scope.cancels {
+		cancel()
+	}
+	scope.cancels = nil
+}
+
+// beginTx begin a transaction with the context of WithContext if it is set, default timeouts are not applied to
+// transactions, as they would be rolled back at the deadline
+func (s *DB) beginTx(db sqlDb) (*sql.Tx, error) {
+	if value, ok := s.Get("gorm:context"); ok {
+		if ctx, ok := value.(context.Context); ok && ctx != nil {
+			return db.BeginTx(ctx, nil)
+		}
+	}
+	return db.Begin()
+}
+
+// contextCommon run sql with ctx, and with timeouts of the policy if it is set, timeouts of queries are kept in cancels
+// until rows are read
+type contextCommon struct {
+	db      sqlContextCommon
+	ctx     context.Context
+	policy  *TimeoutPolicy
+	cancels *[]context.CancelFunc
+}
+
+func (db contextCommon) timeout(query string) time.Duration {
+	if db.policy == nil {
+		return 0
+	}
+	if isSelectSql(query) {
+		return db.policy.Read
+	}
+	return db.policy.Write
+}
+
+// context get context for the query, cancel is nil if there is no timeout
+func (db contextCommon) context(query string) (context.Context, context.CancelFunc) {
+	if timeout := db.timeout(query); timeout > 0 {
+		return context.WithTimeout(db.ctx, timeout)
+	}
+	return db.ctx, nil
+}
+
+func (db contextCommon) Exec(query string, args ...interface{}) (sql.Result, error) {
+	ctx, cancel := db.context(query)
+	if cancel != nil {
+		defer cancel()
+	}
+	return db.db.ExecContext(ctx, query, args...)
+}
+
+func (db contextCommon) Prepare(query string) (*sql.Stmt, error) {
+	ctx, cancel := db.context(query)
+	if cancel != nil {
+		defer cancel()
+	}
+	return db.db.PrepareContext(ctx, query)
+}
+
+// Query rows are read after returning, canceling the context would close them, so it is kept until the scope
+// releases it
+func (db contextCommon) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, cancel := db.context(query)
+	rows, err := db.db.QueryContext(ctx, query, args...)
+	if cancel != nil {
+		if err != nil {
+			cancel()
+		} else {
+			*db.cancels = append(*db.cancels, cancel)
+		}
+	}
+	return rows, err
+}
+
+func (db contextCommon) QueryRow(query string, args ...interface{}) *sql.Row {
+	ctx, cancel := db.context(query)
+	if cancel != nil {
+		*db.cancels = append(*db.cancels, cancel)
+	}
+	return db.db.QueryRowContext(ctx, query, args...)
+}
@@ -0,0 +1,73 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTimeoutPolicy(t *testing.T) {
+	db, err := Open("gorm_fake_test", "")
+	if err != nil {
+		t.Fatalf("failed to open database, got %v", err)
+	}
+	defer db.Close()
+	db.logMode = 1
+
+	db.NewScope(nil).SqlDB().Exec("UPDATE users SET name = 'a'")
+	db.NewScope(nil).SqlDB().Query("SELECT * FROM users")
+	if fakeDeadlines["UPDATE users SET name = 'a'"] != 0 || fakeDeadlines["SELECT * FROM users"] != 0 {
+		t.Errorf("there should be no timeout by default, but got %v", fakeDeadlines)
+	}
+
+	db.SetTimeoutPolicy(TimeoutPolicy{Read: time.Second, Write: time.Minute})
+	db.NewScope(nil).SqlDB().Exec("UPDATE users SET name = 'b'")
+	db.NewScope(nil).SqlDB().Query("SELECT * FROM users WHERE id = 1")
+	if deadline := fakeDeadlines["UPDATE users SET name = 'b'"]; deadline <= time.Second || deadline > time.Minute {
+		t.Errorf("writes should use write timeout, but got %v", deadline)
+	}
+	if deadline := fakeDeadlines["SELECT * FROM users WHERE id = 1"]; deadline <= 0 || deadline > time.Second {
+		t.Errorf("reads should use read timeout, but got %v", deadline)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	db.WithContext(ctx).NewScope(nil).SqlDB().Exec("UPDATE users SET name = 'c'")
+	if deadline := fakeDeadlines["UPDATE users SET name = 'c'"]; deadline <= time.Minute {
+		t.Errorf("context passed by WithContext should be used instead of default timeouts, but got %v", deadline)
+	}
+}
+
+func TestTimeoutContextsReleased(t *testing.T) {
+	db, err := Open("gorm_fake_test", "transactions")
+	if err != nil {
+		t.Fatalf("failed to open database, got %v", err)
+	}
+	defer db.Close()
+	db.logMode = 1
+	db.SetTimeoutPolicy(TimeoutPolicy{Read: time.Minute, Write: time.Minute})
+
+	scope := db.NewScope(nil)
+	rows, _ := scope.SqlDB().Query("SELECT * FROM users WHERE id = 2")
+	if len(scope.cancels) != 1 {
+		t.Errorf("timeout of the query should be kept until its rows are read, but got %v", len(scope.cancels))
+	}
+	rows.Close()
+	scope.releaseContexts()
+	if len(scope.cancels) != 0 {
+		t.Errorf("timeouts should be released, but got %v", len(scope.cancels))
+	}
+
+	var count int
+	countScope := db.Table("users").NewScope(nil)
+	countScope.count(&count)
+	if len(countScope.cancels) != 0 {
+		t.Errorf("timeouts should be released after counting, but got %v", len(countScope.cancels))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := db.WithContext(ctx).Begin().Error; err != context.Canceled {
+		t.Errorf("transaction should begin with the context of WithContext, but got %v", err)
+	}
+}
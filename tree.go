@@ -0,0 +1,159 @@
+package gorm
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// tree fields are declared with tags, e.g:
+//
+//	type Category struct {
+//	  Id       int64
+//	  ParentId int64  `gorm:"tree_parent"`
+//	  Path     string `gorm:"tree_path"`
+//	}
+//
+// the path column stores the ids from root to the node, like `/1/5/9/`
+const treePathSeparator = "/"
+
+func (scope *Scope) treeFields() (parentField *Field, pathField *Field, ok bool) {
+	for _, field := range scope.Fields() {
+		if !field.IsNormal {
+			continue
+		}
+		settings := ParseTagSetting(field.Tag)
+		if _, ok := settings["TREE_PARENT"]; ok {
+			parentField = field
+		}
+		if _, ok := settings["TREE_PATH"]; ok {
+			pathField = field
+		}
+	}
+	return parentField, pathField, parentField != nil && pathField != nil
+}
+
+func (scope *Scope) treePath() (string, error) {
+	parentField, _, _ := scope.treeFields()
+	id := fmt.Sprint(scope.PrimaryKeyValue())
+
+	if parentField.IsBlank {
+		return treePathSeparator + id + treePathSeparator, nil
+	}
+
+	parent := scope.New(reflect.New(scope.GetModelStruct().ModelType).Interface())
+	sql := fmt.Sprintf("%v = ?", scope.Quote(scope.PrimaryKey()))
+	if err := scope.NewDB().Where(sql, parentField.Field.Interface()).First(parent.Value).Error; err != nil {
+		return "", err
+	}
+	_, parentPathField, _ := parent.treeFields()
+	parentPath := fmt.Sprint(parentPathField.Field.Interface())
+	if strings.Contains(parentPath, treePathSeparator+id+treePathSeparator) {
+		return "", errors.New("tree node can't be moved under its own descendant")
+	}
+	return parentPath + id + treePathSeparator, nil
+}
+
+// UpdateTreePath maintain the materialized path of tree models after create and update
+func UpdateTreePath(scope *Scope) {
+	if scope.HasError() || scope.IndirectValue().Kind() != reflect.Struct || scope.PrimaryKeyZero() {
+		return
+	}
+
+	_, pathField, ok := scope.treeFields()
+	if !ok {
+		return
+	}
+
+	newPath, err := scope.treePath()
+	if scope.Err(err) != nil {
+		return
+	}
+
+	// the in-memory path might be stale, always compare with the stored one
+	var oldPaths []string
+	table, column := scope.QuotedTableName(), scope.Quote(pathField.DBName)
+	primaryCondition := fmt.Sprintf("%v = ?", scope.Quote(scope.PrimaryKey()))
+	scope.NewDB().Table(scope.TableName()).Where(primaryCondition, scope.PrimaryKeyValue()).Pluck(pathField.DBName, &oldPaths)
+
+	var oldPath string
+	if len(oldPaths) > 0 {
+		oldPath = oldPaths[0]
+	}
+
+	if oldPath != newPath {
+		scope.Err(scope.NewDB().Exec(fmt.Sprintf("UPDATE %v SET %v = ? WHERE %v", table, column, primaryCondition), newPath, scope.PrimaryKeyValue()).Error)
+	}
+
+	if oldPath != "" && oldPath != newPath {
+		// move descendants along with the node
+		var paths []string
+		scope.NewDB().Table(scope.TableName()).Where(fmt.Sprintf("%v LIKE ?", column), oldPath+"_%").Pluck(pathField.DBName, &paths)
+		for _, path := range paths {
+			sql := fmt.Sprintf("UPDATE %v SET %v = ? WHERE %v = ?", table, column, column)
+			scope.Err(scope.NewDB().Exec(sql, newPath+strings.TrimPrefix(path, oldPath), path).Error)
+		}
+	}
+
+	scope.Err(pathField.Set(newPath))
+}
+
+func (s *DB) treeScope(node interface{}) (*Scope, *Field, error) {
+	scope := s.clone().NewScope(node)
+	_, pathField, ok := scope.treeFields()
+	if !ok {
+		return scope, nil, fmt.Errorf("%v doesn't have tree_parent and tree_path fields", scope.IndirectValue().Type())
+	}
+	if pathField.IsBlank {
+		return scope, nil, errors.New("tree node's path is blank")
+	}
+	return scope, pathField, nil
+}
+
+// Descendants find all descendants of node into out, ordered by their path
+func (s *DB) Descendants(node interface{}, out interface{}) *DB {
+	scope, pathField, err := s.treeScope(node)
+	if err != nil {
+		scope.db.err(err)
+		return scope.db
+	}
+	column := scope.Quote(pathField.DBName)
+	return s.Where(fmt.Sprintf("%v LIKE ?", column), fmt.Sprint(pathField.Field.Interface())+"_%").Order(column).Find(out)
+}
+
+// Ancestors find all ancestors of node into out, from root to its direct parent
+func (s *DB) Ancestors(node interface{}, out interface{}) *DB {
+	scope, pathField, err := s.treeScope(node)
+	if err != nil {
+		scope.db.err(err)
+		return scope.db
+	}
+
+	var ids []string
+	for _, id := range strings.Split(strings.Trim(fmt.Sprint(pathField.Field.Interface()), treePathSeparator), treePathSeparator) {
+		if id != "" && id != fmt.Sprint(scope.PrimaryKeyValue()) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		ids = append(ids, "")
+	}
+	return s.Where(fmt.Sprintf("%v IN (?)", scope.Quote(scope.PrimaryKey())), ids).Order(scope.Quote(pathField.DBName)).Find(out)
+}
+
+// Roots find all nodes without parent into out
+func (s *DB) Roots(out interface{}) *DB {
+	scope := s.clone().NewScope(out)
+	parentField, _, ok := scope.treeFields()
+	if !ok {
+		scope.Err(fmt.Errorf("%v doesn't have tree_parent and tree_path fields", scope.GetModelStruct().ModelType))
+		return scope.db
+	}
+	parentType := parentField.Struct.Type
+	if parentType.Kind() == reflect.Ptr {
+		parentType = parentType.Elem()
+	}
+	column := scope.Quote(parentField.DBName)
+	return s.Where(fmt.Sprintf("%v IS NULL OR %v = ?", column, column), reflect.Zero(parentType).Interface()).Find(out)
+}
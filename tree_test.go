@@ -0,0 +1,62 @@
+package gorm_test
+
+import "testing"
+
+type TreeNode struct {
+	Id       int64
+	Name     string
+	ParentId int64  `gorm:"tree_parent"`
+	Path     string `gorm:"tree_path"`
+}
+
+func TestTreePath(t *testing.T) {
+	DB.DropTable(&TreeNode{})
+	DB.CreateTable(&TreeNode{})
+
+	root := TreeNode{Name: "root"}
+	DB.Save(&root)
+	child := TreeNode{Name: "child", ParentId: root.Id}
+	DB.Save(&child)
+	grandchild := TreeNode{Name: "grandchild", ParentId: child.Id}
+	DB.Save(&grandchild)
+	otherRoot := TreeNode{Name: "other root"}
+	DB.Save(&otherRoot)
+
+	var node TreeNode
+	DB.First(&node, grandchild.Id)
+	if node.Path == "" || node.Path != grandchild.Path {
+		t.Errorf("Tree path should be saved, but got %v", node.Path)
+	}
+
+	var descendants []TreeNode
+	DB.Descendants(&root, &descendants)
+	if len(descendants) != 2 || descendants[0].Name != "child" || descendants[1].Name != "grandchild" {
+		t.Errorf("Should find descendants of root, but got %+v", descendants)
+	}
+
+	var ancestors []TreeNode
+	DB.Ancestors(&grandchild, &ancestors)
+	if len(ancestors) != 2 || ancestors[0].Name != "root" || ancestors[1].Name != "child" {
+		t.Errorf("Should find ancestors of grandchild, but got %+v", ancestors)
+	}
+
+	var roots []TreeNode
+	DB.Roots(&roots)
+	if len(roots) != 2 {
+		t.Errorf("Should find two roots, but got %+v", roots)
+	}
+
+	child.ParentId = otherRoot.Id
+	DB.Save(&child)
+
+	descendants = []TreeNode{}
+	DB.Descendants(&otherRoot, &descendants)
+	if len(descendants) != 2 {
+		t.Errorf("Descendants should be moved together with their parent, but got %+v", descendants)
+	}
+
+	grandchild.ParentId = grandchild.Id
+	if DB.Save(&grandchild).Error == nil {
+		t.Errorf("Should get error when moving a node under itself")
+	}
+}
@@ -0,0 +1,62 @@
+package gorm
+
+import (
+	"fmt"
+)
+
+// HasTrigger check trigger of current model's table exists
+func (m Migrator) HasTrigger(name string) bool {
+	scope := m.newScope()
+	return scope.Dialect().HasTrigger(scope, scope.TableName(), name)
+}
+
+// CreateTrigger create trigger of current model's table with the sql if it doesn't exist, so it could be run
+// in every migration, e.g:
+//
+//	db.Model(&Order{}).Migrator().CreateTrigger("trg_orders_audit", `CREATE TRIGGER trg_orders_audit AFTER UPDATE ON orders
+//		FOR EACH ROW INSERT INTO order_audits (order_id, amount) VALUES (NEW.id, NEW.amount)`)
+func (m Migrator) CreateTrigger(name string, sql string) error {
+	scope := m.newScope()
+	if scope.Dialect().HasTrigger(scope, scope.TableName(), name) {
+		return scope.db.Error
+	}
+	return scope.Raw(sql).Exec().db.Error
+}
+
+// DropTrigger drop trigger of current model's table if it exists
+func (m Migrator) DropTrigger(name string) error {
+	scope := m.newScope()
+	return scope.Raw(scope.Dialect().DropTriggerSql(scope.Quote(name), scope.QuotedTableName())).Exec().db.Error
+}
+
+// HasFunction check stored function exists
+func (m Migrator) HasFunction(name string) bool {
+	scope := m.newScope()
+	return scope.Dialect().HasFunction(scope, name)
+}
+
+// CreateFunction create stored function with the sql if it doesn't exist, so it could be run in every migration, e.g:
+//
+//	db.Migrator().CreateFunction("order_total", `CREATE FUNCTION order_total(price DECIMAL(10,2), quantity INT)
+//		RETURNS DECIMAL(10,2) DETERMINISTIC RETURN price * quantity`)
+func (m Migrator) CreateFunction(name string, sql string) error {
+	scope := m.newScope()
+	if !scope.Dialect().SupportStoredFunction() {
+		scope.Err(StoredFunctionNotSupported)
+		return scope.db.Error
+	}
+	if scope.Dialect().HasFunction(scope, name) {
+		return scope.db.Error
+	}
+	return scope.Raw(sql).Exec().db.Error
+}
+
+// DropFunction drop stored function if it exists
+func (m Migrator) DropFunction(name string) error {
+	scope := m.newScope()
+	if !scope.Dialect().SupportStoredFunction() {
+		scope.Err(StoredFunctionNotSupported)
+		return scope.db.Error
+	}
+	return scope.Raw(fmt.Sprintf("DROP FUNCTION IF EXISTS %v", scope.Quote(name))).Exec().db.Error
+}
@@ -0,0 +1,16 @@
+package gorm
+
+import (
+	"testing"
+)
+
+func TestStoredFunctionNotSupported(t *testing.T) {
+	db := newFakeDB("sqlite3", "")
+
+	if err := db.Migrator().CreateFunction("order_total", "CREATE FUNCTION order_total() RETURNS INT RETURN 1"); err != StoredFunctionNotSupported {
+		t.Errorf("stored function should not be supported by sqlite3, but got %v", err)
+	}
+	if err := db.Migrator().DropFunction("order_total"); err != StoredFunctionNotSupported {
+		t.Errorf("stored function should not be supported by sqlite3, but got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package gorm
+
+import "strings"
+
+func quoteTransactionId(id string) string {
+	return "'" + strings.Replace(id, "'", "''", -1) + "'"
+}
+
+// PrepareTwoPhase prepare current transaction for two-phase commit with a global id, the transaction is ended after prepared,
+// it could be committed or rolled back later by CommitPrepared or RollbackPrepared, even from another connection, e.g:
+//
+//	tx := db.Begin()
+//	tx.Create(&order)
+//	if err := tx.PrepareTwoPhase("order-1").Error; err == nil {
+//		db.CommitPrepared("order-1")
+//	}
+func (s *DB) PrepareTwoPhase(id string) *DB {
+	tx, ok := s.db.(sqlTx)
+	if !ok {
+		s.err(NoValidTransaction)
+		return s
+	}
+
+	sql := s.parent.dialect.PrepareTransactionSql(quoteTransactionId(id))
+	if sql == "" {
+		s.err(TwoPhaseNotSupported)
+		tx.Rollback()
+		return s
+	}
+
+	if _, err := s.db.Exec(sql); err != nil {
+		s.err(err)
+	}
+	// the database has ended the transaction, rollback only returns the connection to pool, so its error is ignored
+	tx.Rollback()
+	return s
+}
+
+// CommitPrepared commit a transaction prepared by PrepareTwoPhase, it should not be called in transaction
+func (s *DB) CommitPrepared(id string) *DB {
+	return s.execTwoPhase(s.parent.dialect.CommitPreparedSql(quoteTransactionId(id)))
+}
+
+// RollbackPrepared rollback a transaction prepared by PrepareTwoPhase, it should not be called in transaction
+func (s *DB) RollbackPrepared(id string) *DB {
+	return s.execTwoPhase(s.parent.dialect.RollbackPreparedSql(quoteTransactionId(id)))
+}
+
+func (s *DB) execTwoPhase(sql string) *DB {
+	c := s.clone()
+	if sql == "" {
+		c.err(TwoPhaseNotSupported)
+	} else if _, err := c.db.Exec(sql); err != nil {
+		c.err(err)
+	}
+	return c
+}
@@ -0,0 +1,33 @@
+package gorm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTwoPhaseCommit(t *testing.T) {
+	db := newFakeDB("postgres", "transactions")
+
+	fakeStatements = nil
+	if err := db.Begin().PrepareTwoPhase("order'1").Error; err != nil {
+		t.Errorf("failed to prepare transaction, got %v", err)
+	}
+	if err := db.CommitPrepared("order'1").Error; err != nil {
+		t.Errorf("failed to commit prepared transaction, got %v", err)
+	}
+	db.RollbackPrepared("order2")
+
+	expected := []string{"BEGIN", "PREPARE TRANSACTION 'order''1'", "ROLLBACK", "COMMIT PREPARED 'order''1'", "ROLLBACK PREPARED 'order2'"}
+	if !reflect.DeepEqual(fakeStatements, expected) {
+		t.Errorf("statements should be %v, but got %v", expected, fakeStatements)
+	}
+
+	if db.PrepareTwoPhase("order3").Error != NoValidTransaction {
+		t.Errorf("PrepareTwoPhase should only work in transaction")
+	}
+
+	mysqlDB := newFakeDB("mysql", "transactions")
+	if mysqlDB.Begin().PrepareTwoPhase("order4").Error != TwoPhaseNotSupported {
+		t.Errorf("PrepareTwoPhase should return error for dialects don't support it")
+	}
+}
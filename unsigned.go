@@ -0,0 +1,73 @@
+package gorm
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// database/sql doesn't accept uint64 with high bit set, those values are sent as decimal strings
+func unsignedVar(value interface{}) interface{} {
+	if _, ok := value.(driver.Valuer); ok {
+		return value
+	}
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		if reflectValue.Uint() > math.MaxInt64 {
+			return strconv.FormatUint(reflectValue.Uint(), 10)
+		}
+	}
+	return value
+}
+
+func isUnsignedKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+// unsignedScanner scan BIGINT UNSIGNED values into unsigned fields,
+// some drivers return values above math.MaxInt64 as strings or overflowed int64
+type unsignedScanner struct {
+	field reflect.Value
+}
+
+func (s unsignedScanner) Scan(src interface{}) error {
+	var value uint64
+	switch v := src.(type) {
+	case nil:
+		value = 0
+	case int64:
+		if v < 0 {
+			return fmt.Errorf("can't scan negative value %v into %v", v, s.field.Type())
+		}
+		value = uint64(v)
+	case uint64:
+		value = v
+	case float64:
+		if v < 0 {
+			return fmt.Errorf("can't scan negative value %v into %v", v, s.field.Type())
+		}
+		value = uint64(v)
+	case []byte:
+		return s.Scan(string(v))
+	case string:
+		var err error
+		if value, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return fmt.Errorf("can't scan %q into %v: %v", v, s.field.Type(), err)
+		}
+	default:
+		return fmt.Errorf("can't scan %T into %v", src, s.field.Type())
+	}
+
+	if s.field.OverflowUint(value) {
+		return fmt.Errorf("value %v overflows %v", value, s.field.Type())
+	}
+	s.field.SetUint(value)
+	return nil
+}
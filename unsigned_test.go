@@ -0,0 +1,59 @@
+package gorm
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestUnsignedVar(t *testing.T) {
+	if value := unsignedVar(uint64(math.MaxUint64)); value != "18446744073709551615" {
+		t.Errorf("uint64 above math.MaxInt64 should be sent as string, but got %#v", value)
+	}
+	if value := unsignedVar(uint64(10)); value != uint64(10) {
+		t.Errorf("small uint64 should be kept, but got %#v", value)
+	}
+}
+
+func TestUnsignedScanner(t *testing.T) {
+	var value uint64
+	scanner := unsignedScanner{reflect.ValueOf(&value).Elem()}
+	for _, src := range []interface{}{[]byte("18446744073709551615"), "18446744073709551615", uint64(math.MaxUint64)} {
+		value = 0
+		if err := scanner.Scan(src); err != nil || value != math.MaxUint64 {
+			t.Errorf("%#v should be scanned as math.MaxUint64, but got %v, %v", src, value, err)
+		}
+	}
+
+	for _, src := range []interface{}{int64(-1), float64(-1), "-1"} {
+		if err := scanner.Scan(src); err == nil {
+			t.Errorf("should return error when scanning negative %#v", src)
+		}
+	}
+
+	var small uint8
+	if err := (unsignedScanner{reflect.ValueOf(&small).Elem()}).Scan(int64(256)); err == nil {
+		t.Errorf("should return error when value overflows the field")
+	}
+}
+
+func TestUnsignedSqlTag(t *testing.T) {
+	cases := []struct {
+		dialect  Dialect
+		value    interface{}
+		expected string
+	}{
+		{&mysql{}, uint32(0), "int"},
+		{&mysql{}, uint(0), "int"},
+		{&mysql{}, uint64(0), "bigint unsigned"},
+		{&postgres{}, uint32(0), "bigint"},
+		{&postgres{}, uint64(0), "bigint"},
+		{&postgres{}, uint(0), "bigint"},
+		{&mssql{}, uint64(0), "bigint"},
+	}
+	for _, c := range cases {
+		if tag := c.dialect.SqlTag(reflect.ValueOf(c.value), 0, false); tag != c.expected {
+			t.Errorf("sql tag of %T for %T should be %v, but got %v", c.value, c.dialect, c.expected, tag)
+		}
+	}
+}
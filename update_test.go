@@ -1,6 +1,8 @@
 package gorm_test
 
 import (
+	"database/sql"
+	"reflect"
 	"testing"
 	"time"
 
@@ -411,3 +413,132 @@ func TestUpdateColumnsSkipsAssociations(t *testing.T) {
 		t.Errorf("Expected user's BillingAddress.Address1=%s to remain unchanged after UpdateColumns invocation, but BillingAddress.Address1=%s", address1, freshUser.BillingAddress.Address1)
 	}
 }
+
+type UserOrder struct {
+	Id       int64
+	UserId   int64
+	UserName string
+}
+
+func TestUpdateFrom(t *testing.T) {
+	DB.DropTableIfExists(&UserOrder{})
+	DB.AutoMigrate(&UserOrder{})
+
+	user1 := User{Name: "update_from_user1"}
+	user2 := User{Name: "update_from_user2"}
+	DB.Save(&user1).Save(&user2)
+
+	order1 := UserOrder{UserId: user1.Id}
+	order2 := UserOrder{UserId: user2.Id, UserName: "kept"}
+	DB.Save(&order1).Save(&order2)
+
+	err := DB.Model(&UserOrder{}).UpdateFrom("users", "users.id = user_orders.user_id").
+		Where("user_orders.user_name = ?", "").UpdateColumn("user_name", gorm.Expr("users.name")).Error
+	if err != nil {
+		t.Errorf("No error should happen when update from joined table, but got %v", err)
+	}
+
+	DB.First(&order1, order1.Id)
+	if order1.UserName != user1.Name {
+		t.Errorf("Order's user name should be copied from users, but got %v", order1.UserName)
+	}
+
+	DB.First(&order2, order2.Id)
+	if order2.UserName != "kept" {
+		t.Errorf("Orders not matching conditions should not be updated, but got %v", order2.UserName)
+	}
+}
+
+func TestUpdatesWithChangeSet(t *testing.T) {
+	user := User{Name: "change_set", Age: 18, Latitude: 1.5}
+	DB.Save(&user)
+
+	changes := gorm.TrackChanges(&user)
+	if len(changes.Changed()) != 0 {
+		t.Errorf("nothing should be changed after tracked, but got %v", changes.Changed())
+	}
+
+	user.Age = 0
+	user.Name = "change_set_new"
+	if changed := changes.Changed(); len(changed) != 2 || changed["age"] != int64(0) {
+		t.Errorf("changed fields should include zero values, but got %v", changed)
+	}
+
+	DB.Exec("UPDATE users SET latitude = 2.5 WHERE id = ?", user.Id)
+	if err := DB.Model(&user).Updates(changes).Error; err != nil {
+		t.Errorf("changed fields should be updated, but got %v", err)
+	}
+
+	var found User
+	DB.First(&found, user.Id)
+	if found.Age != 0 || found.Name != "change_set_new" {
+		t.Errorf("changed fields should be updated even with zero values, but got %v, %v", found.Age, found.Name)
+	}
+	if found.Latitude != 2.5 {
+		t.Errorf("unchanged fields should not be updated, but got %v", found.Latitude)
+	}
+}
+
+type Profile struct {
+	Id       int64
+	Nickname *string
+	Score    sql.NullInt64
+	Level    int64
+}
+
+func TestUpdatesWithPointerAndNullFields(t *testing.T) {
+	DB.DropTableIfExists(&Profile{})
+	DB.AutoMigrate(&Profile{})
+
+	nickname := "jinzhu"
+	profile := Profile{Nickname: &nickname, Score: sql.NullInt64{Int64: 10, Valid: true}, Level: 3}
+	DB.Save(&profile)
+
+	empty := ""
+	DB.Model(&profile).Updates(Profile{Nickname: &empty, Score: sql.NullInt64{Int64: 0, Valid: true}})
+
+	var found Profile
+	DB.First(&found, profile.Id)
+	if found.Nickname == nil || *found.Nickname != "" || found.Score.Int64 != 0 || found.Level != 3 {
+		t.Errorf("non-nil pointer and valid null fields should be updated with zero values, but got %+v", found)
+	}
+}
+
+func TestIncrement(t *testing.T) {
+	product := Product{Code: "increment", Price: 10}
+	DB.Save(&product)
+
+	stale := product
+	if err := DB.Model(&stale).Increment("Price", 5).Error; err != nil {
+		t.Errorf("No error should happen when increment, but got %v", err)
+	}
+	if err := DB.Model(&product).Decrement("price", 2).Error; err != nil {
+		t.Errorf("No error should happen when decrement, but got %v", err)
+	}
+	if product.Price != 13 {
+		t.Errorf("field should be refreshed with the value in database, but got %v", product.Price)
+	}
+
+	var reloaded Product
+	DB.First(&reloaded, product.Id)
+	if reloaded.Price != 13 {
+		t.Errorf("price should be updated atomically, but got %v", reloaded.Price)
+	}
+}
+
+func TestBatchUpdate(t *testing.T) {
+	products := []Product{{Code: "batch_update_1", Price: 1}, {Code: "batch_update_2", Price: 2}}
+	DB.Save(&products[0]).Save(&products[1])
+
+	products[0].Price, products[1].Price = 10, 20
+	products = append([]Product{products[1]}, products[0], Product{Code: "batch_update_3", Price: 30})
+	if err := DB.SortWritesByPrimaryKey().BatchUpdate(&products).Error; err != nil {
+		t.Errorf("No error should happen when batch update, but got %v", err)
+	}
+
+	var prices []int64
+	DB.Model(&Product{}).Where("code LIKE ?", "batch_update_%").Order("code").Pluck("price", &prices)
+	if !reflect.DeepEqual(prices, []int64{10, 20, 30}) || products[2].Id == 0 {
+		t.Errorf("records should be updated, and new records should be created, but got %v", prices)
+	}
+}
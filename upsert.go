@@ -0,0 +1,185 @@
+package gorm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// OnConflict is the conflict behavior of inserting, Target is the name of a unique index or its columns, default
+// to primary keys, conflicting records are ignored with DoNothing, or updated with inserted values of UpdateColumns,
+// which are all inserted columns except the target's, primary keys and CreatedAt if blank
+type OnConflict struct {
+	Target        []string
+	DoNothing     bool
+	UpdateColumns []string
+}
+
+// Upsert insert the value, or update the conflicting record with it, the conflict target could be the name of a
+// unique index, e.g. `unique_index:uix_users_company_email` tagged on multiple fields, or its columns, default to
+// primary keys, MySQL updates on any unique key conflict as it doesn't support conflict targets, e.g:
+//
+//	db.Upsert(&user, "uix_users_company_email")
+//	//// INSERT INTO "users" ("company_id","email","name") VALUES (1,'jinzhu@example.org','jinzhu')
+//	////   ON CONFLICT ("company_id","email") DO UPDATE SET "name"=EXCLUDED."name" RETURNING "users"."id"
+//	db.Upsert(&user, "company_id", "email")
+func (s *DB) Upsert(value interface{}, target ...string) *DB {
+	scope := s.clone().NewScope(value).InstanceSet("gorm:insert_ignore", false).InstanceSet("gorm:on_conflict", OnConflict{Target: target})
+	return scope.callCallbacks(s.parent.callback.creates).db
+}
+
+// BatchUpsert insert values with a single multi-row statement, conflicting records are ignored or updated as
+// onConflict, for idempotent bulk ingestion, primary keys are not scanned back, e.g:
+//
+//	db.BatchUpsert(&events, gorm.OnConflict{Target: []string{"uix_events_source_seq"}, DoNothing: true})
+//	//// INSERT INTO "events" ("source","seq","payload") VALUES ('a',1,'...'),('a',2,'...') ON CONFLICT ("source","seq") DO NOTHING
+//	db.BatchUpsert(&stocks, gorm.OnConflict{Target: []string{"sku"}, UpdateColumns: []string{"Quantity"}})
+//	//// INSERT INTO "stocks" ("sku","quantity","price") VALUES ('A1',3,10),('B2',5,20) ON CONFLICT ("sku") DO UPDATE SET "quantity"=EXCLUDED."quantity"
+func (s *DB) BatchUpsert(value interface{}, onConflict OnConflict) *DB {
+	return s.batchCreate(value, func(scope *Scope) *Scope {
+		return scope.InstanceSet("gorm:insert_ignore", false).InstanceSet("gorm:on_conflict", onConflict)
+	})
+}
+
+// CreateIgnoreDuplicate insert the value unless it conflicts with an existing record on the conflict target, which is
+// the name of a unique index or its columns, default to primary keys, MySQL ignores conflicts of any unique key,
+// RowsAffected reports whether the record is inserted, associations and callbacks after creating are skipped if not,
+// MySQL reports ignored duplicates as affected with the clientFoundRows option of the driver, don't enable it, e.g:
+//
+//	if db.CreateIgnoreDuplicate(&event, "uix_events_source_seq").RowsAffected == 0 {
+//		// duplicated event
+//	}
+//	//// INSERT INTO "events" ("source","seq") VALUES ('a',1) ON CONFLICT ("source","seq") DO NOTHING RETURNING "events"."id"
+func (s *DB) CreateIgnoreDuplicate(value interface{}, target ...string) *DB {
+	scope := s.clone().NewScope(value).InstanceSet("gorm:insert_ignore", false).InstanceSet("gorm:on_conflict", OnConflict{Target: target, DoNothing: true})
+	return scope.callCallbacks(s.parent.callback.creates).db
+}
+
+// upsertsUpdated check if the conflicting record is updated by upserting, MySQL reports 2 affected rows for it, and
+// the last insert id isn't the id of it
+func (scope *Scope) upsertsUpdated() bool {
+	onConflict, ok := scope.InstanceGet("gorm:on_conflict")
+	return ok && !onConflict.(OnConflict).DoNothing && scope.db.RowsAffected > 1
+}
+
+// ignoresDuplicate check if conflicting records are ignored when inserting
+func (scope *Scope) ignoresDuplicate() bool {
+	onConflict, ok := scope.InstanceGet("gorm:on_conflict")
+	return ok && onConflict.(OnConflict).DoNothing
+}
+
+// conflictColumns get columns of the conflict target, which is the name of a unique index, or field names or columns
+func (scope *Scope) conflictColumns(target []string) ([]string, error) {
+	if len(target) == 0 {
+		var columns []string
+		for _, field := range scope.GetModelStruct().PrimaryFields {
+			columns = append(columns, field.DBName)
+		}
+		return columns, nil
+	}
+
+	if len(target) == 1 {
+		if _, uniqueIndexes, _ := scope.tagIndexes(); len(uniqueIndexes[target[0]]) > 0 {
+			return uniqueIndexes[target[0]], nil
+		}
+		for _, index := range scope.modelIndexes() {
+			if index.Unique && index.Name == target[0] {
+				for _, part := range index.Parts {
+					if !columnPartRegexp.MatchString(part) {
+						return nil, fmt.Errorf("unique index %v with expressions can't be the conflict target", index.Name)
+					}
+				}
+				return index.Parts, nil
+			}
+		}
+	}
+
+	var columns []string
+	for _, name := range target {
+		field, ok := scope.FieldByName(name)
+		if !ok || !field.IsNormal {
+			return nil, fmt.Errorf("can't find unique index or field %v for the conflict target", name)
+		}
+		columns = append(columns, field.DBName)
+	}
+	return columns, nil
+}
+
+// onConflictSql get the conflict clause of inserting quoted columns
+func (scope *Scope) onConflictSql(onConflict OnConflict, columns []string) string {
+	conflictColumns, err := scope.conflictColumns(onConflict.Target)
+	if scope.Err(err) != nil {
+		return ""
+	}
+
+	var quotedConflictColumns, updateColumns []string
+	for _, column := range conflictColumns {
+		quotedConflictColumns = append(quotedConflictColumns, scope.Quote(column))
+	}
+	switch {
+	case onConflict.DoNothing:
+	case len(onConflict.UpdateColumns) > 0:
+		for _, name := range onConflict.UpdateColumns {
+			field, ok := scope.FieldByName(name)
+			if !ok || !field.IsNormal {
+				scope.Err(fmt.Errorf("can't find field %v to update on conflict", name))
+				return ""
+			}
+			updateColumns = append(updateColumns, scope.Quote(field.DBName))
+		}
+	default:
+		// primary keys and creation timestamps keep values of the conflicting record
+		keptColumns := append([]string{}, quotedConflictColumns...)
+		for _, field := range scope.GetModelStruct().PrimaryFields {
+			keptColumns = append(keptColumns, scope.Quote(field.DBName))
+		}
+		if field, ok := scope.FieldByName("CreatedAt"); ok && field.IsNormal {
+			keptColumns = append(keptColumns, scope.Quote(field.DBName))
+		}
+		for _, column := range columns {
+			if !inStringSlice(column, keptColumns) {
+				updateColumns = append(updateColumns, column)
+			}
+		}
+		// updating a conflicting column with itself makes the conflicting record returned
+		if len(updateColumns) == 0 && len(quotedConflictColumns) > 0 {
+			updateColumns = quotedConflictColumns[:1]
+		}
+	}
+	if len(updateColumns) > 0 && len(quotedConflictColumns) == 0 {
+		scope.Err(errors.New("conflict target is required to update on conflict"))
+		return ""
+	}
+
+	sql := scope.Dialect().UpsertSql(quotedConflictColumns, updateColumns)
+	if sql == "" {
+		scope.Err(UpsertNotSupported)
+	}
+	return sql
+}
+
+func inStringSlice(str string, strs []string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
+// excludedUpsertSql get `ON CONFLICT` clause updating with EXCLUDED values, used by Postgres and sqlite3,
+// conflicting records are ignored without update columns
+func excludedUpsertSql(conflictColumns []string, updateColumns []string) string {
+	if len(updateColumns) == 0 {
+		if len(conflictColumns) == 0 {
+			return "ON CONFLICT DO NOTHING"
+		}
+		return fmt.Sprintf("ON CONFLICT (%v) DO NOTHING", strings.Join(conflictColumns, ","))
+	}
+
+	var sets []string
+	for _, column := range updateColumns {
+		sets = append(sets, fmt.Sprintf("%v=EXCLUDED.%v", column, column))
+	}
+	return fmt.Sprintf("ON CONFLICT (%v) DO UPDATE SET %v", strings.Join(conflictColumns, ","), strings.Join(sets, ","))
+}
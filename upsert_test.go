@@ -0,0 +1,148 @@
+package gorm
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+type upsertMember struct {
+	Id        int64
+	CompanyId int64  `sql:"unique_index:uix_upsert_members_company_email"`
+	Email     string `sql:"unique_index:uix_upsert_members_company_email"`
+	Name      string
+}
+
+func (upsertMember) Indexes() []Index {
+	return []Index{{Name: "uix_upsert_members_lower_name", Parts: []string{"lower(name)"}, Unique: true}}
+}
+
+func TestUpsertSql(t *testing.T) {
+	columns := []string{`"company_id"`, `"email"`, `"name"`}
+	tests := []struct {
+		dialect string
+		target  []string
+		sql     string
+	}{
+		{"postgres", []string{"uix_upsert_members_company_email"}, `ON CONFLICT ("company_id","email") DO UPDATE SET "name"=EXCLUDED."name"`},
+		{"postgres", []string{"CompanyId", "email"}, `ON CONFLICT ("company_id","email") DO UPDATE SET "name"=EXCLUDED."name"`},
+		{"sqlite3", nil, `ON CONFLICT ("id") DO UPDATE SET "company_id"=EXCLUDED."company_id","email"=EXCLUDED."email","name"=EXCLUDED."name"`},
+		{"postgres", []string{"company_id", "email", "name"}, `ON CONFLICT ("company_id","email","name") DO UPDATE SET "company_id"=EXCLUDED."company_id"`},
+		{"mysql", []string{"uix_upsert_members_company_email"}, "ON DUPLICATE KEY UPDATE `name`=VALUES(`name`)"},
+	}
+
+	for _, test := range tests {
+		db := newFakeDB(test.dialect, "")
+		scope := db.NewScope(&upsertMember{})
+		quoted := columns
+		if test.dialect == "mysql" {
+			quoted = []string{"`company_id`", "`email`", "`name`"}
+		}
+
+		if sql := scope.onConflictSql(OnConflict{Target: test.target}, quoted); sql != test.sql || scope.HasError() {
+			t.Errorf("upsert sql of %v should be %v, but got %v, %v", test.target, test.sql, sql, scope.db.Error)
+		}
+	}
+}
+
+type upsertSubscription struct {
+	Id        int64
+	UserId    int64 `sql:"unique_index:uix_upsert_subscriptions_user"`
+	Topic     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func TestUpsertKeepsPrimaryKeysAndCreatedAt(t *testing.T) {
+	db := newFakeDB("postgres", "")
+	scope := db.NewScope(&upsertSubscription{})
+	columns := []string{`"id"`, `"user_id"`, `"topic"`, `"created_at"`, `"updated_at"`}
+
+	expected := `ON CONFLICT ("user_id") DO UPDATE SET "topic"=EXCLUDED."topic","updated_at"=EXCLUDED."updated_at"`
+	if sql := scope.onConflictSql(OnConflict{Target: []string{"uix_upsert_subscriptions_user"}}, columns); sql != expected || scope.HasError() {
+		t.Errorf("upsert shouldn't update primary keys and creation timestamps, expected %v, but got %v, %v", expected, sql, scope.db.Error)
+	}
+}
+
+func TestUpsertUpdatedKeepsPrimaryKey(t *testing.T) {
+	db := newFakeDB("mysql", "upserted")
+
+	subscription := upsertSubscription{UserId: 1, Topic: "news"}
+	if result := db.Upsert(&subscription, "uix_upsert_subscriptions_user"); result.Error != nil || result.RowsAffected != 2 || subscription.Id != 0 {
+		t.Errorf("last insert id shouldn't be set to the updated record, but got %v, %v, %+v", result.Error, result.RowsAffected, subscription)
+	}
+}
+
+func TestUpsertConflictTargetErrors(t *testing.T) {
+	for _, test := range []struct {
+		dialect string
+		target  []string
+	}{
+		{"postgres", []string{"uix_upsert_members_lower_name"}},
+		{"postgres", []string{"unknown"}},
+		{"mssql", []string{"uix_upsert_members_company_email"}},
+	} {
+		db := newFakeDB(test.dialect, "")
+		scope := db.NewScope(&upsertMember{})
+		if scope.onConflictSql(OnConflict{Target: test.target}, []string{`"name"`}); !scope.HasError() {
+			t.Errorf("should get error for conflict target %v with %v", test.target, test.dialect)
+		}
+	}
+}
+
+func TestOnConflictSql(t *testing.T) {
+	tests := []struct {
+		dialect    string
+		onConflict OnConflict
+		sql        string
+	}{
+		{"postgres", OnConflict{Target: []string{"uix_upsert_members_company_email"}, DoNothing: true}, `ON CONFLICT ("company_id","email") DO NOTHING`},
+		{"sqlite3", OnConflict{Target: []string{"Id"}, UpdateColumns: []string{"Name", "email"}}, `ON CONFLICT ("id") DO UPDATE SET "name"=EXCLUDED."name","email"=EXCLUDED."email"`},
+		{"mysql", OnConflict{DoNothing: true}, "ON DUPLICATE KEY UPDATE `id`=`id`"},
+	}
+
+	for _, test := range tests {
+		db := newFakeDB(test.dialect, "")
+		scope := db.NewScope(&upsertMember{})
+		if sql := scope.onConflictSql(test.onConflict, []string{scope.Quote("company_id"), scope.Quote("email"), scope.Quote("name")}); sql != test.sql || scope.HasError() {
+			t.Errorf("conflict sql of %+v should be %v, but got %v, %v", test.onConflict, test.sql, sql, scope.db.Error)
+		}
+	}
+
+	db := newFakeDB("postgres", "")
+	if scope := db.NewScope(&upsertMember{}); scope.onConflictSql(OnConflict{UpdateColumns: []string{"Unknown"}}, nil) != "" || !scope.HasError() {
+		t.Errorf("should get error when updating unknown fields on conflict")
+	}
+}
+
+type upsertProfile struct {
+	Id     int64
+	UserId int64 `sql:"unique_index"`
+	Notes  []upsertNote
+}
+
+type upsertNote struct {
+	Id              int64
+	UpsertProfileId int64
+	Body            string
+}
+
+func TestCreateIgnoreDuplicate(t *testing.T) {
+	db := newFakeDB("postgres", "")
+
+	member := upsertMember{CompanyId: 1, Email: "jinzhu@example.org"}
+	if result := db.CreateIgnoreDuplicate(&member, "uix_upsert_members_company_email"); result.Error != nil || result.RowsAffected != 0 || member.Id != 0 {
+		t.Errorf("duplicated record should be ignored without error, but got %v, %v, %+v", result.Error, result.RowsAffected, member)
+	}
+
+	fakeQueries = nil
+	profile := upsertProfile{UserId: 1, Notes: []upsertNote{{Body: "hello"}}}
+	if result := db.CreateIgnoreDuplicate(&profile, "UserId"); result.Error != nil || result.RowsAffected != 0 || len(fakeQueries) != 1 {
+		t.Errorf("associations of ignored duplicates shouldn't be saved, but got %v, %v", result.Error, fakeQueries)
+	}
+
+	fakeResults = map[string][][]driver.Value{`INSERT INTO "upsert_members"`: {{int64(7)}}}
+	if result := db.CreateIgnoreDuplicate(&member, "uix_upsert_members_company_email"); result.Error != nil || result.RowsAffected != 1 || member.Id != 7 {
+		t.Errorf("record should be inserted, but got %v, %v, %+v", result.Error, result.RowsAffected, member)
+	}
+}
@@ -79,6 +79,22 @@ func ToDBName(name string) string {
 	return s
 }
 
+// ColumnNameHandler get the column name of a field without `column` tag, replace it to follow legacy naming
+// conventions of a project before any model is used, e.g:
+//
+//	gorm.ColumnNameHandler = gorm.AffixedColumnName("F_", "")
+//	// Enabled => F_enabled, CreatedAt => F_created_at
+var ColumnNameHandler = func(fieldName string) string {
+	return ToDBName(fieldName)
+}
+
+// AffixedColumnName get a ColumnNameHandler adding prefix and suffix to column names of ToDBName
+func AffixedColumnName(prefix, suffix string) func(fieldName string) string {
+	return func(fieldName string) string {
+		return prefix + ToDBName(fieldName) + suffix
+	}
+}
+
 type expr struct {
 	expr string
 	args []interface{}
@@ -113,3 +129,9 @@ func addExtraSpaceIfExist(str string) string {
 	}
 	return ""
 }
+
+// labelComment build sql comment for label, `/*` and `*/` in it are broken, as comments could be nested in some databases
+func labelComment(label string) string {
+	label = strings.Replace(strings.Replace(label, "*/", "* /", -1), "/*", "/ *", -1)
+	return "/* label:" + label + " */"
+}
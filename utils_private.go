@@ -2,22 +2,51 @@ package gorm
 
 import (
 	"fmt"
+	"path/filepath"
 	"reflect"
-	"regexp"
 	"runtime"
+	"strings"
 )
 
+// gormSourceDir is the directory of gorm's source files, frames in it are skipped when looking for callers
+var gormSourceDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// fileWithLineNum return location of the first caller outside of gorm, frames of runtime, reflect
+// and database/sql are skipped too, as gorm calls hooks and drivers through them
 func fileWithLineNum() string {
-	for i := 2; i < 15; i++ {
-		_, file, line, ok := runtime.Caller(i)
-		if ok && (!regexp.MustCompile(`golib/gorm/.*.go`).MatchString(file) || regexp.MustCompile(`golib/gorm/.*test.go`).MatchString(file)) {
-			return fmt.Sprintf("[%v:%v]", file, line)
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if frame.File != "" && !isInternalFrame(frame) {
+			return fmt.Sprintf("[%v:%v]", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
 		}
 	}
-	return ""
+}
+
+func isInternalFrame(frame runtime.Frame) bool {
+	if filepath.Dir(frame.File) == gormSourceDir {
+		return !strings.HasSuffix(frame.File, "_test.go")
+	}
+	for _, prefix := range []string{"runtime.", "reflect.", "database/sql."} {
+		if strings.HasPrefix(frame.Function, prefix) {
+			return true
+		}
+	}
+	return false
 }
 
 func isBlank(value reflect.Value) bool {
+	// IsZero of nil pointers like *time.Time can't be called
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		return true
+	}
 	if zero_func := value.MethodByName("IsZero"); zero_func.IsValid() {
 		switch f := zero_func.Interface().(type) {
 		case func() bool:
@@ -27,6 +56,16 @@ func isBlank(value reflect.Value) bool {
 	return reflect.DeepEqual(value.Interface(), reflect.Zero(value.Type()).Interface())
 }
 
+// isIntKind integer fields of time columns like CreatedAt save unix seconds
+func isIntKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
 func toSearchableMap(attrs ...interface{}) (result interface{}) {
 	if len(attrs) > 1 {
 		if str, ok := attrs[0].(string); ok {
@@ -0,0 +1,71 @@
+package gorm
+
+import (
+	"fmt"
+)
+
+// CheckView return ViewNotWritable error when creating, updating or deleting models backed by views
+func CheckView(scope *Scope) {
+	if scope.GetModelStruct().IsView {
+		scope.Err(ViewNotWritable)
+	}
+}
+
+// CreateView create view with the query, with replace, existing view is replaced, e.g:
+//
+//	db.Migrator().CreateView("active_users", "SELECT id, name FROM users WHERE active = true", true)
+//
+//	type ActiveUser struct {
+//		_    struct{} `gorm:"view"`
+//		Id   int64
+//		Name string
+//	}
+//	db.Find(&activeUsers)
+func (m Migrator) CreateView(name string, query string, replace bool) error {
+	scope := m.newScope()
+	return scope.Raw(scope.Dialect().CreateViewSql(scope.Quote(name), query, replace)).Exec().db.Error
+}
+
+// DropView drop view if it exists
+func (m Migrator) DropView(name string) error {
+	scope := m.newScope()
+	return scope.Raw(fmt.Sprintf("DROP VIEW IF EXISTS %v", scope.Quote(name))).Exec().db.Error
+}
+
+// CreateMaterializedView create materialized view with the query if it doesn't exist, it is supported by Postgres, e.g:
+//
+//	db.Migrator().CreateMaterializedView("daily_sales", "SELECT date(created_at) AS day, sum(amount) AS amount FROM orders GROUP BY 1")
+//	db.Exec("CREATE UNIQUE INDEX uix_daily_sales_day ON daily_sales(day)") // required to refresh concurrently
+func (m Migrator) CreateMaterializedView(name string, query string) error {
+	scope := m.newScope()
+	if !scope.Dialect().SupportMaterializedView() {
+		scope.Err(MaterializedViewNotSupported)
+		return scope.db.Error
+	}
+	return scope.Raw(fmt.Sprintf("CREATE MATERIALIZED VIEW IF NOT EXISTS %v AS %v", scope.Quote(name), query)).Exec().db.Error
+}
+
+// RefreshMaterializedView refresh data of materialized view, with concurrently, queries on the view are not blocked
+// while refreshing, which requires a unique index on the view
+func (m Migrator) RefreshMaterializedView(name string, concurrently bool) error {
+	scope := m.newScope()
+	if !scope.Dialect().SupportMaterializedView() {
+		scope.Err(MaterializedViewNotSupported)
+		return scope.db.Error
+	}
+	sql := "REFRESH MATERIALIZED VIEW "
+	if concurrently {
+		sql += "CONCURRENTLY "
+	}
+	return scope.Raw(sql + scope.Quote(name)).Exec().db.Error
+}
+
+// DropMaterializedView drop materialized view if it exists
+func (m Migrator) DropMaterializedView(name string) error {
+	scope := m.newScope()
+	if !scope.Dialect().SupportMaterializedView() {
+		scope.Err(MaterializedViewNotSupported)
+		return scope.db.Error
+	}
+	return scope.Raw(fmt.Sprintf("DROP MATERIALIZED VIEW IF EXISTS %v", scope.Quote(name))).Exec().db.Error
+}
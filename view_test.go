@@ -0,0 +1,36 @@
+package gorm
+
+import (
+	"testing"
+)
+
+type activeUser struct {
+	_    struct{} `gorm:"view"`
+	Id   int64
+	Name string
+}
+
+func TestCheckView(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	scope := db.NewScope(&activeUser{Id: 1})
+	if CheckView(scope); scope.db.Error != ViewNotWritable {
+		t.Errorf("models backed by views should not be writable, but got %v", scope.db.Error)
+	}
+
+	scope = db.NewScope(&indexedUser{Id: 1})
+	if CheckView(scope); scope.db.Error != nil {
+		t.Errorf("models backed by tables should be writable, but got %v", scope.db.Error)
+	}
+}
+
+func TestMaterializedViewNotSupported(t *testing.T) {
+	db := newFakeDB("mysql", "")
+
+	if err := db.Migrator().CreateMaterializedView("daily_sales", "SELECT 1"); err != MaterializedViewNotSupported {
+		t.Errorf("materialized view should not be supported by mysql, but got %v", err)
+	}
+	if err := db.Migrator().RefreshMaterializedView("daily_sales", true); err != MaterializedViewNotSupported {
+		t.Errorf("materialized view should not be supported by mysql, but got %v", err)
+	}
+}
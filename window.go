@@ -0,0 +1,76 @@
+package gorm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Window is a window function expression that could be used in Select, e.g:
+//
+//	db.Select([]interface{}{"*", gorm.RowNumber().PartitionBy("user_id").OrderBy("created_at DESC").As("rn")})
+//	//// SELECT *, ROW_NUMBER() OVER (PARTITION BY "user_id" ORDER BY "created_at" DESC) AS "rn"
+type Window struct {
+	function   string
+	columns    []string
+	partitions []string
+	orders     []string
+	alias      string
+}
+
+// WindowFunc create a window expression with any function, columns are the function's arguments
+//
+//	gorm.WindowFunc("SUM", "amount").PartitionBy("user_id")
+func WindowFunc(function string, columns ...string) *Window {
+	return &Window{function: function, columns: columns}
+}
+
+func RowNumber() *Window {
+	return WindowFunc("ROW_NUMBER")
+}
+
+func Rank() *Window {
+	return WindowFunc("RANK")
+}
+
+func DenseRank() *Window {
+	return WindowFunc("DENSE_RANK")
+}
+
+func (w *Window) PartitionBy(columns ...string) *Window {
+	w.partitions = append(w.partitions, columns...)
+	return w
+}
+
+// OrderBy accept columns with optional direction, like `created_at DESC`
+func (w *Window) OrderBy(orders ...string) *Window {
+	w.orders = append(w.orders, orders...)
+	return w
+}
+
+func (w *Window) As(alias string) *Window {
+	w.alias = alias
+	return w
+}
+
+func (w *Window) toSql(scope *Scope) string {
+	var over []string
+	if len(w.partitions) > 0 {
+		over = append(over, "PARTITION BY "+strings.Join(scope.quoteColumns(w.partitions), ", "))
+	}
+
+	if len(w.orders) > 0 {
+		var orders []string
+		for _, order := range w.orders {
+			parts := strings.SplitN(strings.TrimSpace(order), " ", 2)
+			parts[0] = scope.quoteColumns(parts[:1])[0]
+			orders = append(orders, strings.Join(parts, " "))
+		}
+		over = append(over, "ORDER BY "+strings.Join(orders, ", "))
+	}
+
+	sql := fmt.Sprintf("%v(%v) OVER (%v)", w.function, strings.Join(scope.quoteColumns(w.columns), ", "), strings.Join(over, " "))
+	if w.alias != "" {
+		sql += " AS " + scope.Quote(w.alias)
+	}
+	return sql
+}